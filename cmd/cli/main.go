@@ -0,0 +1,230 @@
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"tyarus/weather-app/internal/config"
+	"tyarus/weather-app/internal/dto"
+	"tyarus/weather-app/internal/infra"
+	"tyarus/weather-app/internal/repository"
+	"tyarus/weather-app/internal/usecase"
+	"tyarus/weather-app/pkg/utils"
+	"tyarus/weather-app/pkg/weather"
+)
+
+const usage = `usage: weather-cli <command> [flags]
+
+commands:
+  export    export weathers to a file, run "weather-cli export -h" for flags
+  backfill  load the observed history of a location, run "weather-cli backfill -h" for flags
+  backfill-conditions  map the condition code of weathers synced before it was kept
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "export":
+		if err := runExport(os.Args[2:]); err != nil {
+			log.Fatalf("failed to export weathers: %v", err)
+		}
+	case "backfill":
+		if err := runBackfill(os.Args[2:]); err != nil {
+			log.Fatalf("failed to backfill weathers: %v", err)
+		}
+	case "backfill-conditions":
+		if err := runBackfillConditions(); err != nil {
+			log.Fatalf("failed to backfill condition codes: %v", err)
+		}
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
+
+// runExport mirrors GET /api/v1/weathers/export, reading the database
+// configured in the environment. Unlike the endpoint it has no range or row
+// cap.
+func runExport(args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	locationIDs := flags.String("locationIDs", "", "comma separated location ids, every location when empty")
+	from := flags.String("from", "", "RFC3339 timestamp or YYYY-MM-DD, inclusive")
+	to := flags.String("to", "", "RFC3339 timestamp or YYYY-MM-DD, exclusive")
+	forecastType := flags.String("forecastType", "", "hour or day, both when empty")
+	conditions := flags.String("conditions", "", "comma separated condition codes, every condition when empty")
+	format := flags.String("format", dto.WeatherExportFormatCSV, "csv or ndjson")
+	columns := flags.String("columns", "", "comma separated columns, every column when empty: "+strings.Join(dto.WeatherExportColumns(), ","))
+	compress := flags.Bool("gzip", false, "gzip the output, implied by an output file ending in .gz")
+	out := flags.String("out", "", "output file, required")
+	flags.Parse(args)
+
+	if *out == "" {
+		flags.Usage()
+		return fmt.Errorf("out flag is required")
+	}
+
+	query := url.Values{}
+	query.Set("locationIDs", *locationIDs)
+	query.Set("from", *from)
+	query.Set("to", *to)
+	query.Set("forecastType", *forecastType)
+	query.Set("conditions", *conditions)
+	query.Set("format", *format)
+	query.Set("columns", *columns)
+	param, err := dto.ParseExportWeathersQuery(query)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.Load()
+	// icon URLs are absolute on the public base URL when it is configured
+	if param.BaseURL, err = dto.ParsePublicBaseURL(cfg.PublicBaseURL); err != nil {
+		return err
+	}
+
+	db, err := infra.InitDatabase(cfg.MySQLDSN)
+	if err != nil {
+		return fmt.Errorf("failed to connect MySQL: %w", err)
+	}
+	defer db.Close()
+
+	// the export is written next to the output file and only renamed to it
+	// once complete, so a failed export never leaves a partial file behind
+	file, err := os.CreateTemp(filepath.Dir(*out), "."+filepath.Base(*out)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	if err := file.Chmod(0o644); err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+
+	var writer io.Writer = file
+	var gz *gzip.Writer
+	if *compress || strings.HasSuffix(*out, ".gz") {
+		gz = gzip.NewWriter(file)
+		writer = gz
+	}
+
+	exportUc := usecase.NewWeatherExportUsecase(repository.NewWeatherRepository(db))
+	result, err := exportUc.ExportWeathersUsecase(context.Background(), param, writer)
+	if err != nil {
+		return err
+	}
+
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return fmt.Errorf("failed to finish gzip output: %w", err)
+		}
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	if err := os.Rename(file.Name(), *out); err != nil {
+		return fmt.Errorf("failed to move output file: %w", err)
+	}
+
+	log.Printf("exported %d weathers to %s", result.Rows, *out)
+	return nil
+}
+
+// runBackfill loads the provider history of a location into the database
+// configured in the environment, for statistics on earlier periods.
+func runBackfill(args []string) error {
+	flags := flag.NewFlagSet("backfill", flag.ExitOnError)
+	locationID := flags.Int64("locationID", 0, "location id, required")
+	from := flags.String("from", "", "first day as YYYY-MM-DD of the location, required")
+	to := flags.String("to", "", "last day as YYYY-MM-DD of the location, inclusive, required")
+	flags.Parse(args)
+
+	param := dto.BackfillWeatherParam{LocationID: *locationID}
+	var err error
+	if param.From, err = parseDate(*from); err != nil {
+		return fmt.Errorf("invalid from flag: %w", err)
+	}
+	if param.To, err = parseDate(*to); err != nil {
+		return fmt.Errorf("invalid to flag: %w", err)
+	}
+
+	if err := param.Validate(); err != nil {
+		flags.Usage()
+		return err
+	}
+
+	cfg := config.Load()
+	db, err := infra.InitDatabase(cfg.MySQLDSN)
+	if err != nil {
+		return fmt.Errorf("failed to connect MySQL: %w", err)
+	}
+	defer db.Close()
+
+	cache := infra.InitCache(cfg.RedisAddr, cfg.RedisPassword)
+	defer cache.Close()
+
+	weatherUc := usecase.NewWeatherUsecase(
+		repository.NewWeatherRepository(db),
+		repository.NewLocationRepository(db),
+		cache,
+		weather.NewClient(*cfg),
+	)
+	result, err := weatherUc.BackfillWeatherUsecase(context.Background(), param)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("backfilled %d days of location %d", result.Days, param.LocationID)
+	return nil
+}
+
+// runBackfillConditions maps the condition code of the weathers synced
+// before migration 0011 from their provider text.
+func runBackfillConditions() error {
+	cfg := config.Load()
+	db, err := infra.InitDatabase(cfg.MySQLDSN)
+	if err != nil {
+		return fmt.Errorf("failed to connect MySQL: %w", err)
+	}
+	defer db.Close()
+
+	cache := infra.InitCache(cfg.RedisAddr, cfg.RedisPassword)
+	defer cache.Close()
+
+	weatherUc := usecase.NewWeatherUsecase(
+		repository.NewWeatherRepository(db),
+		repository.NewLocationRepository(db),
+		cache,
+		weather.NewClient(*cfg),
+	)
+	updated, err := weatherUc.BackfillConditionCodesUsecase(context.Background())
+	if err != nil {
+		return err
+	}
+
+	log.Printf("backfilled the condition code of %d weathers", updated)
+	return nil
+}
+
+// parseDate reads a date of the location, labelled UTC like forecast times.
+func parseDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(utils.DateFormat, value)
+}
@@ -1,18 +1,29 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 	"tyarus/weather-app/internal/config"
+	"tyarus/weather-app/internal/dto"
 	"tyarus/weather-app/internal/handler"
 	"tyarus/weather-app/internal/infra"
 	"tyarus/weather-app/internal/repository"
 	"tyarus/weather-app/internal/usecase"
+	"tyarus/weather-app/pkg/units"
 	"tyarus/weather-app/pkg/weather"
+	"tyarus/weather-app/pkg/weather/derive"
 
 	"github.com/gorilla/mux"
 )
 
+const serverShutdownTimeout = 10 * time.Second
+
 func main() {
 	cfg := config.Load()
 
@@ -27,15 +38,48 @@ func main() {
 
 	weatherAPIClient := weather.NewClient(*cfg)
 
+	unitPrefs, err := units.ParsePreferences(cfg.DefaultUnits, cfg.APIKeyUnits)
+	if err != nil {
+		log.Fatalf("failed to load unit preferences: %v", err)
+	}
+
+	alertRules, err := derive.ParseRules(cfg.WeatherAlertRules)
+	if err != nil {
+		log.Fatalf("failed to load weather alert rules: %v", err)
+	}
+
+	publicBaseURL, err := dto.ParsePublicBaseURL(cfg.PublicBaseURL)
+	if err != nil {
+		log.Fatalf("failed to load public base URL: %v", err)
+	}
+
 	locationRepo := repository.NewLocationRepository(db)
 	weatherRepo := repository.NewWeatherRepository(db)
+	locationGroupRepo := repository.NewLocationGroupRepository(db)
 
-	locationUc := usecase.NewLocationUsecase(locationRepo)
+	locationUc := usecase.NewLocationUsecase(locationRepo, cache, weatherAPIClient, cfg.LocationDuplicateDistance)
 	weatherUc := usecase.NewWeatherUsecase(weatherRepo, locationRepo, cache, weatherAPIClient)
+	locationGroupUc := usecase.NewLocationGroupUsecase(locationGroupRepo, locationRepo, weatherUc)
+	exportUc := usecase.NewWeatherExportUsecase(weatherRepo)
+	retentionUc := usecase.NewWeatherRetentionUsecase(weatherRepo, locationRepo, dto.WeatherRetentionPolicy{
+		HourlyDays:       cfg.RetentionHourlyDays,
+		PurgeDeletedDays: cfg.RetentionPurgeDeletedDays,
+		BatchSize:        cfg.RetentionBatchSize,
+	})
 
 	commonHandler := handler.NewCommonHandler(db, cache)
 	locationHandler := handler.NewLocationHandler(locationUc)
-	weatherHandler := handler.NewWeatherHandler(weatherUc)
+	weatherHandler := handler.NewWeatherHandler(weatherUc, unitPrefs, alertRules, publicBaseURL)
+	locationGroupHandler := handler.NewLocationGroupHandler(locationGroupUc)
+	weatherRetentionHandler := handler.NewWeatherRetentionHandler(retentionUc)
+	weatherExportHandler := handler.NewWeatherExportHandler(exportUc, publicBaseURL)
+	conditionHandler := handler.NewConditionHandler()
+	weatherSubscriptionHandler := handler.NewWeatherSubscriptionHandler(weatherUc, cache, unitPrefs, cfg.WebsocketMaxSubscriptions, cfg.WebsocketAllowedOrigins, publicBaseURL)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go weatherSubscriptionHandler.Run(ctx)
 
 	routes := mux.NewRouter()
 	routes.HandleFunc("/health", commonHandler.HealthCheck()).Methods(http.MethodGet)
@@ -45,12 +89,62 @@ func main() {
 
 	apiRoutes.HandleFunc("/locations", locationHandler.GetLocationHandler()).Methods(http.MethodGet)
 	apiRoutes.HandleFunc("/locations", locationHandler.CreateLocationHandler()).Methods(http.MethodPost)
+	apiRoutes.HandleFunc("/locations/import", locationHandler.ImportLocationsHandler()).Methods(http.MethodPost)
+	apiRoutes.HandleFunc("/locations/export", locationHandler.ExportLocationsHandler()).Methods(http.MethodGet)
+	apiRoutes.HandleFunc("/locations/suggest", locationHandler.SuggestLocationsHandler()).Methods(http.MethodGet)
+	apiRoutes.HandleFunc("/locations/nearby", locationHandler.GetNearbyLocationsHandler()).Methods(http.MethodGet)
+	apiRoutes.HandleFunc("/locations/{id}/tags", locationHandler.SetLocationTagsHandler()).Methods(http.MethodPut)
+	apiRoutes.HandleFunc("/locations/{id}/names", locationHandler.SetLocationNamesHandler()).Methods(http.MethodPut)
+	apiRoutes.HandleFunc("/locations/{id}/daily", weatherHandler.GetDailyWeatherHandler()).Methods(http.MethodGet)
+	apiRoutes.HandleFunc("/locations/{id}/stats", weatherHandler.GetWeatherStatsHandler()).Methods(http.MethodGet)
+	apiRoutes.HandleFunc("/locations/{id}/forecast.ics", weatherHandler.GetForecastCalendarHandler()).Methods(http.MethodGet)
+	apiRoutes.HandleFunc("/locations/{id}/forecast.atom", weatherHandler.GetForecastFeedHandler()).Methods(http.MethodGet)
+	apiRoutes.HandleFunc("/locations/{id}/chart.svg", weatherHandler.GetWeatherChartHandler()).Methods(http.MethodGet)
+	apiRoutes.HandleFunc("/location-groups", locationGroupHandler.GetLocationGroupsHandler()).Methods(http.MethodGet)
+	apiRoutes.HandleFunc("/location-groups", locationGroupHandler.CreateLocationGroupHandler()).Methods(http.MethodPost)
+	apiRoutes.HandleFunc("/location-groups/{id}", locationGroupHandler.GetLocationGroupHandler()).Methods(http.MethodGet)
+	apiRoutes.HandleFunc("/location-groups/{id}", locationGroupHandler.UpdateLocationGroupHandler()).Methods(http.MethodPut)
+	apiRoutes.HandleFunc("/location-groups/{id}", locationGroupHandler.DeleteLocationGroupHandler()).Methods(http.MethodDelete)
+	apiRoutes.HandleFunc("/location-groups/{id}/locations", locationGroupHandler.AddLocationGroupMembersHandler()).Methods(http.MethodPost)
+	apiRoutes.HandleFunc("/location-groups/{id}/locations", locationGroupHandler.RemoveLocationGroupMembersHandler()).Methods(http.MethodDelete)
+	apiRoutes.HandleFunc("/location-groups/{id}/sync", locationGroupHandler.SyncLocationGroupWeatherHandler()).Methods(http.MethodPost)
 	apiRoutes.HandleFunc("/weathers/sync", weatherHandler.SyncWeatherHandler()).Methods(http.MethodPost)
 	apiRoutes.HandleFunc("/weathers", weatherHandler.GetWeathersHandler()).Methods(http.MethodGet)
+	apiRoutes.HandleFunc("/weathers/batch", weatherHandler.GetWeathersBatchHandler()).Methods(http.MethodGet)
+	apiRoutes.HandleFunc("/weathers/batch", weatherHandler.PostWeathersBatchHandler()).Methods(http.MethodPost)
+	apiRoutes.HandleFunc("/weathers/point", weatherHandler.GetPointWeatherHandler()).Methods(http.MethodGet)
+	apiRoutes.HandleFunc("/weathers/compare", weatherHandler.CompareWeathersHandler()).Methods(http.MethodGet)
+	apiRoutes.HandleFunc("/map/current.geojson", weatherHandler.GetMapLayerHandler()).Methods(http.MethodGet)
+	apiRoutes.HandleFunc("/conditions", conditionHandler.GetConditionsHandler()).Methods(http.MethodGet)
+	apiRoutes.HandleFunc("/conditions/{code}.svg", conditionHandler.GetConditionIconHandler()).Methods(http.MethodGet)
+	apiRoutes.HandleFunc("/icons/{code}.png", weatherHandler.GetIconHandler()).Methods(http.MethodGet)
+	apiRoutes.HandleFunc("/weathers/subscribe", weatherSubscriptionHandler.SubscribeWeatherHandler()).Methods(http.MethodGet)
+
+	// exports read every stored row, only the keys of EXPORT_API_KEYS may
+	exportRoutes := apiRoutes.PathPrefix("/weathers/export").Subrouter()
+	exportRoutes.Use(handler.RequireAPIKey(cfg.ExportAPIKeys, "EXPORT_API_KEYS"))
+	exportRoutes.HandleFunc("", weatherExportHandler.ExportWeathersHandler()).Methods(http.MethodGet)
+
+	adminRoutes := apiRoutes.PathPrefix("/admin").Subrouter()
+	adminRoutes.Use(handler.RequireAdminKey(cfg.AdminAPIKey))
+	adminRoutes.HandleFunc("/locations/{id}/merge", locationHandler.MergeLocationHandler()).Methods(http.MethodPost)
+	adminRoutes.HandleFunc("/weathers/retention", weatherRetentionHandler.StartWeatherRetentionHandler()).Methods(http.MethodPost)
+	adminRoutes.HandleFunc("/weathers/retention", weatherRetentionHandler.GetWeatherRetentionJobHandler()).Methods(http.MethodGet)
+
+	server := &http.Server{Addr: ":" + cfg.Port, Handler: routes}
+	go func() {
+		<-ctx.Done()
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serverShutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("failed to shut down server: %v", err)
+		}
+	}()
 
-	addr := ":" + cfg.Port
-	log.Println("Server running on", addr)
-	if err := http.ListenAndServe(addr, routes); err != nil {
+	log.Println("Server running on", server.Addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		log.Fatal(err)
 	}
+	log.Println("Server stopped")
 }
@@ -13,18 +13,32 @@ import (
 	"tyarus/weather-app/pkg/weather"
 )
 
-func runWorker(weatherUsecase usecase.WeatherUsecaseInterface, config config.Config) {
+func runWorker(weatherUsecase usecase.WeatherUsecaseInterface, retentionUsecase usecase.WeatherRetentionUsecaseInterface, config config.Config) {
 	log.Printf("start weather sync worker with period: %v\n", config.WorkerPeriod)
 
 	ticker := time.NewTicker(time.Duration(config.WorkerPeriod))
 	defer ticker.Stop()
 
+	// retention is disabled when no policy is configured
+	var retention <-chan time.Time
+	if config.RetentionHourlyDays > 0 || config.RetentionPurgeDeletedDays > 0 {
+		log.Printf("start weather retention with period: %v\n", config.RetentionPeriod)
+
+		retentionTicker := time.NewTicker(time.Duration(config.RetentionPeriod))
+		defer retentionTicker.Stop()
+		retention = retentionTicker.C
+
+		applyRetention(retentionUsecase, config)
+	}
+
 	syncWeather(weatherUsecase, config)
 
 	for {
 		select {
 		case <-ticker.C:
 			syncWeather(weatherUsecase, config)
+		case <-retention:
+			applyRetention(retentionUsecase, config)
 		case <-context.Background().Done():
 			log.Println("worker stopped")
 			return
@@ -48,6 +62,24 @@ func syncWeather(weatherUsecase usecase.WeatherUsecaseInterface, config config.C
 	}
 }
 
+func applyRetention(retentionUsecase usecase.WeatherRetentionUsecaseInterface, config config.Config) {
+	log.Println("start weather retention")
+
+	ctx := context.Background()
+	req := dto.ApplyWeatherRetentionRequest{
+		DryRun: config.RetentionDryRun,
+	}
+
+	report, err := retentionUsecase.ApplyWeatherRetentionUsecase(ctx, req)
+	if err != nil {
+		log.Printf("failed to apply weather retention: %v", err)
+		return
+	}
+
+	log.Printf("weather retention completed, dry run: %t, downsampled days: %d, deleted hourly rows: %d, purged rows: %d, batches: %d",
+		report.DryRun, report.DownsampledDays, report.DeletedHourlyRows, report.PurgedRows, report.Batches)
+}
+
 func main() {
 	cfg := config.Load()
 
@@ -66,6 +98,15 @@ func main() {
 	weatherRepo := repository.NewWeatherRepository(db)
 
 	weatherUsecase := usecase.NewWeatherUsecase(weatherRepo, locationRepo, cache, weatherAPIClient)
+	retentionUsecase := usecase.NewWeatherRetentionUsecase(weatherRepo, locationRepo, retentionPolicy(*cfg))
+
+	runWorker(weatherUsecase, retentionUsecase, *cfg)
+}
 
-	runWorker(weatherUsecase, *cfg)
+func retentionPolicy(config config.Config) dto.WeatherRetentionPolicy {
+	return dto.WeatherRetentionPolicy{
+		HourlyDays:       config.RetentionHourlyDays,
+		PurgeDeletedDays: config.RetentionPurgeDeletedDays,
+		BatchSize:        config.RetentionBatchSize,
+	}
 }
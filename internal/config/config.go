@@ -3,35 +3,64 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 )
 
 type Config struct {
-	Port              string
-	MySQLDSN          string
-	RedisAddr         string
-	RedisPassword     string
-	WeatherAPIBaseURL string
-	WeatherAPIKey     string
-	BackoffMaxRetries int
-	BackoffBaseDelay  int
-	BackoffMaxDelay   int
-	WorkerPeriod      int
-	WorkerLimit       int
+	Port                      string
+	MySQLDSN                  string
+	RedisAddr                 string
+	RedisPassword             string
+	WeatherAPIBaseURL         string
+	WeatherAPIKey             string
+	BackoffMaxRetries         int
+	BackoffBaseDelay          int
+	BackoffMaxDelay           int
+	WorkerPeriod              int
+	WorkerLimit               int
+	WebsocketMaxSubscriptions int
+	WebsocketAllowedOrigins   []string
+	LocationDuplicateDistance int
+	AdminAPIKey               string
+	DefaultUnits              string
+	APIKeyUnits               string
+	RetentionPeriod           int
+	RetentionHourlyDays       int
+	RetentionPurgeDeletedDays int
+	RetentionBatchSize        int
+	RetentionDryRun           bool
+	WeatherAlertRules         string
+	PublicBaseURL             string
+	ExportAPIKeys             []string
 }
 
 func Load() *Config {
 	return &Config{
-		Port:              getEnv("PORT", "8080"),
-		MySQLDSN:          getEnv("MYSQL_DSN", "admin:admin@tcp(localhost:3306)/weather-db?charset=utf8mb4&parseTime=true&loc=Local"),
-		RedisAddr:         getEnv("REDIS_ADDR", "localhost:6379"),
-		RedisPassword:     getEnv("REDIS_PASSWORD", ""),
-		WeatherAPIBaseURL: getEnv("WEATHER_API_BASE_URL", "https://api.weatherapi.com/v1"),
-		WeatherAPIKey:     getEnv("WEATHER_API_KEY", ""),
-		BackoffMaxRetries: getEnvInt("BACKOFF_MAX_RETRIES", "3"),
-		BackoffBaseDelay:  getEnvInt("BACKOFF_BASE_DELAY", "200000000"),
-		BackoffMaxDelay:   getEnvInt("BACKOFF_MAX_DELAY", "5000000000"),
-		WorkerPeriod:      getEnvInt("WORKER_PERIOD", "900000000000"),
-		WorkerLimit:       getEnvInt("WORKER_LIMIT", "10"),
+		Port:                      getEnv("PORT", "8080"),
+		MySQLDSN:                  getEnv("MYSQL_DSN", "admin:admin@tcp(localhost:3306)/weather-db?charset=utf8mb4&parseTime=true&loc=Local"),
+		RedisAddr:                 getEnv("REDIS_ADDR", "localhost:6379"),
+		RedisPassword:             getEnv("REDIS_PASSWORD", ""),
+		WeatherAPIBaseURL:         getEnv("WEATHER_API_BASE_URL", "https://api.weatherapi.com/v1"),
+		WeatherAPIKey:             getEnv("WEATHER_API_KEY", ""),
+		BackoffMaxRetries:         getEnvInt("BACKOFF_MAX_RETRIES", "3"),
+		BackoffBaseDelay:          getEnvInt("BACKOFF_BASE_DELAY", "200000000"),
+		BackoffMaxDelay:           getEnvInt("BACKOFF_MAX_DELAY", "5000000000"),
+		WorkerPeriod:              getEnvInt("WORKER_PERIOD", "900000000000"),
+		WorkerLimit:               getEnvInt("WORKER_LIMIT", "10"),
+		WebsocketMaxSubscriptions: getEnvInt("WEBSOCKET_MAX_SUBSCRIPTIONS", "20"),
+		WebsocketAllowedOrigins:   getEnvList("WEBSOCKET_ALLOWED_ORIGINS", ""),
+		LocationDuplicateDistance: getEnvInt("LOCATION_DUPLICATE_DISTANCE", "1000"),
+		AdminAPIKey:               getEnv("ADMIN_API_KEY", ""),
+		DefaultUnits:              getEnv("DEFAULT_UNITS", "metric"),
+		APIKeyUnits:               getEnv("API_KEY_UNITS", ""),
+		RetentionPeriod:           getEnvInt("RETENTION_PERIOD", "86400000000000"),
+		RetentionHourlyDays:       getEnvInt("RETENTION_HOURLY_DAYS", "0"),
+		RetentionPurgeDeletedDays: getEnvInt("RETENTION_PURGE_DELETED_DAYS", "0"),
+		RetentionBatchSize:        getEnvInt("RETENTION_BATCH_SIZE", "1000"),
+		RetentionDryRun:           getEnvBool("RETENTION_DRY_RUN", "false"),
+		WeatherAlertRules:         getEnv("WEATHER_ALERT_RULES", ""),
+		PublicBaseURL:             getEnv("PUBLIC_BASE_URL", ""),
+		ExportAPIKeys:             getEnvList("EXPORT_API_KEYS", ""),
 	}
 }
 
@@ -51,3 +80,28 @@ func getEnvInt(key, fallback string) int {
 	resultInt, _ := strconv.Atoi(result)
 	return resultInt
 }
+
+func getEnvBool(key, fallback string) bool {
+	result := fallback
+	if val, ok := os.LookupEnv(key); ok {
+		result = val
+	}
+
+	resultBool, _ := strconv.ParseBool(result)
+	return resultBool
+}
+
+func getEnvList(key, fallback string) []string {
+	result := fallback
+	if val, ok := os.LookupEnv(key); ok {
+		result = val
+	}
+
+	var items []string
+	for _, item := range strings.Split(result, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
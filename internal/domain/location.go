@@ -2,17 +2,30 @@ package domain
 
 import (
 	"database/sql"
+	"strings"
 	"time"
 )
 
 type Location struct {
-	ID             int64        `json:"id"`
-	Name           string       `json:"name"`
-	Region         string       `json:"region"`
-	Country        string       `json:"country"`
-	Latitude       float64      `json:"latitude"`
-	Longitude      float64      `json:"longitude"`
-	CreatedAt      time.Time    `json:"created_at"`
-	LastModifiedAt sql.NullTime `json:"last_modified_at"`
-	DeletedAt      sql.NullTime `json:"deleted_at"`
+	ID             int64          `json:"id"`
+	ExternalKey    sql.NullString `json:"external_key"`
+	Name           string         `json:"name"`
+	Region         string         `json:"region"`
+	Country        string         `json:"country"`
+	Latitude       float64        `json:"latitude"`
+	Longitude      float64        `json:"longitude"`
+	Timezone       string         `json:"timezone"`
+	CreatedAt      time.Time      `json:"created_at"`
+	LastModifiedAt sql.NullTime   `json:"last_modified_at"`
+	DeletedAt      sql.NullTime   `json:"deleted_at"`
+}
+
+// LocationNameKey identifies a location by its name and country, case and
+// whitespace insensitive. Active locations have unique keys.
+func LocationNameKey(name, country string) string {
+	return normalizeText(name) + "|" + normalizeText(country)
+}
+
+func normalizeText(value string) string {
+	return strings.ToLower(strings.Join(strings.Fields(value), " "))
 }
@@ -0,0 +1,16 @@
+package domain
+
+import (
+	"database/sql"
+	"time"
+)
+
+type LocationGroup struct {
+	ID             int64        `json:"id"`
+	Name           string       `json:"name"`
+	Description    string       `json:"description"`
+	LocationCount  int          `json:"location_count"`
+	CreatedAt      time.Time    `json:"created_at"`
+	LastModifiedAt sql.NullTime `json:"last_modified_at"`
+	DeletedAt      sql.NullTime `json:"deleted_at"`
+}
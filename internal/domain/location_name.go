@@ -0,0 +1,10 @@
+package domain
+
+// LocationName is an alternate name of a location. Names with a language are
+// the localized name in that language, names without one are aliases such as
+// former or colloquial names.
+type LocationName struct {
+	LocationID int64  `json:"location_id"`
+	Name       string `json:"name"`
+	Language   string `json:"language"`
+}
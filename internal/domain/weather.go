@@ -13,17 +13,74 @@ const (
 )
 
 type Weather struct {
-	ID                    int64        `json:"id"`
-	LocationID            int64        `json:"location_id"`
-	TemperatureCelcius    float64      `json:"temperature_celcius"`
-	TemperatureFahrenheit float64      `json:"temperature_fahrenheit"`
-	Humidity              int          `json:"humidity"`
-	WindSpeed             float64      `json:"wind_speed"`
-	ConditionStatus       string       `json:"condition_status"`
-	ConditionIconURL      string       `json:"condition_icon_url"`
-	ForecastTime          time.Time    `json:"forecast_time"`
-	ForecastType          ForecastType `json:"forecast_type"`
-	CreatedAt             time.Time    `json:"created_at"`
-	LastModifiedAt        sql.NullTime `json:"last_modified_at"`
-	DeletedAt             sql.NullTime `json:"deleted_at"`
+	ID                    int64           `json:"id"`
+	LocationID            int64           `json:"location_id"`
+	TemperatureCelcius    float64         `json:"temperature_celcius"`
+	TemperatureFahrenheit float64         `json:"temperature_fahrenheit"`
+	MinTemperatureCelcius sql.NullFloat64 `json:"min_temperature_celcius"`
+	MaxTemperatureCelcius sql.NullFloat64 `json:"max_temperature_celcius"`
+	Humidity              int             `json:"humidity"`
+	WindSpeed             float64         `json:"wind_speed"`
+	PrecipitationMM       sql.NullFloat64 `json:"precipitation_mm"`
+	PressureMB            sql.NullFloat64 `json:"pressure_mb"`
+	VisibilityKM          sql.NullFloat64 `json:"visibility_km"`
+	ConditionStatus       string          `json:"condition_status"`
+	ConditionCode         string          `json:"condition_code"`
+	ConditionIconURL      string          `json:"condition_icon_url"`
+	ForecastTime          time.Time       `json:"forecast_time"`
+	ForecastType          ForecastType    `json:"forecast_type"`
+	CreatedAt             time.Time       `json:"created_at"`
+	LastModifiedAt        sql.NullTime    `json:"last_modified_at"`
+	DeletedAt             sql.NullTime    `json:"deleted_at"`
+}
+
+// DailyWeather aggregates the hourly rows of one day, next to the provider's
+// summary row of that day when there is one.
+type DailyWeather struct {
+	Date                   time.Time       `json:"date"`
+	Hours                  int             `json:"hours"`
+	MinTemperatureCelcius  float64         `json:"min_temperature_celcius"`
+	MaxTemperatureCelcius  float64         `json:"max_temperature_celcius"`
+	AvgTemperatureCelcius  float64         `json:"avg_temperature_celcius"`
+	AvgHumidity            float64         `json:"avg_humidity"`
+	MaxWindSpeed           float64         `json:"max_wind_speed"`
+	TotalPrecipitationMM   sql.NullFloat64 `json:"total_precipitation_mm"`
+	DominantCondition      string          `json:"dominant_condition"`
+	SummaryTemperature     sql.NullFloat64 `json:"summary_temperature"`
+	SummaryWindSpeed       sql.NullFloat64 `json:"summary_wind_speed"`
+	SummaryPrecipitationMM sql.NullFloat64 `json:"summary_precipitation_mm"`
+	SummaryCondition       sql.NullString  `json:"summary_condition"`
+	SummaryConditionCode   sql.NullString  `json:"summary_condition_code"`
+}
+
+// WeatherRetentionCounts is what a retention run would change.
+type WeatherRetentionCounts struct {
+	DownsampleDays int64
+	HourlyRows     int64
+	DeletedRows    int64
+}
+
+const (
+	AstronomySourceProvider = "provider"
+	AstronomySourceComputed = "computed"
+)
+
+// Astronomy is the sun and moon of a location on a day. Times are wall clock
+// times of the location like forecast times, null when the event does not
+// happen on the day. Source tells whether the provider sent the day or it
+// was computed from the coordinate.
+type Astronomy struct {
+	ID               int64        `json:"id"`
+	LocationID       int64        `json:"location_id"`
+	Date             time.Time    `json:"date"`
+	Sunrise          sql.NullTime `json:"sunrise"`
+	Sunset           sql.NullTime `json:"sunset"`
+	Moonrise         sql.NullTime `json:"moonrise"`
+	Moonset          sql.NullTime `json:"moonset"`
+	DaylightMinutes  int          `json:"daylight_minutes"`
+	MoonPhase        string       `json:"moon_phase"`
+	MoonIllumination int          `json:"moon_illumination"`
+	Source           string       `json:"source"`
+	CreatedAt        time.Time    `json:"created_at"`
+	LastModifiedAt   sql.NullTime `json:"last_modified_at"`
 }
@@ -0,0 +1,27 @@
+package dto
+
+import (
+	"time"
+	"tyarus/weather-app/pkg/units"
+	"tyarus/weather-app/pkg/weather/derive"
+)
+
+type GetForecastFeedParam struct {
+	LocationID int64
+	Units      units.System
+	// Languages are the preferred languages of the condition texts
+	Languages []string
+	// SelfURL is the absolute URL the feed was requested from
+	SelfURL string
+	// BaseURL makes the condition icon URLs absolute
+	BaseURL string
+	// AlertRules are listed on the feed entries whose day matches them
+	AlertRules []derive.Rule
+}
+
+// ForecastFeedDocument is a rendered calendar or feed. LastModified is the
+// latest change of the forecast rows in it, zero when there are none.
+type ForecastFeedDocument struct {
+	Body         []byte
+	LastModified time.Time
+}
@@ -2,8 +2,11 @@ package dto
 
 import (
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 	"tyarus/weather-app/internal/domain"
+	"tyarus/weather-app/pkg/geo"
 	"tyarus/weather-app/pkg/response"
 	"tyarus/weather-app/pkg/utils"
 )
@@ -11,15 +14,22 @@ import (
 type HealthResponse response.Response[any]
 
 type GetLocationHandlerResponseItem struct {
-	ID             int64     `json:"id"`
-	Name           string    `json:"name"`
-	Region         string    `json:"region"`
-	Country        string    `json:"country"`
-	Latitude       float64   `json:"latitude"`
-	Longitude      float64   `json:"longitude"`
-	CreatedAt      time.Time `json:"createdAt"`
-	LastModifiedAt time.Time `json:"lastModifiedAt"`
-	DeletedAt      time.Time `json:"deletedAt"`
+	ID          int64    `json:"id"`
+	ExternalKey string   `json:"externalKey,omitempty"`
+	Name        string   `json:"name"`
+	Region      string   `json:"region"`
+	Country     string   `json:"country"`
+	Latitude    float64  `json:"latitude"`
+	Longitude   float64  `json:"longitude"`
+	Timezone    string   `json:"timezone"`
+	Tags        []string `json:"tags,omitempty"`
+	// LocalizedName is the name in the requested language, falling back to
+	// Name. It is only set when a language was requested.
+	LocalizedName  string             `json:"localizedName,omitempty"`
+	AlternateNames []LocationNameItem `json:"alternateNames,omitempty"`
+	CreatedAt      time.Time          `json:"createdAt"`
+	LastModifiedAt time.Time          `json:"lastModifiedAt"`
+	DeletedAt      time.Time          `json:"deletedAt"`
 }
 
 func ParseToGetLocationHandlerResponses(items []domain.Location) []GetLocationHandlerResponseItem {
@@ -27,11 +37,13 @@ func ParseToGetLocationHandlerResponses(items []domain.Location) []GetLocationHa
 	for _, v := range items {
 		results = append(results, GetLocationHandlerResponseItem{
 			ID:             v.ID,
+			ExternalKey:    v.ExternalKey.String,
 			Name:           v.Name,
 			Region:         v.Region,
 			Country:        v.Country,
 			Latitude:       v.Latitude,
 			Longitude:      v.Longitude,
+			Timezone:       v.Timezone,
 			CreatedAt:      v.CreatedAt,
 			LastModifiedAt: v.LastModifiedAt.Time,
 			DeletedAt:      v.DeletedAt.Time,
@@ -44,11 +56,13 @@ func ParseToGetLocationHandlerResponses(items []domain.Location) []GetLocationHa
 func ParseToGetLocationHandlerResponse(item domain.Location) GetLocationHandlerResponseItem {
 	result := GetLocationHandlerResponseItem{
 		ID:             item.ID,
+		ExternalKey:    item.ExternalKey.String,
 		Name:           item.Name,
 		Region:         item.Region,
 		Country:        item.Country,
 		Latitude:       item.Latitude,
 		Longitude:      item.Longitude,
+		Timezone:       item.Timezone,
 		CreatedAt:      item.CreatedAt,
 		LastModifiedAt: item.LastModifiedAt.Time,
 		DeletedAt:      item.DeletedAt.Time,
@@ -58,10 +72,21 @@ func ParseToGetLocationHandlerResponse(item domain.Location) GetLocationHandlerR
 }
 
 type GetLocationHandlerParam struct {
-	Query       string
-	PageSize    int
-	CurrentPage int
-	SortBy      string
+	Query          string
+	NamePrefix     string
+	Country        string
+	Region         string
+	BoundingBox    *geo.BoundingBox
+	CreatedFrom    time.Time
+	CreatedTo      time.Time
+	IncludeDeleted bool
+	GroupID        int64
+	Tag            string
+	Languages      []string
+	Cursor         string
+	PageSize       int
+	CurrentPage    int
+	SortBy         string
 }
 
 func (p *GetLocationHandlerParam) Validate() error {
@@ -69,18 +94,53 @@ func (p *GetLocationHandlerParam) Validate() error {
 		return errors.New("invalid sort_by parameter, only allow created_at_ascend, created_at_descend, name_ascend, name_descend")
 	}
 
+	if p.GroupID < 0 {
+		return errors.New("invalid group parameter, please check your parameter")
+	}
+
+	if err := validateBoundingBox(p.BoundingBox); err != nil {
+		return err
+	}
+
+	if !p.CreatedFrom.IsZero() && !p.CreatedTo.IsZero() && !p.CreatedFrom.Before(p.CreatedTo) {
+		return errors.New("invalid createdFrom parameter, must be before createdTo")
+	}
+
+	return nil
+}
+
+func validateBoundingBox(box *geo.BoundingBox) error {
+	if box == nil {
+		return nil
+	}
+
+	if box.MinLatitude < -90 || box.MaxLatitude > 90 || box.MinLatitude > box.MaxLatitude {
+		return errors.New("invalid bbox parameter, latitude must be between -90 and 90 with min not above max")
+	}
+
+	if box.MinLongitude < -180 || box.MaxLongitude > 180 {
+		return errors.New("invalid bbox parameter, longitude must be between -180 and 180")
+	}
+
 	return nil
 }
 
 type PostLocationHandlerRequest struct {
+	Query     string  `json:"query"`
 	Name      string  `json:"name"`
 	Region    string  `json:"region"`
 	Country   string  `json:"country"`
 	Latitude  float64 `json:"lat"`
 	Longitude float64 `json:"lon"`
+	Timezone  string  `json:"timezone"`
 }
 
 func (r *PostLocationHandlerRequest) Validate() error {
+	// the provider resolves every other field from the query
+	if strings.TrimSpace(r.Query) != "" {
+		return nil
+	}
+
 	if r.Name == "" {
 		return errors.New("invalid name parameter, please check your parameter")
 	}
@@ -93,6 +153,18 @@ func (r *PostLocationHandlerRequest) Validate() error {
 		return errors.New("invalid country parameter, please check your parameter")
 	}
 
+	if r.Latitude == 0 && r.Longitude == 0 {
+		return errors.New("lat and lon parameter is empty, please check your parameter")
+	}
+
+	if r.Latitude < -90 || r.Latitude > 90 {
+		return errors.New("invalid lat parameter, only allow -90 until 90")
+	}
+
+	if r.Longitude < -180 || r.Longitude > 180 {
+		return errors.New("invalid lon parameter, only allow -180 until 180")
+	}
+
 	return nil
 }
 
@@ -103,5 +175,104 @@ func (p *PostLocationHandlerRequest) PostLocationHandlerRequestToDomain() domain
 		Country:   p.Country,
 		Latitude:  p.Latitude,
 		Longitude: p.Longitude,
+		Timezone:  p.Timezone,
 	}
 }
+
+type PostMergeLocationRequest struct {
+	CanonicalID int64 `json:"-"`
+	DuplicateID int64 `json:"duplicateID"`
+}
+
+func (r *PostMergeLocationRequest) Validate() error {
+	if r.DuplicateID <= 0 {
+		return errors.New("invalid duplicateID parameter, please check your parameter")
+	}
+
+	if r.DuplicateID == r.CanonicalID {
+		return errors.New("duplicateID must be different from the location id, please check your parameter")
+	}
+
+	return nil
+}
+
+type MergeLocationResponse struct {
+	Location          GetLocationHandlerResponseItem `json:"location"`
+	MergedLocationID  int64                          `json:"mergedLocationID"`
+	MovedWeathers     int64                          `json:"movedWeathers"`
+	DiscardedWeathers int64                          `json:"discardedWeathers"`
+}
+
+type LocationCandidate struct {
+	Query     string  `json:"query"`
+	Name      string  `json:"name"`
+	Region    string  `json:"region"`
+	Country   string  `json:"country"`
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+}
+
+type GetNearbyLocationsParam struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+	Limit     int
+	Languages []string
+}
+
+func (p *GetNearbyLocationsParam) Validate() error {
+	if p.Latitude < -90 || p.Latitude > 90 {
+		return errors.New("invalid lat parameter, only allow -90 until 90")
+	}
+
+	if p.Longitude < -180 || p.Longitude > 180 {
+		return errors.New("invalid lon parameter, only allow -180 until 180")
+	}
+
+	if p.RadiusKm < 0 || p.RadiusKm > utils.MaxNearbyRadiusKm {
+		return fmt.Errorf("invalid radiusKm parameter, only allow 0 until %.0f", utils.MaxNearbyRadiusKm)
+	}
+
+	if p.Limit < 0 || p.Limit > utils.MaxNearbyLimit {
+		return fmt.Errorf("invalid limit parameter, only allow 0 until %d", utils.MaxNearbyLimit)
+	}
+
+	return nil
+}
+
+type GetNearbyLocationResponseItem struct {
+	Location   GetLocationHandlerResponseItem `json:"location"`
+	DistanceKm float64                        `json:"distanceKm"`
+}
+
+type SuggestLocationsParam struct {
+	Query string
+	Limit int
+}
+
+func (p *SuggestLocationsParam) Validate() error {
+	if strings.TrimSpace(p.Query) == "" {
+		return errors.New("q parameter is empty, please check your parameter")
+	}
+
+	if len(p.Query) > 100 {
+		return errors.New("invalid q parameter, maximum 100 characters")
+	}
+
+	if p.Limit < 0 || p.Limit > utils.MaxSuggestLimit {
+		return fmt.Errorf("invalid limit parameter, only allow 0 until %d", utils.MaxSuggestLimit)
+	}
+
+	return nil
+}
+
+type LocationSuggestion struct {
+	ID           int64   `json:"id"`
+	Name         string  `json:"name"`
+	Region       string  `json:"region"`
+	Country      string  `json:"country"`
+	Latitude     float64 `json:"latitude"`
+	Longitude    float64 `json:"longitude"`
+	MatchedField string  `json:"matchedField"`
+	Score        float64 `json:"score"`
+}
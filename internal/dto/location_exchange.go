@@ -0,0 +1,389 @@
+package dto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"tyarus/weather-app/internal/domain"
+	"tyarus/weather-app/pkg/geojson"
+)
+
+const (
+	LocationFormatCSV     = "csv"
+	LocationFormatJSON    = "json"
+	LocationFormatGeoJSON = "geojson"
+)
+
+var LocationFormatMaps = map[string]bool{
+	LocationFormatCSV:     true,
+	LocationFormatJSON:    true,
+	LocationFormatGeoJSON: true,
+}
+
+const (
+	LocationImportStatusValid   = "valid"
+	LocationImportStatusInvalid = "invalid"
+	LocationImportStatusFailed  = "failed"
+)
+
+var locationCSVHeader = []string{"id", "external_key", "name", "region", "country", "lat", "lon", "timezone"}
+
+// LocationExchangeItem is one location in import and export files.
+type LocationExchangeItem struct {
+	ID          int64   `json:"id,omitempty"`
+	ExternalKey string  `json:"externalKey,omitempty"`
+	Name        string  `json:"name"`
+	Region      string  `json:"region"`
+	Country     string  `json:"country"`
+	Latitude    float64 `json:"lat"`
+	Longitude   float64 `json:"lon"`
+	Timezone    string  `json:"timezone,omitempty"`
+}
+
+func ParseToLocationExchangeItems(items []domain.Location) []LocationExchangeItem {
+	results := []LocationExchangeItem{}
+	for _, v := range items {
+		results = append(results, LocationExchangeItem{
+			ID:          v.ID,
+			ExternalKey: v.ExternalKey.String,
+			Name:        v.Name,
+			Region:      v.Region,
+			Country:     v.Country,
+			Latitude:    v.Latitude,
+			Longitude:   v.Longitude,
+			Timezone:    v.Timezone,
+		})
+	}
+
+	return results
+}
+
+func (i *LocationExchangeItem) Validate() error {
+	if len(i.ExternalKey) > 100 {
+		return errors.New("invalid externalKey parameter, maximum 100 characters")
+	}
+
+	req := PostLocationHandlerRequest{
+		Name:      i.Name,
+		Region:    i.Region,
+		Country:   i.Country,
+		Latitude:  i.Latitude,
+		Longitude: i.Longitude,
+	}
+	return req.Validate()
+}
+
+func (i *LocationExchangeItem) LocationExchangeItemToDomain() domain.Location {
+	location := domain.Location{
+		Name:      strings.TrimSpace(i.Name),
+		Region:    strings.TrimSpace(i.Region),
+		Country:   strings.TrimSpace(i.Country),
+		Latitude:  i.Latitude,
+		Longitude: i.Longitude,
+		Timezone:  strings.TrimSpace(i.Timezone),
+	}
+	if key := strings.TrimSpace(i.ExternalKey); key != "" {
+		location.ExternalKey.String, location.ExternalKey.Valid = key, true
+	}
+
+	return location
+}
+
+type LocationExportParam struct {
+	Offset int
+}
+
+// LocationExportPage is a page of the export, NextOffset is 0 on the last
+// page.
+type LocationExportPage struct {
+	Items      []LocationExchangeItem
+	NextOffset int
+}
+
+// LocationImportRow keeps the 1-based position of the item in the file, and
+// the reason when the row itself could not be parsed.
+type LocationImportRow struct {
+	Row        int
+	Item       LocationExchangeItem
+	ParseError error
+}
+
+type LocationImportRequest struct {
+	Rows   []LocationImportRow
+	DryRun bool
+}
+
+type LocationImportRowResult struct {
+	Row         int    `json:"row"`
+	ExternalKey string `json:"externalKey,omitempty"`
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	LocationID  int64  `json:"locationID,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+type LocationImportResponse struct {
+	DryRun    bool                      `json:"dryRun"`
+	Total     int                       `json:"total"`
+	Valid     int                       `json:"valid"`
+	Invalid   int                       `json:"invalid"`
+	Created   int                       `json:"created"`
+	Updated   int                       `json:"updated"`
+	Unchanged int                       `json:"unchanged"`
+	Failed    int                       `json:"failed"`
+	Rows      []LocationImportRowResult `json:"rows"`
+}
+
+// DetectLocationFormat picks the import format from the format parameter,
+// falling back to the request content type.
+func DetectLocationFormat(format, contentType string) (string, error) {
+	if format != "" {
+		if !LocationFormatMaps[format] {
+			return "", errors.New("invalid format parameter, only allow csv, json, geojson")
+		}
+		return format, nil
+	}
+
+	switch {
+	case strings.HasPrefix(contentType, "text/csv"):
+		return LocationFormatCSV, nil
+	case strings.HasPrefix(contentType, "application/geo+json"):
+		return LocationFormatGeoJSON, nil
+	case strings.HasPrefix(contentType, "application/json"), contentType == "":
+		return LocationFormatJSON, nil
+	}
+
+	return "", errors.New("unsupported content type, please set format parameter to csv, json or geojson")
+}
+
+// utf8BOM starts files saved by spreadsheet applications.
+var utf8BOM = []byte("\ufeff")
+
+func ParseLocationImport(format string, r io.Reader) ([]LocationImportRow, error) {
+	reader := bufio.NewReader(r)
+	if prefix, err := reader.Peek(len(utf8BOM)); err == nil && bytes.Equal(prefix, utf8BOM) {
+		_, _ = reader.Discard(len(utf8BOM))
+	}
+	r = reader
+
+	switch format {
+	case LocationFormatCSV:
+		return parseLocationCSV(r)
+	case LocationFormatGeoJSON:
+		return parseLocationGeoJSON(r)
+	default:
+		return parseLocationJSON(r)
+	}
+}
+
+func parseLocationCSV(r io.Reader) ([]LocationImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read csv header: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		switch name {
+		case "latitude":
+			name = "lat"
+		case "longitude":
+			name = "lon"
+		case "externalkey":
+			name = "external_key"
+		}
+		columns[name] = i
+	}
+
+	for _, required := range []string{"name", "country", "lat", "lon"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("csv header must contain %s column", required)
+		}
+	}
+
+	rows := []LocationImportRow{}
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		row := LocationImportRow{Row: line}
+		if err != nil {
+			row.ParseError = err
+			rows = append(rows, row)
+			continue
+		}
+
+		value := func(column string) string {
+			i, ok := columns[column]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+
+		row.Item = LocationExchangeItem{
+			ExternalKey: value("external_key"),
+			Name:        value("name"),
+			Region:      value("region"),
+			Country:     value("country"),
+			Timezone:    value("timezone"),
+		}
+
+		if row.Item.Latitude, err = strconv.ParseFloat(value("lat"), 64); err != nil {
+			row.ParseError = errors.New("invalid lat value")
+		} else if row.Item.Longitude, err = strconv.ParseFloat(value("lon"), 64); err != nil {
+			row.ParseError = errors.New("invalid lon value")
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func parseLocationJSON(r io.Reader) ([]LocationImportRow, error) {
+	var items []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, fmt.Errorf("failed to decode json array: %w", err)
+	}
+
+	rows := []LocationImportRow{}
+	for i, raw := range items {
+		row := LocationImportRow{Row: i + 1}
+		if err := json.Unmarshal(raw, &row.Item); err != nil {
+			row.ParseError = err
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// geoJSONImport keeps the features raw so that one malformed feature fails
+// its own row instead of the whole file.
+type geoJSONImport struct {
+	Type     string            `json:"type"`
+	Features []json.RawMessage `json:"features"`
+}
+
+func parseLocationGeoJSON(r io.Reader) ([]LocationImportRow, error) {
+	var collection geoJSONImport
+	if err := json.NewDecoder(r).Decode(&collection); err != nil {
+		return nil, fmt.Errorf("failed to decode geojson: %w", err)
+	}
+
+	if collection.Type != geojson.TypeFeatureCollection {
+		return nil, errors.New("geojson must be a FeatureCollection")
+	}
+
+	rows := []LocationImportRow{}
+	for i, raw := range collection.Features {
+		row := LocationImportRow{Row: i + 1}
+		feature, err := parseGeoJSONFeature(raw)
+		if err != nil {
+			row.ParseError = err
+			rows = append(rows, row)
+			continue
+		}
+
+		lat, lon, err := feature.Point()
+		if err != nil {
+			row.ParseError = err
+			rows = append(rows, row)
+			continue
+		}
+
+		row.Item = LocationExchangeItem{
+			ExternalKey: feature.StringProperty("externalKey"),
+			Name:        feature.StringProperty("name"),
+			Region:      feature.StringProperty("region"),
+			Country:     feature.StringProperty("country"),
+			Timezone:    feature.StringProperty("timezone"),
+			Latitude:    lat,
+			Longitude:   lon,
+		}
+		if key, ok := feature.ID.(string); ok && row.Item.ExternalKey == "" {
+			row.Item.ExternalKey = key
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// parseGeoJSONFeature decodes one feature. Geometries other than points are
+// rejected before decoding, since their coordinates are not a position.
+func parseGeoJSONFeature(raw json.RawMessage) (geojson.Feature, error) {
+	var header struct {
+		Geometry *struct {
+			Type string `json:"type"`
+		} `json:"geometry"`
+	}
+	if err := json.Unmarshal(raw, &header); err != nil {
+		return geojson.Feature{}, err
+	}
+	if header.Geometry == nil || header.Geometry.Type != geojson.TypePoint {
+		return geojson.Feature{}, errors.New("feature geometry must be a Point")
+	}
+
+	var feature geojson.Feature
+	if err := json.Unmarshal(raw, &feature); err != nil {
+		return geojson.Feature{}, err
+	}
+	return feature, nil
+}
+
+func WriteLocationExport(w io.Writer, format string, items []LocationExchangeItem) error {
+	switch format {
+	case LocationFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(locationCSVHeader); err != nil {
+			return err
+		}
+		for _, item := range items {
+			err := writer.Write([]string{
+				strconv.FormatInt(item.ID, 10),
+				item.ExternalKey,
+				item.Name,
+				item.Region,
+				item.Country,
+				strconv.FormatFloat(item.Latitude, 'f', -1, 64),
+				strconv.FormatFloat(item.Longitude, 'f', -1, 64),
+				item.Timezone,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	case LocationFormatGeoJSON:
+		features := []geojson.Feature{}
+		for _, item := range items {
+			features = append(features, geojson.NewPointFeature(item.ID, item.Latitude, item.Longitude, map[string]interface{}{
+				"externalKey": item.ExternalKey,
+				"name":        item.Name,
+				"region":      item.Region,
+				"country":     item.Country,
+				"timezone":    item.Timezone,
+			}))
+		}
+		return json.NewEncoder(w).Encode(geojson.NewFeatureCollection(features))
+	default:
+		return json.NewEncoder(w).Encode(items)
+	}
+}
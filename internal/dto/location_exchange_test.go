@@ -0,0 +1,137 @@
+package dto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLocationImport(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		input  string
+		rows   []LocationImportRow
+		errs   []string
+		err    string
+	}{
+		{
+			name:   "WHEN csv has quoted fields, THEN should keep commas and quotes inside them",
+			format: LocationFormatCSV,
+			input:  "name,region,country,lat,lon\n\"Jakarta, Central\",\"DKI \"\"Raya\"\"\",Indonesia,-6.2,106.8\n",
+			rows: []LocationImportRow{
+				{Row: 1, Item: LocationExchangeItem{Name: "Jakarta, Central", Region: `DKI "Raya"`, Country: "Indonesia", Latitude: -6.2, Longitude: 106.8}},
+			},
+			errs: []string{""},
+		},
+		{
+			name:   "WHEN csv starts with a BOM and quoted header, THEN should read the header",
+			format: LocationFormatCSV,
+			input:  "\ufeff\"Name\",\"Country\",\"Latitude\",\"Longitude\",\"externalKey\"\nBandung,Indonesia,-6.9,107.6,bdg\n",
+			rows: []LocationImportRow{
+				{Row: 1, Item: LocationExchangeItem{ExternalKey: "bdg", Name: "Bandung", Country: "Indonesia", Latitude: -6.9, Longitude: 107.6}},
+			},
+			errs: []string{""},
+		},
+		{
+			name:   "WHEN csv rows are malformed, THEN should report them and keep reading",
+			format: LocationFormatCSV,
+			input:  "name,country,lat,lon\nJakarta,Indonesia,north,106.8\nBandung,Indonesia,-6.9,\nBo\"gor,Indonesia,-6.6,106.8\nDepok,Indonesia,-6.4,106.8\n",
+			errs:   []string{"invalid lat value", "invalid lon value", "bare \" in non-quoted-field", ""},
+		},
+		{
+			name:   "WHEN csv lacks a required column, THEN should return error",
+			format: LocationFormatCSV,
+			input:  "name,country,lat\nJakarta,Indonesia,-6.2\n",
+			err:    "csv header must contain lon column",
+		},
+		{
+			name:   "WHEN json starts with a BOM, THEN should decode the array",
+			format: LocationFormatJSON,
+			input:  "\ufeff[{\"name\": \"Jakarta\", \"country\": \"Indonesia\", \"lat\": -6.2, \"lon\": 106.8}]",
+			rows: []LocationImportRow{
+				{Row: 1, Item: LocationExchangeItem{Name: "Jakarta", Country: "Indonesia", Latitude: -6.2, Longitude: 106.8}},
+			},
+			errs: []string{""},
+		},
+		{
+			name:   "WHEN json items are malformed, THEN should report them by position",
+			format: LocationFormatJSON,
+			input:  `[{"name": "Jakarta", "lat": "north"}, "Bandung", {"name": "Depok", "lat": -6.4, "lon": 106.8}]`,
+			errs:   []string{"cannot unmarshal string", "cannot unmarshal string", ""},
+		},
+		{
+			name:   "WHEN json is not an array, THEN should return error",
+			format: LocationFormatJSON,
+			input:  `{"name": "Jakarta"}`,
+			err:    "failed to decode json array",
+		},
+		{
+			name:   "WHEN geojson features have points, THEN should use the feature id as external key",
+			format: LocationFormatGeoJSON,
+			input:  `{"type": "FeatureCollection", "features": [{"type": "Feature", "id": "jkt", "geometry": {"type": "Point", "coordinates": [106.8, -6.2]}, "properties": {"name": "Jakarta", "country": "Indonesia"}}]}`,
+			rows: []LocationImportRow{
+				{Row: 1, Item: LocationExchangeItem{ExternalKey: "jkt", Name: "Jakarta", Country: "Indonesia", Latitude: -6.2, Longitude: 106.8}},
+			},
+			errs: []string{""},
+		},
+		{
+			name:   "WHEN geojson feature is not a point, THEN should report the row",
+			format: LocationFormatGeoJSON,
+			input:  `{"type": "FeatureCollection", "features": [{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[106.8, -6.2], [107.6, -6.9]]}, "properties": {"name": "Road"}}]}`,
+			errs:   []string{"Point"},
+		},
+		{
+			name:   "WHEN geojson is a single feature, THEN should return error",
+			format: LocationFormatGeoJSON,
+			input:  `{"type": "Feature", "geometry": {"type": "Point", "coordinates": [106.8, -6.2]}}`,
+			err:    "geojson must be a FeatureCollection",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ParseLocationImport(tt.format, strings.NewReader(tt.input))
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, rows, len(tt.errs))
+			for i, row := range rows {
+				assert.Equal(t, i+1, row.Row)
+				if tt.errs[i] == "" {
+					assert.NoError(t, row.ParseError)
+				} else {
+					assert.ErrorContains(t, row.ParseError, tt.errs[i])
+				}
+			}
+			if tt.rows != nil {
+				assert.Equal(t, tt.rows, rows)
+			}
+		})
+	}
+}
+
+func TestWriteLocationExport(t *testing.T) {
+	items := []LocationExchangeItem{{ID: 1, ExternalKey: "jkt", Name: "Jakarta, Central", Country: "Indonesia", Latitude: -6.2, Longitude: 106.8}}
+
+	for _, format := range []string{LocationFormatCSV, LocationFormatJSON, LocationFormatGeoJSON} {
+		t.Run("WHEN exported as "+format+", THEN should import back the same items", func(t *testing.T) {
+			var buf strings.Builder
+			require.NoError(t, WriteLocationExport(&buf, format, items))
+
+			rows, err := ParseLocationImport(format, strings.NewReader(buf.String()))
+
+			require.NoError(t, err)
+			require.Len(t, rows, 1)
+			assert.NoError(t, rows[0].ParseError)
+			assert.Equal(t, "Jakarta, Central", rows[0].Item.Name)
+			assert.Equal(t, "jkt", rows[0].Item.ExternalKey)
+			assert.Equal(t, -6.2, rows[0].Item.Latitude)
+		})
+	}
+}
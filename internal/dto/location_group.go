@@ -0,0 +1,154 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"tyarus/weather-app/internal/domain"
+	"tyarus/weather-app/pkg/utils"
+)
+
+type LocationGroupResponseItem struct {
+	ID             int64     `json:"id"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description"`
+	LocationCount  int       `json:"locationCount"`
+	CreatedAt      time.Time `json:"createdAt"`
+	LastModifiedAt time.Time `json:"lastModifiedAt"`
+}
+
+func ParseToLocationGroupResponse(item domain.LocationGroup) LocationGroupResponseItem {
+	return LocationGroupResponseItem{
+		ID:             item.ID,
+		Name:           item.Name,
+		Description:    item.Description,
+		LocationCount:  item.LocationCount,
+		CreatedAt:      item.CreatedAt,
+		LastModifiedAt: item.LastModifiedAt.Time,
+	}
+}
+
+func ParseToLocationGroupResponses(items []domain.LocationGroup) []LocationGroupResponseItem {
+	results := []LocationGroupResponseItem{}
+	for _, v := range items {
+		results = append(results, ParseToLocationGroupResponse(v))
+	}
+
+	return results
+}
+
+type GetLocationGroupDetailResponse struct {
+	LocationGroupResponseItem
+	Locations []GetLocationHandlerResponseItem `json:"locations"`
+}
+
+type GetLocationGroupsParam struct {
+	PageSize    int
+	CurrentPage int
+}
+
+type PostLocationGroupRequest struct {
+	ID          int64  `json:"-"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func (r *PostLocationGroupRequest) Validate() error {
+	name := strings.TrimSpace(r.Name)
+	if name == "" {
+		return errors.New("invalid name parameter, please check your parameter")
+	}
+
+	if len(name) > 100 {
+		return errors.New("invalid name parameter, maximum 100 characters")
+	}
+
+	if len(r.Description) > 255 {
+		return errors.New("invalid description parameter, maximum 255 characters")
+	}
+
+	return nil
+}
+
+func (r *PostLocationGroupRequest) PostLocationGroupRequestToDomain() domain.LocationGroup {
+	return domain.LocationGroup{
+		ID:          r.ID,
+		Name:        strings.TrimSpace(r.Name),
+		Description: strings.TrimSpace(r.Description),
+	}
+}
+
+type LocationGroupMembersRequest struct {
+	GroupID     int64   `json:"-"`
+	LocationIDs []int64 `json:"locationIDs"`
+}
+
+func (r *LocationGroupMembersRequest) Validate() error {
+	if len(r.LocationIDs) == 0 {
+		return errors.New("locationIDs parameter is empty, please check your parameter")
+	}
+
+	if len(r.LocationIDs) > utils.MaxGroupMembers {
+		return fmt.Errorf("too many locationIDs, maximum %d locations per request", utils.MaxGroupMembers)
+	}
+
+	for _, id := range r.LocationIDs {
+		if id <= 0 {
+			return errors.New("invalid locationIDs parameter, please check your parameter")
+		}
+	}
+
+	return nil
+}
+
+type LocationGroupMembersResponse struct {
+	Group               LocationGroupResponseItem `json:"group"`
+	Affected            int64                     `json:"affected"`
+	NotFoundLocationIDs []int64                   `json:"notFoundLocationIDs,omitempty"`
+}
+
+type PutLocationTagsRequest struct {
+	LocationID int64    `json:"-"`
+	Tags       []string `json:"tags"`
+	Languages  []string `json:"-"`
+}
+
+func (r *PutLocationTagsRequest) Validate() error {
+	if len(r.Tags) > utils.MaxLocationTags {
+		return fmt.Errorf("too many tags, maximum %d tags per location", utils.MaxLocationTags)
+	}
+
+	for _, tag := range r.Tags {
+		tag = NormalizeLocationTag(tag)
+		if tag == "" {
+			return errors.New("invalid tags parameter, tag must not be empty")
+		}
+
+		if len(tag) > utils.MaxLocationTagLength {
+			return fmt.Errorf("invalid tags parameter, maximum %d characters per tag", utils.MaxLocationTagLength)
+		}
+	}
+
+	return nil
+}
+
+// NormalizedTags returns the tags normalized, sorted and without duplicates.
+func (r *PutLocationTagsRequest) NormalizedTags() []string {
+	tags := []string{}
+	for _, tag := range r.Tags {
+		tag = NormalizeLocationTag(tag)
+		if !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	slices.Sort(tags)
+
+	return tags
+}
+
+// NormalizeLocationTag makes "Java Ops" and " java  ops" the same tag.
+func NormalizeLocationTag(tag string) string {
+	return strings.ToLower(strings.Join(strings.Fields(tag), " "))
+}
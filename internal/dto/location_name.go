@@ -0,0 +1,105 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"tyarus/weather-app/internal/domain"
+	"tyarus/weather-app/pkg/language"
+	"tyarus/weather-app/pkg/utils"
+)
+
+type LocationNameItem struct {
+	Name     string `json:"name"`
+	Language string `json:"language,omitempty"`
+}
+
+func ParseToLocationNameItems(names []domain.LocationName) []LocationNameItem {
+	results := []LocationNameItem{}
+	for _, v := range names {
+		results = append(results, LocationNameItem{Name: v.Name, Language: v.Language})
+	}
+
+	return results
+}
+
+// LocalizedLocationName picks the name of the first requested language the
+// location has a name for. Aliases without language never match.
+func LocalizedLocationName(names []domain.LocationName, languages []string) (string, bool) {
+	available := make([]string, len(names))
+	for i, name := range names {
+		available[i] = name.Language
+	}
+
+	i, ok := language.Match(languages, available)
+	if !ok {
+		return "", false
+	}
+
+	return names[i].Name, true
+}
+
+// Localize sets the localized name of the location from its names, it does
+// nothing when no language was requested.
+func (i *GetLocationHandlerResponseItem) Localize(names []domain.LocationName, languages []string) {
+	if len(languages) == 0 {
+		return
+	}
+
+	i.LocalizedName = i.Name
+	if name, ok := LocalizedLocationName(names, languages); ok {
+		i.LocalizedName = name
+	}
+}
+
+type PutLocationNamesRequest struct {
+	LocationID int64              `json:"-"`
+	Names      []LocationNameItem `json:"names"`
+	Languages  []string           `json:"-"`
+}
+
+func (r *PutLocationNamesRequest) Validate() error {
+	if len(r.Names) > utils.MaxLocationNames {
+		return fmt.Errorf("too many names, maximum %d names per location", utils.MaxLocationNames)
+	}
+
+	for _, item := range r.Names {
+		name := strings.Join(strings.Fields(item.Name), " ")
+		if name == "" {
+			return errors.New("invalid names parameter, name must not be empty")
+		}
+
+		if len(name) > utils.MaxLocationNameLength {
+			return fmt.Errorf("invalid names parameter, maximum %d characters per name", utils.MaxLocationNameLength)
+		}
+
+		if item.Language != "" && !language.Valid(language.Normalize(item.Language)) {
+			return fmt.Errorf("invalid names parameter, %q is not a language tag such as id or en-US", item.Language)
+		}
+	}
+
+	return nil
+}
+
+// ToDomain returns the names with collapsed spaces and normalized language
+// tags, dropping names repeated in the same language.
+func (r *PutLocationNamesRequest) ToDomain() []domain.LocationName {
+	names := []domain.LocationName{}
+	seen := map[string]bool{}
+	for _, item := range r.Names {
+		name := domain.LocationName{
+			LocationID: r.LocationID,
+			Name:       strings.Join(strings.Fields(item.Name), " "),
+			Language:   language.Normalize(item.Language),
+		}
+
+		key := name.Language + "|" + strings.ToLower(name.Name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		names = append(names, name)
+	}
+
+	return names
+}
@@ -1,9 +1,25 @@
 package dto
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
 	"time"
+	"tyarus/weather-app/internal/domain"
+	"tyarus/weather-app/pkg/condition"
+	"tyarus/weather-app/pkg/response"
+	"tyarus/weather-app/pkg/units"
+	"tyarus/weather-app/pkg/utils"
+	"tyarus/weather-app/pkg/weather"
+	"tyarus/weather-app/pkg/weather/derive"
 )
 
+// GetWeatherResponseItem is built in metric units. Temperature, WindSpeed,
+// Precipitation, Pressure and Visibility follow the unit system of the
+// response after InUnits, measurements the provider did not send are nil.
 type GetWeatherResponseItem struct {
 	ForecastTime          time.Time                `json:"forecastTime"`
 	ForecastType          string                   `json:"forecastType"`
@@ -12,30 +28,441 @@ type GetWeatherResponseItem struct {
 	Temperature           float64                  `json:"temperature"`
 	Humidity              int                      `json:"humidity"`
 	WindSpeed             float64                  `json:"windSpeed"`
+	Precipitation         *float64                 `json:"precipitation,omitempty"`
+	Pressure              *float64                 `json:"pressure,omitempty"`
+	Visibility            *float64                 `json:"visibility,omitempty"`
+	Derived               *DerivedWeatherResponse  `json:"derived,omitempty"`
 	Condition             WeatherConditionResponse `json:"condition"`
+	IsDay                 *bool                    `json:"isDay,omitempty"`
 	CreatedAt             time.Time                `json:"createdAt"`
 	LastModifiedAt        time.Time                `json:"lastModifiedAt"`
 }
 
+func ParseToGetWeatherResponseItem(item domain.Weather) GetWeatherResponseItem {
+	return GetWeatherResponseItem{
+		ForecastTime:          item.ForecastTime,
+		ForecastType:          string(item.ForecastType),
+		TemperatureCelcius:    item.TemperatureCelcius,
+		TemperatureFahrenheit: item.TemperatureFahrenheit,
+		Temperature:           item.TemperatureCelcius,
+		Humidity:              item.Humidity,
+		WindSpeed:             item.WindSpeed,
+		Precipitation:         nullFloat(item.PrecipitationMM),
+		Pressure:              nullFloat(item.PressureMB),
+		Visibility:            nullFloat(item.VisibilityKM),
+		Derived:               DeriveWeather(item.TemperatureCelcius, item.Humidity, item.WindSpeed),
+		Condition:             NewWeatherConditionResponse(item),
+		CreatedAt:             item.CreatedAt,
+		LastModifiedAt:        item.LastModifiedAt.Time,
+	}
+}
+
+// InUnits converts a metric item to the unit system. The Celsius and
+// Fahrenheit fields are kept as they are.
+func (i GetWeatherResponseItem) InUnits(system units.System) GetWeatherResponseItem {
+	i.Temperature = system.Temperature(i.TemperatureCelcius)
+	i.WindSpeed = system.Speed(i.WindSpeed)
+	i.Precipitation = convertFloat(i.Precipitation, system.Precipitation)
+	i.Pressure = convertFloat(i.Pressure, system.Pressure)
+	i.Visibility = convertFloat(i.Visibility, system.Distance)
+	if i.Derived != nil {
+		derived := i.Derived.InUnits(system)
+		i.Derived = &derived
+	}
+	return i
+}
+
+// DerivedWeatherResponse holds comfort indicators computed from temperature,
+// humidity and wind. Indicators undefined for the weather are left out, e.g.
+// wind chill above 10°C.
+type DerivedWeatherResponse struct {
+	DewPoint            *float64 `json:"dewPoint,omitempty"`
+	HeatIndex           *float64 `json:"heatIndex,omitempty"`
+	Humidex             *float64 `json:"humidex,omitempty"`
+	WindChill           *float64 `json:"windChill,omitempty"`
+	ApparentTemperature float64  `json:"apparentTemperature"`
+	// Alerts are the configured alert rules the indicators match
+	Alerts []string `json:"alerts,omitempty"`
+}
+
+// DeriveWeather computes the indicators in metric units. Most indicators
+// need humidity, which daily rows may lack, so nothing is derived without it.
+func DeriveWeather(celsius float64, humidity int, windKph float64) *DerivedWeatherResponse {
+	if humidity <= 0 {
+		return nil
+	}
+
+	indicators := derive.Compute(celsius, float64(humidity), windKph)
+	return &DerivedWeatherResponse{
+		DewPoint:            convertFloat(indicators.DewPoint, units.Metric.Temperature),
+		HeatIndex:           convertFloat(indicators.HeatIndex, units.Metric.Temperature),
+		Humidex:             convertFloat(indicators.Humidex, units.Metric.Temperature),
+		WindChill:           convertFloat(indicators.WindChill, units.Metric.Temperature),
+		ApparentTemperature: units.Metric.Temperature(indicators.ApparentTemperature),
+	}
+}
+
+// Indicators returns the metric indicators back for alert rules.
+func (d DerivedWeatherResponse) Indicators() derive.Indicators {
+	return derive.Indicators{
+		DewPoint:            d.DewPoint,
+		HeatIndex:           d.HeatIndex,
+		Humidex:             d.Humidex,
+		WindChill:           d.WindChill,
+		ApparentTemperature: d.ApparentTemperature,
+	}
+}
+
+// WithAlerts lists the rules matched by a metric item. Items without
+// derived indicators match none.
+func (i GetWeatherResponseItem) WithAlerts(rules []derive.Rule) GetWeatherResponseItem {
+	if i.Derived == nil {
+		return i
+	}
+
+	derived := *i.Derived
+	derived.Alerts = nil
+	indicators := derived.Indicators()
+	for _, rule := range rules {
+		if rule.Match(indicators) {
+			derived.Alerts = append(derived.Alerts, rule.String())
+		}
+	}
+	i.Derived = &derived
+	return i
+}
+
+// InUnits converts the temperatures of metric indicators. Humidex is an
+// index on the Celsius scale and is never converted.
+func (d DerivedWeatherResponse) InUnits(system units.System) DerivedWeatherResponse {
+	d.DewPoint = convertFloat(d.DewPoint, system.Temperature)
+	d.HeatIndex = convertFloat(d.HeatIndex, system.Temperature)
+	d.WindChill = convertFloat(d.WindChill, system.Temperature)
+	d.ApparentTemperature = system.Temperature(d.ApparentTemperature)
+	return d
+}
+
+func nullFloat(value sql.NullFloat64) *float64 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Float64
+}
+
+func convertFloat(value *float64, convert func(float64) float64) *float64 {
+	if value == nil {
+		return nil
+	}
+	converted := convert(*value)
+	return &converted
+}
+
+func itemsInUnits(items []GetWeatherResponseItem, system units.System) []GetWeatherResponseItem {
+	if items == nil {
+		return nil
+	}
+
+	converted := make([]GetWeatherResponseItem, len(items))
+	for i, item := range items {
+		converted[i] = item.InUnits(system)
+	}
+	return converted
+}
+
+// WeatherConditionResponse holds the normalized condition next to the raw
+// text of the provider. Text is in English and icons are paths on this API
+// until Present.
 type WeatherConditionResponse struct {
-	Status  string `json:"status"`
-	IconURL string `json:"iconURL"`
+	Code    condition.Code `json:"code"`
+	Text    string         `json:"text"`
+	Icon    string         `json:"icon"`
+	Status  string         `json:"status"`
+	IconURL string         `json:"iconURL"`
+}
+
+// NewWeatherConditionResponse reads the condition of a weather, rows stored
+// before condition codes were kept are mapped from their text.
+func NewWeatherConditionResponse(item domain.Weather) WeatherConditionResponse {
+	code := condition.Code(item.ConditionCode)
+	if !code.Valid() || code == condition.Unknown {
+		code = condition.FromWeatherAPIText(item.ConditionStatus)
+	}
+
+	// provider icons are served by our icon proxy, unknown URLs are kept
+	iconURL := item.ConditionIconURL
+	if iconCode, ok := weather.IconCode(iconURL); ok {
+		iconURL = IconPath(iconCode)
+	}
+
+	return WeatherConditionResponse{
+		Code:    code,
+		Text:    code.Text(nil),
+		Icon:    ConditionIconPath(code),
+		Status:  item.ConditionStatus,
+		IconURL: iconURL,
+	}
+}
+
+// ConditionResponseItem describes a code of the condition taxonomy.
+type ConditionResponseItem struct {
+	Code condition.Code `json:"code"`
+	Text string         `json:"text"`
+	Icon string         `json:"icon"`
+}
+
+// ConditionIconPath is the path of the icon served for the code.
+func ConditionIconPath(code condition.Code) string {
+	return fmt.Sprintf("/api/v1/conditions/%s.svg", code)
+}
+
+// IconPath is the path of the provider icon proxied for the icon code.
+func IconPath(iconCode string) string {
+	return fmt.Sprintf("/api/v1/icons/%s.png", iconCode)
+}
+
+// AbsoluteURL prefixes a path of this API with baseURL, such as
+// "https://example.com". Other values and an empty baseURL keep it as is.
+func AbsoluteURL(baseURL, path string) string {
+	if baseURL == "" || !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") {
+		return path
+	}
+	return strings.TrimSuffix(baseURL, "/") + path
+}
+
+// ParsePublicBaseURL validates the configured base URL the API is reached on,
+// an absolute http or https URL such as "https://weather.example.com". An
+// empty value is kept, URLs then follow the request.
+func ParsePublicBaseURL(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	parsed, err := url.Parse(value)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || parsed.RawQuery != "" || parsed.Fragment != "" {
+		return "", fmt.Errorf("invalid public base URL %q, expected an absolute http or https URL", value)
+	}
+	return strings.TrimSuffix(value, "/"), nil
+}
+
+// Present translates Text to the first of the preferred languages having a
+// translation and makes the icon paths absolute URLs on baseURL. An empty
+// condition of a missing item stays empty.
+func (c WeatherConditionResponse) Present(preferred []string, baseURL string) WeatherConditionResponse {
+	if c.Code == "" {
+		return c
+	}
+	c.Text = c.Code.Text(preferred)
+	c.Icon = AbsoluteURL(baseURL, c.Icon)
+	c.IconURL = AbsoluteURL(baseURL, c.IconURL)
+	return c
+}
+
+// Present presents the condition of the item.
+func (i GetWeatherResponseItem) Present(preferred []string, baseURL string) GetWeatherResponseItem {
+	i.Condition = i.Condition.Present(preferred, baseURL)
+	return i
+}
+
+func itemsPresented(items []GetWeatherResponseItem, preferred []string, baseURL string) []GetWeatherResponseItem {
+	if items == nil {
+		return nil
+	}
+
+	presented := make([]GetWeatherResponseItem, len(items))
+	for i, item := range items {
+		presented[i] = item.Present(preferred, baseURL)
+	}
+	return presented
 }
 
 type GetWeatherResponse struct {
 	Location    GetLocationHandlerResponseItem `json:"location,omitempty"`
 	CurrentTime GetWeatherResponseItem         `json:"currentTime,omitempty"`
 	Forecast    []GetWeatherResponseItem       `json:"forecast,omitempty"`
+	Astronomy   []AstronomyResponseItem        `json:"astronomy,omitempty"`
+	Summary     string                         `json:"summary,omitempty"`
+	Units       units.Labels                   `json:"units"`
+	response.CursorData
+}
+
+// AstronomyResponseItem is the sun and moon of a day, events the day does
+// not have are nil.
+type AstronomyResponseItem struct {
+	Date             string     `json:"date"`
+	Sunrise          *time.Time `json:"sunrise"`
+	Sunset           *time.Time `json:"sunset"`
+	Moonrise         *time.Time `json:"moonrise"`
+	Moonset          *time.Time `json:"moonset"`
+	DaylightHours    float64    `json:"daylightHours"`
+	MoonPhase        string     `json:"moonPhase"`
+	MoonIllumination int        `json:"moonIllumination"`
+	Source           string     `json:"source"`
+}
+
+func ParseToAstronomyResponseItem(item domain.Astronomy) AstronomyResponseItem {
+	return AstronomyResponseItem{
+		Date:             item.Date.Format(utils.DateFormat),
+		Sunrise:          nullTime(item.Sunrise),
+		Sunset:           nullTime(item.Sunset),
+		Moonrise:         nullTime(item.Moonrise),
+		Moonset:          nullTime(item.Moonset),
+		DaylightHours:    math.Round(float64(item.DaylightMinutes)/60*100) / 100,
+		MoonPhase:        item.MoonPhase,
+		MoonIllumination: item.MoonIllumination,
+		Source:           item.Source,
+	}
+}
+
+func nullTime(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Time
+}
+
+// InUnits converts a metric response to the unit system.
+func (r GetWeatherResponse) InUnits(system units.System) GetWeatherResponse {
+	r.CurrentTime = r.CurrentTime.InUnits(system)
+	r.Forecast = itemsInUnits(r.Forecast, system)
+	r.Units = system.Labels()
+	return r
+}
+
+// WithAlerts evaluates the alert rules on a metric response.
+func (r GetWeatherResponse) WithAlerts(rules []derive.Rule) GetWeatherResponse {
+	r.CurrentTime = r.CurrentTime.WithAlerts(rules)
+	if r.Forecast != nil {
+		forecast := make([]GetWeatherResponseItem, len(r.Forecast))
+		for i, item := range r.Forecast {
+			forecast[i] = item.WithAlerts(rules)
+		}
+		r.Forecast = forecast
+	}
+	return r
+}
+
+// Present presents the conditions of the response.
+func (r GetWeatherResponse) Present(preferred []string, baseURL string) GetWeatherResponse {
+	r.CurrentTime = r.CurrentTime.Present(preferred, baseURL)
+	r.Forecast = itemsPresented(r.Forecast, preferred, baseURL)
+	return r
 }
 
 type GetWeathersParam struct {
 	LocationID  int
+	Cursor      string
 	PageSize    int
 	CurrentPage int
+	Units       units.System
+	// Summary adds a forecast summary in the first of Languages having
+	// templates, condition texts are translated the same way
+	Summary   bool
+	Languages []string
+	// BaseURL makes icon URLs absolute, e.g. "https://example.com"
+	BaseURL string
+	// AlertRules are evaluated on every item
+	AlertRules []derive.Rule
 }
 
 type PostWeatherSyncUsecaseRequest struct {
-	LocationID       int `json:"locationID"`
-	Limit            int `json:"limit"`
-	ForecastDayTotal int `json:"forecastDayTotal"`
+	LocationID       int   `json:"locationID"`
+	GroupID          int64 `json:"groupID"`
+	Limit            int   `json:"limit"`
+	ForecastDayTotal int   `json:"forecastDayTotal"`
+}
+
+// GetWeathersBatchParam selects locations by id, by group, by tag or by a
+// combination of them. Group and tag narrow down the given ids.
+type GetWeathersBatchParam struct {
+	LocationIDs     []int  `json:"locationIDs"`
+	GroupID         int64  `json:"groupID"`
+	Tag             string `json:"tag"`
+	IncludeForecast bool   `json:"includeForecast"`
+	ForecastDays    int    `json:"forecastDays"`
+	Summary         bool   `json:"summary"`
+	// Units, Languages and BaseURL come from the query string or headers for
+	// both GET and POST
+	Units     units.System `json:"-"`
+	Languages []string     `json:"-"`
+	BaseURL   string       `json:"-"`
+}
+
+func (p *GetWeathersBatchParam) Validate() error {
+	if len(p.LocationIDs) == 0 && p.GroupID == 0 && strings.TrimSpace(p.Tag) == "" {
+		return errors.New("locationIDs, groupID or tag parameter is required, please check your parameter")
+	}
+
+	if p.GroupID < 0 {
+		return errors.New("invalid groupID parameter, please check your parameter")
+	}
+
+	if len(p.LocationIDs) > utils.MaxBatchLocations {
+		return fmt.Errorf("too many locationIDs, maximum %d locations per request", utils.MaxBatchLocations)
+	}
+
+	for _, id := range p.LocationIDs {
+		if id <= 0 {
+			return errors.New("invalid locationIDs parameter, please check your parameter")
+		}
+	}
+
+	if p.ForecastDays < 0 || p.ForecastDays > utils.MaxForecastDays {
+		return fmt.Errorf("invalid forecastDays parameter, only allow 0 until %d", utils.MaxForecastDays)
+	}
+
+	return nil
+}
+
+type GetWeathersBatchResponseItem struct {
+	Location    GetLocationHandlerResponseItem `json:"location"`
+	CurrentTime *GetWeatherResponseItem        `json:"currentTime,omitempty"`
+	Forecast    []GetWeatherResponseItem       `json:"forecast,omitempty"`
+	Astronomy   []AstronomyResponseItem        `json:"astronomy,omitempty"`
+	Summary     string                         `json:"summary,omitempty"`
+}
+
+// InUnits converts a metric item to the unit system.
+func (i GetWeathersBatchResponseItem) InUnits(system units.System) GetWeathersBatchResponseItem {
+	if i.CurrentTime != nil {
+		current := i.CurrentTime.InUnits(system)
+		i.CurrentTime = &current
+	}
+	i.Forecast = itemsInUnits(i.Forecast, system)
+	return i
+}
+
+// Present presents the conditions of the item.
+func (i GetWeathersBatchResponseItem) Present(preferred []string, baseURL string) GetWeathersBatchResponseItem {
+	if i.CurrentTime != nil {
+		current := i.CurrentTime.Present(preferred, baseURL)
+		i.CurrentTime = &current
+	}
+	i.Forecast = itemsPresented(i.Forecast, preferred, baseURL)
+	return i
+}
+
+type GetWeathersBatchResponse struct {
+	Items               []GetWeathersBatchResponseItem `json:"items"`
+	NotFoundLocationIDs []int                          `json:"notFoundLocationIDs,omitempty"`
+	Units               units.Labels                   `json:"units"`
+	// Truncated is set when the group or tag has more locations than a
+	// batch returns.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+type GetPointWeatherParam struct {
+	GetNearbyLocationsParam
+	Units     units.System
+	Languages []string
+	BaseURL   string
+}
+
+type GetPointWeatherResponse struct {
+	Latitude    float64                `json:"latitude"`
+	Longitude   float64                `json:"longitude"`
+	CurrentTime GetWeatherResponseItem `json:"currentTime"`
+	// Astronomy is computed for the coordinate itself
+	Astronomy *AstronomyResponseItem          `json:"astronomy,omitempty"`
+	Sources   []GetNearbyLocationResponseItem `json:"sources"`
+	Units     units.Labels                    `json:"units"`
 }
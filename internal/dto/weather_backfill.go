@@ -0,0 +1,41 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"tyarus/weather-app/pkg/utils"
+)
+
+// BackfillWeatherParam selects the past days From until To, both inclusive,
+// as dates of the location. Days after today are skipped.
+type BackfillWeatherParam struct {
+	LocationID int64
+	From       time.Time
+	To         time.Time
+}
+
+func (p *BackfillWeatherParam) Validate() error {
+	if p.LocationID <= 0 {
+		return errors.New("invalid location id, please check your parameter")
+	}
+
+	if p.From.IsZero() || p.To.IsZero() {
+		return errors.New("from and to are required")
+	}
+
+	if p.To.Before(p.From) {
+		return errors.New("to must not be before from")
+	}
+
+	if p.To.Sub(p.From) >= time.Duration(utils.MaxStatsRangeDays)*24*time.Hour {
+		return fmt.Errorf("date range too long, maximum %d days per backfill", utils.MaxStatsRangeDays)
+	}
+
+	return nil
+}
+
+// BackfillWeatherResult counts the days loaded from the provider.
+type BackfillWeatherResult struct {
+	Days int `json:"days"`
+}
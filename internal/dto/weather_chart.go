@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"tyarus/weather-app/pkg/svgchart"
+	"tyarus/weather-app/pkg/units"
+	"tyarus/weather-app/pkg/utils"
+)
+
+// GetWeatherChartParam charts a metric of the hourly forecast from the
+// current hour on. Zero values are filled by the usecase.
+type GetWeatherChartParam struct {
+	LocationID int64
+	Metric     string
+	Range      time.Duration
+	Width      int
+	Height     int
+	Theme      string
+	Units      units.System
+}
+
+func (p *GetWeatherChartParam) Validate() error {
+	if p.Metric != "" && !CompareMetricMaps[p.Metric] {
+		return fmt.Errorf("invalid metric parameter %q, only allow temperature, humidity, windSpeed, precipitation", p.Metric)
+	}
+
+	if p.Range != 0 && (p.Range < time.Hour || p.Range > time.Duration(utils.MaxChartRangeHours)*time.Hour) {
+		return fmt.Errorf("invalid range parameter, must be between 1h and %dh", utils.MaxChartRangeHours)
+	}
+
+	if p.Width != 0 && (p.Width < utils.MinChartWidth || p.Width > utils.MaxChartWidth) {
+		return fmt.Errorf("invalid width parameter, must be between %d and %d", utils.MinChartWidth, utils.MaxChartWidth)
+	}
+
+	if p.Height != 0 && (p.Height < utils.MinChartHeight || p.Height > utils.MaxChartHeight) {
+		return fmt.Errorf("invalid height parameter, must be between %d and %d", utils.MinChartHeight, utils.MaxChartHeight)
+	}
+
+	if _, ok := svgchart.Themes[p.Theme]; p.Theme != "" && !ok {
+		return errors.New("invalid theme parameter, only allow light, dark")
+	}
+
+	return nil
+}
+
+// WeatherChart is a rendered SVG chart. LastModified is the latest change of
+// the weather rows in it, zero when there are none.
+type WeatherChart struct {
+	Body         []byte
+	LastModified time.Time
+}
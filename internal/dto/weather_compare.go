@@ -0,0 +1,155 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"tyarus/weather-app/pkg/units"
+	"tyarus/weather-app/pkg/utils"
+)
+
+const (
+	CompareMetricTemperature   = "temperature"
+	CompareMetricHumidity      = "humidity"
+	CompareMetricWindSpeed     = "windSpeed"
+	CompareMetricPrecipitation = "precipitation"
+)
+
+// CompareMetrics lists every metric in response order.
+var CompareMetrics = []string{
+	CompareMetricTemperature,
+	CompareMetricHumidity,
+	CompareMetricWindSpeed,
+	CompareMetricPrecipitation,
+}
+
+var CompareMetricMaps = map[string]bool{
+	CompareMetricTemperature:   true,
+	CompareMetricHumidity:      true,
+	CompareMetricWindSpeed:     true,
+	CompareMetricPrecipitation: true,
+}
+
+// CompareWeathersParam selects the hourly weather of the locations from From,
+// inclusive, until To, exclusive. Zero values are filled by the usecase.
+type CompareWeathersParam struct {
+	LocationIDs []int
+	From        time.Time
+	To          time.Time
+	Metrics     []string
+	Units       units.System
+}
+
+func (p *CompareWeathersParam) Validate() error {
+	if len(p.LocationIDs) == 0 {
+		return errors.New("locationIDs parameter is empty, please check your parameter")
+	}
+
+	if len(p.LocationIDs) > utils.MaxCompareLocations {
+		return fmt.Errorf("too many locationIDs, maximum %d locations per request", utils.MaxCompareLocations)
+	}
+
+	for _, id := range p.LocationIDs {
+		if id <= 0 {
+			return errors.New("invalid locationIDs parameter, please check your parameter")
+		}
+	}
+
+	for _, metric := range p.Metrics {
+		if !CompareMetricMaps[metric] {
+			return fmt.Errorf("invalid metrics parameter %q, only allow temperature, humidity, windSpeed, precipitation", metric)
+		}
+	}
+
+	if !p.From.IsZero() && !p.To.IsZero() {
+		if !p.To.After(p.From) {
+			return errors.New("to parameter must be after from parameter")
+		}
+		if p.To.Sub(p.From) > time.Duration(utils.MaxCompareRangeHours)*time.Hour {
+			return fmt.Errorf("time range too long, maximum %d hours per request", utils.MaxCompareRangeHours)
+		}
+	}
+
+	return nil
+}
+
+// CompareWeathersLocation holds one value per response time for every
+// requested metric, nil where the location has no data for the hour.
+type CompareWeathersLocation struct {
+	Location GetLocationHandlerResponseItem `json:"location"`
+	Series   map[string][]*float64          `json:"series"`
+	Missing  int                            `json:"missing"`
+}
+
+// CompareWeathersRanking orders the locations having data for the metric,
+// best first.
+type CompareWeathersRanking struct {
+	Name   string                       `json:"name"`
+	Metric string                       `json:"metric"`
+	Items  []CompareWeathersRankingItem `json:"items"`
+}
+
+// CompareWeathersRankingItem has the hourly mean of the location, Hours is
+// the number of hours it was computed from.
+type CompareWeathersRankingItem struct {
+	Rank       int     `json:"rank"`
+	LocationID int64   `json:"locationID"`
+	Value      float64 `json:"value"`
+	Hours      int     `json:"hours"`
+}
+
+type CompareWeathersResponse struct {
+	From                string                    `json:"from"`
+	To                  string                    `json:"to"`
+	Metrics             []string                  `json:"metrics"`
+	Times               []time.Time               `json:"times"`
+	Locations           []CompareWeathersLocation `json:"locations"`
+	Rankings            []CompareWeathersRanking  `json:"rankings"`
+	NotFoundLocationIDs []int                     `json:"notFoundLocationIDs,omitempty"`
+	Units               units.Labels              `json:"units"`
+}
+
+// InUnits converts a metric response to the unit system.
+func (r CompareWeathersResponse) InUnits(system units.System) CompareWeathersResponse {
+	locations := make([]CompareWeathersLocation, len(r.Locations))
+	for i, location := range r.Locations {
+		series := make(map[string][]*float64, len(location.Series))
+		for metric, values := range location.Series {
+			converted := make([]*float64, len(values))
+			for j, value := range values {
+				converted[j] = convertFloat(value, compareMetricConversion(metric, system))
+			}
+			series[metric] = converted
+		}
+		location.Series = series
+		locations[i] = location
+	}
+	r.Locations = locations
+
+	rankings := make([]CompareWeathersRanking, len(r.Rankings))
+	for i, ranking := range r.Rankings {
+		convert := compareMetricConversion(ranking.Metric, system)
+		items := make([]CompareWeathersRankingItem, len(ranking.Items))
+		for j, item := range ranking.Items {
+			item.Value = convert(item.Value)
+			items[j] = item
+		}
+		ranking.Items = items
+		rankings[i] = ranking
+	}
+	r.Rankings = rankings
+	r.Units = system.Labels()
+	return r
+}
+
+func compareMetricConversion(metric string, system units.System) func(float64) float64 {
+	switch metric {
+	case CompareMetricTemperature:
+		return system.Temperature
+	case CompareMetricWindSpeed:
+		return system.Speed
+	case CompareMetricPrecipitation:
+		return system.Precipitation
+	}
+	return func(value float64) float64 { return value }
+}
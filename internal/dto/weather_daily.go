@@ -0,0 +1,117 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+	"tyarus/weather-app/internal/domain"
+	"tyarus/weather-app/pkg/units"
+	"tyarus/weather-app/pkg/utils"
+)
+
+// GetDailyWeatherParam selects the days From until To, both inclusive. Zero
+// dates are filled by the usecase.
+type GetDailyWeatherParam struct {
+	LocationID int64
+	From       time.Time
+	To         time.Time
+	Units      units.System
+}
+
+func (p *GetDailyWeatherParam) Validate() error {
+	if p.LocationID <= 0 {
+		return errors.New("invalid id parameter, please check your parameter")
+	}
+
+	if !p.From.IsZero() && !p.To.IsZero() {
+		if p.To.Before(p.From) {
+			return errors.New("to parameter must not be before from parameter")
+		}
+		if p.To.Sub(p.From) >= time.Duration(utils.MaxDailyRangeDays)*24*time.Hour {
+			return fmt.Errorf("date range too long, maximum %d days per request", utils.MaxDailyRangeDays)
+		}
+	}
+
+	return nil
+}
+
+// DailyWeatherResponseItem is built in metric units like
+// GetWeatherResponseItem. Consistent is nil when the provider sent no summary
+// for the day.
+type DailyWeatherResponseItem struct {
+	Date               string               `json:"date"`
+	Hours              int                  `json:"hours"`
+	MinTemperature     float64              `json:"minTemperature"`
+	MaxTemperature     float64              `json:"maxTemperature"`
+	AvgTemperature     float64              `json:"avgTemperature"`
+	AvgHumidity        float64              `json:"avgHumidity"`
+	MaxWindSpeed       float64              `json:"maxWindSpeed"`
+	TotalPrecipitation *float64             `json:"totalPrecipitation,omitempty"`
+	DominantCondition  string               `json:"dominantCondition"`
+	Summary            *DailyWeatherSummary `json:"summary,omitempty"`
+	Consistent         *bool                `json:"consistent,omitempty"`
+	Issues             []string             `json:"issues,omitempty"`
+}
+
+// DailyWeatherSummary is the day row sent by the provider.
+type DailyWeatherSummary struct {
+	AvgTemperature     float64  `json:"avgTemperature"`
+	MaxWindSpeed       float64  `json:"maxWindSpeed"`
+	TotalPrecipitation *float64 `json:"totalPrecipitation,omitempty"`
+	Condition          string   `json:"condition"`
+}
+
+func ParseToDailyWeatherResponseItem(item domain.DailyWeather) DailyWeatherResponseItem {
+	result := DailyWeatherResponseItem{
+		Date:               item.Date.Format(utils.DateFormat),
+		Hours:              item.Hours,
+		MinTemperature:     item.MinTemperatureCelcius,
+		MaxTemperature:     item.MaxTemperatureCelcius,
+		AvgTemperature:     roundTo2(item.AvgTemperatureCelcius),
+		AvgHumidity:        roundTo2(item.AvgHumidity),
+		MaxWindSpeed:       item.MaxWindSpeed,
+		TotalPrecipitation: nullFloat(item.TotalPrecipitationMM),
+		DominantCondition:  item.DominantCondition,
+	}
+
+	if item.SummaryTemperature.Valid {
+		result.Summary = &DailyWeatherSummary{
+			AvgTemperature:     item.SummaryTemperature.Float64,
+			MaxWindSpeed:       item.SummaryWindSpeed.Float64,
+			TotalPrecipitation: nullFloat(item.SummaryPrecipitationMM),
+			Condition:          item.SummaryCondition.String,
+		}
+	}
+
+	return result
+}
+
+// InUnits converts a metric item to the unit system.
+func (i DailyWeatherResponseItem) InUnits(system units.System) DailyWeatherResponseItem {
+	i.MinTemperature = system.Temperature(i.MinTemperature)
+	i.MaxTemperature = system.Temperature(i.MaxTemperature)
+	i.AvgTemperature = system.Temperature(i.AvgTemperature)
+	i.MaxWindSpeed = system.Speed(i.MaxWindSpeed)
+	i.TotalPrecipitation = convertFloat(i.TotalPrecipitation, system.Precipitation)
+	if i.Summary != nil {
+		summary := *i.Summary
+		summary.AvgTemperature = system.Temperature(summary.AvgTemperature)
+		summary.MaxWindSpeed = system.Speed(summary.MaxWindSpeed)
+		summary.TotalPrecipitation = convertFloat(summary.TotalPrecipitation, system.Precipitation)
+		i.Summary = &summary
+	}
+	return i
+}
+
+type GetDailyWeatherResponse struct {
+	Location GetLocationHandlerResponseItem `json:"location"`
+	From     string                         `json:"from"`
+	To       string                         `json:"to"`
+	Days     []DailyWeatherResponseItem     `json:"days"`
+	Units    units.Labels                   `json:"units"`
+}
+
+func roundTo2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
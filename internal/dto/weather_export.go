@@ -0,0 +1,329 @@
+package dto
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"tyarus/weather-app/internal/domain"
+	"tyarus/weather-app/pkg/condition"
+	"tyarus/weather-app/pkg/utils"
+)
+
+const (
+	WeatherExportFormatCSV    = "csv"
+	WeatherExportFormatNDJSON = "ndjson"
+)
+
+var WeatherExportFormatMaps = map[string]bool{
+	WeatherExportFormatCSV:    true,
+	WeatherExportFormatNDJSON: true,
+}
+
+// weatherExportColumn reads one column of a weather row. Value returns nil
+// for a missing measurement.
+type weatherExportColumn struct {
+	name  string
+	value func(domain.Weather) interface{}
+}
+
+// weatherExportColumns lists every exportable column in export order. Values
+// are exported as stored, in metric units, except the icon URL that points
+// to the icon proxy of this API.
+var weatherExportColumns = []weatherExportColumn{
+	{"id", func(w domain.Weather) interface{} { return w.ID }},
+	{"locationID", func(w domain.Weather) interface{} { return w.LocationID }},
+	{"forecastTime", func(w domain.Weather) interface{} { return w.ForecastTime }},
+	{"forecastType", func(w domain.Weather) interface{} { return string(w.ForecastType) }},
+	{"temperatureCelcius", func(w domain.Weather) interface{} { return w.TemperatureCelcius }},
+	{"temperatureFahrenheit", func(w domain.Weather) interface{} { return w.TemperatureFahrenheit }},
+	{"humidity", func(w domain.Weather) interface{} { return w.Humidity }},
+	{"windSpeedKph", func(w domain.Weather) interface{} { return w.WindSpeed }},
+	{"precipitationMM", func(w domain.Weather) interface{} { return nullFloatValue(w.PrecipitationMM) }},
+	{"pressureMB", func(w domain.Weather) interface{} { return nullFloatValue(w.PressureMB) }},
+	{"visibilityKM", func(w domain.Weather) interface{} { return nullFloatValue(w.VisibilityKM) }},
+	{"conditionStatus", func(w domain.Weather) interface{} { return w.ConditionStatus }},
+	{"conditionCode", func(w domain.Weather) interface{} { return w.ConditionCode }},
+	{"conditionIconURL", func(w domain.Weather) interface{} { return w.ConditionIconURL }},
+	{"createdAt", func(w domain.Weather) interface{} { return w.CreatedAt }},
+	{"lastModifiedAt", func(w domain.Weather) interface{} {
+		if !w.LastModifiedAt.Valid {
+			return nil
+		}
+		return w.LastModifiedAt.Time
+	}},
+}
+
+// WeatherExportColumns returns the names of every exportable column.
+func WeatherExportColumns() []string {
+	names := make([]string, len(weatherExportColumns))
+	for i, column := range weatherExportColumns {
+		names[i] = column.name
+	}
+	return names
+}
+
+func nullFloatValue(value sql.NullFloat64) interface{} {
+	if !value.Valid {
+		return nil
+	}
+	return value.Float64
+}
+
+// ExportWeathersParam selects the rows to export, every zero field matches
+// all rows. To is exclusive, no columns exports every column.
+type ExportWeathersParam struct {
+	LocationIDs  []int
+	From         time.Time
+	To           time.Time
+	ForecastType string
+	// Conditions are normalized condition codes
+	Conditions []string
+	Format     string
+	Columns    []string
+	// MaxRows stops the export after this many rows, zero exports every row
+	MaxRows int
+	// BaseURL makes the icon URLs absolute, they are paths of this API when
+	// it is empty
+	BaseURL string
+}
+
+// ExportWeathersResult is what an export wrote, Truncated is set when it
+// stopped at MaxRows with rows left.
+type ExportWeathersResult struct {
+	Rows      int64
+	Truncated bool
+}
+
+// ParseExportWeathersQuery reads the parameters of an export from the query
+// of GET /api/v1/weathers/export, the CLI passes its flags the same way so
+// both parse them alike. The format defaults to csv.
+func ParseExportWeathersQuery(query url.Values) (ExportWeathersParam, error) {
+	param := ExportWeathersParam{
+		ForecastType: query.Get("forecastType"),
+		Conditions:   splitList(query.Get("conditions")),
+		Format:       query.Get("format"),
+		Columns:      splitList(query.Get("columns")),
+	}
+	if param.Format == "" {
+		param.Format = WeatherExportFormatCSV
+	}
+
+	for _, item := range splitList(query.Get("locationIDs")) {
+		id, err := strconv.Atoi(item)
+		if err != nil {
+			return param, errors.New("invalid locationIDs parameter, please check your parameter")
+		}
+		param.LocationIDs = append(param.LocationIDs, id)
+	}
+
+	var err error
+	if value := query.Get("from"); value != "" {
+		if param.From, err = ParseLocalTime(value); err != nil {
+			return param, errors.New("invalid from parameter, use RFC3339 or YYYY-MM-DD")
+		}
+	}
+
+	if value := query.Get("to"); value != "" {
+		if param.To, err = ParseLocalTime(value); err != nil {
+			return param, errors.New("invalid to parameter, use RFC3339 or YYYY-MM-DD")
+		}
+	}
+
+	return param, param.Validate()
+}
+
+// ParseLocalTime accepts a full RFC3339 timestamp or a plain date of the
+// locations, labelled UTC like forecast times.
+func ParseLocalTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(utils.DateFormat, value)
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func (p *ExportWeathersParam) Validate() error {
+	// columnar formats such as Parquet are left out on purpose, csv and
+	// ndjson stream row by row and load into notebooks as they are
+	if !WeatherExportFormatMaps[p.Format] {
+		return errors.New("invalid format parameter, only allow csv, ndjson")
+	}
+
+	for _, id := range p.LocationIDs {
+		if id <= 0 {
+			return errors.New("invalid locationIDs parameter, please check your parameter")
+		}
+	}
+
+	if p.ForecastType != "" && p.ForecastType != string(domain.ForecastTypeHour) && p.ForecastType != string(domain.ForecastTypeDay) {
+		return errors.New("invalid forecastType parameter, only allow hour, day")
+	}
+
+	for _, code := range p.Conditions {
+		if !condition.Code(code).Valid() {
+			return fmt.Errorf("invalid conditions parameter %q, please check your parameter", code)
+		}
+	}
+
+	if !p.From.IsZero() && !p.To.IsZero() && !p.To.After(p.From) {
+		return errors.New("to parameter must be after from parameter")
+	}
+
+	_, err := selectWeatherExportColumns(p.Columns)
+	return err
+}
+
+// ValidateRange requires both ends of the export and at most maxDays
+// between them, so a single request cannot dump the whole table.
+func (p *ExportWeathersParam) ValidateRange(maxDays int) error {
+	if p.From.IsZero() || p.To.IsZero() {
+		return errors.New("from and to parameters are required")
+	}
+
+	if p.To.Sub(p.From) > time.Duration(maxDays)*24*time.Hour {
+		return fmt.Errorf("from and to parameters must be at most %d days apart", maxDays)
+	}
+
+	return nil
+}
+
+func selectWeatherExportColumns(names []string) ([]weatherExportColumn, error) {
+	if len(names) == 0 {
+		return weatherExportColumns, nil
+	}
+
+	columns := make([]weatherExportColumn, 0, len(names))
+	for _, name := range names {
+		found := false
+		for _, column := range weatherExportColumns {
+			if column.name == name {
+				columns = append(columns, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("invalid columns parameter %q, please check your parameter", name)
+		}
+	}
+	return columns, nil
+}
+
+// WeatherExportWriter writes weathers one at a time. Close must be called
+// after the last row to flush the output, it does not close the underlying
+// writer.
+type WeatherExportWriter interface {
+	Write(weather domain.Weather) error
+	Close() error
+}
+
+// NewWeatherExportWriter returns a writer of the selected columns in the
+// format. The CSV output starts with a header row.
+func NewWeatherExportWriter(w io.Writer, format string, names []string) (WeatherExportWriter, error) {
+	columns, err := selectWeatherExportColumns(names)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case WeatherExportFormatCSV:
+		header := make([]string, len(columns))
+		for i, column := range columns {
+			header[i] = column.name
+		}
+
+		writer := csv.NewWriter(w)
+		if err := writer.Write(header); err != nil {
+			return nil, err
+		}
+		return &weatherCSVWriter{writer: writer, columns: columns}, nil
+	case WeatherExportFormatNDJSON:
+		return &weatherNDJSONWriter{writer: bufio.NewWriter(w), columns: columns}, nil
+	}
+
+	return nil, errors.New("invalid format parameter, only allow csv, ndjson")
+}
+
+type weatherCSVWriter struct {
+	writer  *csv.Writer
+	columns []weatherExportColumn
+}
+
+func (c *weatherCSVWriter) Write(weather domain.Weather) error {
+	record := make([]string, len(c.columns))
+	for i, column := range c.columns {
+		record[i] = formatCSVValue(column.value(weather))
+	}
+	return c.writer.Write(record)
+}
+
+func (c *weatherCSVWriter) Close() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+func formatCSVValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	}
+	return fmt.Sprint(value)
+}
+
+type weatherNDJSONWriter struct {
+	writer  *bufio.Writer
+	columns []weatherExportColumn
+}
+
+// Write keeps the column order in every object, which a map would not.
+func (n *weatherNDJSONWriter) Write(weather domain.Weather) error {
+	n.writer.WriteByte('{')
+	for i, column := range n.columns {
+		if i > 0 {
+			n.writer.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(column.name)
+		value, err := json.Marshal(column.value(weather))
+		if err != nil {
+			return err
+		}
+		n.writer.Write(key)
+		n.writer.WriteByte(':')
+		n.writer.Write(value)
+	}
+	_, err := n.writer.WriteString("}\n")
+	return err
+}
+
+func (n *weatherNDJSONWriter) Close() error {
+	return n.writer.Flush()
+}
@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"time"
+	"tyarus/weather-app/internal/domain"
+	"tyarus/weather-app/pkg/geo"
+	"tyarus/weather-app/pkg/geojson"
+	"tyarus/weather-app/pkg/units"
+)
+
+// GetMapLayerParam selects the locations inside BoundingBox, every location
+// when it is nil. A zero ForecastTime asks for the current weather, otherwise
+// the layer is sliced at the forecast hour containing it.
+type GetMapLayerParam struct {
+	BoundingBox  *geo.BoundingBox
+	ForecastTime time.Time
+	Units        units.System
+	// BaseURL makes icon URLs absolute, e.g. "https://example.com"
+	BaseURL string
+}
+
+func (p *GetMapLayerParam) Validate() error {
+	return validateBoundingBox(p.BoundingBox)
+}
+
+// MapLayer is a FeatureCollection with a point per location. LastModified is
+// the latest change of the weather rows in it, zero when there are none.
+type MapLayer struct {
+	Collection   geojson.FeatureCollection
+	LastModified time.Time
+}
+
+// NewMapLayerFeature describes a location and its weather, the weather
+// properties are left out when weather is nil.
+func NewMapLayerFeature(location domain.Location, weather *domain.Weather, system units.System, baseURL string) geojson.Feature {
+	labels := system.Labels()
+	properties := map[string]interface{}{
+		"name":    location.Name,
+		"region":  location.Region,
+		"country": location.Country,
+	}
+
+	if weather != nil {
+		properties["forecastTime"] = weather.ForecastTime
+		properties["temperature"] = system.Temperature(weather.TemperatureCelcius)
+		properties["temperatureUnit"] = labels.Temperature
+		properties["humidity"] = weather.Humidity
+		properties["windSpeed"] = system.Speed(weather.WindSpeed)
+		properties["windSpeedUnit"] = labels.Speed
+		condition := NewWeatherConditionResponse(*weather).Present(nil, baseURL)
+		properties["condition"] = condition.Status
+		properties["conditionCode"] = condition.Code
+		properties["iconURL"] = condition.IconURL
+	}
+
+	return geojson.NewPointFeature(location.ID, location.Latitude, location.Longitude, properties)
+}
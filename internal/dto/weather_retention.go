@@ -0,0 +1,48 @@
+package dto
+
+import "time"
+
+// WeatherRetentionPolicy configures retention, a zero number of days disables
+// that step.
+type WeatherRetentionPolicy struct {
+	// HourlyDays keeps hourly rows for this many days, older days are
+	// downsampled to a daily row and their hourly rows deleted
+	HourlyDays int
+	// PurgeDeletedDays hard deletes rows soft deleted this many days ago
+	PurgeDeletedDays int
+	// BatchSize bounds the rows a single delete statement removes
+	BatchSize int
+}
+
+type ApplyWeatherRetentionRequest struct {
+	DryRun bool
+}
+
+// WeatherRetentionReport describes a retention run. A dry run reports what a
+// run would change without changing anything.
+type WeatherRetentionReport struct {
+	DryRun            bool   `json:"dryRun"`
+	HourlyBefore      string `json:"hourlyBefore,omitempty"`
+	DeletedBefore     string `json:"deletedBefore,omitempty"`
+	Locations         int    `json:"locations"`
+	DownsampledDays   int64  `json:"downsampledDays"`
+	DeletedHourlyRows int64  `json:"deletedHourlyRows"`
+	PurgedRows        int64  `json:"purgedRows"`
+	Batches           int    `json:"batches"`
+}
+
+const (
+	WeatherRetentionJobRunning = "running"
+	WeatherRetentionJobDone    = "done"
+	WeatherRetentionJobFailed  = "failed"
+)
+
+// WeatherRetentionJob is the last retention run started from the API. The
+// report of a failed run has what was changed before it failed.
+type WeatherRetentionJob struct {
+	Status     string                  `json:"status,omitempty"`
+	StartedAt  *time.Time              `json:"startedAt,omitempty"`
+	FinishedAt *time.Time              `json:"finishedAt,omitempty"`
+	Report     *WeatherRetentionReport `json:"report,omitempty"`
+	Error      string                  `json:"error,omitempty"`
+}
@@ -0,0 +1,123 @@
+package dto
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"tyarus/weather-app/pkg/stats"
+	"tyarus/weather-app/pkg/units"
+	"tyarus/weather-app/pkg/utils"
+)
+
+const (
+	StatsPeriodDay   = "day"
+	StatsPeriodWeek  = "week"
+	StatsPeriodMonth = "month"
+	StatsPeriodYear  = "year"
+)
+
+var StatsPeriodMaps = map[string]bool{
+	StatsPeriodDay:   true,
+	StatsPeriodWeek:  true,
+	StatsPeriodMonth: true,
+	StatsPeriodYear:  true,
+}
+
+// GetWeatherStatsParam splits the days From until To, both inclusive, into
+// calendar periods. Zero values are filled by the usecase.
+type GetWeatherStatsParam struct {
+	LocationID int64
+	Period     string
+	From       time.Time
+	To         time.Time
+	Units      units.System
+}
+
+func (p *GetWeatherStatsParam) Validate() error {
+	if p.LocationID <= 0 {
+		return errors.New("invalid id parameter, please check your parameter")
+	}
+
+	if p.Period != "" && !StatsPeriodMaps[p.Period] {
+		return errors.New("invalid period parameter, only allow day, week, month, year")
+	}
+
+	if !p.From.IsZero() && !p.To.IsZero() {
+		if p.To.Before(p.From) {
+			return errors.New("to parameter must not be before from parameter")
+		}
+		if p.To.Sub(p.From) >= time.Duration(utils.MaxStatsRangeDays)*24*time.Hour {
+			return fmt.Errorf("date range too long, maximum %d days per request", utils.MaxStatsRangeDays)
+		}
+	}
+
+	return nil
+}
+
+// WeatherStatsPeriod is built in metric units. Percentiles come from the
+// hourly rows, day counts and extremes from the daily aggregates.
+type WeatherStatsPeriod struct {
+	From               string                   `json:"from"`
+	To                 string                   `json:"to"`
+	Hours              int                      `json:"hours"`
+	Days               int                      `json:"days"`
+	Temperature        stats.Summary            `json:"temperature"`
+	Humidity           stats.Summary            `json:"humidity"`
+	WindSpeed          stats.Summary            `json:"windSpeed"`
+	TotalPrecipitation *float64                 `json:"totalPrecipitation,omitempty"`
+	RainyDays          int                      `json:"rainyDays"`
+	HottestDay         *WeatherStatsDay         `json:"hottestDay,omitempty"`
+	ColdestDay         *WeatherStatsDay         `json:"coldestDay,omitempty"`
+	PreviousYears      []WeatherStatsComparison `json:"previousYears"`
+}
+
+type WeatherStatsDay struct {
+	Date        string  `json:"date"`
+	Temperature float64 `json:"temperature"`
+}
+
+// WeatherStatsComparison describes the same period some years earlier and
+// how the current period differs from it. Deltas are current minus previous.
+type WeatherStatsComparison struct {
+	Year                    int      `json:"year"`
+	Days                    int      `json:"days"`
+	AvgTemperature          float64  `json:"avgTemperature"`
+	TotalPrecipitation      *float64 `json:"totalPrecipitation,omitempty"`
+	RainyDays               int      `json:"rainyDays"`
+	AvgTemperatureDelta     *float64 `json:"avgTemperatureDelta,omitempty"`
+	TotalPrecipitationDelta *float64 `json:"totalPrecipitationDelta,omitempty"`
+	RainyDaysDelta          int      `json:"rainyDaysDelta"`
+}
+
+// InUnits converts a metric period to the unit system.
+func (p WeatherStatsPeriod) InUnits(system units.System) WeatherStatsPeriod {
+	p.Temperature = p.Temperature.Map(system.Temperature)
+	p.WindSpeed = p.WindSpeed.Map(system.Speed)
+	p.TotalPrecipitation = convertFloat(p.TotalPrecipitation, system.Precipitation)
+	if p.HottestDay != nil {
+		p.HottestDay = &WeatherStatsDay{Date: p.HottestDay.Date, Temperature: system.Temperature(p.HottestDay.Temperature)}
+	}
+	if p.ColdestDay != nil {
+		p.ColdestDay = &WeatherStatsDay{Date: p.ColdestDay.Date, Temperature: system.Temperature(p.ColdestDay.Temperature)}
+	}
+
+	previousYears := make([]WeatherStatsComparison, len(p.PreviousYears))
+	for i, item := range p.PreviousYears {
+		item.AvgTemperature = system.Temperature(item.AvgTemperature)
+		item.TotalPrecipitation = convertFloat(item.TotalPrecipitation, system.Precipitation)
+		item.AvgTemperatureDelta = convertFloat(item.AvgTemperatureDelta, system.TemperatureDifference)
+		item.TotalPrecipitationDelta = convertFloat(item.TotalPrecipitationDelta, system.Precipitation)
+		previousYears[i] = item
+	}
+	p.PreviousYears = previousYears
+	return p
+}
+
+type GetWeatherStatsResponse struct {
+	Location GetLocationHandlerResponseItem `json:"location"`
+	Period   string                         `json:"period"`
+	From     string                         `json:"from"`
+	To       string                         `json:"to"`
+	Periods  []WeatherStatsPeriod           `json:"periods"`
+	Units    units.Labels                   `json:"units"`
+}
@@ -0,0 +1,171 @@
+package dto
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	WeatherSubscriptionActionSubscribe   = "subscribe"
+	WeatherSubscriptionActionUnsubscribe = "unsubscribe"
+)
+
+const (
+	WeatherSubscriptionMessageSubscribed   = "subscribed"
+	WeatherSubscriptionMessageUnsubscribed = "unsubscribed"
+	WeatherSubscriptionMessageSnapshot     = "snapshot"
+	WeatherSubscriptionMessageUpdate       = "update"
+	WeatherSubscriptionMessageError        = "error"
+)
+
+type WeatherSubscriptionRequest struct {
+	Action      string `json:"action"`
+	LocationIDs []int  `json:"locationIDs"`
+}
+
+func (r *WeatherSubscriptionRequest) Validate() error {
+	if r.Action != WeatherSubscriptionActionSubscribe && r.Action != WeatherSubscriptionActionUnsubscribe {
+		return errors.New("invalid action parameter, only allow subscribe, unsubscribe")
+	}
+
+	if len(r.LocationIDs) == 0 {
+		return errors.New("locationIDs parameter is empty, please check your parameter")
+	}
+
+	for _, id := range r.LocationIDs {
+		if id <= 0 {
+			return errors.New("invalid locationIDs parameter, please check your parameter")
+		}
+	}
+
+	return nil
+}
+
+type WeatherSubscriptionMessage struct {
+	Type        string               `json:"type"`
+	LocationID  int                  `json:"locationID,omitempty"`
+	LocationIDs []int                `json:"locationIDs,omitempty"`
+	Message     string               `json:"message,omitempty"`
+	Snapshot    *GetWeatherResponse  `json:"snapshot,omitempty"`
+	Diff        *WeatherForecastDiff `json:"diff,omitempty"`
+}
+
+type WeatherForecastKey struct {
+	ForecastTime time.Time `json:"forecastTime"`
+	ForecastType string    `json:"forecastType"`
+}
+
+// WeatherForecastDiff has Astronomy only when a day of it changed, then it
+// lists every day of the next response.
+type WeatherForecastDiff struct {
+	CurrentTime GetWeatherResponseItem   `json:"currentTime"`
+	Upserted    []GetWeatherResponseItem `json:"upserted,omitempty"`
+	Removed     []WeatherForecastKey     `json:"removed,omitempty"`
+	Astronomy   []AstronomyResponseItem  `json:"astronomy,omitempty"`
+}
+
+func (d WeatherForecastDiff) IsEmpty() bool {
+	return len(d.Upserted) == 0 && len(d.Removed) == 0 && len(d.Astronomy) == 0
+}
+
+// DiffWeatherResponse returns forecast items of next that are new or changed
+// compared to prev, the keys of items that no longer exist in next and the
+// astronomy of next when it changed.
+func DiffWeatherResponse(prev, next GetWeatherResponse) WeatherForecastDiff {
+	prevItems := make(map[WeatherForecastKey]GetWeatherResponseItem, len(prev.Forecast))
+	for _, item := range prev.Forecast {
+		prevItems[forecastKey(item)] = item
+	}
+
+	diff := WeatherForecastDiff{CurrentTime: next.CurrentTime}
+	for _, item := range next.Forecast {
+		key := forecastKey(item)
+		prevItem, ok := prevItems[key]
+		delete(prevItems, key)
+		if ok && sameWeatherValues(prevItem, item) {
+			continue
+		}
+		diff.Upserted = append(diff.Upserted, item)
+	}
+
+	for _, item := range prev.Forecast {
+		if _, ok := prevItems[forecastKey(item)]; ok {
+			diff.Removed = append(diff.Removed, forecastKey(item))
+		}
+	}
+
+	if !sameAstronomy(prev.Astronomy, next.Astronomy) {
+		diff.Astronomy = next.Astronomy
+	}
+
+	return diff
+}
+
+func forecastKey(item GetWeatherResponseItem) WeatherForecastKey {
+	return WeatherForecastKey{ForecastTime: item.ForecastTime.UTC(), ForecastType: item.ForecastType}
+}
+
+// sameWeatherValues ignores bookkeeping timestamps, which change on every
+// upsert even when the provider sends identical values.
+func sameWeatherValues(a, b GetWeatherResponseItem) bool {
+	if !sameFloat(a.Precipitation, b.Precipitation) || !sameFloat(a.Pressure, b.Pressure) || !sameFloat(a.Visibility, b.Visibility) {
+		return false
+	}
+	a.Precipitation, b.Precipitation = nil, nil
+	a.Pressure, b.Pressure = nil, nil
+	a.Visibility, b.Visibility = nil, nil
+	if !sameBool(a.IsDay, b.IsDay) {
+		return false
+	}
+	a.IsDay, b.IsDay = nil, nil
+	// derived indicators only depend on the values compared below
+	a.Derived, b.Derived = nil, nil
+
+	a.CreatedAt, b.CreatedAt = time.Time{}, time.Time{}
+	a.LastModifiedAt, b.LastModifiedAt = time.Time{}, time.Time{}
+	a.ForecastTime, b.ForecastTime = a.ForecastTime.UTC(), b.ForecastTime.UTC()
+	return a == b
+}
+
+// sameAstronomy compares the days by value, their times are pointers.
+func sameAstronomy(a, b []AstronomyResponseItem) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		x, y := a[i], b[i]
+		if !sameTime(x.Sunrise, y.Sunrise) || !sameTime(x.Sunset, y.Sunset) || !sameTime(x.Moonrise, y.Moonrise) || !sameTime(x.Moonset, y.Moonset) {
+			return false
+		}
+		x.Sunrise, y.Sunrise = nil, nil
+		x.Sunset, y.Sunset = nil, nil
+		x.Moonrise, y.Moonrise = nil, nil
+		x.Moonset, y.Moonset = nil, nil
+		if x != y {
+			return false
+		}
+	}
+	return true
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func sameFloat(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func sameBool(a, b *bool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
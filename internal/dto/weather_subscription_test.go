@@ -0,0 +1,66 @@
+package dto
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffWeatherResponse(t *testing.T) {
+	forecastTime := time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)
+	hour := func(offset int, temperature float64, isDay bool) GetWeatherResponseItem {
+		// every item gets its own flag, as responses built on each sync do
+		return GetWeatherResponseItem{
+			ForecastTime:       forecastTime.Add(time.Duration(offset) * time.Hour),
+			ForecastType:       "hour",
+			TemperatureCelcius: temperature,
+			IsDay:              &isDay,
+		}
+	}
+
+	t.Run("WHEN hourly items only differ in the address of their day flag, THEN should be empty", func(t *testing.T) {
+		prev := GetWeatherResponse{Forecast: []GetWeatherResponseItem{hour(0, 24, false), hour(1, 25, true)}}
+		next := GetWeatherResponse{Forecast: []GetWeatherResponseItem{hour(0, 24, false), hour(1, 25, true)}}
+
+		assert.True(t, DiffWeatherResponse(prev, next).IsEmpty())
+	})
+
+	t.Run("WHEN the day flag or a value of an hour changes, THEN should upsert that hour", func(t *testing.T) {
+		prev := GetWeatherResponse{Forecast: []GetWeatherResponseItem{hour(0, 24, false), hour(1, 25, true), hour(2, 26, true)}}
+		next := GetWeatherResponse{Forecast: []GetWeatherResponseItem{hour(0, 24, true), hour(1, 25, true), hour(2, 27, true)}}
+
+		diff := DiffWeatherResponse(prev, next)
+
+		if assert.Len(t, diff.Upserted, 2) {
+			assert.True(t, *diff.Upserted[0].IsDay)
+			assert.Equal(t, 27.0, diff.Upserted[1].TemperatureCelcius)
+		}
+		assert.Empty(t, diff.Removed)
+	})
+
+	t.Run("WHEN an hour gains its day flag, THEN should upsert it", func(t *testing.T) {
+		withoutFlag := hour(0, 24, true)
+		withoutFlag.IsDay = nil
+		prev := GetWeatherResponse{Forecast: []GetWeatherResponseItem{withoutFlag}}
+		next := GetWeatherResponse{Forecast: []GetWeatherResponseItem{hour(0, 24, true)}}
+
+		assert.Len(t, DiffWeatherResponse(prev, next).Upserted, 1)
+	})
+
+	t.Run("WHEN only the astronomy changes, THEN should send every day of it", func(t *testing.T) {
+		sunrise := forecastTime.Add(-30 * time.Minute)
+		copied := sunrise
+		later := sunrise.Add(time.Minute)
+		prev := GetWeatherResponse{Astronomy: []AstronomyResponseItem{{Date: "2024-01-01", Sunrise: &sunrise}}}
+		same := GetWeatherResponse{Astronomy: []AstronomyResponseItem{{Date: "2024-01-01", Sunrise: &copied}}}
+		next := GetWeatherResponse{Astronomy: []AstronomyResponseItem{{Date: "2024-01-01", Sunrise: &later}}}
+
+		assert.True(t, DiffWeatherResponse(prev, same).IsEmpty())
+
+		diff := DiffWeatherResponse(prev, next)
+
+		assert.False(t, diff.IsEmpty())
+		assert.Equal(t, next.Astronomy, diff.Astronomy)
+	})
+}
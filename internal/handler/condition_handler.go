@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+	"tyarus/weather-app/internal/dto"
+	"tyarus/weather-app/pkg/condition"
+	"tyarus/weather-app/pkg/response"
+	"tyarus/weather-app/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+type conditionHandler struct{}
+
+func NewConditionHandler() conditionHandler {
+	return conditionHandler{}
+}
+
+// GetConditionsHandler lists the condition taxonomy with texts in the
+// requested language.
+func (h *conditionHandler) GetConditionsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		languages, err := parseLanguages(r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		items := make([]dto.ConditionResponseItem, len(condition.Codes))
+		for i, code := range condition.Codes {
+			items[i] = dto.ConditionResponseItem{
+				Code: code,
+				Text: code.Text(languages),
+				Icon: dto.ConditionIconPath(code),
+			}
+		}
+
+		response.JSON(w, http.StatusOK, "success", "fetch conditions successfully", items)
+	}
+}
+
+// GetConditionIconHandler serves the SVG icon of a condition code.
+func (h *conditionHandler) GetConditionIconHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		code := condition.Code(mux.Vars(r)["code"])
+		if !code.Valid() {
+			response.Error(w, http.StatusNotFound, "condition not found")
+			return
+		}
+
+		response.Cached(w, r, "image/svg+xml", condition.Icon(code), time.Time{}, utils.ConditionIconMaxAge)
+	}
+}
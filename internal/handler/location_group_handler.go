@@ -0,0 +1,245 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"tyarus/weather-app/internal/dto"
+	"tyarus/weather-app/internal/usecase"
+	"tyarus/weather-app/pkg/response"
+)
+
+type locationGroupHandler struct {
+	locationGroupUc usecase.LocationGroupUsecaseInterface
+}
+
+func NewLocationGroupHandler(locationGroupUc usecase.LocationGroupUsecaseInterface) locationGroupHandler {
+	return locationGroupHandler{locationGroupUc: locationGroupUc}
+}
+
+func (h *locationGroupHandler) GetLocationGroupsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		pageSize, currentPage := 0, 0
+		var err error
+		if r.URL.Query().Get("pageSize") != "" {
+			pageSize, err = strconv.Atoi(r.URL.Query().Get("pageSize"))
+			if err != nil {
+				response.Error(w, http.StatusBadRequest, "invalid pageSize parameter, please check your parameter")
+				return
+			}
+		}
+
+		if r.URL.Query().Get("currentPage") != "" {
+			currentPage, err = strconv.Atoi(r.URL.Query().Get("currentPage"))
+			if err != nil {
+				response.Error(w, http.StatusBadRequest, "invalid currentPage parameter, please check your parameter")
+				return
+			}
+		}
+
+		groups, err := h.locationGroupUc.GetLocationGroupsUsecase(ctx, dto.GetLocationGroupsParam{
+			PageSize:    pageSize,
+			CurrentPage: currentPage,
+		})
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "error occurred on fetch location groups: "+err.Error())
+			return
+		}
+
+		response.JSON(w, http.StatusOK, "success", "fetch location groups successfully", groups)
+	}
+}
+
+func (h *locationGroupHandler) GetLocationGroupHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		groupID, err := parsePathID(r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid id parameter, please check your parameter")
+			return
+		}
+
+		group, err := h.locationGroupUc.GetLocationGroupUsecase(ctx, groupID)
+		if errors.Is(err, usecase.ErrLocationGroupNotFound) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "error occurred on fetch location group: "+err.Error())
+			return
+		}
+
+		response.JSON(w, http.StatusOK, "success", "fetch location group successfully", group)
+	}
+}
+
+func (h *locationGroupHandler) CreateLocationGroupHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		var req dto.PostLocationGroupRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+
+		result, err := h.locationGroupUc.CreateLocationGroupUsecase(ctx, req)
+		if errors.Is(err, usecase.ErrLocationGroupExists) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "failed to insert location group: "+err.Error())
+			return
+		}
+
+		response.JSON(w, http.StatusOK, "success", "create location group successfully", result)
+	}
+}
+
+func (h *locationGroupHandler) UpdateLocationGroupHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		groupID, err := parsePathID(r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid id parameter, please check your parameter")
+			return
+		}
+
+		var req dto.PostLocationGroupRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		req.ID = groupID
+
+		if err := req.Validate(); err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+
+		result, err := h.locationGroupUc.UpdateLocationGroupUsecase(ctx, req)
+		if errors.Is(err, usecase.ErrLocationGroupNotFound) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if errors.Is(err, usecase.ErrLocationGroupExists) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "failed to update location group: "+err.Error())
+			return
+		}
+
+		response.JSON(w, http.StatusOK, "success", "update location group successfully", result)
+	}
+}
+
+func (h *locationGroupHandler) DeleteLocationGroupHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		groupID, err := parsePathID(r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid id parameter, please check your parameter")
+			return
+		}
+
+		err = h.locationGroupUc.DeleteLocationGroupUsecase(ctx, groupID)
+		if errors.Is(err, usecase.ErrLocationGroupNotFound) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "failed to delete location group: "+err.Error())
+			return
+		}
+
+		response.JSON[any](w, http.StatusOK, "success", "delete location group successfully", nil)
+	}
+}
+
+func (h *locationGroupHandler) AddLocationGroupMembersHandler() http.HandlerFunc {
+	return h.changeLocationGroupMembers(h.locationGroupUc.AddLocationGroupMembersUsecase, "add locations to group")
+}
+
+func (h *locationGroupHandler) RemoveLocationGroupMembersHandler() http.HandlerFunc {
+	return h.changeLocationGroupMembers(h.locationGroupUc.RemoveLocationGroupMembersUsecase, "remove locations from group")
+}
+
+func (h *locationGroupHandler) changeLocationGroupMembers(
+	change func(ctx context.Context, req dto.LocationGroupMembersRequest) (dto.LocationGroupMembersResponse, error),
+	action string,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		groupID, err := parsePathID(r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid id parameter, please check your parameter")
+			return
+		}
+
+		var req dto.LocationGroupMembersRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		req.GroupID = groupID
+
+		if err := req.Validate(); err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+
+		result, err := change(ctx, req)
+		if errors.Is(err, usecase.ErrLocationGroupNotFound) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "failed to "+action+": "+err.Error())
+			return
+		}
+
+		response.JSON(w, http.StatusOK, "success", action+" successfully", result)
+	}
+}
+
+func (h *locationGroupHandler) SyncLocationGroupWeatherHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		groupID, err := parsePathID(r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid id parameter, please check your parameter")
+			return
+		}
+
+		var req dto.PostWeatherSyncUsecaseRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				response.Error(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+				return
+			}
+		}
+		req.GroupID = groupID
+
+		err = h.locationGroupUc.SyncLocationGroupWeatherUsecase(ctx, req)
+		if errors.Is(err, usecase.ErrLocationGroupNotFound) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "failed to sync weather: "+err.Error())
+			return
+		}
+
+		response.JSON(w, http.StatusOK, "success", "sync weather successfully", req)
+	}
+}
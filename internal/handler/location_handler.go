@@ -2,11 +2,21 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 	"tyarus/weather-app/internal/dto"
 	"tyarus/weather-app/internal/usecase"
+	"tyarus/weather-app/pkg/cursor"
+	"tyarus/weather-app/pkg/geo"
+	"tyarus/weather-app/pkg/language"
 	"tyarus/weather-app/pkg/response"
+	"tyarus/weather-app/pkg/utils"
+
+	"github.com/gorilla/mux"
 )
 
 type locationHandler struct {
@@ -38,13 +48,39 @@ func (h *locationHandler) GetLocationHandler() http.HandlerFunc {
 			}
 		}
 
+		var groupID int64
+		if r.URL.Query().Get("group") != "" {
+			groupID, err = strconv.ParseInt(r.URL.Query().Get("group"), 10, 64)
+			if err != nil {
+				response.Error(w, http.StatusBadRequest, "invalid group parameter, please check your parameter")
+				return
+			}
+		}
+
 		param := dto.GetLocationHandlerParam{
 			Query:       r.URL.Query().Get("query"),
+			NamePrefix:  r.URL.Query().Get("namePrefix"),
+			Country:     r.URL.Query().Get("country"),
+			Region:      r.URL.Query().Get("region"),
+			GroupID:     groupID,
+			Tag:         r.URL.Query().Get("tag"),
+			Cursor:      r.URL.Query().Get("cursor"),
 			SortBy:      r.URL.Query().Get("sortBy"),
 			PageSize:    pageSize,
 			CurrentPage: currentPage,
 		}
 
+		if err = parseLocationFilter(r, &param); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		param.Languages, err = parseLanguages(r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
 		err = param.Validate()
 		if err != nil {
 			response.Error(w, http.StatusBadRequest, err.Error())
@@ -52,6 +88,10 @@ func (h *locationHandler) GetLocationHandler() http.HandlerFunc {
 		}
 
 		locations, err := h.locationUc.GetLocationsUsecase(ctx, param)
+		if errors.Is(err, cursor.ErrInvalidCursor) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		if err != nil {
 			response.Error(w, http.StatusInternalServerError, "error occurred on fetch locations: "+err.Error())
 			return
@@ -77,6 +117,20 @@ func (h *locationHandler) CreateLocationHandler() http.HandlerFunc {
 		}
 
 		result, err := h.locationUc.CreateLocationUsecase(ctx, req)
+		var candidatesErr *usecase.LocationCandidatesError
+		if errors.As(err, &candidatesErr) {
+			response.JSON(w, http.StatusMultipleChoices, "error", candidatesErr.Error(), candidatesErr.Candidates)
+			return
+		}
+		var duplicateErr *usecase.DuplicateLocationError
+		if errors.As(err, &duplicateErr) {
+			response.JSON(w, http.StatusConflict, "error", duplicateErr.Error(), duplicateErr.Existing)
+			return
+		}
+		if errors.Is(err, usecase.ErrLocationNotResolved) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
 		if err != nil {
 			response.Error(w, http.StatusInternalServerError, "failed to insert location: "+err.Error())
 			return
@@ -85,3 +139,389 @@ func (h *locationHandler) CreateLocationHandler() http.HandlerFunc {
 		response.JSON(w, http.StatusOK, "success", "create location successfully", result)
 	}
 }
+
+func (h *locationHandler) GetNearbyLocationsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		param, err := parseNearbyParam(r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if param.Languages, err = parseLanguages(r); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		locations, err := h.locationUc.GetNearbyLocationsUsecase(ctx, param)
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "error occurred on fetch nearby locations: "+err.Error())
+			return
+		}
+
+		response.JSON(w, http.StatusOK, "success", "fetch nearby locations successfully", locations)
+	}
+}
+
+func (h *locationHandler) SuggestLocationsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		param := dto.SuggestLocationsParam{Query: r.URL.Query().Get("q")}
+		if r.URL.Query().Get("limit") != "" {
+			limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+			if err != nil {
+				response.Error(w, http.StatusBadRequest, "invalid limit parameter, please check your parameter")
+				return
+			}
+			param.Limit = limit
+		}
+
+		if err := param.Validate(); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		suggestions, err := h.locationUc.SuggestLocationsUsecase(ctx, param)
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "error occurred on suggest locations: "+err.Error())
+			return
+		}
+
+		response.JSON(w, http.StatusOK, "success", "suggest locations successfully", suggestions)
+	}
+}
+
+func (h *locationHandler) MergeLocationHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		locationID, err := parsePathID(r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid id parameter, please check your parameter")
+			return
+		}
+
+		var req dto.PostMergeLocationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		req.CanonicalID = locationID
+
+		if err := req.Validate(); err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+
+		result, err := h.locationUc.MergeLocationsUsecase(ctx, req)
+		if errors.Is(err, usecase.ErrLocationNotFound) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "failed to merge locations: "+err.Error())
+			return
+		}
+
+		response.JSON(w, http.StatusOK, "success", "merge locations successfully", result)
+	}
+}
+
+func (h *locationHandler) ImportLocationsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		format, err := dto.DetectLocationFormat(r.URL.Query().Get("format"), r.Header.Get("Content-Type"))
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		dryRun := false
+		if r.URL.Query().Get("dryRun") != "" {
+			dryRun, err = strconv.ParseBool(r.URL.Query().Get("dryRun"))
+			if err != nil {
+				response.Error(w, http.StatusBadRequest, "invalid dryRun parameter, please check your parameter")
+				return
+			}
+		}
+
+		body := http.MaxBytesReader(w, r.Body, int64(utils.MaxLocationImportSize))
+		rows, err := dto.ParseLocationImport(format, body)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+
+		result, err := h.locationUc.ImportLocationsUsecase(ctx, dto.LocationImportRequest{Rows: rows, DryRun: dryRun})
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "failed to import locations: "+err.Error())
+			return
+		}
+
+		response.JSON(w, http.StatusOK, "success", "import locations successfully", result)
+	}
+}
+
+func (h *locationHandler) ExportLocationsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		var err error
+		format := r.URL.Query().Get("format")
+		if format == "" {
+			format = dto.LocationFormatJSON
+		}
+		if !dto.LocationFormatMaps[format] {
+			response.Error(w, http.StatusBadRequest, "invalid format parameter, only allow csv, json, geojson")
+			return
+		}
+
+		param := dto.LocationExportParam{}
+		if offset := r.URL.Query().Get("offset"); offset != "" {
+			param.Offset, err = strconv.Atoi(offset)
+			if err != nil || param.Offset < 0 {
+				response.Error(w, http.StatusBadRequest, "invalid offset parameter, please check your parameter")
+				return
+			}
+		}
+
+		page, err := h.locationUc.ExportLocationsUsecase(ctx, param)
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "error occurred on export locations: "+err.Error())
+			return
+		}
+
+		contentTypes := map[string]string{
+			dto.LocationFormatCSV:     "text/csv",
+			dto.LocationFormatJSON:    "application/json",
+			dto.LocationFormatGeoJSON: "application/geo+json",
+		}
+		w.Header().Set("Content-Type", contentTypes[format])
+		w.Header().Set("Content-Disposition", "attachment; filename=locations."+format)
+		if page.NextOffset > 0 {
+			w.Header().Set("X-Next-Offset", strconv.Itoa(page.NextOffset))
+		}
+		w.WriteHeader(http.StatusOK)
+
+		if err := dto.WriteLocationExport(w, format, page.Items); err != nil {
+			log.Println("[ERROR LOG] failed to write locations export: ", err)
+		}
+	}
+}
+
+func (h *locationHandler) SetLocationTagsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		locationID, err := parsePathID(r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid id parameter, please check your parameter")
+			return
+		}
+
+		var req dto.PutLocationTagsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		req.LocationID = locationID
+
+		if err := req.Validate(); err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+
+		if req.Languages, err = parseLanguages(r); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		result, err := h.locationUc.SetLocationTagsUsecase(ctx, req)
+		if errors.Is(err, usecase.ErrLocationNotFound) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "failed to set location tags: "+err.Error())
+			return
+		}
+
+		response.JSON(w, http.StatusOK, "success", "set location tags successfully", result)
+	}
+}
+
+func (h *locationHandler) SetLocationNamesHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		locationID, err := parsePathID(r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid id parameter, please check your parameter")
+			return
+		}
+
+		var req dto.PutLocationNamesRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+		req.LocationID = locationID
+
+		if err := req.Validate(); err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+
+		if req.Languages, err = parseLanguages(r); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		result, err := h.locationUc.SetLocationNamesUsecase(ctx, req)
+		if errors.Is(err, usecase.ErrLocationNotFound) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "failed to set location names: "+err.Error())
+			return
+		}
+
+		response.JSON(w, http.StatusOK, "success", "set location names successfully", result)
+	}
+}
+
+// parseLanguages returns the requested languages, most preferred first. The
+// lang query parameter wins over the Accept-Language header.
+func parseLanguages(r *http.Request) ([]string, error) {
+	if lang := r.URL.Query().Get("lang"); lang != "" {
+		lang = language.Normalize(lang)
+		if !language.Valid(lang) {
+			return nil, errors.New("invalid lang parameter, expected a language tag such as id or en-US")
+		}
+		return []string{lang}, nil
+	}
+
+	return language.ParseAcceptLanguage(r.Header.Get("Accept-Language")), nil
+}
+
+// parsePathID reads the {id} route variable.
+func parsePathID(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil || id <= 0 {
+		return 0, errors.New("invalid id parameter")
+	}
+	return id, nil
+}
+
+// parseLocationFilter reads the bbox, createdFrom, createdTo and
+// includeDeleted query parameters.
+func parseLocationFilter(r *http.Request, param *dto.GetLocationHandlerParam) error {
+	query := r.URL.Query()
+	box, err := parseBoundingBox(query.Get("bbox"))
+	if err != nil {
+		return err
+	}
+	param.BoundingBox = box
+
+	if query.Get("createdFrom") != "" {
+		if param.CreatedFrom, err = parseTimeParam(query.Get("createdFrom")); err != nil {
+			return errors.New("invalid createdFrom parameter, use RFC3339 or YYYY-MM-DD")
+		}
+	}
+
+	if query.Get("createdTo") != "" {
+		if param.CreatedTo, err = parseTimeParam(query.Get("createdTo")); err != nil {
+			return errors.New("invalid createdTo parameter, use RFC3339 or YYYY-MM-DD")
+		}
+	}
+
+	if query.Get("includeDeleted") != "" {
+		if param.IncludeDeleted, err = strconv.ParseBool(query.Get("includeDeleted")); err != nil {
+			return errors.New("invalid includeDeleted parameter, please check your parameter")
+		}
+	}
+
+	return nil
+}
+
+// parseBoundingBox reads a minLon,minLat,maxLon,maxLat box like in GeoJSON,
+// it is nil when value is empty.
+func parseBoundingBox(value string) (*geo.BoundingBox, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	parts := strings.Split(value, ",")
+	if len(parts) != 4 {
+		return nil, errors.New("invalid bbox parameter, expected minLon,minLat,maxLon,maxLat")
+	}
+
+	values := make([]float64, len(parts))
+	for i, part := range parts {
+		number, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return nil, errors.New("invalid bbox parameter, expected minLon,minLat,maxLon,maxLat")
+		}
+		values[i] = number
+	}
+
+	return &geo.BoundingBox{
+		MinLongitude: values[0],
+		MinLatitude:  values[1],
+		MaxLongitude: values[2],
+		MaxLatitude:  values[3],
+	}, nil
+}
+
+// parseTimeParam accepts a full RFC3339 timestamp or a plain date.
+func parseTimeParam(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(utils.DateFormat, value)
+}
+
+// parseNearbyParam reads the lat, lon, radiusKm and limit query parameters.
+func parseNearbyParam(r *http.Request) (dto.GetNearbyLocationsParam, error) {
+	param, err := parsePointParam(r)
+	if err != nil {
+		return param, err
+	}
+
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		param.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return param, errors.New("invalid limit parameter, please check your parameter")
+		}
+	}
+
+	return param, param.Validate()
+}
+
+// parsePointParam reads the lat, lon and radiusKm query parameters.
+func parsePointParam(r *http.Request) (dto.GetNearbyLocationsParam, error) {
+	param := dto.GetNearbyLocationsParam{}
+	query := r.URL.Query()
+	if query.Get("lat") == "" || query.Get("lon") == "" {
+		return param, errors.New("lat and lon parameter is empty, please check your parameter")
+	}
+
+	var err error
+	param.Latitude, err = strconv.ParseFloat(query.Get("lat"), 64)
+	if err != nil {
+		return param, errors.New("invalid lat parameter, please check your parameter")
+	}
+
+	param.Longitude, err = strconv.ParseFloat(query.Get("lon"), 64)
+	if err != nil {
+		return param, errors.New("invalid lon parameter, please check your parameter")
+	}
+
+	if query.Get("radiusKm") != "" {
+		param.RadiusKm, err = strconv.ParseFloat(query.Get("radiusKm"), 64)
+		if err != nil {
+			return param, errors.New("invalid radiusKm parameter, please check your parameter")
+		}
+	}
+
+	return param, param.Validate()
+}
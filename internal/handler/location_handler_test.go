@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"tyarus/weather-app/internal/dto"
+	"tyarus/weather-app/pkg/geo"
+)
+
+func TestParseTimeParam(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Time
+		wantErr  bool
+	}{
+		{"WHEN value is a date, THEN should return its midnight in UTC", "2024-03-01", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), false},
+		{"WHEN value is RFC3339 in UTC, THEN should keep the time", "2024-03-01T10:30:00Z", time.Date(2024, 3, 1, 10, 30, 0, 0, time.UTC), false},
+		{"WHEN value has an offset, THEN should keep the instant", "2024-03-01T10:30:00+07:00", time.Date(2024, 3, 1, 3, 30, 0, 0, time.UTC), false},
+		{"WHEN value has no zone, THEN should return error", "2024-03-01T10:30:00", time.Time{}, true},
+		{"WHEN value is not a date, THEN should return error", "yesterday", time.Time{}, true},
+		{"WHEN date does not exist, THEN should return error", "2024-02-30", time.Time{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseTimeParam(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, tt.expected.Equal(result), "expected %s, got %s", tt.expected, result)
+		})
+	}
+}
+
+func TestParseLocationFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected dto.GetLocationHandlerParam
+		err      string
+	}{
+		{
+			name:     "WHEN no filter is given, THEN should leave the param empty",
+			query:    "",
+			expected: dto.GetLocationHandlerParam{},
+		},
+		{
+			name:  "WHEN every filter is given, THEN should read them",
+			query: "bbox=106.5,-6.5,107,-6&createdFrom=2024-01-01&createdTo=2024-02-01T12:00:00Z&includeDeleted=true",
+			expected: dto.GetLocationHandlerParam{
+				BoundingBox:    &geo.BoundingBox{MinLongitude: 106.5, MinLatitude: -6.5, MaxLongitude: 107, MaxLatitude: -6},
+				CreatedFrom:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+				CreatedTo:      time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC),
+				IncludeDeleted: true,
+			},
+		},
+		{
+			name:  "WHEN bbox has three values, THEN should return error",
+			query: "bbox=106.5,-6.5,107",
+			err:   "invalid bbox parameter",
+		},
+		{
+			name:  "WHEN bbox value is not a number, THEN should return error",
+			query: "bbox=106.5,-6.5,east,-6",
+			err:   "invalid bbox parameter",
+		},
+		{
+			name:  "WHEN createdFrom is malformed, THEN should return error",
+			query: "createdFrom=01-01-2024",
+			err:   "invalid createdFrom parameter",
+		},
+		{
+			name:  "WHEN createdTo is malformed, THEN should return error",
+			query: "createdTo=2024-01-01T25:00:00Z",
+			err:   "invalid createdTo parameter",
+		},
+		{
+			name:  "WHEN includeDeleted is not a bool, THEN should return error",
+			query: "includeDeleted=maybe",
+			err:   "invalid includeDeleted parameter",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/api/v1/locations?"+tt.query, nil)
+			param := dto.GetLocationHandlerParam{}
+
+			err := parseLocationFilter(req, &param)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, param)
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"tyarus/weather-app/pkg/response"
+
+	"github.com/gorilla/mux"
+)
+
+// AdminKeyHeader carries the key of admin requests.
+const AdminKeyHeader = "X-Admin-Key"
+
+// APIKeyHeader carries the key of API clients, which also picks their
+// default units.
+const APIKeyHeader = "X-API-Key"
+
+// RequireAdminKey rejects requests without the admin key. Every request is
+// rejected when no key is configured, admin endpoints are then disabled.
+func RequireAdminKey(key string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if key == "" {
+				response.Error(w, http.StatusForbidden, "admin endpoints are disabled, please configure ADMIN_API_KEY")
+				return
+			}
+
+			if subtle.ConstantTimeCompare([]byte(r.Header.Get(AdminKeyHeader)), []byte(key)) != 1 {
+				response.Error(w, http.StatusUnauthorized, "invalid admin key, please check your "+AdminKeyHeader+" header")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequireAPIKey rejects requests without one of the keys. Every request is
+// rejected when no key is configured, the endpoints are then disabled.
+func RequireAPIKey(keys []string, setting string) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(keys) == 0 {
+				response.Error(w, http.StatusForbidden, "endpoint is disabled, please configure "+setting)
+				return
+			}
+
+			header := []byte(r.Header.Get(APIKeyHeader))
+			for _, key := range keys {
+				if subtle.ConstantTimeCompare(header, []byte(key)) == 1 {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+
+			response.Error(w, http.StatusUnauthorized, "invalid API key, please check your "+APIKeyHeader+" header")
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireAdminKey(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name       string
+		key        string
+		header     string
+		statusCode int
+	}{
+		{"WHEN no key is configured, THEN should reject every request", "", "", http.StatusForbidden},
+		{"WHEN header is missing, THEN should reject the request", "secret", "", http.StatusUnauthorized},
+		{"WHEN header is wrong, THEN should reject the request", "secret", "guess", http.StatusUnauthorized},
+		{"WHEN header matches, THEN should call the handler", "secret", "secret", http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/locations/1/merge", nil)
+			if tt.header != "" {
+				req.Header.Set(AdminKeyHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			RequireAdminKey(tt.key)(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+		})
+	}
+}
+
+func TestRequireAPIKey(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name       string
+		keys       []string
+		header     string
+		statusCode int
+	}{
+		{"WHEN no key is configured, THEN should reject every request", nil, "", http.StatusForbidden},
+		{"WHEN header is missing, THEN should reject the request", []string{"first"}, "", http.StatusUnauthorized},
+		{"WHEN header is wrong, THEN should reject the request", []string{"first", "second"}, "guess", http.StatusUnauthorized},
+		{"WHEN header matches any key, THEN should call the handler", []string{"first", "second"}, "second", http.StatusNoContent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/weathers/export", nil)
+			if tt.header != "" {
+				req.Header.Set(APIKeyHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+
+			RequireAPIKey(tt.keys, "EXPORT_API_KEYS")(next).ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.statusCode, rec.Code)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"compress/gzip"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"tyarus/weather-app/internal/dto"
+	"tyarus/weather-app/internal/usecase"
+	"tyarus/weather-app/pkg/response"
+	"tyarus/weather-app/pkg/utils"
+)
+
+// ExportTruncatedTrailer is set to true after the last row of an export cut
+// short at utils.MaxExportRows.
+const ExportTruncatedTrailer = "X-Export-Truncated"
+
+type weatherExportHandler struct {
+	exportUc usecase.WeatherExportUsecaseInterface
+	// baseURL is the configured public base URL, empty to follow the request
+	baseURL string
+}
+
+func NewWeatherExportHandler(exportUc usecase.WeatherExportUsecaseInterface, publicBaseURL string) weatherExportHandler {
+	return weatherExportHandler{exportUc: exportUc, baseURL: publicBaseURL}
+}
+
+func (h *weatherExportHandler) ExportWeathersHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		query := r.URL.Query()
+		param, err := dto.ParseExportWeathersQuery(query)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err := param.ValidateRange(utils.MaxExportRangeDays); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		param.MaxRows = utils.MaxExportRows
+		param.BaseURL = requestBaseURL(h.baseURL, r)
+
+		download := false
+		if query.Get("gzip") != "" {
+			if download, err = strconv.ParseBool(query.Get("gzip")); err != nil {
+				response.Error(w, http.StatusBadRequest, "invalid gzip parameter, please check your parameter")
+				return
+			}
+		}
+
+		// gzip=true downloads a .gz file, otherwise clients accepting gzip get
+		// a compressed transfer of the plain file
+		contentTypes := map[string]string{
+			dto.WeatherExportFormatCSV:    "text/csv",
+			dto.WeatherExportFormatNDJSON: "application/x-ndjson",
+		}
+		filename := "weathers." + param.Format
+		compress := download || acceptsGzip(r.Header.Get("Accept-Encoding"))
+		if download {
+			filename += ".gz"
+			w.Header().Set("Content-Type", "application/gzip")
+		} else {
+			w.Header().Set("Content-Type", contentTypes[param.Format])
+			w.Header().Set("Vary", "Accept-Encoding")
+			if compress {
+				w.Header().Set("Content-Encoding", "gzip")
+			}
+		}
+		w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+		w.Header().Set("Trailer", ExportTruncatedTrailer)
+		w.WriteHeader(http.StatusOK)
+
+		var out io.Writer = w
+		var gz *gzip.Writer
+		if compress {
+			gz = gzip.NewWriter(w)
+			out = gz
+		}
+
+		// the status is already sent, a failure can only cut the stream short
+		result, err := h.exportUc.ExportWeathersUsecase(ctx, param, out)
+		if err != nil {
+			log.Println("[ERROR LOG] failed to write weathers export: ", err)
+		}
+
+		if gz != nil {
+			if err := gz.Close(); err != nil {
+				log.Println("[ERROR LOG] failed to finish weathers export: ", err)
+			}
+		}
+		w.Header().Set(ExportTruncatedTrailer, strconv.FormatBool(result.Truncated))
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip, by name
+// or through "*", with a quality above zero. An explicit gzip entry wins
+// over "*".
+func acceptsGzip(header string) bool {
+	gzipQuality, anyQuality := -1.0, -1.0
+	for _, item := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(item, ";")
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(name), "q") {
+				continue
+			}
+
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				parsed = 0
+			}
+			quality = parsed
+		}
+
+		switch strings.ToLower(strings.TrimSpace(coding)) {
+		case "gzip", "x-gzip":
+			gzipQuality = quality
+		case "*":
+			anyQuality = quality
+		}
+	}
+
+	if gzipQuality >= 0 {
+		return gzipQuality > 0
+	}
+	return anyQuality > 0
+}
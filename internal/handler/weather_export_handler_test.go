@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"tyarus/weather-app/internal/dto"
+	"tyarus/weather-app/mocks"
+	"tyarus/weather-app/pkg/utils"
+)
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected bool
+	}{
+		{"WHEN header is empty, THEN should not compress", "", false},
+		{"WHEN gzip is listed, THEN should compress", "deflate, gzip", true},
+		{"WHEN gzip has a quality, THEN should compress", "gzip;q=0.5, br", true},
+		{"WHEN gzip has quality zero, THEN should not compress", "gzip;q=0, br", false},
+		{"WHEN gzip is refused with spaces, THEN should not compress", "br, gzip ; q=0.0", false},
+		{"WHEN only any coding is accepted, THEN should compress", "*", true},
+		{"WHEN gzip is refused but any coding accepted, THEN should not compress", "*, gzip;q=0", false},
+		{"WHEN any coding is refused, THEN should not compress", "br, *;q=0", false},
+		{"WHEN gzip is only a prefix, THEN should not compress", "gzipped", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, acceptsGzip(tt.header))
+		})
+	}
+}
+
+func TestExportWeathersHandler(t *testing.T) {
+	t.Run("WHEN range is missing, THEN should reject the export", func(t *testing.T) {
+		mockExportUc := mocks.NewWeatherExportUsecaseInterface(t)
+		handler := NewWeatherExportHandler(mockExportUc, "")
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/weathers/export?from=2024-01-01", nil)
+		rec := httptest.NewRecorder()
+		handler.ExportWeathersHandler().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("WHEN range is too long, THEN should reject the export", func(t *testing.T) {
+		mockExportUc := mocks.NewWeatherExportUsecaseInterface(t)
+		handler := NewWeatherExportHandler(mockExportUc, "")
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/weathers/export?from=2023-01-01&to=2024-06-01", nil)
+		rec := httptest.NewRecorder()
+		handler.ExportWeathersHandler().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("WHEN rows are left past the cap, THEN should mark the export as truncated", func(t *testing.T) {
+		mockExportUc := mocks.NewWeatherExportUsecaseInterface(t)
+		handler := NewWeatherExportHandler(mockExportUc, "")
+
+		mockExportUc.On("ExportWeathersUsecase", mock.Anything, mock.MatchedBy(func(param dto.ExportWeathersParam) bool {
+			return param.MaxRows == utils.MaxExportRows && param.Format == dto.WeatherExportFormatCSV
+		}), mock.Anything).Return(dto.ExportWeathersResult{Rows: int64(utils.MaxExportRows), Truncated: true}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/weathers/export?from=2024-01-01&to=2024-02-01", nil)
+		req.Header.Set("Accept-Encoding", "gzip;q=0")
+		rec := httptest.NewRecorder()
+		handler.ExportWeathersHandler().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "Accept-Encoding", rec.Header().Get("Vary"))
+		assert.Empty(t, rec.Header().Get("Content-Encoding"))
+		assert.Equal(t, "true", rec.Result().Trailer.Get(ExportTruncatedTrailer))
+	})
+}
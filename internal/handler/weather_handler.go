@@ -1,20 +1,34 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 	"tyarus/weather-app/internal/dto"
 	"tyarus/weather-app/internal/usecase"
+	"tyarus/weather-app/pkg/cursor"
 	"tyarus/weather-app/pkg/response"
+	"tyarus/weather-app/pkg/units"
+	"tyarus/weather-app/pkg/utils"
+	"tyarus/weather-app/pkg/weather/derive"
+
+	"github.com/gorilla/mux"
 )
 
 type weatherHandler struct {
 	weatherUc usecase.WeatherUsecaseInterface
+	units     units.Preferences
+	alerts    []derive.Rule
+	// baseURL is the configured public base URL, empty to follow the request
+	baseURL string
 }
 
-func NewWeatherHandler(weatherUc usecase.WeatherUsecaseInterface) weatherHandler {
-	return weatherHandler{weatherUc: weatherUc}
+func NewWeatherHandler(weatherUc usecase.WeatherUsecaseInterface, unitPrefs units.Preferences, alertRules []derive.Rule, publicBaseURL string) weatherHandler {
+	return weatherHandler{weatherUc: weatherUc, units: unitPrefs, alerts: alertRules, baseURL: publicBaseURL}
 }
 
 func (h *weatherHandler) GetWeathersHandler() http.HandlerFunc {
@@ -49,13 +63,40 @@ func (h *weatherHandler) GetWeathersHandler() http.HandlerFunc {
 			return
 		}
 
+		system, err := parseUnits(h.units, r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
 		param := dto.GetWeathersParam{
 			PageSize:    pageSize,
 			CurrentPage: currentPage,
 			LocationID:  locationID,
+			Cursor:      r.URL.Query().Get("cursor"),
+			Units:       system,
+			BaseURL:     requestBaseURL(h.baseURL, r),
+			AlertRules:  h.alerts,
+		}
+
+		if r.URL.Query().Get("summary") != "" {
+			param.Summary, err = strconv.ParseBool(r.URL.Query().Get("summary"))
+			if err != nil {
+				response.Error(w, http.StatusBadRequest, "invalid summary parameter, please check your parameter")
+				return
+			}
+		}
+
+		if param.Languages, err = parseLanguages(r); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
 		}
 
 		weathers, err := h.weatherUc.GetWeathersUsecase(ctx, param)
+		if errors.Is(err, cursor.ErrInvalidCursor) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		if err != nil {
 			response.Error(w, http.StatusInternalServerError, "error occurred on fetch weathers: "+err.Error())
 			return
@@ -83,3 +124,528 @@ func (h *weatherHandler) SyncWeatherHandler() http.HandlerFunc {
 		response.JSON(w, http.StatusOK, "success", "sync weather successfully", req)
 	}
 }
+
+func (h *weatherHandler) GetWeathersBatchHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		locationIDs, err := parseIntList(r.URL.Query().Get("locationIDs"))
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid locationIDs parameter, please check your parameter")
+			return
+		}
+
+		param := dto.GetWeathersBatchParam{LocationIDs: locationIDs, Tag: r.URL.Query().Get("tag")}
+		if r.URL.Query().Get("group") != "" {
+			param.GroupID, err = strconv.ParseInt(r.URL.Query().Get("group"), 10, 64)
+			if err != nil {
+				response.Error(w, http.StatusBadRequest, "invalid group parameter, please check your parameter")
+				return
+			}
+		}
+
+		if r.URL.Query().Get("includeForecast") != "" {
+			param.IncludeForecast, err = strconv.ParseBool(r.URL.Query().Get("includeForecast"))
+			if err != nil {
+				response.Error(w, http.StatusBadRequest, "invalid includeForecast parameter, please check your parameter")
+				return
+			}
+		}
+
+		if r.URL.Query().Get("forecastDays") != "" {
+			param.ForecastDays, err = strconv.Atoi(r.URL.Query().Get("forecastDays"))
+			if err != nil {
+				response.Error(w, http.StatusBadRequest, "invalid forecastDays parameter, please check your parameter")
+				return
+			}
+		}
+
+		if r.URL.Query().Get("summary") != "" {
+			param.Summary, err = strconv.ParseBool(r.URL.Query().Get("summary"))
+			if err != nil {
+				response.Error(w, http.StatusBadRequest, "invalid summary parameter, please check your parameter")
+				return
+			}
+		}
+
+		h.getWeathersBatch(w, r, param)
+	}
+}
+
+func (h *weatherHandler) PostWeathersBatchHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var param dto.GetWeathersBatchParam
+		if err := json.NewDecoder(r.Body).Decode(&param); err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+			return
+		}
+
+		h.getWeathersBatch(w, r, param)
+	}
+}
+
+func (h *weatherHandler) getWeathersBatch(w http.ResponseWriter, r *http.Request, param dto.GetWeathersBatchParam) {
+	if err := param.Validate(); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	system, err := parseUnits(h.units, r)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	param.Units = system
+	param.BaseURL = requestBaseURL(h.baseURL, r)
+
+	if param.Languages, err = parseLanguages(r); err != nil {
+		response.Error(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	weathers, err := h.weatherUc.GetWeathersBatchUsecase(r.Context(), param)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "error occurred on fetch weathers: "+err.Error())
+		return
+	}
+
+	response.JSON(w, http.StatusOK, "success", "fetch weathers successfully", weathers)
+}
+
+func (h *weatherHandler) GetPointWeatherHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		nearbyParam, err := parsePointParam(r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		system, err := parseUnits(h.units, r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		languages, err := parseLanguages(r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		weather, err := h.weatherUc.GetPointWeatherUsecase(ctx, dto.GetPointWeatherParam{
+			GetNearbyLocationsParam: nearbyParam,
+			Units:                   system,
+			Languages:               languages,
+			BaseURL:                 requestBaseURL(h.baseURL, r),
+		})
+		if errors.Is(err, usecase.ErrNoNearbyLocation) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "error occurred on fetch point weather: "+err.Error())
+			return
+		}
+
+		response.JSON(w, http.StatusOK, "success", "fetch point weather successfully", weather)
+	}
+}
+
+func (h *weatherHandler) GetDailyWeatherHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		locationID, err := parsePathID(r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid id parameter, please check your parameter")
+			return
+		}
+
+		param := dto.GetDailyWeatherParam{LocationID: locationID}
+		if param.From, param.To, err = parseDateRange(r); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if param.Units, err = parseUnits(h.units, r); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err := param.Validate(); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		daily, err := h.weatherUc.GetDailyWeatherUsecase(ctx, param)
+		if errors.Is(err, usecase.ErrLocationNotFound) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "error occurred on fetch daily weather: "+err.Error())
+			return
+		}
+
+		response.JSON(w, http.StatusOK, "success", "fetch daily weather successfully", daily)
+	}
+}
+
+func (h *weatherHandler) GetWeatherStatsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		locationID, err := parsePathID(r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid id parameter, please check your parameter")
+			return
+		}
+
+		param := dto.GetWeatherStatsParam{
+			LocationID: locationID,
+			Period:     strings.ToLower(r.URL.Query().Get("period")),
+		}
+		if param.From, param.To, err = parseDateRange(r); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if param.Units, err = parseUnits(h.units, r); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err := param.Validate(); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		result, err := h.weatherUc.GetWeatherStatsUsecase(ctx, param)
+		if errors.Is(err, usecase.ErrLocationNotFound) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "error occurred on fetch weather stats: "+err.Error())
+			return
+		}
+
+		response.JSON(w, http.StatusOK, "success", "fetch weather stats successfully", result)
+	}
+}
+
+func (h *weatherHandler) CompareWeathersHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		locationIDs, err := parseIntList(r.URL.Query().Get("locationIDs"))
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid locationIDs parameter, please check your parameter")
+			return
+		}
+
+		param := dto.CompareWeathersParam{LocationIDs: locationIDs}
+		for _, metric := range strings.Split(r.URL.Query().Get("metrics"), ",") {
+			if metric = strings.TrimSpace(metric); metric != "" {
+				param.Metrics = append(param.Metrics, metric)
+			}
+		}
+
+		if value := r.URL.Query().Get("from"); value != "" {
+			if param.From, err = dto.ParseLocalTime(value); err != nil {
+				response.Error(w, http.StatusBadRequest, "invalid from parameter, use RFC3339 or YYYY-MM-DD")
+				return
+			}
+		}
+
+		if value := r.URL.Query().Get("to"); value != "" {
+			if param.To, err = dto.ParseLocalTime(value); err != nil {
+				response.Error(w, http.StatusBadRequest, "invalid to parameter, use RFC3339 or YYYY-MM-DD")
+				return
+			}
+		}
+
+		if param.Units, err = parseUnits(h.units, r); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err := param.Validate(); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		result, err := h.weatherUc.CompareWeathersUsecase(ctx, param)
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "error occurred on compare weathers: "+err.Error())
+			return
+		}
+
+		response.JSON(w, http.StatusOK, "success", "compare weathers successfully", result)
+	}
+}
+
+func (h *weatherHandler) GetMapLayerHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		box, err := parseBoundingBox(r.URL.Query().Get("bbox"))
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		param := dto.GetMapLayerParam{BoundingBox: box, BaseURL: requestBaseURL(h.baseURL, r)}
+		if value := r.URL.Query().Get("forecastTime"); value != "" {
+			if param.ForecastTime, err = dto.ParseLocalTime(value); err != nil {
+				response.Error(w, http.StatusBadRequest, "invalid forecastTime parameter, use RFC3339 or YYYY-MM-DD")
+				return
+			}
+		}
+
+		if param.Units, err = parseUnits(h.units, r); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err := param.Validate(); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		layer, err := h.weatherUc.GetMapLayerUsecase(ctx, param)
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "error occurred on build map layer: "+err.Error())
+			return
+		}
+
+		body, err := json.Marshal(layer.Collection)
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "error occurred on build map layer: "+err.Error())
+			return
+		}
+
+		varyUnits(w, r)
+		response.Cached(w, r, "application/geo+json", body, layer.LastModified, utils.MapLayerMaxAge)
+	}
+}
+
+// GetIconHandler serves a provider icon from the local cache, so clients
+// never reach the provider's CDN.
+func (h *weatherHandler) GetIconHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		icon, err := h.weatherUc.GetIconUsecase(r.Context(), mux.Vars(r)["code"])
+		if errors.Is(err, usecase.ErrIconNotFound) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "error occurred on fetch icon: "+err.Error())
+			return
+		}
+
+		response.Cached(w, r, "image/png", icon, time.Time{}, utils.IconMaxAge)
+	}
+}
+
+func (h *weatherHandler) GetWeatherChartHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		locationID, err := parsePathID(r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid id parameter, please check your parameter")
+			return
+		}
+
+		query := r.URL.Query()
+		param := dto.GetWeatherChartParam{
+			LocationID: locationID,
+			Metric:     query.Get("metric"),
+			Theme:      query.Get("theme"),
+		}
+
+		if value := query.Get("range"); value != "" {
+			if param.Range, err = time.ParseDuration(value); err != nil || param.Range%time.Hour != 0 {
+				response.Error(w, http.StatusBadRequest, "invalid range parameter, use whole hours e.g. 72h")
+				return
+			}
+		}
+
+		if value := query.Get("width"); value != "" {
+			if param.Width, err = strconv.Atoi(value); err != nil {
+				response.Error(w, http.StatusBadRequest, "invalid width parameter, please check your parameter")
+				return
+			}
+		}
+
+		if value := query.Get("height"); value != "" {
+			if param.Height, err = strconv.Atoi(value); err != nil {
+				response.Error(w, http.StatusBadRequest, "invalid height parameter, please check your parameter")
+				return
+			}
+		}
+
+		if param.Units, err = parseUnits(h.units, r); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		if err := param.Validate(); err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		chart, err := h.weatherUc.GetWeatherChartUsecase(ctx, param)
+		if errors.Is(err, usecase.ErrLocationNotFound) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "error occurred on render chart: "+err.Error())
+			return
+		}
+
+		varyUnits(w, r)
+		response.Cached(w, r, "image/svg+xml", chart.Body, chart.LastModified, utils.ChartMaxAge)
+	}
+}
+
+func (h *weatherHandler) GetForecastCalendarHandler() http.HandlerFunc {
+	return h.forecastFeedHandler("text/calendar; charset=utf-8", h.weatherUc.GetForecastCalendarUsecase)
+}
+
+func (h *weatherHandler) GetForecastFeedHandler() http.HandlerFunc {
+	return h.forecastFeedHandler("application/atom+xml; charset=utf-8", h.weatherUc.GetForecastFeedUsecase)
+}
+
+func (h *weatherHandler) forecastFeedHandler(contentType string, render func(context.Context, dto.GetForecastFeedParam) (dto.ForecastFeedDocument, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		locationID, err := parsePathID(r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid id parameter, please check your parameter")
+			return
+		}
+
+		system, err := parseUnits(h.units, r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		languages, err := parseLanguages(r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		baseURL := requestBaseURL(h.baseURL, r)
+		document, err := render(ctx, dto.GetForecastFeedParam{
+			LocationID: locationID,
+			Units:      system,
+			Languages:  languages,
+			SelfURL:    baseURL + r.URL.RequestURI(),
+			BaseURL:    baseURL,
+			AlertRules: h.alerts,
+		})
+		if errors.Is(err, usecase.ErrLocationNotFound) {
+			response.Error(w, http.StatusNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "error occurred on render forecast: "+err.Error())
+			return
+		}
+
+		// condition texts follow Accept-Language unless lang is given
+		w.Header().Add("Vary", "Accept-Language")
+		varyUnits(w, r)
+		response.Cached(w, r, contentType, document.Body, document.LastModified, utils.ForecastFeedMaxAge)
+	}
+}
+
+// parseDateRange reads the optional from and to query parameters as dates of
+// the location, labelled UTC like forecast times.
+func parseDateRange(r *http.Request) (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+	if value := r.URL.Query().Get("from"); value != "" {
+		if from, err = time.Parse(utils.DateFormat, value); err != nil {
+			return from, to, errors.New("invalid from parameter, use YYYY-MM-DD")
+		}
+	}
+
+	if value := r.URL.Query().Get("to"); value != "" {
+		if to, err = time.Parse(utils.DateFormat, value); err != nil {
+			return from, to, errors.New("invalid to parameter, use YYYY-MM-DD")
+		}
+	}
+
+	return from, to, nil
+}
+
+// parseUnits reads the units query parameter, falling back to the default of
+// the caller's X-API-Key and then to the configured default.
+func parseUnits(prefs units.Preferences, r *http.Request) (units.System, error) {
+	system, err := prefs.Resolve(r.URL.Query().Get("units"), r.Header.Get(APIKeyHeader))
+	if err != nil {
+		return "", errors.New("invalid units parameter, only allow metric, imperial, si")
+	}
+
+	return system, nil
+}
+
+// varyUnits tells shared caches that the body depends on the X-API-Key
+// header when the units were left to the default of the key.
+func varyUnits(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("units") == "" {
+		w.Header().Add("Vary", APIKeyHeader)
+	}
+}
+
+// requestBaseURL is the configured public base URL. Without one it is the
+// scheme and host the request was sent to, honouring an http or https
+// X-Forwarded-Proto header of a TLS terminating proxy, any other value is
+// ignored.
+func requestBaseURL(publicBaseURL string, r *http.Request) string {
+	if publicBaseURL != "" {
+		return publicBaseURL
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	// a proxy chain sends a list, the first proxy saw the client
+	proto, _, _ := strings.Cut(r.Header.Get("X-Forwarded-Proto"), ",")
+	if proto = strings.ToLower(strings.TrimSpace(proto)); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host
+}
+
+// parseIntList parses a comma separated list such as "1,2,3".
+func parseIntList(value string) ([]int, error) {
+	results := []int{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		number, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, number)
+	}
+
+	return results, nil
+}
+
+// parseStringList splits a comma separated list such as "a,b", leaving out
+// empty items.
+func parseStringList(value string) []string {
+	var results []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			results = append(results, item)
+		}
+	}
+	return results
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"crypto/tls"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestBaseURL(t *testing.T) {
+	t.Run("WHEN a public base URL is configured, THEN should ignore the request", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "http://attacker.example.com/api/v1/weathers", nil)
+		r.Header.Set("X-Forwarded-Proto", "https")
+
+		assert.Equal(t, "https://weather.example.com", requestBaseURL("https://weather.example.com", r))
+	})
+
+	t.Run("WHEN a proxy forwards https, THEN should use it", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "http://localhost:8080/api/v1/weathers", nil)
+		r.Header.Set("X-Forwarded-Proto", "HTTPS, http")
+
+		assert.Equal(t, "https://localhost:8080", requestBaseURL("", r))
+	})
+
+	t.Run("WHEN the forwarded scheme is not http or https, THEN should ignore it", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "http://localhost:8080/api/v1/weathers", nil)
+		r.Header.Set("X-Forwarded-Proto", "javascript")
+
+		assert.Equal(t, "http://localhost:8080", requestBaseURL("", r))
+
+		r.TLS = &tls.ConnectionState{}
+		assert.Equal(t, "https://localhost:8080", requestBaseURL("", r))
+	})
+}
+
+func TestVaryUnits(t *testing.T) {
+	t.Run("WHEN units are left to the API key, THEN should vary on the key", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/api/v1/weathers/chart?locationId=1", nil)
+		w := httptest.NewRecorder()
+		w.Header().Add("Vary", "Accept-Language")
+
+		varyUnits(w, r)
+
+		assert.Equal(t, []string{"Accept-Language", "X-API-Key"}, w.Header().Values("Vary"))
+	})
+
+	t.Run("WHEN units are given, THEN should not vary on the key", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/api/v1/weathers/chart?locationId=1&units=imperial", nil)
+		w := httptest.NewRecorder()
+
+		varyUnits(w, r)
+
+		assert.Empty(t, w.Header().Values("Vary"))
+	})
+}
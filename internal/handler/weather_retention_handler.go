@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"tyarus/weather-app/internal/dto"
+	"tyarus/weather-app/internal/usecase"
+	"tyarus/weather-app/pkg/response"
+)
+
+type weatherRetentionHandler struct {
+	retentionUc usecase.WeatherRetentionUsecaseInterface
+}
+
+func NewWeatherRetentionHandler(retentionUc usecase.WeatherRetentionUsecaseInterface) weatherRetentionHandler {
+	return weatherRetentionHandler{retentionUc: retentionUc}
+}
+
+// StartWeatherRetentionHandler starts a retention run in the background, its
+// progress is read with GetWeatherRetentionJobHandler.
+func (h *weatherRetentionHandler) StartWeatherRetentionHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		var req dto.ApplyWeatherRetentionRequest
+		if r.URL.Query().Get("dryRun") != "" {
+			var err error
+			req.DryRun, err = strconv.ParseBool(r.URL.Query().Get("dryRun"))
+			if err != nil {
+				response.Error(w, http.StatusBadRequest, "invalid dryRun parameter, please check your parameter")
+				return
+			}
+		}
+
+		job, err := h.retentionUc.StartWeatherRetentionUsecase(ctx, req)
+		if errors.Is(err, usecase.ErrWeatherRetentionRunning) {
+			response.JSON(w, http.StatusConflict, "error", err.Error(), job)
+			return
+		}
+		if err != nil {
+			response.Error(w, http.StatusInternalServerError, "failed to start weather retention: "+err.Error())
+			return
+		}
+
+		response.JSON(w, http.StatusAccepted, "success", "weather retention started", job)
+	}
+}
+
+func (h *weatherRetentionHandler) GetWeatherRetentionJobHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		job := h.retentionUc.GetWeatherRetentionJobUsecase(r.Context())
+		response.JSON(w, http.StatusOK, "success", "get weather retention successfully", job)
+	}
+}
@@ -0,0 +1,336 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"tyarus/weather-app/internal/dto"
+	"tyarus/weather-app/internal/infra"
+	"tyarus/weather-app/internal/usecase"
+	"tyarus/weather-app/pkg/response"
+	"tyarus/weather-app/pkg/units"
+	"tyarus/weather-app/pkg/utils"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	subscriptionWriteWait  = 10 * time.Second
+	subscriptionPongWait   = 60 * time.Second
+	subscriptionPingPeriod = subscriptionPongWait * 9 / 10
+	subscriptionSendBuffer = 16
+)
+
+type weatherSubscriptionHandler struct {
+	weatherUc        usecase.WeatherUsecaseInterface
+	cache            infra.CacheInterface
+	units            units.Preferences
+	maxSubscriptions int
+	upgrader         websocket.Upgrader
+	// baseURL is the configured public base URL, empty to follow the request
+	baseURL string
+
+	mu      sync.RWMutex
+	clients map[*subscriptionClient]struct{}
+}
+
+type subscriptionClient struct {
+	conn *websocket.Conn
+	send chan dto.WeatherSubscriptionMessage
+	done chan struct{}
+	// baseURL makes the icon URLs of the client absolute
+	baseURL string
+	// units are resolved once when the connection is opened
+	units units.System
+
+	mu        sync.Mutex
+	snapshots map[int]dto.GetWeatherResponse
+}
+
+func NewWeatherSubscriptionHandler(weatherUc usecase.WeatherUsecaseInterface, cache infra.CacheInterface, unitPrefs units.Preferences, maxSubscriptions int, allowedOrigins []string, publicBaseURL string) *weatherSubscriptionHandler {
+	return &weatherSubscriptionHandler{
+		weatherUc:        weatherUc,
+		cache:            cache,
+		units:            unitPrefs,
+		maxSubscriptions: maxSubscriptions,
+		baseURL:          publicBaseURL,
+		upgrader: websocket.Upgrader{
+			CheckOrigin: checkOrigin(allowedOrigins),
+		},
+		clients: map[*subscriptionClient]struct{}{},
+	}
+}
+
+// checkOrigin accepts connections from the host of the API itself, from the
+// allowed origins and from clients that are not browsers and send no Origin.
+// Any other website could otherwise open a connection in a visitor's browser.
+func checkOrigin(allowedOrigins []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+
+		for _, allowed := range allowedOrigins {
+			if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin) {
+				return true
+			}
+		}
+
+		parsed, err := url.Parse(origin)
+		if err != nil {
+			return false
+		}
+		return strings.EqualFold(parsed.Host, r.Host)
+	}
+}
+
+// Run listens for synced locations and pushes forecast diffs to every
+// connection subscribed to them. It blocks until ctx is cancelled.
+func (h *weatherSubscriptionHandler) Run(ctx context.Context) {
+	for payload := range h.cache.Subscribe(ctx, utils.WeatherSyncChannel) {
+		locationID, err := strconv.Atoi(payload)
+		if err != nil {
+			log.Printf("invalid weather sync payload %q: %v", payload, err)
+			continue
+		}
+
+		h.broadcast(ctx, locationID)
+	}
+}
+
+func (h *weatherSubscriptionHandler) SubscribeWeatherHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		system, err := parseUnits(h.units, r)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		conn, err := h.upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			log.Println("[ERROR LOG] failed to upgrade websocket connection: ", err)
+			return
+		}
+
+		client := &subscriptionClient{
+			conn:      conn,
+			send:      make(chan dto.WeatherSubscriptionMessage, subscriptionSendBuffer),
+			done:      make(chan struct{}),
+			baseURL:   requestBaseURL(h.baseURL, r),
+			units:     system,
+			snapshots: map[int]dto.GetWeatherResponse{},
+		}
+
+		h.mu.Lock()
+		h.clients[client] = struct{}{}
+		h.mu.Unlock()
+
+		go client.writePump()
+		h.readPump(r.Context(), client)
+
+		h.mu.Lock()
+		delete(h.clients, client)
+		h.mu.Unlock()
+		close(client.done)
+	}
+}
+
+func (h *weatherSubscriptionHandler) readPump(ctx context.Context, client *subscriptionClient) {
+	defer client.conn.Close()
+
+	client.conn.SetReadLimit(4096)
+	_ = client.conn.SetReadDeadline(time.Now().Add(subscriptionPongWait))
+	client.conn.SetPongHandler(func(string) error {
+		return client.conn.SetReadDeadline(time.Now().Add(subscriptionPongWait))
+	})
+
+	for {
+		var req dto.WeatherSubscriptionRequest
+		if err := client.conn.ReadJSON(&req); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				log.Println("[ERROR LOG] websocket read failed: ", err)
+			}
+			return
+		}
+
+		if err := req.Validate(); err != nil {
+			client.push(errorMessage(0, err.Error()))
+			continue
+		}
+
+		switch req.Action {
+		case dto.WeatherSubscriptionActionSubscribe:
+			h.subscribe(ctx, client, req.LocationIDs)
+		case dto.WeatherSubscriptionActionUnsubscribe:
+			client.unsubscribe(req.LocationIDs)
+		}
+	}
+}
+
+func (h *weatherSubscriptionHandler) subscribe(ctx context.Context, client *subscriptionClient, locationIDs []int) {
+	newIDs := []int{}
+	client.mu.Lock()
+	for _, id := range locationIDs {
+		if _, ok := client.snapshots[id]; !ok && !slices.Contains(newIDs, id) {
+			newIDs = append(newIDs, id)
+		}
+	}
+	total := len(client.snapshots) + len(newIDs)
+	client.mu.Unlock()
+
+	if total > h.maxSubscriptions {
+		client.push(errorMessage(0, fmt.Sprintf("subscription limit reached, maximum %d locations per connection", h.maxSubscriptions)))
+		return
+	}
+
+	subscribed := []int{}
+	for _, id := range newIDs {
+		resp, err := h.weatherUc.GetWeathersUsecase(ctx, dto.GetWeathersParam{LocationID: id, Units: client.units})
+		if err != nil {
+			client.push(errorMessage(id, "failed to subscribe location: "+err.Error()))
+			continue
+		}
+
+		snapshot := resp.Data.Present(nil, client.baseURL)
+		client.mu.Lock()
+		client.snapshots[id] = snapshot
+		client.mu.Unlock()
+
+		client.push(dto.WeatherSubscriptionMessage{
+			Type:       dto.WeatherSubscriptionMessageSnapshot,
+			LocationID: id,
+			Snapshot:   &snapshot,
+		})
+		subscribed = append(subscribed, id)
+	}
+
+	if len(subscribed) > 0 {
+		client.push(dto.WeatherSubscriptionMessage{
+			Type:        dto.WeatherSubscriptionMessageSubscribed,
+			LocationIDs: subscribed,
+		})
+	}
+}
+
+func (h *weatherSubscriptionHandler) broadcast(ctx context.Context, locationID int) {
+	h.mu.RLock()
+	clientsByUnits := map[units.System][]*subscriptionClient{}
+	for client := range h.clients {
+		if client.isSubscribed(locationID) {
+			clientsByUnits[client.units] = append(clientsByUnits[client.units], client)
+		}
+	}
+	h.mu.RUnlock()
+
+	// one read per unit system the subscribers asked for
+	for system, clients := range clientsByUnits {
+		resp, err := h.weatherUc.GetWeathersUsecase(ctx, dto.GetWeathersParam{LocationID: locationID, Units: system})
+		if err != nil {
+			log.Printf("failed to get weather for location %d: %v", locationID, err)
+			continue
+		}
+
+		pushDiffs(clients, locationID, resp.Data)
+	}
+}
+
+// pushDiffs sends the changes of data to the clients still subscribed to the
+// location.
+func pushDiffs(clients []*subscriptionClient, locationID int, data dto.GetWeatherResponse) {
+	for _, client := range clients {
+		current := data.Present(nil, client.baseURL)
+
+		client.mu.Lock()
+		prev, ok := client.snapshots[locationID]
+		if ok {
+			client.snapshots[locationID] = current
+		}
+		client.mu.Unlock()
+
+		if !ok {
+			continue
+		}
+
+		diff := dto.DiffWeatherResponse(prev, current)
+		if diff.IsEmpty() && diff.CurrentTime.ForecastTime.Equal(prev.CurrentTime.ForecastTime) {
+			continue
+		}
+
+		client.push(dto.WeatherSubscriptionMessage{
+			Type:       dto.WeatherSubscriptionMessageUpdate,
+			LocationID: locationID,
+			Diff:       &diff,
+		})
+	}
+}
+
+func (c *subscriptionClient) isSubscribed(locationID int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.snapshots[locationID]
+	return ok
+}
+
+func (c *subscriptionClient) unsubscribe(locationIDs []int) {
+	c.mu.Lock()
+	for _, id := range locationIDs {
+		delete(c.snapshots, id)
+	}
+	c.mu.Unlock()
+
+	c.push(dto.WeatherSubscriptionMessage{
+		Type:        dto.WeatherSubscriptionMessageUnsubscribed,
+		LocationIDs: locationIDs,
+	})
+}
+
+// push drops the message when the client is gone or too slow to keep up,
+// so one stuck connection never blocks the broadcast to the others.
+func (c *subscriptionClient) push(msg dto.WeatherSubscriptionMessage) {
+	select {
+	case <-c.done:
+	case c.send <- msg:
+	default:
+		log.Println("[ERROR LOG] websocket send buffer full, dropping message")
+	}
+}
+
+func (c *subscriptionClient) writePump() {
+	ticker := time.NewTicker(subscriptionPingPeriod)
+	defer ticker.Stop()
+	defer c.conn.Close()
+
+	for {
+		select {
+		case msg := <-c.send:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(subscriptionWriteWait))
+			if err := c.conn.WriteJSON(msg); err != nil {
+				return
+			}
+		case <-ticker.C:
+			_ = c.conn.SetWriteDeadline(time.Now().Add(subscriptionWriteWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+func errorMessage(locationID int, message string) dto.WeatherSubscriptionMessage {
+	return dto.WeatherSubscriptionMessage{
+		Type:       dto.WeatherSubscriptionMessageError,
+		LocationID: locationID,
+		Message:    message,
+	}
+}
@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"tyarus/weather-app/internal/dto"
+	"tyarus/weather-app/mocks"
+	"tyarus/weather-app/pkg/response"
+	"tyarus/weather-app/pkg/units"
+	"tyarus/weather-app/pkg/utils"
+)
+
+func newSubscriptionTestServer(t *testing.T, weatherUc *mocks.WeatherUsecaseInterface, cache *mocks.CacheInterface, maxSubscriptions int) (*weatherSubscriptionHandler, *websocket.Conn) {
+	h := NewWeatherSubscriptionHandler(weatherUc, cache, units.Preferences{Default: units.Metric}, maxSubscriptions, nil, "")
+
+	server := httptest.NewServer(h.SubscribeWeatherHandler())
+	t.Cleanup(server.Close)
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return h, conn
+}
+
+func readSubscriptionMessage(t *testing.T, conn *websocket.Conn) dto.WeatherSubscriptionMessage {
+	var msg dto.WeatherSubscriptionMessage
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	require.NoError(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+func weatherResponse(locationID int64, items ...dto.GetWeatherResponseItem) response.Response[dto.GetWeatherResponse] {
+	return response.Response[dto.GetWeatherResponse]{
+		Status: "success",
+		Data: dto.GetWeatherResponse{
+			Location: dto.GetLocationHandlerResponseItem{ID: locationID},
+			Forecast: items,
+		},
+	}
+}
+
+func TestSubscribeWeatherHandler(t *testing.T) {
+	forecastTime := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("WHEN client subscribes to locations, THEN should receive snapshot for each location", func(t *testing.T) {
+		mockWeatherUc := mocks.NewWeatherUsecaseInterface(t)
+		_, conn := newSubscriptionTestServer(t, mockWeatherUc, mocks.NewCacheInterface(t), 5)
+
+		mockWeatherUc.On("GetWeathersUsecase", mock.Anything, dto.GetWeathersParam{LocationID: 1, Units: units.Metric}).Return(weatherResponse(1), nil)
+		mockWeatherUc.On("GetWeathersUsecase", mock.Anything, dto.GetWeathersParam{LocationID: 2, Units: units.Metric}).Return(weatherResponse(2), nil)
+
+		require.NoError(t, conn.WriteJSON(dto.WeatherSubscriptionRequest{Action: "subscribe", LocationIDs: []int{1, 2}}))
+
+		first := readSubscriptionMessage(t, conn)
+		second := readSubscriptionMessage(t, conn)
+		ack := readSubscriptionMessage(t, conn)
+
+		assert.Equal(t, dto.WeatherSubscriptionMessageSnapshot, first.Type)
+		assert.Equal(t, int64(1), first.Snapshot.Location.ID)
+		assert.Equal(t, dto.WeatherSubscriptionMessageSnapshot, second.Type)
+		assert.Equal(t, int64(2), second.Snapshot.Location.ID)
+		assert.Equal(t, dto.WeatherSubscriptionMessageSubscribed, ack.Type)
+		assert.Equal(t, []int{1, 2}, ack.LocationIDs)
+	})
+
+	t.Run("WHEN subscriptions exceed the connection limit, THEN should return error message", func(t *testing.T) {
+		mockWeatherUc := mocks.NewWeatherUsecaseInterface(t)
+		_, conn := newSubscriptionTestServer(t, mockWeatherUc, mocks.NewCacheInterface(t), 1)
+
+		require.NoError(t, conn.WriteJSON(dto.WeatherSubscriptionRequest{Action: "subscribe", LocationIDs: []int{1, 2}}))
+
+		msg := readSubscriptionMessage(t, conn)
+
+		assert.Equal(t, dto.WeatherSubscriptionMessageError, msg.Type)
+		assert.Contains(t, msg.Message, "subscription limit reached")
+	})
+
+	t.Run("WHEN request action is invalid, THEN should return error message", func(t *testing.T) {
+		mockWeatherUc := mocks.NewWeatherUsecaseInterface(t)
+		_, conn := newSubscriptionTestServer(t, mockWeatherUc, mocks.NewCacheInterface(t), 5)
+
+		require.NoError(t, conn.WriteJSON(dto.WeatherSubscriptionRequest{Action: "watch", LocationIDs: []int{1}}))
+
+		msg := readSubscriptionMessage(t, conn)
+
+		assert.Equal(t, dto.WeatherSubscriptionMessageError, msg.Type)
+		assert.Contains(t, msg.Message, "invalid action parameter")
+	})
+
+	t.Run("WHEN location failed to load, THEN should return error message for that location", func(t *testing.T) {
+		mockWeatherUc := mocks.NewWeatherUsecaseInterface(t)
+		_, conn := newSubscriptionTestServer(t, mockWeatherUc, mocks.NewCacheInterface(t), 5)
+
+		mockWeatherUc.On("GetWeathersUsecase", mock.Anything, dto.GetWeathersParam{LocationID: 9, Units: units.Metric}).
+			Return(response.Response[dto.GetWeatherResponse]{}, errors.New("location not found, please check your parameter"))
+
+		require.NoError(t, conn.WriteJSON(dto.WeatherSubscriptionRequest{Action: "subscribe", LocationIDs: []int{9}}))
+
+		msg := readSubscriptionMessage(t, conn)
+
+		assert.Equal(t, dto.WeatherSubscriptionMessageError, msg.Type)
+		assert.Equal(t, 9, msg.LocationID)
+		assert.Contains(t, msg.Message, "location not found")
+	})
+
+	t.Run("WHEN subscribed location is synced, THEN should receive forecast diff", func(t *testing.T) {
+		mockWeatherUc := mocks.NewWeatherUsecaseInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+		h, conn := newSubscriptionTestServer(t, mockWeatherUc, mockCache, 5)
+
+		before := dto.GetWeatherResponseItem{ForecastTime: forecastTime, ForecastType: "hour", TemperatureCelcius: 30}
+		after := dto.GetWeatherResponseItem{ForecastTime: forecastTime, ForecastType: "hour", TemperatureCelcius: 27}
+		mockWeatherUc.On("GetWeathersUsecase", mock.Anything, dto.GetWeathersParam{LocationID: 1, Units: units.Metric}).Return(weatherResponse(1, before), nil).Once()
+		mockWeatherUc.On("GetWeathersUsecase", mock.Anything, dto.GetWeathersParam{LocationID: 1, Units: units.Metric}).Return(weatherResponse(1, after), nil).Once()
+
+		require.NoError(t, conn.WriteJSON(dto.WeatherSubscriptionRequest{Action: "subscribe", LocationIDs: []int{1}}))
+		readSubscriptionMessage(t, conn)
+		readSubscriptionMessage(t, conn)
+
+		synced := make(chan string, 1)
+		mockCache.On("Subscribe", mock.Anything, utils.WeatherSyncChannel).Return((<-chan string)(synced))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go h.Run(ctx)
+		synced <- "1"
+		close(synced)
+
+		msg := readSubscriptionMessage(t, conn)
+
+		assert.Equal(t, dto.WeatherSubscriptionMessageUpdate, msg.Type)
+		assert.Equal(t, 1, msg.LocationID)
+		require.Len(t, msg.Diff.Upserted, 1)
+		assert.Equal(t, float64(27), msg.Diff.Upserted[0].TemperatureCelcius)
+	})
+}
+
+func TestSubscribeWeatherHandlerUnits(t *testing.T) {
+	forecastTime := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+	prefs := units.Preferences{Default: units.Metric, ByAPIKey: map[string]units.System{"mobile-app": units.Imperial}}
+
+	newServer := func(t *testing.T, weatherUc *mocks.WeatherUsecaseInterface, cache *mocks.CacheInterface) (*weatherSubscriptionHandler, string) {
+		h := NewWeatherSubscriptionHandler(weatherUc, cache, prefs, 5, nil, "")
+		server := httptest.NewServer(h.SubscribeWeatherHandler())
+		t.Cleanup(server.Close)
+		return h, "ws" + strings.TrimPrefix(server.URL, "http")
+	}
+
+	t.Run("WHEN units are invalid, THEN should reject the connection", func(t *testing.T) {
+		_, url := newServer(t, mocks.NewWeatherUsecaseInterface(t), mocks.NewCacheInterface(t))
+
+		_, resp, err := websocket.DefaultDialer.Dial(url+"?units=kelvin", nil)
+
+		assert.ErrorIs(t, err, websocket.ErrBadHandshake)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("WHEN clients asked for different units, THEN should read each location once per units", func(t *testing.T) {
+		mockWeatherUc := mocks.NewWeatherUsecaseInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+		h, url := newServer(t, mockWeatherUc, mockCache)
+
+		before := dto.GetWeatherResponseItem{ForecastTime: forecastTime, ForecastType: "hour", TemperatureCelcius: 30}
+		after := dto.GetWeatherResponseItem{ForecastTime: forecastTime, ForecastType: "hour", TemperatureCelcius: 27}
+		// every connection reads its snapshot, the broadcast reads once per units
+		for system, connections := range map[units.System]int{units.SI: 2, units.Imperial: 1} {
+			param := dto.GetWeathersParam{LocationID: 1, Units: system}
+			mockWeatherUc.On("GetWeathersUsecase", mock.Anything, param).Return(weatherResponse(1, before), nil).Times(connections)
+			mockWeatherUc.On("GetWeathersUsecase", mock.Anything, param).Return(weatherResponse(1, after), nil).Once()
+		}
+
+		// two connections share SI, the API key of the third picks imperial
+		var conns []*websocket.Conn
+		for _, dial := range []struct {
+			query  string
+			header http.Header
+		}{
+			{"?units=si", nil},
+			{"?units=si", nil},
+			{"", http.Header{APIKeyHeader: []string{"mobile-app"}}},
+		} {
+			conn, _, err := websocket.DefaultDialer.Dial(url+dial.query, dial.header)
+			require.NoError(t, err)
+			t.Cleanup(func() { conn.Close() })
+
+			require.NoError(t, conn.WriteJSON(dto.WeatherSubscriptionRequest{Action: "subscribe", LocationIDs: []int{1}}))
+			readSubscriptionMessage(t, conn)
+			readSubscriptionMessage(t, conn)
+			conns = append(conns, conn)
+		}
+
+		synced := make(chan string, 1)
+		mockCache.On("Subscribe", mock.Anything, utils.WeatherSyncChannel).Return((<-chan string)(synced))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go h.Run(ctx)
+		synced <- "1"
+		close(synced)
+
+		for _, conn := range conns {
+			msg := readSubscriptionMessage(t, conn)
+			assert.Equal(t, dto.WeatherSubscriptionMessageUpdate, msg.Type)
+		}
+	})
+}
+
+func TestSubscribeWeatherHandlerOrigin(t *testing.T) {
+	h := NewWeatherSubscriptionHandler(mocks.NewWeatherUsecaseInterface(t), mocks.NewCacheInterface(t), units.Preferences{Default: units.Metric}, 5, []string{"https://dashboard.example.com"}, "")
+
+	server := httptest.NewServer(h.SubscribeWeatherHandler())
+	t.Cleanup(server.Close)
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+
+	dial := func(origin string) error {
+		conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": []string{origin}})
+		if err == nil {
+			conn.Close()
+		}
+		return err
+	}
+
+	t.Run("WHEN origin is another website, THEN should reject the connection", func(t *testing.T) {
+		assert.ErrorIs(t, dial("https://evil.example.com"), websocket.ErrBadHandshake)
+	})
+
+	t.Run("WHEN origin is allowed, THEN should accept the connection", func(t *testing.T) {
+		assert.NoError(t, dial("https://dashboard.example.com"))
+	})
+
+	t.Run("WHEN origin is the API itself, THEN should accept the connection", func(t *testing.T) {
+		assert.NoError(t, dial(server.URL))
+	})
+}
@@ -14,6 +14,11 @@ type cache struct {
 type CacheInterface interface {
 	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
 	Get(ctx context.Context, key string) (string, error)
+	MGet(ctx context.Context, keys ...string) ([]string, error)
+	MSet(ctx context.Context, values map[string]interface{}, expiration time.Duration) error
+	Del(ctx context.Context, keys ...string) error
+	Publish(ctx context.Context, channel string, message interface{}) error
+	Subscribe(ctx context.Context, channel string) <-chan string
 	Close() error
 	Ping(ctx context.Context) error
 }
@@ -45,6 +50,73 @@ func (c *cache) Get(ctx context.Context, key string) (string, error) {
 	return value, nil
 }
 
+// MGet returns the values in the same order as keys, with an empty string
+// for every key that does not exist.
+func (c *cache) MGet(ctx context.Context, keys ...string) ([]string, error) {
+	values, err := c.redisClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]string, len(values))
+	for i, v := range values {
+		if str, ok := v.(string); ok {
+			results[i] = str
+		}
+	}
+
+	return results, nil
+}
+
+// MSet stores every value with the same expiration in one round trip.
+func (c *cache) MSet(ctx context.Context, values map[string]interface{}, expiration time.Duration) error {
+	pipe := c.redisClient.Pipeline()
+	for key, value := range values {
+		pipe.Set(ctx, key, value, expiration)
+	}
+
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *cache) Del(ctx context.Context, keys ...string) error {
+	return c.redisClient.Del(ctx, keys...).Err()
+}
+
+func (c *cache) Publish(ctx context.Context, channel string, message interface{}) error {
+	return c.redisClient.Publish(ctx, channel, message).Err()
+}
+
+// Subscribe returns payloads published on channel until ctx is cancelled.
+func (c *cache) Subscribe(ctx context.Context, channel string) <-chan string {
+	pubsub := c.redisClient.Subscribe(ctx, channel)
+	messages := make(chan string)
+
+	go func() {
+		defer close(messages)
+		defer pubsub.Close()
+
+		ch := pubsub.Channel()
+		for {
+			select {
+			case msg, ok := <-ch:
+				if !ok {
+					return
+				}
+				select {
+				case messages <- msg.Payload:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return messages
+}
+
 func (c *cache) Close() error {
 	return c.redisClient.Close()
 }
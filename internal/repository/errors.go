@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// ErrDuplicateKey is wrapped by writes rejected by a unique index.
+var ErrDuplicateKey = errors.New("duplicate entry")
+
+// mysqlDuplicateEntry is the MySQL error number of ER_DUP_ENTRY.
+const mysqlDuplicateEntry = 1062
+
+// wrapDuplicateKey adds ErrDuplicateKey to the chain of err when MySQL
+// rejected the write for a duplicate unique key.
+func wrapDuplicateKey(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlDuplicateEntry {
+		return errors.Join(ErrDuplicateKey, err)
+	}
+	return err
+}
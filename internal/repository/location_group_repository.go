@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"tyarus/weather-app/internal/domain"
+	"tyarus/weather-app/pkg/utils"
+)
+
+type GetLocationGroupsParam struct {
+	ID     int64
+	Limit  int
+	Offset int
+}
+
+type LocationGroupRepositoryInterface interface {
+	GetLocationGroups(ctx context.Context, param GetLocationGroupsParam) ([]domain.LocationGroup, error)
+	GetLocationGroupsCount(ctx context.Context) (int, error)
+	InsertLocationGroup(ctx context.Context, group domain.LocationGroup) (domain.LocationGroup, error)
+	UpdateLocationGroup(ctx context.Context, group domain.LocationGroup) error
+	DeleteLocationGroup(ctx context.Context, id int64) error
+	AddLocationGroupMembers(ctx context.Context, groupID int64, locationIDs []int64) (int64, error)
+	RemoveLocationGroupMembers(ctx context.Context, groupID int64, locationIDs []int64) (int64, error)
+}
+
+type locationGroupRepository struct {
+	db *sql.DB
+}
+
+func NewLocationGroupRepository(db *sql.DB) LocationGroupRepositoryInterface {
+	return &locationGroupRepository{db: db}
+}
+
+func (r *locationGroupRepository) GetLocationGroups(ctx context.Context, param GetLocationGroupsParam) ([]domain.LocationGroup, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	query := `SELECT g.id, g.name, g.description,
+	          (SELECT COUNT(*) FROM location_group_members m
+	           JOIN locations l ON l.id = m.location_id AND l.deleted_at IS NULL
+	           WHERE m.group_id = g.id) AS location_count,
+	          g.created_at, g.last_modified_at, g.deleted_at
+	          FROM location_groups g WHERE g.deleted_at IS NULL`
+	params := []interface{}{}
+	if param.ID > 0 {
+		query = query + " AND g.id = ?"
+		params = append(params, param.ID)
+	}
+
+	query = query + " ORDER BY g.name ASC"
+
+	if param.Limit > 0 {
+		query = query + " LIMIT ?"
+		params = append(params, param.Limit)
+	}
+
+	if param.Offset > 0 {
+		query = query + " OFFSET ?"
+		params = append(params, param.Offset)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query location groups: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []domain.LocationGroup
+	for rows.Next() {
+		var item domain.LocationGroup
+		if err := rows.Scan(
+			&item.ID,
+			&item.Name,
+			&item.Description,
+			&item.LocationCount,
+			&item.CreatedAt,
+			&item.LastModifiedAt,
+			&item.DeletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan location group: %w", err)
+		}
+		groups = append(groups, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return groups, nil
+}
+
+func (r *locationGroupRepository) GetLocationGroupsCount(ctx context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	var count int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM location_groups WHERE deleted_at IS NULL").Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get location groups count: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *locationGroupRepository) InsertLocationGroup(ctx context.Context, group domain.LocationGroup) (domain.LocationGroup, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	res, err := r.db.ExecContext(ctx, `INSERT INTO location_groups (name, name_key, description) VALUES (?, ?, ?)`,
+		group.Name, group.Name, group.Description,
+	)
+	if err != nil {
+		return group, fmt.Errorf("failed to insert location group: %w", wrapDuplicateKey(err))
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return group, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	group.ID = id
+	return group, nil
+}
+
+func (r *locationGroupRepository) UpdateLocationGroup(ctx context.Context, group domain.LocationGroup) error {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	_, err := r.db.ExecContext(ctx, `UPDATE location_groups SET name = ?, name_key = ?, description = ? WHERE id = ? AND deleted_at IS NULL`,
+		group.Name, group.Name, group.Description, group.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update location group: %w", wrapDuplicateKey(err))
+	}
+
+	return nil
+}
+
+// DeleteLocationGroup soft deletes the group and drops its memberships, the
+// member locations themselves are kept. The name is freed for new groups.
+func (r *locationGroupRepository) DeleteLocationGroup(ctx context.Context, id int64) error {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM location_group_members WHERE group_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete location group members: %w", err)
+	}
+
+	if _, err = tx.ExecContext(ctx, `UPDATE location_groups SET deleted_at = NOW(), name_key = NULL WHERE id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete location group: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// AddLocationGroupMembers returns how many locations were newly added,
+// locations that are already members are skipped.
+func (r *locationGroupRepository) AddLocationGroupMembers(ctx context.Context, groupID int64, locationIDs []int64) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	if len(locationIDs) == 0 {
+		return 0, nil
+	}
+
+	query := `INSERT IGNORE INTO location_group_members (group_id, location_id) VALUES `
+	params := []interface{}{}
+	for i, id := range locationIDs {
+		if i > 0 {
+			query = query + ", "
+		}
+		query = query + "(?, ?)"
+		params = append(params, groupID, id)
+	}
+
+	res, err := r.db.ExecContext(ctx, query, params...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add location group members: %w", err)
+	}
+
+	added, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return added, nil
+}
+
+func (r *locationGroupRepository) RemoveLocationGroupMembers(ctx context.Context, groupID int64, locationIDs []int64) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	if len(locationIDs) == 0 {
+		return 0, nil
+	}
+
+	params := []interface{}{groupID}
+	for _, id := range locationIDs {
+		params = append(params, id)
+	}
+
+	res, err := r.db.ExecContext(ctx,
+		`DELETE FROM location_group_members WHERE group_id = ? AND location_id IN (`+placeholders(len(locationIDs))+`)`,
+		params...,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove location group members: %w", err)
+	}
+
+	removed, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get affected rows: %w", err)
+	}
+
+	return removed, nil
+}
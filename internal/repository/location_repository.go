@@ -4,22 +4,70 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 	"tyarus/weather-app/internal/domain"
+	"tyarus/weather-app/pkg/geo"
 	"tyarus/weather-app/pkg/utils"
 )
 
+const locationColumns = "id, external_key, name, region, country, latitude, longitude, timezone, created_at, last_modified_at, deleted_at"
+
+// GetLocationsParam filters locations, every non-zero field narrows down the
+// result. Text filters are case insensitive and matched literally.
 type GetLocationsParam struct {
-	ID       int
-	NameLike string
-	Limit    int
-	Offset   int
-	OrderBy  string
+	ID             int
+	IDs            []int64
+	NameLike       string
+	NamePrefix     string
+	Country        string
+	Region         string
+	BoundingBox    *geo.BoundingBox
+	GroupID        int64
+	Tag            string
+	CreatedFrom    time.Time
+	CreatedTo      time.Time
+	IncludeDeleted bool
+	After          *Keyset
+	Limit          int
+	Offset         int
+	OrderBy        string
+}
+
+// FindDuplicateLocationsParam matches locations having one of NameKeys, see
+// domain.LocationNameKey, or located inside one of BoundingBoxes.
+type FindDuplicateLocationsParam struct {
+	NameKeys      []string
+	BoundingBoxes []geo.BoundingBox
+}
+
+const (
+	UpsertStatusCreated   = "created"
+	UpsertStatusUpdated   = "updated"
+	UpsertStatusUnchanged = "unchanged"
+)
+
+type UpsertLocationResult struct {
+	ID     int64
+	Status string
+}
+
+type MergeLocationsResult struct {
+	MovedWeathers     int64
+	DiscardedWeathers int64
 }
 
 type LocationRepositoryInterface interface {
 	GetLocations(ctx context.Context, param GetLocationsParam) ([]domain.Location, error)
 	InsertLocation(ctx context.Context, location domain.Location) (domain.Location, error)
-	GetLocationsCount(ctx context.Context) (int, error)
+	GetLocationsCount(ctx context.Context, param GetLocationsParam) (int, error)
+	FindDuplicateLocations(ctx context.Context, param FindDuplicateLocationsParam) ([]domain.Location, error)
+	MergeLocations(ctx context.Context, canonicalID, duplicateID int64) (MergeLocationsResult, error)
+	UpsertLocations(ctx context.Context, locations []domain.Location) ([]UpsertLocationResult, error)
+	GetLocationTags(ctx context.Context, locationIDs []int64) (map[int64][]string, error)
+	SetLocationTags(ctx context.Context, locationID int64, tags []string) error
+	GetLocationNames(ctx context.Context, locationIDs []int64) (map[int64][]domain.LocationName, error)
+	SetLocationNames(ctx context.Context, locationID int64, names []domain.LocationName) error
 }
 
 type locationRepository struct {
@@ -34,35 +82,19 @@ func (r *locationRepository) GetLocations(ctx context.Context, param GetLocation
 	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
 	defer cancel()
 
-	query := `SELECT * FROM locations WHERE deleted_at IS NULL`
-	params := []interface{}{}
-	if param.ID > 0 {
-		query = query + " AND id = ?"
-		params = append(params, param.ID)
-	}
-
-	if param.NameLike != "" {
-		query = query + " AND name ILIKE %?%"
-		params = append(params, param.NameLike)
-	}
+	where, params := locationFilter(param)
+	query := `SELECT ` + locationColumns + ` FROM locations WHERE ` + where
 
-	if param.OrderBy != "" {
-		ascStatement := " ORDER BY %s ASC"
-		descStatement := " ORDER BY %s DESC"
-		statement := ""
-		switch param.OrderBy {
-		case utils.OrderByCreatedAtAsc:
-			query = query + fmt.Sprintf(ascStatement, "created_at")
-		case utils.OrderByCreatedAtDesc:
-			statement = fmt.Sprintf(descStatement, "created_at")
-		case utils.OrderByNameAsc:
-			statement = fmt.Sprintf(ascStatement, "name")
-		case utils.OrderByNameDesc:
-			statement = fmt.Sprintf(descStatement, "name")
-		default:
-			statement = fmt.Sprintf(ascStatement, "created_at")
+	if param.OrderBy != "" || param.After != nil {
+		column, desc := locationOrder(param.OrderBy)
+		if param.After != nil {
+			condition, keysetParams := param.After.condition(column, desc)
+			query = query + " AND " + condition
+			params = append(params, keysetParams...)
 		}
-		query = query + statement
+
+		direction := param.After.orderDirection(desc)
+		query = query + fmt.Sprintf(" ORDER BY %s %s, id %s", column, direction, direction)
 	}
 
 	if param.Limit > 0 {
@@ -81,36 +113,43 @@ func (r *locationRepository) GetLocations(ctx context.Context, param GetLocation
 	}
 	defer rows.Close()
 
-	var locations []domain.Location
-	for rows.Next() {
-		var item domain.Location
-		if err := rows.Scan(
-			&item.ID,
-			&item.Name,
-			&item.Region,
-			&item.Country,
-			&item.Latitude,
-			&item.Longitude,
-			&item.CreatedAt,
-			&item.LastModifiedAt,
-			&item.DeletedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan location: %w", err)
-		}
-		locations = append(locations, item)
+	locations, err := scanLocations(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if param.After != nil && param.After.Backward {
+		reverseRows(locations)
 	}
 
 	return locations, nil
 }
 
+// locationOrder returns the sort column of an OrderBy value and whether it is
+// descending. Unknown values sort by creation time.
+func locationOrder(orderBy string) (string, bool) {
+	switch orderBy {
+	case utils.OrderByCreatedAtDesc:
+		return "created_at", true
+	case utils.OrderByNameAsc:
+		return "name", false
+	case utils.OrderByNameDesc:
+		return "name", true
+	default:
+		return "created_at", false
+	}
+}
+
 func (r *locationRepository) InsertLocation(ctx context.Context, location domain.Location) (domain.Location, error) {
 	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
 	defer cancel()
 
-	res, err := r.db.ExecContext(ctx, `INSERT INTO locations (name, region, country, latitude, longitude) VALUES (?, ?, ?, ?, ?)`,
-		location.Name, location.Region, location.Country, location.Latitude, location.Longitude,
+	res, err := r.db.ExecContext(ctx, `INSERT INTO locations (external_key, name, region, country, latitude, longitude, timezone, name_key) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		location.ExternalKey, location.Name, location.Region, location.Country, location.Latitude, location.Longitude, location.Timezone,
+		domain.LocationNameKey(location.Name, location.Country),
 	)
 	if err != nil {
-		return location, fmt.Errorf("failed to insert location: %w", err)
+		return location, fmt.Errorf("failed to insert location: %w", wrapDuplicateKey(err))
 	}
 
 	id, err := res.LastInsertId()
@@ -122,16 +161,481 @@ func (r *locationRepository) InsertLocation(ctx context.Context, location domain
 	return location, nil
 }
 
-func (r *locationRepository) GetLocationsCount(ctx context.Context) (int, error) {
+// GetLocationsCount counts the locations matching the same filters as
+// GetLocations, ignoring limit, offset and order.
+func (r *locationRepository) GetLocationsCount(ctx context.Context, param GetLocationsParam) (int, error) {
 	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
 	defer cancel()
 
 	var count int
-	query := "SELECT COUNT(*) FROM locations"
-	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	where, params := locationFilter(param)
+	query := "SELECT COUNT(*) FROM locations WHERE " + where
+	err := r.db.QueryRowContext(ctx, query, params...).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get locations count: %w", err)
 	}
 
 	return count, err
 }
+
+// locationFilter builds the WHERE clause shared by GetLocations and
+// GetLocationsCount. Values are always bound, never formatted into the query.
+func locationFilter(param GetLocationsParam) (string, []interface{}) {
+	conditions := []string{}
+	params := []interface{}{}
+	if !param.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+
+	if param.ID > 0 {
+		conditions = append(conditions, "id = ?")
+		params = append(params, param.ID)
+	}
+
+	if len(param.IDs) > 0 {
+		conditions = append(conditions, "id IN ("+placeholders(len(param.IDs))+")")
+		for _, id := range param.IDs {
+			params = append(params, id)
+		}
+	}
+
+	if param.BoundingBox != nil {
+		conditions = append(conditions, "latitude BETWEEN ? AND ?")
+		params = append(params, param.BoundingBox.MinLatitude, param.BoundingBox.MaxLatitude)
+
+		// a box crossing the antimeridian has its western edge east of its
+		// eastern edge
+		if param.BoundingBox.MinLongitude <= param.BoundingBox.MaxLongitude {
+			conditions = append(conditions, "longitude BETWEEN ? AND ?")
+		} else {
+			conditions = append(conditions, "(longitude >= ? OR longitude <= ?)")
+		}
+		params = append(params, param.BoundingBox.MinLongitude, param.BoundingBox.MaxLongitude)
+	}
+
+	if param.GroupID > 0 {
+		conditions = append(conditions, "id IN (SELECT location_id FROM location_group_members WHERE group_id = ?)")
+		params = append(params, param.GroupID)
+	}
+
+	if param.Tag != "" {
+		conditions = append(conditions, "id IN (SELECT location_id FROM location_tags WHERE tag = ?)")
+		params = append(params, param.Tag)
+	}
+
+	// name filters match alternate and localized names as well. Text columns
+	// use a case insensitive collation, so comparing them as they are keeps
+	// their indexes usable.
+	if param.NameLike != "" {
+		pattern := "%" + escapeLike(param.NameLike) + "%"
+		conditions = append(conditions, "(name LIKE ? OR id IN (SELECT location_id FROM location_names WHERE name LIKE ?))")
+		params = append(params, pattern, pattern)
+	}
+
+	if param.NamePrefix != "" {
+		pattern := escapeLike(param.NamePrefix) + "%"
+		conditions = append(conditions, "(name LIKE ? OR id IN (SELECT location_id FROM location_names WHERE name LIKE ?))")
+		params = append(params, pattern, pattern)
+	}
+
+	if param.Country != "" {
+		conditions = append(conditions, "country = ?")
+		params = append(params, param.Country)
+	}
+
+	if param.Region != "" {
+		conditions = append(conditions, "region = ?")
+		params = append(params, param.Region)
+	}
+
+	if !param.CreatedFrom.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		params = append(params, param.CreatedFrom)
+	}
+
+	if !param.CreatedTo.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		params = append(params, param.CreatedTo)
+	}
+
+	if len(conditions) == 0 {
+		return "1 = 1", params
+	}
+
+	return strings.Join(conditions, " AND "), params
+}
+
+// FindDuplicateLocations returns active locations with one of the name keys,
+// or located inside one of the bounding boxes. Callers still have to check
+// the exact distance of the bounding box matches.
+func (r *locationRepository) FindDuplicateLocations(ctx context.Context, param FindDuplicateLocationsParam) ([]domain.Location, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	conditions := []string{}
+	params := []interface{}{}
+	if len(param.NameKeys) > 0 {
+		conditions = append(conditions, "name_key IN ("+placeholders(len(param.NameKeys))+")")
+		for _, key := range param.NameKeys {
+			params = append(params, key)
+		}
+	}
+	for _, box := range param.BoundingBoxes {
+		conditions = append(conditions, "(latitude BETWEEN ? AND ? AND longitude BETWEEN ? AND ?)")
+		params = append(params, box.MinLatitude, box.MaxLatitude, box.MinLongitude, box.MaxLongitude)
+	}
+	if len(conditions) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT ` + locationColumns + ` FROM locations WHERE deleted_at IS NULL
+	          AND (` + strings.Join(conditions, " OR ") + `)
+	          ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query duplicate locations: %w", err)
+	}
+	defer rows.Close()
+
+	return scanLocations(rows)
+}
+
+// MergeLocations moves every reference of the duplicate location to the
+// canonical one and soft deletes the duplicate, all in one transaction.
+// Weather rows the canonical location already has for the same forecast time
+// are kept and the duplicate ones are discarded.
+func (r *locationRepository) MergeLocations(ctx context.Context, canonicalID, duplicateID int64) (MergeLocationsResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	result := MergeLocationsResult{}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `SELECT id FROM locations WHERE id IN (?, ?) AND deleted_at IS NULL FOR UPDATE`, canonicalID, duplicateID)
+	if err != nil {
+		return result, fmt.Errorf("failed to lock locations: %w", err)
+	}
+	count := 0
+	for rows.Next() {
+		count++
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return result, fmt.Errorf("failed to lock locations: %w", err)
+	}
+	if count != 2 {
+		return result, fmt.Errorf("failed to merge locations: %w", sql.ErrNoRows)
+	}
+
+	res, err := tx.ExecContext(ctx, `UPDATE IGNORE weathers SET location_id = ? WHERE location_id = ?`, canonicalID, duplicateID)
+	if err != nil {
+		return result, fmt.Errorf("failed to move weathers: %w", err)
+	}
+	if result.MovedWeathers, err = res.RowsAffected(); err != nil {
+		return result, fmt.Errorf("failed to get moved weathers: %w", err)
+	}
+
+	res, err = tx.ExecContext(ctx, `DELETE FROM weathers WHERE location_id = ?`, duplicateID)
+	if err != nil {
+		return result, fmt.Errorf("failed to delete duplicate weathers: %w", err)
+	}
+	if result.DiscardedWeathers, err = res.RowsAffected(); err != nil {
+		return result, fmt.Errorf("failed to get discarded weathers: %w", err)
+	}
+
+	// group memberships, tags, names and astronomy days the canonical
+	// location already has are kept
+	for _, table := range []string{"location_group_members", "location_tags", "location_names", "astronomies"} {
+		if _, err = tx.ExecContext(ctx, `UPDATE IGNORE `+table+` SET location_id = ? WHERE location_id = ?`, canonicalID, duplicateID); err != nil {
+			return result, fmt.Errorf("failed to move %s: %w", table, err)
+		}
+		if _, err = tx.ExecContext(ctx, `DELETE FROM `+table+` WHERE location_id = ?`, duplicateID); err != nil {
+			return result, fmt.Errorf("failed to delete duplicate %s: %w", table, err)
+		}
+	}
+
+	// deleted locations give up their name key, so the name can be used again
+	_, err = tx.ExecContext(ctx, `UPDATE locations SET deleted_at = NOW(), name_key = NULL WHERE id = ?`, duplicateID)
+	if err != nil {
+		return result, fmt.Errorf("failed to delete duplicate location: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return result, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return result, nil
+}
+
+// UpsertLocations inserts the locations in one transaction. Locations with an
+// external key that already exists update the stored row instead, and are
+// restored when it was soft deleted. Results are in the same order as the
+// input.
+func (r *locationRepository) UpsertLocations(ctx context.Context, locations []domain.Location) ([]UpsertLocationResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	if len(locations) == 0 {
+		return nil, nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// LAST_INSERT_ID(id) makes the id of an updated row available as well
+	query := `INSERT INTO locations (external_key, name, region, country, latitude, longitude, timezone, name_key)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	          ON DUPLICATE KEY UPDATE
+	          id = LAST_INSERT_ID(id),
+	          name = VALUES(name),
+	          region = VALUES(region),
+	          country = VALUES(country),
+	          latitude = VALUES(latitude),
+	          longitude = VALUES(longitude),
+	          timezone = VALUES(timezone),
+	          name_key = VALUES(name_key),
+	          deleted_at = NULL`
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	results := make([]UpsertLocationResult, len(locations))
+	for i, location := range locations {
+		res, err := stmt.ExecContext(ctx,
+			location.ExternalKey,
+			location.Name,
+			location.Region,
+			location.Country,
+			location.Latitude,
+			location.Longitude,
+			location.Timezone,
+			domain.LocationNameKey(location.Name, location.Country),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to upsert location %s: %w", location.Name, wrapDuplicateKey(err))
+		}
+
+		id, err := res.LastInsertId()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get last insert id: %w", err)
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get affected rows: %w", err)
+		}
+
+		// MySQL reports 1 for an inserted row, 2 for an updated row and 0 when
+		// the existing row already had the same values
+		results[i] = UpsertLocationResult{ID: id, Status: UpsertStatusUnchanged}
+		switch affected {
+		case 1:
+			results[i].Status = UpsertStatusCreated
+		case 2:
+			results[i].Status = UpsertStatusUpdated
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return results, nil
+}
+
+// GetLocationTags returns the tags of every location, sorted by tag name.
+// Locations without tags are not in the map.
+func (r *locationRepository) GetLocationTags(ctx context.Context, locationIDs []int64) (map[int64][]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	tags := map[int64][]string{}
+	if len(locationIDs) == 0 {
+		return tags, nil
+	}
+
+	params := []interface{}{}
+	for _, id := range locationIDs {
+		params = append(params, id)
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT location_id, tag FROM location_tags WHERE location_id IN (`+placeholders(len(locationIDs))+`) ORDER BY location_id, tag`,
+		params...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query location tags: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var locationID int64
+		var tag string
+		if err := rows.Scan(&locationID, &tag); err != nil {
+			return nil, fmt.Errorf("failed to scan location tag: %w", err)
+		}
+		tags[locationID] = append(tags[locationID], tag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return tags, nil
+}
+
+// SetLocationTags replaces every tag of the location in one transaction.
+func (r *locationRepository) SetLocationTags(ctx context.Context, locationID int64, tags []string) error {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM location_tags WHERE location_id = ?`, locationID); err != nil {
+		return fmt.Errorf("failed to delete location tags: %w", err)
+	}
+
+	if len(tags) > 0 {
+		query := `INSERT INTO location_tags (location_id, tag) VALUES `
+		params := []interface{}{}
+		for i, tag := range tags {
+			if i > 0 {
+				query = query + ", "
+			}
+			query = query + "(?, ?)"
+			params = append(params, locationID, tag)
+		}
+
+		if _, err = tx.ExecContext(ctx, query, params...); err != nil {
+			return fmt.Errorf("failed to insert location tags: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetLocationNames returns the alternate names of every location, sorted by
+// language and name. Locations without names are not in the map.
+func (r *locationRepository) GetLocationNames(ctx context.Context, locationIDs []int64) (map[int64][]domain.LocationName, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	names := map[int64][]domain.LocationName{}
+	if len(locationIDs) == 0 {
+		return names, nil
+	}
+
+	params := []interface{}{}
+	for _, id := range locationIDs {
+		params = append(params, id)
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT location_id, name, language FROM location_names WHERE location_id IN (`+placeholders(len(locationIDs))+`) ORDER BY location_id, language, name`,
+		params...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query location names: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.LocationName
+		if err := rows.Scan(&item.LocationID, &item.Name, &item.Language); err != nil {
+			return nil, fmt.Errorf("failed to scan location name: %w", err)
+		}
+		names[item.LocationID] = append(names[item.LocationID], item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return names, nil
+}
+
+// SetLocationNames replaces every alternate name of the location in one
+// transaction.
+func (r *locationRepository) SetLocationNames(ctx context.Context, locationID int64, names []domain.LocationName) error {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, `DELETE FROM location_names WHERE location_id = ?`, locationID); err != nil {
+		return fmt.Errorf("failed to delete location names: %w", err)
+	}
+
+	if len(names) > 0 {
+		query := `INSERT INTO location_names (location_id, name, language) VALUES `
+		params := []interface{}{}
+		for i, name := range names {
+			if i > 0 {
+				query = query + ", "
+			}
+			query = query + "(?, ?, ?)"
+			params = append(params, locationID, name.Name, name.Language)
+		}
+
+		if _, err = tx.ExecContext(ctx, query, params...); err != nil {
+			return fmt.Errorf("failed to insert location names: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func scanLocations(rows *sql.Rows) ([]domain.Location, error) {
+	var locations []domain.Location
+	for rows.Next() {
+		var item domain.Location
+		if err := rows.Scan(
+			&item.ID,
+			&item.ExternalKey,
+			&item.Name,
+			&item.Region,
+			&item.Country,
+			&item.Latitude,
+			&item.Longitude,
+			&item.Timezone,
+			&item.CreatedAt,
+			&item.LastModifiedAt,
+			&item.DeletedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan location: %w", err)
+		}
+		locations = append(locations, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return locations, nil
+}
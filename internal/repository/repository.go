@@ -0,0 +1,51 @@
+package repository
+
+import "strings"
+
+// Keyset continues a listing after the row with the given sort value and id,
+// or before it when Backward is set.
+type Keyset struct {
+	Value    interface{}
+	ID       int64
+	Backward bool
+}
+
+// condition returns the WHERE condition selecting the rows past the keyset
+// for a listing sorted by column, then by id, in the given direction.
+func (k *Keyset) condition(column string, desc bool) (string, []interface{}) {
+	operator := ">"
+	if desc != k.Backward {
+		operator = "<"
+	}
+
+	return "(" + column + " " + operator + " ? OR (" + column + " = ? AND id " + operator + " ?))",
+		[]interface{}{k.Value, k.Value, k.ID}
+}
+
+// orderDirection returns the SQL direction of the listing. Backward listings
+// are read in reverse and flipped back by reverseRows.
+func (k *Keyset) orderDirection(desc bool) string {
+	if desc != (k != nil && k.Backward) {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+func reverseRows[T any](rows []T) {
+	for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+		rows[i], rows[j] = rows[j], rows[i]
+	}
+}
+
+// placeholders returns n comma separated bind variables for an IN clause.
+func placeholders(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// escapeLike escapes the LIKE wildcards so user input is matched literally.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
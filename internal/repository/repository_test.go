@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"tyarus/weather-app/pkg/geo"
+)
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected string
+	}{
+		{"WHEN value has no wildcard, THEN should keep it", "Jakarta", "Jakarta"},
+		{"WHEN value has a percent sign, THEN should escape it", "100%", `100\%`},
+		{"WHEN value has an underscore, THEN should escape it", "new_york", `new\_york`},
+		{"WHEN value has a backslash, THEN should escape it first", `a\%`, `a\\\%`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, escapeLike(tt.value))
+		})
+	}
+}
+
+func TestLocationFilter(t *testing.T) {
+	createdFrom := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		param    GetLocationsParam
+		where    string
+		expected []interface{}
+	}{
+		{
+			name:     "WHEN nothing is given, THEN should only skip deleted locations",
+			param:    GetLocationsParam{},
+			where:    "deleted_at IS NULL",
+			expected: []interface{}{},
+		},
+		{
+			name:     "WHEN deleted locations are included without filter, THEN should match every row",
+			param:    GetLocationsParam{IncludeDeleted: true},
+			where:    "1 = 1",
+			expected: []interface{}{},
+		},
+		{
+			name:  "WHEN name filters have wildcards, THEN should match them literally",
+			param: GetLocationsParam{NameLike: "50%_off", NamePrefix: "Ja"},
+			where: "deleted_at IS NULL" +
+				" AND (name LIKE ? OR id IN (SELECT location_id FROM location_names WHERE name LIKE ?))" +
+				" AND (name LIKE ? OR id IN (SELECT location_id FROM location_names WHERE name LIKE ?))",
+			expected: []interface{}{`%50\%\_off%`, `%50\%\_off%`, "Ja%", "Ja%"},
+		},
+		{
+			name:     "WHEN country and region are given, THEN should compare them as they are",
+			param:    GetLocationsParam{Country: "Indonesia", Region: "West Java", CreatedFrom: createdFrom},
+			where:    "deleted_at IS NULL AND country = ? AND region = ? AND created_at >= ?",
+			expected: []interface{}{"Indonesia", "West Java", createdFrom},
+		},
+		{
+			name:     "WHEN ids, group and tag are given, THEN should bind every value",
+			param:    GetLocationsParam{IDs: []int64{1, 2}, GroupID: 3, Tag: "airport"},
+			where:    "deleted_at IS NULL AND id IN (?,?) AND id IN (SELECT location_id FROM location_group_members WHERE group_id = ?) AND id IN (SELECT location_id FROM location_tags WHERE tag = ?)",
+			expected: []interface{}{int64(1), int64(2), int64(3), "airport"},
+		},
+		{
+			name:     "WHEN bounding box crosses the antimeridian, THEN should match both sides",
+			param:    GetLocationsParam{BoundingBox: &geo.BoundingBox{MinLatitude: -10, MaxLatitude: 10, MinLongitude: 170, MaxLongitude: -170}},
+			where:    "deleted_at IS NULL AND latitude BETWEEN ? AND ? AND (longitude >= ? OR longitude <= ?)",
+			expected: []interface{}{-10.0, 10.0, 170.0, -170.0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			where, params := locationFilter(tt.param)
+
+			assert.Equal(t, tt.where, where)
+			assert.Equal(t, tt.expected, params)
+		})
+	}
+}
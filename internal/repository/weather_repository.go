@@ -4,21 +4,123 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
+	"time"
 	"tyarus/weather-app/internal/domain"
 	"tyarus/weather-app/pkg/utils"
 )
 
+const weatherColumns = "id, location_id, temperature_celcius, temperature_fahrenheit, min_temperature_celcius, max_temperature_celcius, humidity, wind_speed, precipitation_mm, pressure_mb, visibility_km, condition_status, condition_code, condition_icon_url, forecast_time, forecast_type, created_at, last_modified_at, deleted_at"
+
+// GetWeathersParam lists weathers in OrderBy order. With After the listing
+// is keyset paginated on forecast time and id, newest first, and OrderBy is
+// ignored.
 type GetWeathersParam struct {
+	LocationID   int64
+	ForecastType string
+	After        *Keyset
+	Limit        int
+	Offset       int
+	OrderBy      string
+}
+
+type GetWeatherSummariesParam struct {
+	LocationIDs  []int64
+	CurrentTime  time.Time
+	ForecastDays int
+}
+
+// GetDailyWeathersParam selects the days From until To, both inclusive.
+type GetDailyWeathersParam struct {
 	LocationID int64
+	From       time.Time
+	To         time.Time
+}
+
+// GetWeatherSeriesParam selects the hourly rows of every location in
+// LocationIDs from From, inclusive, until To, exclusive.
+type GetWeatherSeriesParam struct {
+	LocationIDs []int64
+	From        time.Time
+	To          time.Time
+}
+
+// GetLatestWeathersParam selects, for every window, the hourly row of its
+// location with the latest forecast time in the window.
+type GetLatestWeathersParam struct {
+	Windows []LatestWeatherWindow
+}
+
+// LatestWeatherWindow bounds the forecast times of a location from From,
+// inclusive, until To, exclusive.
+type LatestWeatherWindow struct {
+	LocationID int64
+	From       time.Time
+	To         time.Time
+}
+
+// WeatherRetentionParam selects rows older than Before, not older than From
+// when it is set, of LocationID when it is set. Limit bounds the rows a
+// single delete removes.
+type WeatherRetentionParam struct {
+	LocationID int64
+	From       time.Time
+	Before     time.Time
 	Limit      int
-	Offset     int
-	OrderBy    string
+}
+
+// CountWeatherRetentionParam has a zero cutoff for every disabled policy.
+type CountWeatherRetentionParam struct {
+	HourlyBefore  time.Time
+	DeletedBefore time.Time
+}
+
+// StreamWeathersParam filters streamed weathers, every zero field matches
+// all rows. To is exclusive.
+type StreamWeathersParam struct {
+	LocationIDs    []int64
+	From           time.Time
+	To             time.Time
+	ForecastType   string
+	ConditionCodes []string
+	// Limit bounds the rows streamed, zero streams every row
+	Limit int
+}
+
+// GetAstronomiesParam selects the days of every location in LocationIDs
+// from From until To, both inclusive.
+type GetAstronomiesParam struct {
+	LocationIDs []int64
+	From        time.Time
+	To          time.Time
+}
+
+// UpdateConditionCodesParam maps up to Limit rows with the unknown
+// condition code and the provider text Status to Code.
+type UpdateConditionCodesParam struct {
+	Status string
+	Code   string
+	Limit  int
 }
 
 type WeatherRepositoryInterface interface {
 	GetWeathers(ctx context.Context, param GetWeathersParam) ([]domain.Weather, error)
+	GetWeatherSummaries(ctx context.Context, param GetWeatherSummariesParam) ([]domain.Weather, error)
 	BulkUpsertWeather(ctx context.Context, weathers []domain.Weather) ([]domain.Weather, error)
 	GetWeathersCount(ctx context.Context) (int, error)
+	GetDailyWeathers(ctx context.Context, param GetDailyWeathersParam) ([]domain.DailyWeather, error)
+	GetWeatherSeries(ctx context.Context, param GetWeatherSeriesParam) ([]domain.Weather, error)
+	GetLatestWeathers(ctx context.Context, param GetLatestWeathersParam) ([]domain.Weather, error)
+	CountWeatherRetention(ctx context.Context, param CountWeatherRetentionParam) (domain.WeatherRetentionCounts, error)
+	GetOldestHourlyForecastTime(ctx context.Context, locationID int64) (time.Time, error)
+	DownsampleHourlyWeathers(ctx context.Context, param WeatherRetentionParam) (int64, error)
+	DeleteHourlyWeathers(ctx context.Context, param WeatherRetentionParam) (int64, error)
+	PurgeDeletedWeathers(ctx context.Context, param WeatherRetentionParam) (int64, error)
+	StreamWeathers(ctx context.Context, param StreamWeathersParam, fn func(domain.Weather) error) error
+	GetUnknownConditionStatuses(ctx context.Context) ([]string, error)
+	UpdateConditionCodes(ctx context.Context, param UpdateConditionCodesParam) (int64, error)
+	BulkUpsertAstronomies(ctx context.Context, astronomies []domain.Astronomy) error
+	GetAstronomies(ctx context.Context, param GetAstronomiesParam) ([]domain.Astronomy, error)
 }
 
 type weatherRepository struct {
@@ -33,14 +135,26 @@ func (r *weatherRepository) GetWeathers(ctx context.Context, param GetWeathersPa
 	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
 	defer cancel()
 
-	query := `SELECT * FROM weathers WHERE deleted_at IS NULL`
+	query := `SELECT ` + weatherColumns + ` FROM weathers WHERE deleted_at IS NULL`
 	params := []interface{}{}
 	if param.LocationID != 0 {
 		query += " AND location_id = ?"
 		params = append(params, param.LocationID)
 	}
 
-	if param.OrderBy != "" {
+	if param.ForecastType != "" {
+		query += " AND forecast_type = ?"
+		params = append(params, param.ForecastType)
+	}
+
+	if param.After != nil {
+		condition, keysetParams := param.After.condition("forecast_time", true)
+		query += " AND " + condition
+		params = append(params, keysetParams...)
+
+		direction := param.After.orderDirection(true)
+		query += " ORDER BY forecast_time " + direction + ", id " + direction
+	} else if param.OrderBy != "" {
 		query += " ORDER BY " + param.OrderBy
 	} else {
 		query += " ORDER BY created_at DESC"
@@ -62,37 +176,168 @@ func (r *weatherRepository) GetWeathers(ctx context.Context, param GetWeathersPa
 	}
 	defer rows.Close()
 
+	weathers, err := scanWeathers(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if param.After != nil && param.After.Backward {
+		reverseRows(weathers)
+	}
+
+	return weathers, nil
+}
+
+// GetWeatherSummaries fetches, in a single query, the hourly rows around
+// CurrentTime and the daily rows of the next ForecastDays days for every
+// location in LocationIDs.
+func (r *weatherRepository) GetWeatherSummaries(ctx context.Context, param GetWeatherSummariesParam) ([]domain.Weather, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	if len(param.LocationIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT ` + weatherColumns + ` FROM weathers WHERE deleted_at IS NULL AND location_id IN (` + placeholders(len(param.LocationIDs)) + `)
+	          AND ((forecast_type = 'hour' AND forecast_time BETWEEN ? AND ?)
+	          OR (forecast_type = 'day' AND forecast_time >= ? AND forecast_time < ?))
+	          ORDER BY location_id, forecast_type, forecast_time`
+
+	params := []interface{}{}
+	for _, id := range param.LocationIDs {
+		params = append(params, id)
+	}
+
+	today := time.Date(param.CurrentTime.Year(), param.CurrentTime.Month(), param.CurrentTime.Day(), 0, 0, 0, 0, param.CurrentTime.Location())
+	params = append(params,
+		param.CurrentTime.Add(-time.Hour),
+		param.CurrentTime.Add(time.Hour),
+		today,
+		today.AddDate(0, 0, param.ForecastDays),
+	)
+
+	rows, err := r.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query weather summaries: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWeathers(rows)
+}
+
+// GetWeatherSeries fetches the hourly rows of many locations with a single
+// query, ordered by location and forecast time.
+func (r *weatherRepository) GetWeatherSeries(ctx context.Context, param GetWeatherSeriesParam) ([]domain.Weather, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	if len(param.LocationIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT ` + weatherColumns + ` FROM weathers WHERE deleted_at IS NULL AND location_id IN (` + placeholders(len(param.LocationIDs)) + `)
+	          AND forecast_type = 'hour' AND forecast_time >= ? AND forecast_time < ?
+	          ORDER BY location_id, forecast_time`
+
+	params := []interface{}{}
+	for _, id := range param.LocationIDs {
+		params = append(params, id)
+	}
+	params = append(params, param.From, param.To)
+
+	rows, err := r.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query weather series: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWeathers(rows)
+}
+
+// GetLatestWeathers fetches at most one hourly row per window with a single
+// query, ordered by location.
+func (r *weatherRepository) GetLatestWeathers(ctx context.Context, param GetLatestWeathersParam) ([]domain.Weather, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	if len(param.Windows) == 0 {
+		return nil, nil
+	}
+
+	// the windows are joined as a derived table so every location reads only
+	// its own range of the history
+	windows := make([]string, len(param.Windows))
+	params := []interface{}{}
+	for i, window := range param.Windows {
+		windows[i] = "SELECT ? AS window_location_id, ? AS window_from, ? AS window_to"
+		params = append(params, window.LocationID, window.From, window.To)
+	}
+
+	query := `SELECT ` + weatherColumns + ` FROM (
+	            SELECT ` + weatherColumns + `, ROW_NUMBER() OVER (PARTITION BY location_id ORDER BY forecast_time DESC, id DESC) AS row_num
+	            FROM weathers
+	            JOIN (` + strings.Join(windows, " UNION ALL ") + `) windows ON windows.window_location_id = location_id
+	            WHERE deleted_at IS NULL AND forecast_type = 'hour'
+	            AND forecast_time >= windows.window_from AND forecast_time < windows.window_to
+	          ) latest WHERE row_num = 1 ORDER BY location_id`
+
+	rows, err := r.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query latest weathers: %w", err)
+	}
+	defer rows.Close()
+
+	return scanWeathers(rows)
+}
+
+func scanWeathers(rows *sql.Rows) ([]domain.Weather, error) {
 	var weathers []domain.Weather
 	for rows.Next() {
-		var w domain.Weather
-		err := rows.Scan(
-			&w.ID,
-			&w.LocationID,
-			&w.TemperatureCelcius,
-			&w.TemperatureFahrenheit,
-			&w.Humidity,
-			&w.WindSpeed,
-			&w.ConditionStatus,
-			&w.ConditionIconURL,
-			&w.ForecastTime,
-			&w.ForecastType,
-			&w.CreatedAt,
-			&w.LastModifiedAt,
-			&w.DeletedAt,
-		)
+		w, err := scanWeather(rows)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan weather: %w", err)
+			return nil, err
 		}
 		weathers = append(weathers, w)
 	}
 
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("error iterating rows: %w", err)
 	}
 
 	return weathers, nil
 }
 
+func scanWeather(rows *sql.Rows) (domain.Weather, error) {
+	var w domain.Weather
+	err := rows.Scan(
+		&w.ID,
+		&w.LocationID,
+		&w.TemperatureCelcius,
+		&w.TemperatureFahrenheit,
+		&w.MinTemperatureCelcius,
+		&w.MaxTemperatureCelcius,
+		&w.Humidity,
+		&w.WindSpeed,
+		&w.PrecipitationMM,
+		&w.PressureMB,
+		&w.VisibilityKM,
+		&w.ConditionStatus,
+		&w.ConditionCode,
+		&w.ConditionIconURL,
+		&w.ForecastTime,
+		&w.ForecastType,
+		&w.CreatedAt,
+		&w.LastModifiedAt,
+		&w.DeletedAt,
+	)
+	if err != nil {
+		return w, fmt.Errorf("failed to scan weather: %w", err)
+	}
+
+	return w, nil
+}
+
 func (r *weatherRepository) BulkUpsertWeather(ctx context.Context, weathers []domain.Weather) ([]domain.Weather, error) {
 	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
 	defer cancel()
@@ -107,14 +352,20 @@ func (r *weatherRepository) BulkUpsertWeather(ctx context.Context, weathers []do
 	}
 	defer tx.Rollback()
 
-	query := `INSERT INTO weathers (location_id, temperature_celcius, temperature_fahrenheit, humidity, wind_speed, condition_status, condition_icon_url, forecast_time, forecast_type) 
-	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	query := `INSERT INTO weathers (location_id, temperature_celcius, temperature_fahrenheit, min_temperature_celcius, max_temperature_celcius, humidity, wind_speed, precipitation_mm, pressure_mb, visibility_km, condition_status, condition_code, condition_icon_url, forecast_time, forecast_type) 
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 			  ON DUPLICATE KEY UPDATE 
 			  temperature_celcius = VALUES(temperature_celcius),
 			  temperature_fahrenheit = VALUES(temperature_fahrenheit),
+			  min_temperature_celcius = VALUES(min_temperature_celcius),
+			  max_temperature_celcius = VALUES(max_temperature_celcius),
 			  humidity = VALUES(humidity),
 			  wind_speed = VALUES(wind_speed),
+			  precipitation_mm = VALUES(precipitation_mm),
+			  pressure_mb = VALUES(pressure_mb),
+			  visibility_km = VALUES(visibility_km),
 			  condition_status = VALUES(condition_status),
+			  condition_code = VALUES(condition_code),
 			  condition_icon_url = VALUES(condition_icon_url)`
 
 	stmt, err := tx.PrepareContext(ctx, query)
@@ -130,9 +381,15 @@ func (r *weatherRepository) BulkUpsertWeather(ctx context.Context, weathers []do
 			weather.LocationID,
 			weather.TemperatureCelcius,
 			weather.TemperatureFahrenheit,
+			weather.MinTemperatureCelcius,
+			weather.MaxTemperatureCelcius,
 			weather.Humidity,
 			weather.WindSpeed,
+			weather.PrecipitationMM,
+			weather.PressureMB,
+			weather.VisibilityKM,
 			weather.ConditionStatus,
+			weather.ConditionCode,
 			weather.ConditionIconURL,
 			weather.ForecastTime,
 			weather.ForecastType,
@@ -161,3 +418,454 @@ func (r *weatherRepository) GetWeathersCount(ctx context.Context) (int, error) {
 	}
 	return count, nil
 }
+
+// forecastDate is the date of a forecast time column. Forecast times are the
+// wall clock of the location stored as UTC, so the date is taken in UTC
+// whatever the time zone of the session is.
+func forecastDate(column string) string {
+	return fmt.Sprintf("DATE(CONVERT_TZ(%s, @@session.time_zone, '+00:00'))", column)
+}
+
+// GetDailyWeathers aggregates the hourly rows of every day with at least one
+// hourly row. The dominant condition is the most frequent one, the earliest
+// wins a tie. Days left with only their daily row, e.g. after retention
+// downsampled them, are returned from that row with zero hours.
+func (r *weatherRepository) GetDailyWeathers(ctx context.Context, param GetDailyWeathersParam) ([]domain.DailyWeather, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	query := fmt.Sprintf(`WITH hourly AS (
+	              SELECT %s AS day, forecast_time, temperature_celcius, humidity, wind_speed, precipitation_mm, condition_status
+	              FROM weathers
+	              WHERE deleted_at IS NULL AND location_id = ? AND forecast_type = 'hour'
+	              AND forecast_time >= ? AND forecast_time < ?
+	          ),
+	          conditions AS (
+	              SELECT day, condition_status,
+	              ROW_NUMBER() OVER (PARTITION BY day ORDER BY COUNT(*) DESC, MIN(forecast_time)) AS position
+	              FROM hourly
+	              GROUP BY day, condition_status
+	          )
+	          SELECT h.day AS day, COUNT(*),
+	          MIN(h.temperature_celcius), MAX(h.temperature_celcius), AVG(h.temperature_celcius),
+	          AVG(h.humidity), MAX(h.wind_speed), SUM(h.precipitation_mm),
+	          c.condition_status,
+	          ANY_VALUE(d.temperature_celcius), ANY_VALUE(d.wind_speed), ANY_VALUE(d.precipitation_mm), ANY_VALUE(d.condition_status), ANY_VALUE(d.condition_code)
+	          FROM hourly h
+	          JOIN conditions c ON c.day = h.day AND c.position = 1
+	          LEFT JOIN weathers d ON d.location_id = ? AND d.forecast_type = 'day' AND d.deleted_at IS NULL AND %s = h.day
+	          GROUP BY h.day, c.condition_status
+	          UNION ALL
+	          SELECT %s, 0,
+	          COALESCE(d.min_temperature_celcius, d.temperature_celcius), COALESCE(d.max_temperature_celcius, d.temperature_celcius), d.temperature_celcius,
+	          d.humidity, d.wind_speed, d.precipitation_mm,
+	          d.condition_status,
+	          d.temperature_celcius, d.wind_speed, d.precipitation_mm, d.condition_status, d.condition_code
+	          FROM weathers d
+	          WHERE d.deleted_at IS NULL AND d.location_id = ? AND d.forecast_type = 'day'
+	          AND d.forecast_time >= ? AND d.forecast_time < ?
+	          AND %s NOT IN (SELECT day FROM hourly)
+	          ORDER BY day`, forecastDate("forecast_time"), forecastDate("d.forecast_time"), forecastDate("d.forecast_time"), forecastDate("d.forecast_time"))
+
+	rows, err := r.db.QueryContext(ctx, query,
+		param.LocationID,
+		param.From,
+		param.To.AddDate(0, 0, 1),
+		param.LocationID,
+		param.LocationID,
+		param.From,
+		param.To.AddDate(0, 0, 1),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query daily weathers: %w", err)
+	}
+	defer rows.Close()
+
+	var days []domain.DailyWeather
+	for rows.Next() {
+		var d domain.DailyWeather
+		err := rows.Scan(
+			&d.Date,
+			&d.Hours,
+			&d.MinTemperatureCelcius,
+			&d.MaxTemperatureCelcius,
+			&d.AvgTemperatureCelcius,
+			&d.AvgHumidity,
+			&d.MaxWindSpeed,
+			&d.TotalPrecipitationMM,
+			&d.DominantCondition,
+			&d.SummaryTemperature,
+			&d.SummaryWindSpeed,
+			&d.SummaryPrecipitationMM,
+			&d.SummaryCondition,
+			&d.SummaryConditionCode,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan daily weather: %w", err)
+		}
+		days = append(days, d)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return days, nil
+}
+
+// CountWeatherRetention counts the days DownsampleHourlyWeathers would add,
+// the hourly rows DeleteHourlyWeathers would remove and the soft deleted rows
+// PurgeDeletedWeathers would remove after them.
+func (r *weatherRepository) CountWeatherRetention(ctx context.Context, param CountWeatherRetentionParam) (domain.WeatherRetentionCounts, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.RetentionDBTimeout)
+	defer cancel()
+
+	var counts domain.WeatherRetentionCounts
+	if !param.HourlyBefore.IsZero() {
+		query := fmt.Sprintf(`SELECT COUNT(*) FROM (
+		              SELECT location_id, %s AS day
+		              FROM weathers
+		              WHERE deleted_at IS NULL AND forecast_type = 'hour' AND forecast_time < ?
+		              GROUP BY location_id, day
+		          ) h
+		          WHERE NOT EXISTS (
+		              SELECT 1 FROM weathers d
+		              WHERE d.location_id = h.location_id AND d.forecast_type = 'day' AND %s = h.day
+		          )`, forecastDate("forecast_time"), forecastDate("d.forecast_time"))
+		if err := r.db.QueryRowContext(ctx, query, param.HourlyBefore).Scan(&counts.DownsampleDays); err != nil {
+			return counts, fmt.Errorf("failed to count days to downsample: %w", err)
+		}
+
+		query = `SELECT COUNT(*) FROM weathers WHERE forecast_type = 'hour' AND forecast_time < ?`
+		if err := r.db.QueryRowContext(ctx, query, param.HourlyBefore).Scan(&counts.HourlyRows); err != nil {
+			return counts, fmt.Errorf("failed to count hourly weathers to delete: %w", err)
+		}
+	}
+
+	if !param.DeletedBefore.IsZero() {
+		query := `SELECT COUNT(*) FROM weathers WHERE deleted_at < ?`
+		params := []interface{}{param.DeletedBefore}
+		// hourly rows are already counted above
+		if !param.HourlyBefore.IsZero() {
+			query += ` AND NOT (forecast_type = 'hour' AND forecast_time < ?)`
+			params = append(params, param.HourlyBefore)
+		}
+
+		if err := r.db.QueryRowContext(ctx, query, params...).Scan(&counts.DeletedRows); err != nil {
+			return counts, fmt.Errorf("failed to count deleted weathers to purge: %w", err)
+		}
+	}
+
+	return counts, nil
+}
+
+// GetOldestHourlyForecastTime returns the earliest forecast time of the
+// hourly rows of the location, soft deleted or not, or the zero time when it
+// has none.
+func (r *weatherRepository) GetOldestHourlyForecastTime(ctx context.Context, locationID int64) (time.Time, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	query := `SELECT MIN(forecast_time) FROM weathers WHERE location_id = ? AND forecast_type = 'hour'`
+	var oldest sql.NullTime
+	if err := r.db.QueryRowContext(ctx, query, locationID).Scan(&oldest); err != nil {
+		return time.Time{}, fmt.Errorf("failed to get oldest hourly forecast time: %w", err)
+	}
+
+	return oldest.Time, nil
+}
+
+// DownsampleHourlyWeathers adds a daily row aggregated from the hourly rows
+// of every day of the location from From until Before that has none. From
+// and Before must be the start of a forecast day, see forecastDate. Existing
+// daily rows from the provider are kept.
+func (r *weatherRepository) DownsampleHourlyWeathers(ctx context.Context, param WeatherRetentionParam) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.RetentionDBTimeout)
+	defer cancel()
+
+	// the day is a UTC date, it is stored back as its midnight in UTC like
+	// the daily rows of the provider
+	query := fmt.Sprintf(`INSERT INTO weathers (location_id, temperature_celcius, temperature_fahrenheit, min_temperature_celcius, max_temperature_celcius, humidity, wind_speed, precipitation_mm, condition_status, condition_code, condition_icon_url, forecast_time, forecast_type)
+	          WITH hourly AS (
+	              SELECT %s AS day, forecast_time, temperature_celcius, temperature_fahrenheit, humidity, wind_speed, precipitation_mm, condition_status, condition_code, condition_icon_url
+	              FROM weathers
+	              WHERE deleted_at IS NULL AND location_id = ? AND forecast_type = 'hour' AND forecast_time >= ? AND forecast_time < ?
+	          ),
+	          conditions AS (
+	              SELECT day, condition_status, ANY_VALUE(condition_code) AS condition_code, ANY_VALUE(condition_icon_url) AS condition_icon_url,
+	              ROW_NUMBER() OVER (PARTITION BY day ORDER BY COUNT(*) DESC, MIN(forecast_time)) AS position
+	              FROM hourly
+	              GROUP BY day, condition_status
+	          )
+	          SELECT ?, ROUND(AVG(h.temperature_celcius), 2), ROUND(AVG(h.temperature_fahrenheit), 2),
+	          MIN(h.temperature_celcius), MAX(h.temperature_celcius), ROUND(AVG(h.humidity)),
+	          MAX(h.wind_speed), SUM(h.precipitation_mm), c.condition_status, c.condition_code, c.condition_icon_url,
+	          CONVERT_TZ(h.day, '+00:00', @@session.time_zone), 'day'
+	          FROM hourly h
+	          JOIN conditions c ON c.day = h.day AND c.position = 1
+	          WHERE NOT EXISTS (
+	              SELECT 1 FROM weathers d
+	              WHERE d.location_id = ? AND d.forecast_type = 'day' AND %s = h.day
+	          )
+	          GROUP BY h.day, c.condition_status, c.condition_code, c.condition_icon_url`, forecastDate("forecast_time"), forecastDate("d.forecast_time"))
+
+	result, err := r.db.ExecContext(ctx, query, param.LocationID, param.From, param.Before, param.LocationID, param.LocationID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to downsample hourly weathers: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// DeleteHourlyWeathers hard deletes up to Limit hourly rows of the location
+// before Before, soft deleted or not.
+func (r *weatherRepository) DeleteHourlyWeathers(ctx context.Context, param WeatherRetentionParam) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	query := `DELETE FROM weathers WHERE location_id = ? AND forecast_type = 'hour' AND forecast_time < ? LIMIT ?`
+	result, err := r.db.ExecContext(ctx, query, param.LocationID, param.Before, param.Limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete hourly weathers: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// GetUnknownConditionStatuses lists the distinct provider texts of the rows
+// with the unknown condition code.
+func (r *weatherRepository) GetUnknownConditionStatuses(ctx context.Context) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.RetentionDBTimeout)
+	defer cancel()
+
+	query := `SELECT DISTINCT condition_status FROM weathers WHERE condition_code = 'unknown'`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get unknown condition statuses: %w", err)
+	}
+	defer rows.Close()
+
+	var statuses []string
+	for rows.Next() {
+		var status string
+		if err := rows.Scan(&status); err != nil {
+			return nil, fmt.Errorf("failed to scan condition status: %w", err)
+		}
+		statuses = append(statuses, status)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return statuses, nil
+}
+
+// UpdateConditionCodes sets the condition code of up to Limit rows with the
+// unknown code and the provider text Status.
+func (r *weatherRepository) UpdateConditionCodes(ctx context.Context, param UpdateConditionCodesParam) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	query := `UPDATE weathers SET condition_code = ? WHERE condition_code = 'unknown' AND condition_status = ? LIMIT ?`
+	result, err := r.db.ExecContext(ctx, query, param.Code, param.Status, param.Limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to update condition codes: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// PurgeDeletedWeathers hard deletes up to Limit rows soft deleted before
+// Before.
+func (r *weatherRepository) PurgeDeletedWeathers(ctx context.Context, param WeatherRetentionParam) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	query := `DELETE FROM weathers WHERE deleted_at < ? LIMIT ?`
+	result, err := r.db.ExecContext(ctx, query, param.Before, param.Limit)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted weathers: %w", err)
+	}
+
+	return result.RowsAffected()
+}
+
+// StreamWeathers calls fn with every matching row, ordered by location and
+// forecast time, as it is read from the database so the result never has to
+// fit in memory. The query has no timeout of its own, it ends with ctx. An
+// error returned by fn stops the stream and is returned as is.
+func (r *weatherRepository) StreamWeathers(ctx context.Context, param StreamWeathersParam, fn func(domain.Weather) error) error {
+	query := `SELECT ` + weatherColumns + ` FROM weathers WHERE deleted_at IS NULL`
+	params := []interface{}{}
+	if len(param.LocationIDs) > 0 {
+		query += ` AND location_id IN (` + placeholders(len(param.LocationIDs)) + `)`
+		for _, id := range param.LocationIDs {
+			params = append(params, id)
+		}
+	}
+
+	if !param.From.IsZero() {
+		query += " AND forecast_time >= ?"
+		params = append(params, param.From)
+	}
+
+	if !param.To.IsZero() {
+		query += " AND forecast_time < ?"
+		params = append(params, param.To)
+	}
+
+	if param.ForecastType != "" {
+		query += " AND forecast_type = ?"
+		params = append(params, param.ForecastType)
+	}
+
+	if len(param.ConditionCodes) > 0 {
+		query += ` AND condition_code IN (` + placeholders(len(param.ConditionCodes)) + `)`
+		for _, code := range param.ConditionCodes {
+			params = append(params, code)
+		}
+	}
+
+	query += " ORDER BY location_id, forecast_time, forecast_type"
+	if param.Limit > 0 {
+		query += " LIMIT ?"
+		params = append(params, param.Limit)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return fmt.Errorf("failed to query weathers: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		w, err := scanWeather(rows)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(w); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return nil
+}
+
+func (r *weatherRepository) BulkUpsertAstronomies(ctx context.Context, astronomies []domain.Astronomy) error {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	if len(astronomies) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO astronomies (location_id, date, sunrise, sunset, moonrise, moonset, daylight_minutes, moon_phase, moon_illumination, source)
+	          VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	          ON DUPLICATE KEY UPDATE
+	          sunrise = VALUES(sunrise),
+	          sunset = VALUES(sunset),
+	          moonrise = VALUES(moonrise),
+	          moonset = VALUES(moonset),
+	          daylight_minutes = VALUES(daylight_minutes),
+	          moon_phase = VALUES(moon_phase),
+	          moon_illumination = VALUES(moon_illumination),
+	          source = VALUES(source)`
+
+	stmt, err := tx.PrepareContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("failed to prepare statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, astronomy := range astronomies {
+		_, err := stmt.ExecContext(
+			ctx,
+			astronomy.LocationID,
+			astronomy.Date.Format(utils.DateFormat),
+			astronomy.Sunrise,
+			astronomy.Sunset,
+			astronomy.Moonrise,
+			astronomy.Moonset,
+			astronomy.DaylightMinutes,
+			astronomy.MoonPhase,
+			astronomy.MoonIllumination,
+			astronomy.Source,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to upsert astronomy: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *weatherRepository) GetAstronomies(ctx context.Context, param GetAstronomiesParam) ([]domain.Astronomy, error) {
+	ctx, cancel := context.WithTimeout(ctx, utils.DefaultDBTimeout)
+	defer cancel()
+
+	if len(param.LocationIDs) == 0 {
+		return nil, nil
+	}
+
+	query := `SELECT id, location_id, date, sunrise, sunset, moonrise, moonset, daylight_minutes, moon_phase, moon_illumination, source, created_at, last_modified_at
+	          FROM astronomies WHERE location_id IN (` + placeholders(len(param.LocationIDs)) + `) AND date BETWEEN ? AND ?
+	          ORDER BY location_id, date`
+
+	params := []interface{}{}
+	for _, id := range param.LocationIDs {
+		params = append(params, id)
+	}
+	params = append(params, param.From.Format(utils.DateFormat), param.To.Format(utils.DateFormat))
+
+	rows, err := r.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query astronomies: %w", err)
+	}
+	defer rows.Close()
+
+	var astronomies []domain.Astronomy
+	for rows.Next() {
+		var a domain.Astronomy
+		err := rows.Scan(
+			&a.ID,
+			&a.LocationID,
+			&a.Date,
+			&a.Sunrise,
+			&a.Sunset,
+			&a.Moonrise,
+			&a.Moonset,
+			&a.DaylightMinutes,
+			&a.MoonPhase,
+			&a.MoonIllumination,
+			&a.Source,
+			&a.CreatedAt,
+			&a.LastModifiedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan astronomy: %w", err)
+		}
+		astronomies = append(astronomies, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return astronomies, nil
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordedQuery is a statement run on a recordingConn with its arguments.
+type recordedQuery struct {
+	query string
+	args  []driver.Value
+}
+
+// recordingConn is a database connection that records every statement and
+// answers them with fixed results, enough to test the SQL a repository
+// sends without a database.
+type recordingConn struct {
+	queries  []recordedQuery
+	affected int64
+	columns  []string
+	values   [][]driver.Value
+}
+
+func newRecordingDB(conn *recordingConn) *sql.DB {
+	return sql.OpenDB(recordingConnector{conn: conn})
+}
+
+type recordingConnector struct {
+	conn *recordingConn
+}
+
+func (c recordingConnector) Connect(context.Context) (driver.Conn, error) { return c.conn, nil }
+func (c recordingConnector) Driver() driver.Driver                        { return recordingDriver{conn: c.conn} }
+
+type recordingDriver struct {
+	conn *recordingConn
+}
+
+func (d recordingDriver) Open(string) (driver.Conn, error) { return d.conn, nil }
+
+func (c *recordingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not supported")
+}
+func (c *recordingConn) Close() error { return nil }
+func (c *recordingConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+func (c *recordingConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.record(query, args)
+	return driver.RowsAffected(c.affected), nil
+}
+
+func (c *recordingConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.record(query, args)
+	return &recordingRows{columns: c.columns, values: c.values}, nil
+}
+
+func (c *recordingConn) record(query string, args []driver.NamedValue) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg.Value
+	}
+	c.queries = append(c.queries, recordedQuery{query: query, args: values})
+}
+
+type recordingRows struct {
+	columns []string
+	values  [][]driver.Value
+}
+
+func (r *recordingRows) Columns() []string { return r.columns }
+func (r *recordingRows) Close() error      { return nil }
+
+func (r *recordingRows) Next(dest []driver.Value) error {
+	if len(r.values) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.values[0])
+	r.values = r.values[1:]
+	return nil
+}
+
+func TestWeatherRetentionQueries(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("WHEN downsampling, THEN should aggregate the forecast days of the window with their range", func(t *testing.T) {
+		conn := &recordingConn{affected: 3}
+		repo := NewWeatherRepository(newRecordingDB(conn))
+
+		days, err := repo.DownsampleHourlyWeathers(context.Background(), WeatherRetentionParam{LocationID: 7, From: from, Before: before})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), days)
+		if assert.Len(t, conn.queries, 1) {
+			query := conn.queries[0]
+			assert.Contains(t, query.query, forecastDate("forecast_time")+" AS day")
+			assert.Contains(t, query.query, "AND "+forecastDate("d.forecast_time")+" = h.day")
+			assert.Contains(t, query.query, "forecast_time >= ? AND forecast_time < ?")
+			assert.Contains(t, query.query, "MIN(h.temperature_celcius), MAX(h.temperature_celcius)")
+			assert.Contains(t, query.query, "CONVERT_TZ(h.day, '+00:00', @@session.time_zone)")
+			assert.NotContains(t, query.query, "DATE(forecast_time)")
+			assert.Equal(t, []driver.Value{int64(7), from, before, int64(7), int64(7)}, query.args)
+		}
+	})
+
+	t.Run("WHEN counting, THEN should group by the same forecast days as downsampling", func(t *testing.T) {
+		conn := &recordingConn{columns: []string{"count"}, values: [][]driver.Value{{int64(0)}}}
+		repo := NewWeatherRepository(newRecordingDB(conn))
+
+		_, err := repo.CountWeatherRetention(context.Background(), CountWeatherRetentionParam{HourlyBefore: before})
+
+		assert.NoError(t, err)
+		if assert.Len(t, conn.queries, 2) {
+			assert.Contains(t, conn.queries[0].query, forecastDate("forecast_time")+" AS day")
+			assert.Contains(t, conn.queries[0].query, forecastDate("d.forecast_time")+" = h.day")
+			assert.NotContains(t, conn.queries[0].query, "DATE(forecast_time)")
+		}
+	})
+
+	t.Run("WHEN deleting hourly rows, THEN should bound the batch", func(t *testing.T) {
+		conn := &recordingConn{affected: 2}
+		repo := NewWeatherRepository(newRecordingDB(conn))
+
+		deleted, err := repo.DeleteHourlyWeathers(context.Background(), WeatherRetentionParam{LocationID: 7, Before: before, Limit: 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+		if assert.Len(t, conn.queries, 1) {
+			assert.Contains(t, conn.queries[0].query, "LIMIT ?")
+			assert.Equal(t, []driver.Value{int64(7), before, int64(2)}, conn.queries[0].args)
+		}
+	})
+
+	t.Run("WHEN the location has no hourly row, THEN should return the zero time", func(t *testing.T) {
+		conn := &recordingConn{columns: []string{"oldest"}, values: [][]driver.Value{{nil}}}
+		repo := NewWeatherRepository(newRecordingDB(conn))
+
+		oldest, err := repo.GetOldestHourlyForecastTime(context.Background(), 7)
+
+		assert.NoError(t, err)
+		assert.True(t, oldest.IsZero())
+	})
+
+	t.Run("WHEN the location has hourly rows, THEN should return the oldest forecast time", func(t *testing.T) {
+		conn := &recordingConn{columns: []string{"oldest"}, values: [][]driver.Value{{from}}}
+		repo := NewWeatherRepository(newRecordingDB(conn))
+
+		oldest, err := repo.GetOldestHourlyForecastTime(context.Background(), 7)
+
+		assert.NoError(t, err)
+		assert.Equal(t, from, oldest)
+		assert.Equal(t, []driver.Value{int64(7)}, conn.queries[0].args)
+	})
+}
+
+func TestGetLatestWeathersQuery(t *testing.T) {
+	from := time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	conn := &recordingConn{columns: []string{"id"}}
+	repo := NewWeatherRepository(newRecordingDB(conn))
+
+	_, err := repo.GetLatestWeathers(context.Background(), GetLatestWeathersParam{Windows: []LatestWeatherWindow{
+		{LocationID: 1, From: from, To: to},
+		{LocationID: 2, From: from.Add(time.Hour), To: to.Add(time.Hour)},
+	}})
+
+	assert.NoError(t, err)
+	if assert.Len(t, conn.queries, 1) {
+		query := conn.queries[0]
+		assert.Contains(t, query.query, "forecast_time >= windows.window_from AND forecast_time < windows.window_to")
+		assert.Equal(t, 1, strings.Count(query.query, "UNION ALL"))
+		assert.Equal(t, []driver.Value{int64(1), from, to, int64(2), from.Add(time.Hour), to.Add(time.Hour)}, query.args)
+	}
+}
+
+func TestUpdateConditionCodesQuery(t *testing.T) {
+	conn := &recordingConn{affected: 2}
+	repo := NewWeatherRepository(newRecordingDB(conn))
+
+	updated, err := repo.UpdateConditionCodes(context.Background(), UpdateConditionCodesParam{Status: "Light rain", Code: "rain", Limit: 100})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), updated)
+	if assert.Len(t, conn.queries, 1) {
+		assert.Contains(t, conn.queries[0].query, "WHERE condition_code = 'unknown' AND condition_status = ? LIMIT ?")
+		assert.Equal(t, []driver.Value{"rain", "Light rain", int64(100)}, conn.queries[0].args)
+	}
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"tyarus/weather-app/internal/domain"
+	"tyarus/weather-app/internal/dto"
+	"tyarus/weather-app/internal/repository"
+	"tyarus/weather-app/pkg/response"
+	"tyarus/weather-app/pkg/utils"
+)
+
+var (
+	ErrLocationGroupNotFound = errors.New("location group not found, please check your parameter")
+	ErrLocationGroupExists   = errors.New("location group with the same name already exists")
+)
+
+type LocationGroupUsecaseInterface interface {
+	GetLocationGroupsUsecase(ctx context.Context, param dto.GetLocationGroupsParam) (response.Response[response.PaginationData[dto.LocationGroupResponseItem]], error)
+	GetLocationGroupUsecase(ctx context.Context, id int64) (dto.GetLocationGroupDetailResponse, error)
+	CreateLocationGroupUsecase(ctx context.Context, req dto.PostLocationGroupRequest) (dto.LocationGroupResponseItem, error)
+	UpdateLocationGroupUsecase(ctx context.Context, req dto.PostLocationGroupRequest) (dto.LocationGroupResponseItem, error)
+	DeleteLocationGroupUsecase(ctx context.Context, id int64) error
+	AddLocationGroupMembersUsecase(ctx context.Context, req dto.LocationGroupMembersRequest) (dto.LocationGroupMembersResponse, error)
+	RemoveLocationGroupMembersUsecase(ctx context.Context, req dto.LocationGroupMembersRequest) (dto.LocationGroupMembersResponse, error)
+	SyncLocationGroupWeatherUsecase(ctx context.Context, req dto.PostWeatherSyncUsecaseRequest) error
+}
+
+type locationGroupUsecase struct {
+	locationGroupRepo repository.LocationGroupRepositoryInterface
+	locationRepo      repository.LocationRepositoryInterface
+	weatherUc         WeatherUsecaseInterface
+}
+
+func NewLocationGroupUsecase(
+	locationGroupRepo repository.LocationGroupRepositoryInterface,
+	locationRepo repository.LocationRepositoryInterface,
+	weatherUc WeatherUsecaseInterface,
+) LocationGroupUsecaseInterface {
+	return &locationGroupUsecase{
+		locationGroupRepo: locationGroupRepo,
+		locationRepo:      locationRepo,
+		weatherUc:         weatherUc,
+	}
+}
+
+func (u *locationGroupUsecase) GetLocationGroupsUsecase(ctx context.Context, param dto.GetLocationGroupsParam) (response.Response[response.PaginationData[dto.LocationGroupResponseItem]], error) {
+	if param.PageSize <= 0 {
+		param.PageSize = 10
+	}
+	if param.CurrentPage <= 0 {
+		param.CurrentPage = 1
+	}
+
+	resp := response.Response[response.PaginationData[dto.LocationGroupResponseItem]]{}
+	groups, err := u.locationGroupRepo.GetLocationGroups(ctx, repository.GetLocationGroupsParam{
+		Limit:  param.PageSize,
+		Offset: (param.CurrentPage - 1) * param.PageSize,
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	count, err := u.locationGroupRepo.GetLocationGroupsCount(ctx)
+	if err != nil {
+		return resp, err
+	}
+
+	resp.Data.Items = dto.ParseToLocationGroupResponses(groups)
+	resp.Data.Total = count
+	resp.Data.CurrentPage = param.CurrentPage
+	resp.Data.PageSize = param.PageSize
+
+	return resp, nil
+}
+
+func (u *locationGroupUsecase) GetLocationGroupUsecase(ctx context.Context, id int64) (dto.GetLocationGroupDetailResponse, error) {
+	resp := dto.GetLocationGroupDetailResponse{}
+	group, err := u.getLocationGroup(ctx, id)
+	if err != nil {
+		return resp, err
+	}
+
+	locations, err := u.locationRepo.GetLocations(ctx, repository.GetLocationsParam{
+		GroupID: id,
+		OrderBy: utils.OrderByNameAsc,
+	})
+	if err != nil {
+		return resp, fmt.Errorf("failed to get group locations: %w", err)
+	}
+
+	resp.LocationGroupResponseItem = dto.ParseToLocationGroupResponse(group)
+	resp.Locations = dto.ParseToGetLocationHandlerResponses(locations)
+	return resp, nil
+}
+
+func (u *locationGroupUsecase) CreateLocationGroupUsecase(ctx context.Context, req dto.PostLocationGroupRequest) (dto.LocationGroupResponseItem, error) {
+	group, err := u.locationGroupRepo.InsertLocationGroup(ctx, req.PostLocationGroupRequestToDomain())
+	if errors.Is(err, repository.ErrDuplicateKey) {
+		return dto.LocationGroupResponseItem{}, ErrLocationGroupExists
+	}
+	if err != nil {
+		return dto.LocationGroupResponseItem{}, err
+	}
+
+	return dto.ParseToLocationGroupResponse(group), nil
+}
+
+func (u *locationGroupUsecase) UpdateLocationGroupUsecase(ctx context.Context, req dto.PostLocationGroupRequest) (dto.LocationGroupResponseItem, error) {
+	if _, err := u.getLocationGroup(ctx, req.ID); err != nil {
+		return dto.LocationGroupResponseItem{}, err
+	}
+
+	err := u.locationGroupRepo.UpdateLocationGroup(ctx, req.PostLocationGroupRequestToDomain())
+	if errors.Is(err, repository.ErrDuplicateKey) {
+		return dto.LocationGroupResponseItem{}, ErrLocationGroupExists
+	}
+	if err != nil {
+		return dto.LocationGroupResponseItem{}, err
+	}
+
+	group, err := u.getLocationGroup(ctx, req.ID)
+	if err != nil {
+		return dto.LocationGroupResponseItem{}, err
+	}
+
+	return dto.ParseToLocationGroupResponse(group), nil
+}
+
+func (u *locationGroupUsecase) DeleteLocationGroupUsecase(ctx context.Context, id int64) error {
+	if _, err := u.getLocationGroup(ctx, id); err != nil {
+		return err
+	}
+
+	return u.locationGroupRepo.DeleteLocationGroup(ctx, id)
+}
+
+// AddLocationGroupMembersUsecase adds the existing locations to the group and
+// reports the ids that do not belong to any active location.
+func (u *locationGroupUsecase) AddLocationGroupMembersUsecase(ctx context.Context, req dto.LocationGroupMembersRequest) (dto.LocationGroupMembersResponse, error) {
+	resp := dto.LocationGroupMembersResponse{}
+	if _, err := u.getLocationGroup(ctx, req.GroupID); err != nil {
+		return resp, err
+	}
+
+	locations, err := u.locationRepo.GetLocations(ctx, repository.GetLocationsParam{IDs: req.LocationIDs})
+	if err != nil {
+		return resp, fmt.Errorf("failed to get locations: %w", err)
+	}
+
+	found := []int64{}
+	for _, location := range locations {
+		found = append(found, location.ID)
+	}
+	for _, id := range req.LocationIDs {
+		if !slices.Contains(found, id) && !slices.Contains(resp.NotFoundLocationIDs, id) {
+			resp.NotFoundLocationIDs = append(resp.NotFoundLocationIDs, id)
+		}
+	}
+
+	resp.Affected, err = u.locationGroupRepo.AddLocationGroupMembers(ctx, req.GroupID, found)
+	if err != nil {
+		return resp, err
+	}
+
+	return u.withGroup(ctx, req.GroupID, resp)
+}
+
+func (u *locationGroupUsecase) RemoveLocationGroupMembersUsecase(ctx context.Context, req dto.LocationGroupMembersRequest) (dto.LocationGroupMembersResponse, error) {
+	resp := dto.LocationGroupMembersResponse{}
+	if _, err := u.getLocationGroup(ctx, req.GroupID); err != nil {
+		return resp, err
+	}
+
+	affected, err := u.locationGroupRepo.RemoveLocationGroupMembers(ctx, req.GroupID, req.LocationIDs)
+	if err != nil {
+		return resp, err
+	}
+	resp.Affected = affected
+
+	return u.withGroup(ctx, req.GroupID, resp)
+}
+
+// SyncLocationGroupWeatherUsecase syncs the weather of every member of the
+// group, a missing group is told apart from an empty one.
+func (u *locationGroupUsecase) SyncLocationGroupWeatherUsecase(ctx context.Context, req dto.PostWeatherSyncUsecaseRequest) error {
+	if _, err := u.getLocationGroup(ctx, req.GroupID); err != nil {
+		return err
+	}
+
+	req.LocationID = 0
+	return u.weatherUc.SyncWeatherUsecase(ctx, req)
+}
+
+// withGroup reloads the group so the response carries the new member count.
+func (u *locationGroupUsecase) withGroup(ctx context.Context, id int64, resp dto.LocationGroupMembersResponse) (dto.LocationGroupMembersResponse, error) {
+	group, err := u.getLocationGroup(ctx, id)
+	if err != nil {
+		return resp, err
+	}
+
+	resp.Group = dto.ParseToLocationGroupResponse(group)
+	return resp, nil
+}
+
+func (u *locationGroupUsecase) getLocationGroup(ctx context.Context, id int64) (domain.LocationGroup, error) {
+	groups, err := u.locationGroupRepo.GetLocationGroups(ctx, repository.GetLocationGroupsParam{ID: id, Limit: 1})
+	if err != nil {
+		return domain.LocationGroup{}, fmt.Errorf("failed to get location group: %w", err)
+	}
+
+	if len(groups) == 0 {
+		return domain.LocationGroup{}, ErrLocationGroupNotFound
+	}
+
+	return groups[0], nil
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"tyarus/weather-app/internal/domain"
+	"tyarus/weather-app/internal/dto"
+	"tyarus/weather-app/internal/repository"
+	"tyarus/weather-app/mocks"
+)
+
+func TestCreateLocationGroupUsecase(t *testing.T) {
+	t.Run("WHEN group with the same name exists, THEN should return ErrLocationGroupExists", func(t *testing.T) {
+		mockGroupRepo := mocks.NewLocationGroupRepositoryInterface(t)
+		usecase := NewLocationGroupUsecase(mockGroupRepo, mocks.NewLocationRepositoryInterface(t), nil)
+		ctx := context.Background()
+
+		mockGroupRepo.On("InsertLocationGroup", ctx, domain.LocationGroup{Name: "Java ops"}).
+			Return(domain.LocationGroup{}, fmt.Errorf("failed to insert location group: %w", errors.Join(repository.ErrDuplicateKey, errors.New("Error 1062"))))
+
+		_, err := usecase.CreateLocationGroupUsecase(ctx, dto.PostLocationGroupRequest{Name: " Java ops "})
+
+		assert.ErrorIs(t, err, ErrLocationGroupExists)
+	})
+
+	t.Run("WHEN name is unique, THEN should insert the group", func(t *testing.T) {
+		mockGroupRepo := mocks.NewLocationGroupRepositoryInterface(t)
+		usecase := NewLocationGroupUsecase(mockGroupRepo, mocks.NewLocationRepositoryInterface(t), nil)
+		ctx := context.Background()
+
+		mockGroupRepo.On("InsertLocationGroup", ctx, domain.LocationGroup{Name: "Airports", Description: "every airport"}).
+			Return(domain.LocationGroup{ID: 4, Name: "Airports", Description: "every airport"}, nil)
+
+		result, err := usecase.CreateLocationGroupUsecase(ctx, dto.PostLocationGroupRequest{Name: "Airports", Description: "every airport"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), result.ID)
+	})
+}
+
+func TestUpdateLocationGroupUsecase(t *testing.T) {
+	t.Run("WHEN group does not exist, THEN should return ErrLocationGroupNotFound", func(t *testing.T) {
+		mockGroupRepo := mocks.NewLocationGroupRepositoryInterface(t)
+		usecase := NewLocationGroupUsecase(mockGroupRepo, mocks.NewLocationRepositoryInterface(t), nil)
+		ctx := context.Background()
+
+		mockGroupRepo.On("GetLocationGroups", ctx, repository.GetLocationGroupsParam{ID: 9, Limit: 1}).Return(nil, nil)
+
+		_, err := usecase.UpdateLocationGroupUsecase(ctx, dto.PostLocationGroupRequest{ID: 9, Name: "Airports"})
+
+		assert.ErrorIs(t, err, ErrLocationGroupNotFound)
+	})
+
+	t.Run("WHEN group keeps its own name, THEN should update it", func(t *testing.T) {
+		mockGroupRepo := mocks.NewLocationGroupRepositoryInterface(t)
+		usecase := NewLocationGroupUsecase(mockGroupRepo, mocks.NewLocationRepositoryInterface(t), nil)
+		ctx := context.Background()
+
+		group := domain.LocationGroup{ID: 2, Name: "Airports"}
+		mockGroupRepo.On("GetLocationGroups", ctx, repository.GetLocationGroupsParam{ID: 2, Limit: 1}).Return([]domain.LocationGroup{group}, nil)
+		mockGroupRepo.On("UpdateLocationGroup", ctx, domain.LocationGroup{ID: 2, Name: "Airports", Description: "updated"}).Return(nil)
+
+		result, err := usecase.UpdateLocationGroupUsecase(ctx, dto.PostLocationGroupRequest{ID: 2, Name: "Airports", Description: "updated"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), result.ID)
+	})
+
+	t.Run("WHEN name is taken by another group, THEN should return ErrLocationGroupExists", func(t *testing.T) {
+		mockGroupRepo := mocks.NewLocationGroupRepositoryInterface(t)
+		usecase := NewLocationGroupUsecase(mockGroupRepo, mocks.NewLocationRepositoryInterface(t), nil)
+		ctx := context.Background()
+
+		mockGroupRepo.On("GetLocationGroups", ctx, repository.GetLocationGroupsParam{ID: 2, Limit: 1}).
+			Return([]domain.LocationGroup{{ID: 2, Name: "Airports"}}, nil)
+		mockGroupRepo.On("UpdateLocationGroup", ctx, domain.LocationGroup{ID: 2, Name: "Java ops"}).
+			Return(fmt.Errorf("failed to update location group: %w", errors.Join(repository.ErrDuplicateKey, errors.New("Error 1062"))))
+
+		_, err := usecase.UpdateLocationGroupUsecase(ctx, dto.PostLocationGroupRequest{ID: 2, Name: "Java ops"})
+
+		assert.ErrorIs(t, err, ErrLocationGroupExists)
+	})
+}
+
+func TestAddLocationGroupMembersUsecase(t *testing.T) {
+	t.Run("WHEN some locations do not exist, THEN should add the others and report the missing ids", func(t *testing.T) {
+		mockGroupRepo := mocks.NewLocationGroupRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationGroupUsecase(mockGroupRepo, mockLocationRepo, nil)
+		ctx := context.Background()
+
+		mockGroupRepo.On("GetLocationGroups", ctx, repository.GetLocationGroupsParam{ID: 1, Limit: 1}).
+			Return([]domain.LocationGroup{{ID: 1, Name: "Java ops", LocationCount: 2}}, nil)
+		mockLocationRepo.On("GetLocations", ctx, repository.GetLocationsParam{IDs: []int64{1, 2, 99}}).
+			Return([]domain.Location{{ID: 1}, {ID: 2}}, nil)
+		mockGroupRepo.On("AddLocationGroupMembers", ctx, int64(1), []int64{1, 2}).Return(int64(2), nil)
+
+		result, err := usecase.AddLocationGroupMembersUsecase(ctx, dto.LocationGroupMembersRequest{GroupID: 1, LocationIDs: []int64{1, 2, 99}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), result.Affected)
+		assert.Equal(t, []int64{99}, result.NotFoundLocationIDs)
+		assert.Equal(t, 2, result.Group.LocationCount)
+	})
+
+	t.Run("WHEN error occurred on add members, THEN should return error", func(t *testing.T) {
+		mockGroupRepo := mocks.NewLocationGroupRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationGroupUsecase(mockGroupRepo, mockLocationRepo, nil)
+		ctx := context.Background()
+
+		expectedError := errors.New("database error")
+		mockGroupRepo.On("GetLocationGroups", ctx, repository.GetLocationGroupsParam{ID: 1, Limit: 1}).
+			Return([]domain.LocationGroup{{ID: 1}}, nil)
+		mockLocationRepo.On("GetLocations", ctx, repository.GetLocationsParam{IDs: []int64{1}}).Return([]domain.Location{{ID: 1}}, nil)
+		mockGroupRepo.On("AddLocationGroupMembers", ctx, int64(1), []int64{1}).Return(int64(0), expectedError)
+
+		_, err := usecase.AddLocationGroupMembersUsecase(ctx, dto.LocationGroupMembersRequest{GroupID: 1, LocationIDs: []int64{1}})
+
+		assert.Equal(t, expectedError, err)
+	})
+}
+
+func TestDeleteLocationGroupUsecase(t *testing.T) {
+	t.Run("WHEN group does not exist, THEN should return ErrLocationGroupNotFound", func(t *testing.T) {
+		mockGroupRepo := mocks.NewLocationGroupRepositoryInterface(t)
+		usecase := NewLocationGroupUsecase(mockGroupRepo, mocks.NewLocationRepositoryInterface(t), nil)
+		ctx := context.Background()
+
+		mockGroupRepo.On("GetLocationGroups", ctx, repository.GetLocationGroupsParam{ID: 5, Limit: 1}).Return([]domain.LocationGroup{}, nil)
+
+		err := usecase.DeleteLocationGroupUsecase(ctx, 5)
+
+		assert.ErrorIs(t, err, ErrLocationGroupNotFound)
+	})
+}
+
+func TestSyncLocationGroupWeatherUsecase(t *testing.T) {
+	t.Run("WHEN group does not exist, THEN should return ErrLocationGroupNotFound without syncing", func(t *testing.T) {
+		mockGroupRepo := mocks.NewLocationGroupRepositoryInterface(t)
+		mockWeatherUc := mocks.NewWeatherUsecaseInterface(t)
+		usecase := NewLocationGroupUsecase(mockGroupRepo, mocks.NewLocationRepositoryInterface(t), mockWeatherUc)
+		ctx := context.Background()
+
+		mockGroupRepo.On("GetLocationGroups", ctx, repository.GetLocationGroupsParam{ID: 5, Limit: 1}).Return(nil, nil)
+
+		err := usecase.SyncLocationGroupWeatherUsecase(ctx, dto.PostWeatherSyncUsecaseRequest{GroupID: 5})
+
+		assert.ErrorIs(t, err, ErrLocationGroupNotFound)
+	})
+
+	t.Run("WHEN group exists, THEN should sync the group and ignore a location id", func(t *testing.T) {
+		mockGroupRepo := mocks.NewLocationGroupRepositoryInterface(t)
+		mockWeatherUc := mocks.NewWeatherUsecaseInterface(t)
+		usecase := NewLocationGroupUsecase(mockGroupRepo, mocks.NewLocationRepositoryInterface(t), mockWeatherUc)
+		ctx := context.Background()
+
+		mockGroupRepo.On("GetLocationGroups", ctx, repository.GetLocationGroupsParam{ID: 5, Limit: 1}).
+			Return([]domain.LocationGroup{{ID: 5}}, nil)
+		mockWeatherUc.On("SyncWeatherUsecase", ctx, dto.PostWeatherSyncUsecaseRequest{GroupID: 5, ForecastDayTotal: 3}).Return(nil)
+
+		err := usecase.SyncLocationGroupWeatherUsecase(ctx, dto.PostWeatherSyncUsecaseRequest{LocationID: 7, GroupID: 5, ForecastDayTotal: 3})
+
+		assert.NoError(t, err)
+	})
+}
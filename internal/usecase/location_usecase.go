@@ -2,22 +2,106 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"tyarus/weather-app/internal/domain"
 	"tyarus/weather-app/internal/dto"
+	"tyarus/weather-app/internal/infra"
 	"tyarus/weather-app/internal/repository"
+	"tyarus/weather-app/pkg/geo"
 	"tyarus/weather-app/pkg/response"
+	"tyarus/weather-app/pkg/search"
+	"tyarus/weather-app/pkg/utils"
+	"tyarus/weather-app/pkg/weather"
 )
 
+var (
+	ErrLocationNotResolved = errors.New("no location matches the query, please check your parameter")
+	ErrLocationNotFound    = errors.New("location not found, please check your parameter")
+)
+
+// DuplicateLocationError is returned when the location to create already
+// exists, either by name and country or by coordinates.
+type DuplicateLocationError struct {
+	Existing dto.GetLocationHandlerResponseItem
+}
+
+func (e *DuplicateLocationError) Error() string {
+	return fmt.Sprintf("location already exists with id %d", e.Existing.ID)
+}
+
+// LocationCandidatesError is returned when a query matches more than one
+// location, the client has to pick one of the candidates.
+type LocationCandidatesError struct {
+	Candidates []dto.LocationCandidate
+}
+
+func (e *LocationCandidatesError) Error() string {
+	return fmt.Sprintf("query matches %d locations, please choose one of the candidates", len(e.Candidates))
+}
+
 type LocationUsecaseInterface interface {
 	GetLocationsUsecase(ctx context.Context, param dto.GetLocationHandlerParam) (response.Response[response.PaginationData[dto.GetLocationHandlerResponseItem]], error)
 	CreateLocationUsecase(ctx context.Context, req dto.PostLocationHandlerRequest) (dto.GetLocationHandlerResponseItem, error)
+	GetNearbyLocationsUsecase(ctx context.Context, param dto.GetNearbyLocationsParam) (response.Response[[]dto.GetNearbyLocationResponseItem], error)
+	MergeLocationsUsecase(ctx context.Context, req dto.PostMergeLocationRequest) (dto.MergeLocationResponse, error)
+	ImportLocationsUsecase(ctx context.Context, req dto.LocationImportRequest) (dto.LocationImportResponse, error)
+	ExportLocationsUsecase(ctx context.Context, param dto.LocationExportParam) (dto.LocationExportPage, error)
+	SetLocationTagsUsecase(ctx context.Context, req dto.PutLocationTagsRequest) (dto.GetLocationHandlerResponseItem, error)
+	SuggestLocationsUsecase(ctx context.Context, param dto.SuggestLocationsParam) (response.Response[[]dto.LocationSuggestion], error)
+	SetLocationNamesUsecase(ctx context.Context, req dto.PutLocationNamesRequest) (dto.GetLocationHandlerResponseItem, error)
 }
 
+// locationSearchFields are the document fields of the suggest index, the
+// weights rank name matches above alternate name, region and country
+// matches. Every alternate name is a field of its own after country.
+var (
+	locationSearchFields  = []string{"name", "region", "country", "alternateName"}
+	locationSearchWeights = []float64{1, 0.6, 0.5, 0.9}
+)
+
 type locationUsecase struct {
-	locationRepo repository.LocationRepositoryInterface
+	locationRepo      repository.LocationRepositoryInterface
+	cache             infra.CacheInterface
+	weatherAPIClient  weather.WeatherAPIClientInterface
+	duplicateDistance float64
+
+	// searchMu guards the fields below, it is never held while loading
+	// from database. searchLoadMu lets one request at a time load the index.
+	searchMu       sync.Mutex
+	searchLoadMu   sync.Mutex
+	searchIndex    *search.Index[domain.Location]
+	searchLoadedAt time.Time
+	// searchNames keeps the alternate names of indexed locations, so a
+	// location can be reindexed without querying its names again
+	searchNames map[int64][]string
+	// searchPending collects the changes made while the index is loaded, a
+	// nil document is a removal. It is nil when no load is running.
+	searchPending map[int64]*search.Document[domain.Location]
 }
 
-func NewLocationUsecase(locationRepo repository.LocationRepositoryInterface) LocationUsecaseInterface {
-	return &locationUsecase{locationRepo: locationRepo}
+// NewLocationUsecase treats locations closer than duplicateDistance meters as
+// duplicates of each other.
+func NewLocationUsecase(
+	locationRepo repository.LocationRepositoryInterface,
+	cache infra.CacheInterface,
+	weatherAPIClient weather.WeatherAPIClientInterface,
+	duplicateDistance int,
+) LocationUsecaseInterface {
+	return &locationUsecase{
+		locationRepo:      locationRepo,
+		cache:             cache,
+		weatherAPIClient:  weatherAPIClient,
+		duplicateDistance: float64(duplicateDistance) / 1000,
+		searchIndex:       search.NewIndex[domain.Location](locationSearchWeights...),
+		searchNames:       map[int64][]string{},
+	}
 }
 
 func (u *locationUsecase) GetLocationsUsecase(ctx context.Context, param dto.GetLocationHandlerParam) (response.Response[response.PaginationData[dto.GetLocationHandlerResponseItem]], error) {
@@ -27,27 +111,76 @@ func (u *locationUsecase) GetLocationsUsecase(ctx context.Context, param dto.Get
 	if param.CurrentPage <= 0 {
 		param.CurrentPage = 1
 	}
+	// cursors need a stable order
+	if param.SortBy == "" {
+		param.SortBy = utils.OrderByCreatedAtAsc
+	}
 
 	offset := (param.CurrentPage - 1) * param.PageSize
 	resp := response.Response[response.PaginationData[dto.GetLocationHandlerResponseItem]]{}
-	locations, err := u.locationRepo.GetLocations(ctx, repository.GetLocationsParam{
-		NameLike: param.Query,
-		Limit:    param.PageSize,
-		Offset:   offset,
-		OrderBy:  param.SortBy,
-	})
+	filter := repository.GetLocationsParam{
+		NameLike:       strings.TrimSpace(param.Query),
+		NamePrefix:     strings.TrimSpace(param.NamePrefix),
+		Country:        strings.TrimSpace(param.Country),
+		Region:         strings.TrimSpace(param.Region),
+		BoundingBox:    param.BoundingBox,
+		GroupID:        param.GroupID,
+		Tag:            dto.NormalizeLocationTag(param.Tag),
+		CreatedFrom:    param.CreatedFrom,
+		CreatedTo:      param.CreatedTo,
+		IncludeDeleted: param.IncludeDeleted,
+	}
+
+	listParam := filter
+	listParam.Limit = param.PageSize
+	listParam.Offset = offset
+	listParam.OrderBy = param.SortBy
+	if param.Cursor != "" {
+		keyset, err := locationKeyset(param.Cursor, param.SortBy)
+		if err != nil {
+			return resp, err
+		}
+
+		// one extra row tells whether another page follows
+		listParam.After = keyset
+		listParam.Limit = param.PageSize + 1
+		listParam.Offset = 0
+	}
+
+	locations, err := u.locationRepo.GetLocations(ctx, listParam)
 	if err != nil {
 		return resp, err
 	}
 
-	count, err := u.locationRepo.GetLocationsCount(ctx)
+	count, err := u.locationRepo.GetLocationsCount(ctx, filter)
 	if err != nil {
 		return resp, err
 	}
 
+	hasPrev, hasNext := param.CurrentPage > 1, offset+len(locations) < count
+	resp.Data.CurrentPage = param.CurrentPage
+	if listParam.After != nil {
+		locations, hasPrev, hasNext = keysetPage(locations, param.PageSize, listParam.After.Backward)
+		resp.Data.CurrentPage = 0
+	}
+
+	if len(locations) > 0 {
+		if hasNext {
+			resp.Data.NextCursor = locationCursor(locations[len(locations)-1], param.SortBy, false)
+		}
+		if hasPrev {
+			resp.Data.PrevCursor = locationCursor(locations[0], param.SortBy, true)
+		}
+	}
+
 	resp.Data.Items = dto.ParseToGetLocationHandlerResponses(locations)
+	if err = u.attachLocationTags(ctx, resp.Data.Items); err != nil {
+		return resp, err
+	}
+	if err = u.attachLocationNames(ctx, resp.Data.Items, param.Languages); err != nil {
+		return resp, err
+	}
 	resp.Data.Total = count
-	resp.Data.CurrentPage = param.CurrentPage
 	resp.Data.PageSize = param.PageSize
 
 	return resp, nil
@@ -55,10 +188,691 @@ func (u *locationUsecase) GetLocationsUsecase(ctx context.Context, param dto.Get
 }
 
 func (u *locationUsecase) CreateLocationUsecase(ctx context.Context, req dto.PostLocationHandlerRequest) (dto.GetLocationHandlerResponseItem, error) {
-	location, err := u.locationRepo.InsertLocation(ctx, req.PostLocationHandlerRequestToDomain())
+	location := req.PostLocationHandlerRequestToDomain()
+	if strings.TrimSpace(req.Query) != "" {
+		resolved, err := u.resolveLocation(ctx, strings.TrimSpace(req.Query))
+		if err != nil {
+			return dto.GetLocationHandlerResponseItem{}, err
+		}
+		location = resolved
+	}
+
+	existing, err := u.findDuplicateLocation(ctx, location)
 	if err != nil {
 		return dto.GetLocationHandlerResponseItem{}, err
 	}
+	if existing != nil {
+		return dto.GetLocationHandlerResponseItem{}, &DuplicateLocationError{Existing: dto.ParseToGetLocationHandlerResponse(*existing)}
+	}
+
+	inserted, err := u.locationRepo.InsertLocation(ctx, location)
+	if errors.Is(err, repository.ErrDuplicateKey) {
+		// another request created the same location since the check above
+		existing, findErr := u.findDuplicateLocation(ctx, location)
+		if findErr == nil && existing != nil {
+			return dto.GetLocationHandlerResponseItem{}, &DuplicateLocationError{Existing: dto.ParseToGetLocationHandlerResponse(*existing)}
+		}
+	}
+	if err != nil {
+		return dto.GetLocationHandlerResponseItem{}, err
+	}
+	location = inserted
+	u.indexLocation(location)
 
 	return dto.ParseToGetLocationHandlerResponse(location), nil
 }
+
+func (u *locationUsecase) findDuplicateLocation(ctx context.Context, location domain.Location) (*domain.Location, error) {
+	duplicates, err := u.findDuplicateLocations(ctx, []domain.Location{location})
+	if err != nil {
+		return nil, err
+	}
+	return duplicates[0], nil
+}
+
+// findDuplicateLocations returns for every location the existing location
+// with the same name and country or closer than the duplicate distance, nil
+// when there is none. Locations are looked up with a query per batch.
+func (u *locationUsecase) findDuplicateLocations(ctx context.Context, locations []domain.Location) ([]*domain.Location, error) {
+	duplicates := make([]*domain.Location, len(locations))
+	for start := 0; start < len(locations); start += utils.LocationImportBatchSize {
+		batch := locations[start:min(start+utils.LocationImportBatchSize, len(locations))]
+		param := repository.FindDuplicateLocationsParam{}
+		for _, location := range batch {
+			param.NameKeys = append(param.NameKeys, domain.LocationNameKey(location.Name, location.Country))
+			param.BoundingBoxes = append(param.BoundingBoxes, geo.BoundingBoxAround(location.Latitude, location.Longitude, u.duplicateDistance))
+		}
+
+		candidates, err := u.locationRepo.FindDuplicateLocations(ctx, param)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find duplicate locations: %w", err)
+		}
+
+		for i, location := range batch {
+			duplicates[start+i] = u.matchDuplicateLocation(location, candidates)
+		}
+	}
+
+	return duplicates, nil
+}
+
+func (u *locationUsecase) matchDuplicateLocation(location domain.Location, candidates []domain.Location) *domain.Location {
+	nameKey := domain.LocationNameKey(location.Name, location.Country)
+	for _, candidate := range candidates {
+		sameName := domain.LocationNameKey(candidate.Name, candidate.Country) == nameKey
+		distance := geo.Distance(location.Latitude, location.Longitude, candidate.Latitude, candidate.Longitude)
+		if sameName || distance <= u.duplicateDistance {
+			return &candidate
+		}
+	}
+
+	return nil
+}
+
+// resolveLocation geocodes the query with the weather provider. A single
+// match is returned with its timezone, several matches are returned to the
+// client as a LocationCandidatesError.
+func (u *locationUsecase) resolveLocation(ctx context.Context, query string) (domain.Location, error) {
+	results, err := u.weatherAPIClient.SearchLocations(ctx, query)
+	if err != nil {
+		return domain.Location{}, fmt.Errorf("failed to search location: %w", err)
+	}
+
+	if len(results) == 0 {
+		return domain.Location{}, ErrLocationNotResolved
+	}
+
+	if len(results) > 1 {
+		candidates := []dto.LocationCandidate{}
+		for _, result := range results {
+			candidates = append(candidates, dto.LocationCandidate{
+				Query:     fmt.Sprintf("id:%d", result.ID),
+				Name:      result.Name,
+				Region:    result.Region,
+				Country:   result.Country,
+				Latitude:  result.Lat,
+				Longitude: result.Lon,
+			})
+		}
+		return domain.Location{}, &LocationCandidatesError{Candidates: candidates}
+	}
+
+	result := results[0]
+	timezone, err := u.weatherAPIClient.GetTimezone(ctx, fmt.Sprintf("%f,%f", result.Lat, result.Lon))
+	if err != nil {
+		return domain.Location{}, fmt.Errorf("failed to get timezone: %w", err)
+	}
+
+	return domain.Location{
+		Name:      result.Name,
+		Region:    result.Region,
+		Country:   result.Country,
+		Latitude:  result.Lat,
+		Longitude: result.Lon,
+		Timezone:  timezone.TzID,
+	}, nil
+}
+
+func (u *locationUsecase) GetNearbyLocationsUsecase(ctx context.Context, param dto.GetNearbyLocationsParam) (response.Response[[]dto.GetNearbyLocationResponseItem], error) {
+	resp := response.Response[[]dto.GetNearbyLocationResponseItem]{
+		Status:  "success",
+		Message: "get nearby locations success",
+	}
+
+	if param.RadiusKm <= 0 {
+		param.RadiusKm = utils.DefaultNearbyRadiusKm
+	}
+	if param.Limit <= 0 {
+		param.Limit = utils.DefaultNearbyLimit
+	}
+
+	nearby, err := findNearbyLocations(ctx, u.locationRepo, param.Latitude, param.Longitude, param.RadiusKm, param.Limit)
+	if err != nil {
+		return resp, err
+	}
+
+	resp.Data = []dto.GetNearbyLocationResponseItem{}
+	for _, item := range nearby {
+		resp.Data = append(resp.Data, dto.GetNearbyLocationResponseItem{
+			Location:   dto.ParseToGetLocationHandlerResponse(item.location),
+			DistanceKm: item.distanceKm,
+		})
+	}
+
+	items := make([]*dto.GetLocationHandlerResponseItem, len(resp.Data))
+	for i := range resp.Data {
+		items[i] = &resp.Data[i].Location
+	}
+	if err := localizeLocations(ctx, u.locationRepo, items, param.Languages); err != nil {
+		return resp, err
+	}
+
+	return resp, nil
+}
+
+func (u *locationUsecase) MergeLocationsUsecase(ctx context.Context, req dto.PostMergeLocationRequest) (dto.MergeLocationResponse, error) {
+	resp := dto.MergeLocationResponse{MergedLocationID: req.DuplicateID}
+	result, err := u.locationRepo.MergeLocations(ctx, req.CanonicalID, req.DuplicateID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return resp, ErrLocationNotFound
+	}
+	if err != nil {
+		return resp, err
+	}
+
+	// the duplicate's alternate names now belong to the canonical location
+	u.searchMu.Lock()
+	u.searchNames[req.CanonicalID] = append(u.searchNames[req.CanonicalID], u.searchNames[req.DuplicateID]...)
+	delete(u.searchNames, req.DuplicateID)
+	u.searchMu.Unlock()
+	u.unindexLocation(req.DuplicateID)
+
+	// the cached weather of the canonical location misses the moved rows,
+	// the one of the duplicate still has them
+	notifyWeatherSynced(ctx, u.cache, req.CanonicalID, req.DuplicateID)
+
+	locations, err := u.locationRepo.GetLocations(ctx, repository.GetLocationsParam{
+		ID:    int(req.CanonicalID),
+		Limit: 1,
+	})
+	if err != nil {
+		return resp, err
+	}
+	if len(locations) == 0 {
+		return resp, ErrLocationNotFound
+	}
+
+	u.indexLocation(locations[0])
+
+	resp.Location = dto.ParseToGetLocationHandlerResponse(locations[0])
+	resp.MovedWeathers = result.MovedWeathers
+	resp.DiscardedWeathers = result.DiscardedWeathers
+	return resp, nil
+}
+
+// ImportLocationsUsecase validates every row, then upserts the valid ones by
+// external key in transactional batches. A failing batch only marks its own
+// rows as failed. With DryRun nothing is written.
+func (u *locationUsecase) ImportLocationsUsecase(ctx context.Context, req dto.LocationImportRequest) (dto.LocationImportResponse, error) {
+	resp := dto.LocationImportResponse{
+		DryRun: req.DryRun,
+		Total:  len(req.Rows),
+		Rows:   make([]dto.LocationImportRowResult, len(req.Rows)),
+	}
+
+	checkedRows := []int{}
+	checkedLocations := []domain.Location{}
+	seenKeys := map[string]int{}
+	seenNames := map[string]int{}
+	for i, row := range req.Rows {
+		location := row.Item.LocationExchangeItemToDomain()
+		resp.Rows[i] = dto.LocationImportRowResult{
+			Row:         row.Row,
+			ExternalKey: location.ExternalKey.String,
+			Name:        location.Name,
+			Status:      dto.LocationImportStatusValid,
+		}
+
+		if reason := validateImportRow(row, location, seenKeys, seenNames); reason != "" {
+			resp.Rows[i].Status = dto.LocationImportStatusInvalid
+			resp.Rows[i].Error = reason
+			continue
+		}
+
+		checkedRows = append(checkedRows, i)
+		checkedLocations = append(checkedLocations, location)
+	}
+
+	// a row may update the location owning the same external key, but must not
+	// create another copy of an existing location
+	duplicates, err := u.findDuplicateLocations(ctx, checkedLocations)
+	if err != nil {
+		return resp, err
+	}
+
+	validRows := []int{}
+	for i, rowIndex := range checkedRows {
+		key := checkedLocations[i].ExternalKey.String
+		if existing := duplicates[i]; existing != nil && (key == "" || existing.ExternalKey.String != key) {
+			resp.Rows[rowIndex].Status = dto.LocationImportStatusInvalid
+			resp.Rows[rowIndex].Error = fmt.Sprintf("location already exists with id %d", existing.ID)
+			continue
+		}
+		validRows = append(validRows, rowIndex)
+	}
+	resp.Valid = len(validRows)
+	resp.Invalid = resp.Total - resp.Valid
+
+	if req.DryRun {
+		return resp, nil
+	}
+
+	for start := 0; start < len(validRows); start += utils.LocationImportBatchSize {
+		batch := validRows[start:min(start+utils.LocationImportBatchSize, len(validRows))]
+		locations := make([]domain.Location, len(batch))
+		for i, rowIndex := range batch {
+			locations[i] = req.Rows[rowIndex].Item.LocationExchangeItemToDomain()
+		}
+
+		results, err := u.locationRepo.UpsertLocations(ctx, locations)
+		for i, rowIndex := range batch {
+			if err != nil {
+				resp.Rows[rowIndex].Status = dto.LocationImportStatusFailed
+				resp.Rows[rowIndex].Error = err.Error()
+				resp.Failed++
+				continue
+			}
+
+			resp.Rows[rowIndex].Status = results[i].Status
+			resp.Rows[rowIndex].LocationID = results[i].ID
+			switch results[i].Status {
+			case repository.UpsertStatusCreated:
+				resp.Created++
+			case repository.UpsertStatusUpdated:
+				resp.Updated++
+			default:
+				resp.Unchanged++
+			}
+
+			// unchanged rows are indexed already
+			if results[i].Status != repository.UpsertStatusUnchanged && results[i].ID > 0 {
+				locations[i].ID = results[i].ID
+				u.indexLocation(locations[i])
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+// validateImportRow returns why the row can not be imported, or an empty
+// string when it can. Existing locations are checked by the caller.
+func validateImportRow(row dto.LocationImportRow, location domain.Location, seenKeys, seenNames map[string]int) string {
+	if row.ParseError != nil {
+		return row.ParseError.Error()
+	}
+
+	if err := row.Item.Validate(); err != nil {
+		return err.Error()
+	}
+
+	key := location.ExternalKey.String
+	if key != "" {
+		if previous, ok := seenKeys[key]; ok {
+			return fmt.Sprintf("externalKey already used by row %d", previous)
+		}
+		seenKeys[key] = row.Row
+	}
+
+	name := domain.LocationNameKey(location.Name, location.Country)
+	if previous, ok := seenNames[name]; ok && key == "" {
+		return fmt.Sprintf("location already listed in row %d", previous)
+	}
+	seenNames[name] = row.Row
+
+	return ""
+}
+
+// ExportLocationsUsecase returns up to utils.MaxLocationExportRows locations
+// from the offset, oldest first. NextOffset tells where the next page starts
+// when more locations follow.
+func (u *locationUsecase) ExportLocationsUsecase(ctx context.Context, param dto.LocationExportParam) (dto.LocationExportPage, error) {
+	page := dto.LocationExportPage{}
+	// one extra row tells whether another page follows
+	locations, err := u.locationRepo.GetLocations(ctx, repository.GetLocationsParam{
+		OrderBy: utils.OrderByCreatedAtAsc,
+		Limit:   utils.MaxLocationExportRows + 1,
+		Offset:  param.Offset,
+	})
+	if err != nil {
+		return page, err
+	}
+
+	if len(locations) > utils.MaxLocationExportRows {
+		locations = locations[:utils.MaxLocationExportRows]
+		page.NextOffset = param.Offset + utils.MaxLocationExportRows
+	}
+
+	page.Items = dto.ParseToLocationExchangeItems(locations)
+	return page, nil
+}
+
+func (u *locationUsecase) SetLocationTagsUsecase(ctx context.Context, req dto.PutLocationTagsRequest) (dto.GetLocationHandlerResponseItem, error) {
+	locations, err := u.locationRepo.GetLocations(ctx, repository.GetLocationsParam{ID: int(req.LocationID), Limit: 1})
+	if err != nil {
+		return dto.GetLocationHandlerResponseItem{}, fmt.Errorf("failed to get location: %w", err)
+	}
+
+	if len(locations) == 0 {
+		return dto.GetLocationHandlerResponseItem{}, ErrLocationNotFound
+	}
+
+	tags := req.NormalizedTags()
+	if err = u.locationRepo.SetLocationTags(ctx, req.LocationID, tags); err != nil {
+		return dto.GetLocationHandlerResponseItem{}, err
+	}
+
+	result := dto.ParseToGetLocationHandlerResponse(locations[0])
+	result.Tags = tags
+	if err := localizeLocations(ctx, u.locationRepo, []*dto.GetLocationHandlerResponseItem{&result}, req.Languages); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// attachLocationTags fills the tags of every item with a single query.
+func (u *locationUsecase) attachLocationTags(ctx context.Context, items []dto.GetLocationHandlerResponseItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+
+	tags, err := u.locationRepo.GetLocationTags(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get location tags: %w", err)
+	}
+
+	for i := range items {
+		items[i].Tags = tags[items[i].ID]
+	}
+
+	return nil
+}
+
+func (u *locationUsecase) SetLocationNamesUsecase(ctx context.Context, req dto.PutLocationNamesRequest) (dto.GetLocationHandlerResponseItem, error) {
+	locations, err := u.locationRepo.GetLocations(ctx, repository.GetLocationsParam{ID: int(req.LocationID), Limit: 1})
+	if err != nil {
+		return dto.GetLocationHandlerResponseItem{}, fmt.Errorf("failed to get location: %w", err)
+	}
+
+	if len(locations) == 0 {
+		return dto.GetLocationHandlerResponseItem{}, ErrLocationNotFound
+	}
+
+	names := req.ToDomain()
+	if err = u.locationRepo.SetLocationNames(ctx, req.LocationID, names); err != nil {
+		return dto.GetLocationHandlerResponseItem{}, err
+	}
+
+	u.searchMu.Lock()
+	u.searchNames[req.LocationID] = locationNameValues(names)
+	u.searchMu.Unlock()
+	u.indexLocation(locations[0])
+
+	result := dto.ParseToGetLocationHandlerResponse(locations[0])
+	result.AlternateNames = dto.ParseToLocationNameItems(names)
+	result.Localize(names, req.Languages)
+	return result, nil
+}
+
+// attachLocationNames fills the alternate names of every item with a single
+// query, and the localized name when languages were requested.
+func (u *locationUsecase) attachLocationNames(ctx context.Context, items []dto.GetLocationHandlerResponseItem, languages []string) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+
+	names, err := u.locationRepo.GetLocationNames(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get location names: %w", err)
+	}
+
+	for i := range items {
+		if len(names[items[i].ID]) > 0 {
+			items[i].AlternateNames = dto.ParseToLocationNameItems(names[items[i].ID])
+		}
+		items[i].Localize(names[items[i].ID], languages)
+	}
+
+	return nil
+}
+
+// localizeLocations sets the localized name of every item with a single
+// query, it does nothing when no language was requested.
+func localizeLocations(ctx context.Context, locationRepo repository.LocationRepositoryInterface, items []*dto.GetLocationHandlerResponseItem, languages []string) error {
+	if len(items) == 0 || len(languages) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+
+	names, err := locationRepo.GetLocationNames(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to get location names: %w", err)
+	}
+
+	for _, item := range items {
+		item.Localize(names[item.ID], languages)
+	}
+
+	return nil
+}
+
+// SuggestLocationsUsecase ranks locations by how well their name, region or
+// country matches the query, tolerating typos and matching word prefixes.
+func (u *locationUsecase) SuggestLocationsUsecase(ctx context.Context, param dto.SuggestLocationsParam) (response.Response[[]dto.LocationSuggestion], error) {
+	resp := response.Response[[]dto.LocationSuggestion]{
+		Status:  "success",
+		Message: "suggest locations success",
+		Data:    []dto.LocationSuggestion{},
+	}
+
+	if param.Limit == 0 {
+		param.Limit = utils.DefaultSuggestLimit
+	}
+
+	if err := u.loadSearchIndex(ctx); err != nil {
+		return resp, err
+	}
+
+	u.searchMu.Lock()
+	index := u.searchIndex
+	u.searchMu.Unlock()
+
+	for _, result := range index.Search(param.Query, param.Limit) {
+		resp.Data = append(resp.Data, dto.LocationSuggestion{
+			ID:           result.Value.ID,
+			Name:         result.Value.Name,
+			Region:       result.Value.Region,
+			Country:      result.Value.Country,
+			Latitude:     result.Value.Latitude,
+			Longitude:    result.Value.Longitude,
+			MatchedField: locationSearchFields[min(result.Field, len(locationSearchFields)-1)],
+			Score:        math.Round(result.Score*1000) / 1000,
+		})
+	}
+
+	return resp, nil
+}
+
+// loadSearchIndex fills the suggest index from database on first use and
+// reloads it periodically, so locations changed by other instances show up
+// as well. Changes made by this instance are indexed right away. The new
+// index is built aside and swapped in, searches keep using the old one
+// meanwhile.
+func (u *locationUsecase) loadSearchIndex(ctx context.Context) error {
+	if u.searchIndexFresh() {
+		return nil
+	}
+
+	u.searchMu.Lock()
+	loaded := !u.searchLoadedAt.IsZero()
+	u.searchMu.Unlock()
+
+	// only the first load makes requests wait, a reload already running is
+	// not waited for
+	if loaded {
+		if !u.searchLoadMu.TryLock() {
+			return nil
+		}
+	} else {
+		u.searchLoadMu.Lock()
+	}
+	defer u.searchLoadMu.Unlock()
+
+	if u.searchIndexFresh() {
+		return nil
+	}
+
+	u.searchMu.Lock()
+	u.searchPending = map[int64]*search.Document[domain.Location]{}
+	u.searchMu.Unlock()
+
+	index, searchNames, err := u.buildSearchIndex(ctx)
+
+	u.searchMu.Lock()
+	defer u.searchMu.Unlock()
+
+	pending := u.searchPending
+	u.searchPending = nil
+	if err != nil {
+		// a stale index is better than no suggestions
+		if loaded {
+			fmt.Printf("Failed to reload location search index: %v\n", err)
+			return nil
+		}
+		return err
+	}
+
+	for id, doc := range pending {
+		if doc == nil {
+			index.Remove(id)
+			delete(searchNames, id)
+			continue
+		}
+		index.Add(*doc)
+		searchNames[id] = u.searchNames[id]
+	}
+
+	u.searchIndex = index
+	u.searchNames = searchNames
+	u.searchLoadedAt = time.Now()
+
+	return nil
+}
+
+func (u *locationUsecase) searchIndexFresh() bool {
+	u.searchMu.Lock()
+	defer u.searchMu.Unlock()
+
+	return !u.searchLoadedAt.IsZero() && time.Since(u.searchLoadedAt) < utils.LocationSearchRefreshInterval
+}
+
+// buildSearchIndex indexes every active location with its alternate names.
+func (u *locationUsecase) buildSearchIndex(ctx context.Context) (*search.Index[domain.Location], map[int64][]string, error) {
+	locations, err := u.locationRepo.GetLocations(ctx, repository.GetLocationsParam{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load locations: %w", err)
+	}
+
+	ids := make([]int64, len(locations))
+	for i, location := range locations {
+		ids[i] = location.ID
+	}
+
+	names, err := u.locationRepo.GetLocationNames(ctx, ids)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load location names: %w", err)
+	}
+
+	searchNames := map[int64][]string{}
+	docs := make([]search.Document[domain.Location], len(locations))
+	for i, location := range locations {
+		if len(names[location.ID]) > 0 {
+			searchNames[location.ID] = locationNameValues(names[location.ID])
+		}
+		docs[i] = locationSearchDocument(location, searchNames[location.ID])
+	}
+
+	index := search.NewIndex[domain.Location](locationSearchWeights...)
+	index.Replace(docs)
+	return index, searchNames, nil
+}
+
+func (u *locationUsecase) indexLocation(location domain.Location) {
+	u.searchMu.Lock()
+	doc := locationSearchDocument(location, u.searchNames[location.ID])
+	index := u.searchIndex
+	if u.searchPending != nil {
+		u.searchPending[location.ID] = &doc
+	}
+	u.searchMu.Unlock()
+
+	index.Add(doc)
+}
+
+func (u *locationUsecase) unindexLocation(id int64) {
+	u.searchMu.Lock()
+	index := u.searchIndex
+	if u.searchPending != nil {
+		u.searchPending[id] = nil
+	}
+	u.searchMu.Unlock()
+
+	index.Remove(id)
+}
+
+func locationSearchDocument(location domain.Location, names []string) search.Document[domain.Location] {
+	return search.Document[domain.Location]{
+		ID:     location.ID,
+		Fields: append([]string{location.Name, location.Region, location.Country}, names...),
+		Value:  location,
+	}
+}
+
+func locationNameValues(names []domain.LocationName) []string {
+	values := make([]string, len(names))
+	for i, name := range names {
+		values[i] = name.Name
+	}
+
+	return values
+}
+
+type nearbyLocation struct {
+	location   domain.Location
+	distanceKm float64
+}
+
+// findNearbyLocations prefilters candidates with a bounding box in SQL, then
+// keeps the ones within radiusKm sorted by great-circle distance.
+func findNearbyLocations(ctx context.Context, locationRepo repository.LocationRepositoryInterface, lat, lon, radiusKm float64, limit int) ([]nearbyLocation, error) {
+	box := geo.BoundingBoxAround(lat, lon, radiusKm)
+	locations, err := locationRepo.GetLocations(ctx, repository.GetLocationsParam{BoundingBox: &box})
+	if err != nil {
+		return nil, err
+	}
+
+	results := []nearbyLocation{}
+	for _, location := range locations {
+		distance := geo.Distance(lat, lon, location.Latitude, location.Longitude)
+		if distance <= radiusKm {
+			results = append(results, nearbyLocation{location: location, distanceKm: distance})
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].distanceKm == results[j].distanceKm {
+			return results[i].location.ID < results[j].location.ID
+		}
+		return results[i].distanceKm < results[j].distanceKm
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -14,12 +15,15 @@ import (
 	"tyarus/weather-app/internal/dto"
 	"tyarus/weather-app/internal/repository"
 	"tyarus/weather-app/mocks"
+	"tyarus/weather-app/pkg/cursor"
+	"tyarus/weather-app/pkg/geo"
+	"tyarus/weather-app/pkg/weather"
 )
 
 func TestGetLocationsUsecase(t *testing.T) {
 	t.Run("WHEN error occurred on get location from database, THEN should return error accordingly", func(t *testing.T) {
 		mockRepo := mocks.NewLocationRepositoryInterface(t)
-		usecase := NewLocationUsecase(mockRepo)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
 		ctx := context.Background()
 		param := dto.GetLocationHandlerParam{
 			Query:       "test",
@@ -44,7 +48,7 @@ func TestGetLocationsUsecase(t *testing.T) {
 
 	t.Run("WHEN error occurred on get count from database, THEN should return error accordingly", func(t *testing.T) {
 		mockRepo := mocks.NewLocationRepositoryInterface(t)
-		usecase := NewLocationUsecase(mockRepo)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
 		ctx := context.Background()
 		param := dto.GetLocationHandlerParam{
 			Query:       "test",
@@ -72,7 +76,7 @@ func TestGetLocationsUsecase(t *testing.T) {
 			Offset:   0,
 			OrderBy:  "name_ascend",
 		}).Return(locations, nil)
-		mockRepo.On("GetLocationsCount", ctx).Return(0, expectedError)
+		mockRepo.On("GetLocationsCount", ctx, repository.GetLocationsParam{NameLike: "test"}).Return(0, expectedError)
 
 		_, err := usecase.GetLocationsUsecase(ctx, param)
 
@@ -82,7 +86,7 @@ func TestGetLocationsUsecase(t *testing.T) {
 
 	t.Run("WHEN page size is zero or negative, THEN should use default page size", func(t *testing.T) {
 		mockRepo := mocks.NewLocationRepositoryInterface(t)
-		usecase := NewLocationUsecase(mockRepo)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
 		ctx := context.Background()
 		param := dto.GetLocationHandlerParam{
 			Query:       "test",
@@ -99,7 +103,7 @@ func TestGetLocationsUsecase(t *testing.T) {
 			Offset:   0,
 			OrderBy:  "name_ascend",
 		}).Return(locations, nil)
-		mockRepo.On("GetLocationsCount", ctx).Return(expectedCount, nil)
+		mockRepo.On("GetLocationsCount", ctx, repository.GetLocationsParam{NameLike: "test"}).Return(expectedCount, nil)
 
 		result, err := usecase.GetLocationsUsecase(ctx, param)
 
@@ -109,7 +113,7 @@ func TestGetLocationsUsecase(t *testing.T) {
 
 	t.Run("WHEN current page is zero or negative, THEN should use default current page", func(t *testing.T) {
 		mockRepo := mocks.NewLocationRepositoryInterface(t)
-		usecase := NewLocationUsecase(mockRepo)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
 		ctx := context.Background()
 		param := dto.GetLocationHandlerParam{
 			Query:       "test",
@@ -126,7 +130,7 @@ func TestGetLocationsUsecase(t *testing.T) {
 			Offset:   0,
 			OrderBy:  "name_ascend",
 		}).Return(locations, nil)
-		mockRepo.On("GetLocationsCount", ctx).Return(expectedCount, nil)
+		mockRepo.On("GetLocationsCount", ctx, repository.GetLocationsParam{NameLike: "test"}).Return(expectedCount, nil)
 
 		result, err := usecase.GetLocationsUsecase(ctx, param)
 
@@ -136,7 +140,7 @@ func TestGetLocationsUsecase(t *testing.T) {
 
 	t.Run("WHEN no locations found, THEN should return empty results with zero count", func(t *testing.T) {
 		mockRepo := mocks.NewLocationRepositoryInterface(t)
-		usecase := NewLocationUsecase(mockRepo)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
 		ctx := context.Background()
 		param := dto.GetLocationHandlerParam{
 			Query:       "test",
@@ -153,7 +157,7 @@ func TestGetLocationsUsecase(t *testing.T) {
 			Offset:   0,
 			OrderBy:  "name_ascend",
 		}).Return(locations, nil)
-		mockRepo.On("GetLocationsCount", ctx).Return(expectedCount, nil)
+		mockRepo.On("GetLocationsCount", ctx, repository.GetLocationsParam{NameLike: "test"}).Return(expectedCount, nil)
 
 		result, err := usecase.GetLocationsUsecase(ctx, param)
 
@@ -164,7 +168,7 @@ func TestGetLocationsUsecase(t *testing.T) {
 
 	t.Run("WHEN multiple locations returned, THEN should return all items with no error", func(t *testing.T) {
 		mockRepo := mocks.NewLocationRepositoryInterface(t)
-		usecase := NewLocationUsecase(mockRepo)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
 		ctx := context.Background()
 		param := dto.GetLocationHandlerParam{
 			PageSize:    10,
@@ -200,22 +204,69 @@ func TestGetLocationsUsecase(t *testing.T) {
 			Offset:  0,
 			OrderBy: "name_ascend",
 		}).Return(locations, nil)
-		mockRepo.On("GetLocationsCount", ctx).Return(expectedCount, nil)
+		mockRepo.On("GetLocationsCount", ctx, repository.GetLocationsParam{}).Return(expectedCount, nil)
+		mockRepo.On("GetLocationTags", ctx, []int64{1, 2}).Return(map[int64][]string{1: {"airport"}}, nil)
+		mockRepo.On("GetLocationNames", ctx, []int64{1, 2}).Return(map[int64][]domain.LocationName{}, nil)
 
 		result, err := usecase.GetLocationsUsecase(ctx, param)
 
 		assert.NoError(t, err)
 		assert.Equal(t, expectedCount, result.Data.Total)
 		assert.Len(t, result.Data.Items, 2)
+		assert.Equal(t, []string{"airport"}, result.Data.Items[0].Tags)
+		assert.Empty(t, result.Data.Items[1].Tags)
 		assert.Equal(t, locations[0].ID, result.Data.Items[0].ID)
 		assert.Equal(t, locations[1].ID, result.Data.Items[1].ID)
 	})
 }
 
+func TestGetLocationsUsecaseFilters(t *testing.T) {
+	t.Run("WHEN filters are given, THEN should apply them to both the items and the total", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		box := &geo.BoundingBox{MinLatitude: -8, MaxLatitude: -6, MinLongitude: 106, MaxLongitude: 113}
+		createdFrom := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		param := dto.GetLocationHandlerParam{
+			Query:          " jaka ",
+			NamePrefix:     "Ja",
+			Country:        "Indonesia",
+			Region:         "DKI Jakarta",
+			BoundingBox:    box,
+			CreatedFrom:    createdFrom,
+			IncludeDeleted: true,
+			PageSize:       5,
+			CurrentPage:    2,
+			SortBy:         "name_ascend",
+		}
+
+		filter := repository.GetLocationsParam{
+			NameLike:       "jaka",
+			NamePrefix:     "Ja",
+			Country:        "Indonesia",
+			Region:         "DKI Jakarta",
+			BoundingBox:    box,
+			CreatedFrom:    createdFrom,
+			IncludeDeleted: true,
+		}
+		listParam := filter
+		listParam.Limit, listParam.Offset, listParam.OrderBy = 5, 5, "name_ascend"
+
+		mockRepo.On("GetLocations", ctx, listParam).Return([]domain.Location{}, nil)
+		mockRepo.On("GetLocationsCount", ctx, filter).Return(6, nil)
+
+		result, err := usecase.GetLocationsUsecase(ctx, param)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 6, result.Data.Total)
+	})
+}
+
 func TestCreateLocationUsecase(t *testing.T) {
 	t.Run("WHEN error occurred on insert location, THEN should return error", func(t *testing.T) {
 		mockRepo := mocks.NewLocationRepositoryInterface(t)
-		usecase := NewLocationUsecase(mockRepo)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
 		ctx := context.Background()
 		req := dto.PostLocationHandlerRequest{
 			Name:      "Test Location",
@@ -225,6 +276,7 @@ func TestCreateLocationUsecase(t *testing.T) {
 		}
 
 		expectedError := errors.New("insert failed")
+		mockRepo.On("FindDuplicateLocations", ctx, mock.Anything).Return([]domain.Location{}, nil)
 		mockRepo.On("InsertLocation", ctx, mock.Anything).Return(domain.Location{}, expectedError)
 
 		_, err := usecase.CreateLocationUsecase(ctx, req)
@@ -235,7 +287,7 @@ func TestCreateLocationUsecase(t *testing.T) {
 
 	t.Run("WHEN insert location succeeds, THEN should return no error", func(t *testing.T) {
 		mockRepo := mocks.NewLocationRepositoryInterface(t)
-		usecase := NewLocationUsecase(mockRepo)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
 		ctx := context.Background()
 		req := dto.PostLocationHandlerRequest{
 			Name:      "Test Location",
@@ -255,6 +307,7 @@ func TestCreateLocationUsecase(t *testing.T) {
 			LastModifiedAt: sql.NullTime{Time: now},
 		}
 
+		mockRepo.On("FindDuplicateLocations", ctx, mock.Anything).Return([]domain.Location{}, nil)
 		mockRepo.On("InsertLocation", ctx, mock.Anything).Return(expectedLocation, nil)
 
 		result, err := usecase.CreateLocationUsecase(ctx, req)
@@ -268,3 +321,702 @@ func TestCreateLocationUsecase(t *testing.T) {
 	})
 }
 
+func TestCreateLocationUsecaseDuplicate(t *testing.T) {
+	req := dto.PostLocationHandlerRequest{
+		Name:      "  jakarta ",
+		Region:    "DKI Jakarta",
+		Country:   "indonesia",
+		Latitude:  -6.2,
+		Longitude: 106.8,
+	}
+
+	t.Run("WHEN error occurred on find duplicate locations, THEN should return error", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		mockRepo.On("FindDuplicateLocations", ctx, mock.Anything).Return(nil, errors.New("database error"))
+
+		_, err := usecase.CreateLocationUsecase(ctx, req)
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to find duplicate locations")
+	})
+
+	t.Run("WHEN same normalized name and country exists, THEN should return DuplicateLocationError", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		existing := domain.Location{ID: 1, Name: "Jakarta", Country: "Indonesia", Latitude: -6.2088, Longitude: 106.8456}
+		mockRepo.On("FindDuplicateLocations", ctx, mock.MatchedBy(func(param repository.FindDuplicateLocationsParam) bool {
+			return assert.ObjectsAreEqual([]string{"jakarta|indonesia"}, param.NameKeys)
+		})).Return([]domain.Location{existing}, nil)
+
+		_, err := usecase.CreateLocationUsecase(ctx, req)
+
+		var duplicateErr *DuplicateLocationError
+		assert.ErrorAs(t, err, &duplicateErr)
+		assert.Equal(t, int64(1), duplicateErr.Existing.ID)
+	})
+
+	t.Run("WHEN another location is within the duplicate distance, THEN should return DuplicateLocationError", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		existing := domain.Location{ID: 3, Name: "Central Jakarta", Country: "Indonesia", Latitude: -6.2005, Longitude: 106.8005}
+		mockRepo.On("FindDuplicateLocations", ctx, mock.Anything).Return([]domain.Location{existing}, nil)
+
+		_, err := usecase.CreateLocationUsecase(ctx, req)
+
+		var duplicateErr *DuplicateLocationError
+		assert.ErrorAs(t, err, &duplicateErr)
+		assert.Equal(t, int64(3), duplicateErr.Existing.ID)
+	})
+
+	t.Run("WHEN bounding box candidate is farther than the duplicate distance, THEN should insert location", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		// inside the bounding box corner but about 1.2km away
+		corner := domain.Location{ID: 4, Name: "Menteng", Country: "Indonesia", Latitude: -6.2085, Longitude: 106.8085}
+		mockRepo.On("FindDuplicateLocations", ctx, mock.Anything).Return([]domain.Location{corner}, nil)
+		mockRepo.On("InsertLocation", ctx, mock.Anything).Return(domain.Location{ID: 5, Name: "Jakarta"}, nil)
+
+		result, err := usecase.CreateLocationUsecase(ctx, req)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), result.ID)
+	})
+
+	t.Run("WHEN same location is inserted concurrently, THEN should return DuplicateLocationError", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		existing := domain.Location{ID: 6, Name: "Jakarta", Country: "Indonesia", Latitude: -6.2088, Longitude: 106.8456}
+		mockRepo.On("FindDuplicateLocations", ctx, mock.Anything).Return(nil, nil).Once()
+		mockRepo.On("InsertLocation", ctx, mock.Anything).
+			Return(domain.Location{}, fmt.Errorf("failed to insert location: %w", repository.ErrDuplicateKey))
+		mockRepo.On("FindDuplicateLocations", ctx, mock.Anything).Return([]domain.Location{existing}, nil).Once()
+
+		_, err := usecase.CreateLocationUsecase(ctx, req)
+
+		var duplicateErr *DuplicateLocationError
+		assert.ErrorAs(t, err, &duplicateErr)
+		assert.Equal(t, int64(6), duplicateErr.Existing.ID)
+	})
+}
+
+func TestMergeLocationsUsecase(t *testing.T) {
+	t.Run("WHEN one of the locations does not exist, THEN should return ErrLocationNotFound", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		mockRepo.On("MergeLocations", ctx, int64(1), int64(2)).
+			Return(repository.MergeLocationsResult{}, fmt.Errorf("failed to merge locations: %w", sql.ErrNoRows))
+
+		_, err := usecase.MergeLocationsUsecase(ctx, dto.PostMergeLocationRequest{CanonicalID: 1, DuplicateID: 2})
+
+		assert.ErrorIs(t, err, ErrLocationNotFound)
+	})
+
+	t.Run("WHEN error occurred on merge, THEN should return error", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		expectedError := errors.New("failed to move weathers")
+		mockRepo.On("MergeLocations", ctx, int64(1), int64(2)).Return(repository.MergeLocationsResult{}, expectedError)
+
+		_, err := usecase.MergeLocationsUsecase(ctx, dto.PostMergeLocationRequest{CanonicalID: 1, DuplicateID: 2})
+
+		assert.Equal(t, expectedError, err)
+	})
+
+	t.Run("WHEN merge succeeds, THEN should return canonical location and moved rows", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+		usecase := NewLocationUsecase(mockRepo, mockCache, nil, 1000)
+		ctx := context.Background()
+
+		mockRepo.On("MergeLocations", ctx, int64(1), int64(2)).
+			Return(repository.MergeLocationsResult{MovedWeathers: 300, DiscardedWeathers: 50}, nil)
+		mockCache.On("Del", ctx, "weather:location:1", "weather:batch:location:1", "weather:location:2", "weather:batch:location:2").Return(nil)
+		mockCache.On("Publish", ctx, "weather:synced", int64(1)).Return(nil)
+		mockRepo.On("GetLocations", ctx, repository.GetLocationsParam{ID: 1, Limit: 1}).
+			Return([]domain.Location{{ID: 1, Name: "Jakarta"}}, nil)
+
+		result, err := usecase.MergeLocationsUsecase(ctx, dto.PostMergeLocationRequest{CanonicalID: 1, DuplicateID: 2})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), result.Location.ID)
+		assert.Equal(t, int64(2), result.MergedLocationID)
+		assert.Equal(t, int64(300), result.MovedWeathers)
+		assert.Equal(t, int64(50), result.DiscardedWeathers)
+	})
+}
+
+func TestCreateLocationUsecaseWithQuery(t *testing.T) {
+	t.Run("WHEN search location failed, THEN should return error", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		mockClient := mocks.NewWeatherAPIClientInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, mockClient, 1000)
+		ctx := context.Background()
+
+		mockClient.On("SearchLocations", ctx, "Jakarta").Return(nil, errors.New("weather api returned status 500"))
+
+		_, err := usecase.CreateLocationUsecase(ctx, dto.PostLocationHandlerRequest{Query: "Jakarta"})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to search location")
+	})
+
+	t.Run("WHEN query matches nothing, THEN should return ErrLocationNotResolved", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		mockClient := mocks.NewWeatherAPIClientInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, mockClient, 1000)
+		ctx := context.Background()
+
+		mockClient.On("SearchLocations", ctx, "Atlantis").Return([]weather.SearchLocation{}, nil)
+
+		_, err := usecase.CreateLocationUsecase(ctx, dto.PostLocationHandlerRequest{Query: " Atlantis "})
+
+		assert.ErrorIs(t, err, ErrLocationNotResolved)
+	})
+
+	t.Run("WHEN query matches many locations, THEN should return candidates without inserting", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		mockClient := mocks.NewWeatherAPIClientInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, mockClient, 1000)
+		ctx := context.Background()
+
+		mockClient.On("SearchLocations", ctx, "San Jose").Return([]weather.SearchLocation{
+			{ID: 10, Name: "San Jose", Region: "California", Country: "United States of America", Lat: 37.34, Lon: -121.89},
+			{ID: 11, Name: "San Jose", Region: "San Jose", Country: "Costa Rica", Lat: 9.93, Lon: -84.08},
+		}, nil)
+
+		_, err := usecase.CreateLocationUsecase(ctx, dto.PostLocationHandlerRequest{Query: "San Jose"})
+
+		var candidatesErr *LocationCandidatesError
+		assert.ErrorAs(t, err, &candidatesErr)
+		assert.Len(t, candidatesErr.Candidates, 2)
+		assert.Equal(t, "id:11", candidatesErr.Candidates[1].Query)
+		assert.Equal(t, "Costa Rica", candidatesErr.Candidates[1].Country)
+	})
+
+	t.Run("WHEN query matches a single location, THEN should insert it with timezone", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		mockClient := mocks.NewWeatherAPIClientInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, mockClient, 1000)
+		ctx := context.Background()
+
+		mockClient.On("SearchLocations", ctx, "id:11").Return([]weather.SearchLocation{
+			{ID: 11, Name: "San Jose", Region: "San Jose", Country: "Costa Rica", Lat: 9.93, Lon: -84.08},
+		}, nil)
+		mockClient.On("GetTimezone", ctx, "9.930000,-84.080000").Return(&weather.Location{TzID: "America/Costa_Rica"}, nil)
+
+		expectedLocation := domain.Location{
+			Name:      "San Jose",
+			Region:    "San Jose",
+			Country:   "Costa Rica",
+			Latitude:  9.93,
+			Longitude: -84.08,
+			Timezone:  "America/Costa_Rica",
+		}
+		inserted := expectedLocation
+		inserted.ID = 7
+		mockRepo.On("FindDuplicateLocations", ctx, mock.Anything).Return([]domain.Location{}, nil)
+		mockRepo.On("InsertLocation", ctx, expectedLocation).Return(inserted, nil)
+
+		result, err := usecase.CreateLocationUsecase(ctx, dto.PostLocationHandlerRequest{Query: "id:11"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), result.ID)
+		assert.Equal(t, "America/Costa_Rica", result.Timezone)
+	})
+}
+
+func TestGetNearbyLocationsUsecase(t *testing.T) {
+	t.Run("WHEN error occurred on get locations, THEN should return error accordingly", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		expectedError := errors.New("database error")
+		mockRepo.On("GetLocations", ctx, mock.Anything).Return(nil, expectedError)
+
+		_, err := usecase.GetNearbyLocationsUsecase(ctx, dto.GetNearbyLocationsParam{Latitude: -6.2, Longitude: 106.8})
+
+		assert.Error(t, err)
+		assert.Equal(t, expectedError, err)
+	})
+
+	t.Run("WHEN locations found in bounding box, THEN should return the ones within radius sorted by distance", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		locations := []domain.Location{
+			{ID: 2, Name: "Bandung", Latitude: -6.9175, Longitude: 107.6191},
+			{ID: 1, Name: "Jakarta", Latitude: -6.2088, Longitude: 106.8456},
+			{ID: 4, Name: "Corner", Latitude: -7.5, Longitude: 108.1},
+		}
+		mockRepo.On("GetLocations", ctx, mock.MatchedBy(func(param repository.GetLocationsParam) bool {
+			return param.BoundingBox != nil &&
+				param.BoundingBox.MinLatitude < -6.2088 && param.BoundingBox.MaxLatitude > -6.2088
+		})).Return(locations, nil)
+
+		result, err := usecase.GetNearbyLocationsUsecase(ctx, dto.GetNearbyLocationsParam{
+			Latitude:  -6.2088,
+			Longitude: 106.8456,
+			RadiusKm:  150,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Data, 2)
+		assert.Equal(t, int64(1), result.Data[0].Location.ID)
+		assert.Equal(t, float64(0), result.Data[0].DistanceKm)
+		assert.Equal(t, int64(2), result.Data[1].Location.ID)
+		assert.InDelta(t, 116.0, result.Data[1].DistanceKm, 1.0)
+	})
+
+	t.Run("WHEN limit is set, THEN should return only the nearest locations", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		locations := []domain.Location{
+			{ID: 2, Name: "Bandung", Latitude: -6.9175, Longitude: 107.6191},
+			{ID: 1, Name: "Jakarta", Latitude: -6.2088, Longitude: 106.8456},
+		}
+		mockRepo.On("GetLocations", ctx, mock.Anything).Return(locations, nil)
+
+		result, err := usecase.GetNearbyLocationsUsecase(ctx, dto.GetNearbyLocationsParam{
+			Latitude:  -6.9,
+			Longitude: 107.6,
+			RadiusKm:  200,
+			Limit:     1,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Data, 1)
+		assert.Equal(t, int64(2), result.Data[0].Location.ID)
+	})
+}
+
+func TestImportLocationsUsecase(t *testing.T) {
+	jakarta := dto.LocationExchangeItem{ExternalKey: "jkt", Name: "Jakarta", Region: "DKI Jakarta", Country: "Indonesia", Latitude: -6.2, Longitude: 106.8}
+	bandung := dto.LocationExchangeItem{Name: "Bandung", Region: "West Java", Country: "Indonesia", Latitude: -6.9, Longitude: 107.6}
+
+	t.Run("WHEN dry run, THEN should validate rows without writing", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		mockRepo.On("FindDuplicateLocations", ctx, mock.Anything).Return([]domain.Location{}, nil)
+
+		result, err := usecase.ImportLocationsUsecase(ctx, dto.LocationImportRequest{
+			DryRun: true,
+			Rows: []dto.LocationImportRow{
+				{Row: 1, Item: jakarta},
+				{Row: 2, Item: dto.LocationExchangeItem{Name: "Nowhere", Country: "Indonesia", Latitude: 100, Longitude: 10}},
+				{Row: 3, ParseError: errors.New("invalid lat value")},
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 3, result.Total)
+		assert.Equal(t, 1, result.Valid)
+		assert.Equal(t, 2, result.Invalid)
+		assert.Equal(t, dto.LocationImportStatusValid, result.Rows[0].Status)
+		assert.Equal(t, dto.LocationImportStatusInvalid, result.Rows[1].Status)
+		assert.Equal(t, "invalid lat value", result.Rows[2].Error)
+		mockRepo.AssertNotCalled(t, "UpsertLocations", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WHEN rows duplicate each other or an existing location, THEN should mark them invalid", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		// rows checked against the database are looked up with a single query
+		mockRepo.On("FindDuplicateLocations", ctx, mock.MatchedBy(func(p repository.FindDuplicateLocationsParam) bool {
+			return assert.ObjectsAreEqual([]string{"jakarta|indonesia", "bandung|indonesia"}, p.NameKeys) && len(p.BoundingBoxes) == 2
+		})).Return([]domain.Location{{ID: 7, Name: "Bandung", Country: "Indonesia", Latitude: -6.9, Longitude: 107.6}}, nil).Once()
+
+		result, err := usecase.ImportLocationsUsecase(ctx, dto.LocationImportRequest{
+			DryRun: true,
+			Rows: []dto.LocationImportRow{
+				{Row: 1, Item: jakarta},
+				{Row: 2, Item: jakarta},
+				{Row: 3, Item: bandung},
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Valid)
+		assert.Contains(t, result.Rows[1].Error, "externalKey already used by row 1")
+		assert.Contains(t, result.Rows[2].Error, "location already exists with id 7")
+	})
+
+	t.Run("WHEN rows are valid, THEN should upsert them and count the results", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		mockRepo.On("FindDuplicateLocations", ctx, mock.Anything).Return([]domain.Location{}, nil)
+		mockRepo.On("UpsertLocations", ctx, mock.MatchedBy(func(locations []domain.Location) bool {
+			return len(locations) == 2 && locations[0].ExternalKey.String == "jkt"
+		})).Return([]repository.UpsertLocationResult{
+			{ID: 1, Status: repository.UpsertStatusUpdated},
+			{ID: 2, Status: repository.UpsertStatusCreated},
+		}, nil)
+
+		result, err := usecase.ImportLocationsUsecase(ctx, dto.LocationImportRequest{
+			Rows: []dto.LocationImportRow{{Row: 1, Item: jakarta}, {Row: 2, Item: bandung}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Created)
+		assert.Equal(t, 1, result.Updated)
+		assert.Equal(t, int64(1), result.Rows[0].LocationID)
+		assert.Equal(t, repository.UpsertStatusCreated, result.Rows[1].Status)
+	})
+
+	t.Run("WHEN a batch failed to write, THEN should mark its rows failed", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		mockRepo.On("FindDuplicateLocations", ctx, mock.Anything).Return([]domain.Location{}, nil)
+		mockRepo.On("UpsertLocations", ctx, mock.Anything).Return(nil, errors.New("deadlock found"))
+
+		result, err := usecase.ImportLocationsUsecase(ctx, dto.LocationImportRequest{
+			Rows: []dto.LocationImportRow{{Row: 1, Item: jakarta}, {Row: 2, Item: bandung}},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Failed)
+		assert.Equal(t, dto.LocationImportStatusFailed, result.Rows[0].Status)
+		assert.Equal(t, "deadlock found", result.Rows[1].Error)
+	})
+}
+
+func TestExportLocationsUsecase(t *testing.T) {
+	t.Run("WHEN locations exist, THEN should return them as exchange items", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		mockRepo.On("GetLocations", ctx, repository.GetLocationsParam{OrderBy: "created_at_ascend", Limit: 10001}).
+			Return([]domain.Location{{ID: 1, Name: "Jakarta", ExternalKey: sql.NullString{String: "jkt", Valid: true}}}, nil)
+
+		result, err := usecase.ExportLocationsUsecase(ctx, dto.LocationExportParam{})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Items, 1)
+		assert.Equal(t, "jkt", result.Items[0].ExternalKey)
+		assert.Zero(t, result.NextOffset)
+	})
+
+	t.Run("WHEN more locations than a page exist, THEN should return the offset of the next page", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		locations := make([]domain.Location, 10001)
+		mockRepo.On("GetLocations", ctx, repository.GetLocationsParam{OrderBy: "created_at_ascend", Limit: 10001, Offset: 10000}).
+			Return(locations, nil)
+
+		result, err := usecase.ExportLocationsUsecase(ctx, dto.LocationExportParam{Offset: 10000})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Items, 10000)
+		assert.Equal(t, 20000, result.NextOffset)
+	})
+}
+
+func TestSetLocationTagsUsecase(t *testing.T) {
+	t.Run("WHEN location does not exist, THEN should return ErrLocationNotFound", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		mockRepo.On("GetLocations", ctx, repository.GetLocationsParam{ID: 3, Limit: 1}).Return(nil, nil)
+
+		_, err := usecase.SetLocationTagsUsecase(ctx, dto.PutLocationTagsRequest{LocationID: 3, Tags: []string{"airport"}})
+
+		assert.ErrorIs(t, err, ErrLocationNotFound)
+	})
+
+	t.Run("WHEN tags are given, THEN should store them normalized without duplicates", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		mockRepo.On("GetLocations", ctx, repository.GetLocationsParam{ID: 3, Limit: 1}).Return([]domain.Location{{ID: 3, Name: "Jakarta"}}, nil)
+		mockRepo.On("SetLocationTags", ctx, int64(3), []string{"airport", "java ops"}).Return(nil)
+
+		result, err := usecase.SetLocationTagsUsecase(ctx, dto.PutLocationTagsRequest{
+			LocationID: 3,
+			Tags:       []string{"Java  Ops", "airport", "AIRPORT"},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"airport", "java ops"}, result.Tags)
+	})
+}
+
+func TestSetLocationNamesUsecase(t *testing.T) {
+	t.Run("WHEN location does not exist, THEN should return ErrLocationNotFound", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		mockRepo.On("GetLocations", ctx, repository.GetLocationsParam{ID: 3, Limit: 1}).Return(nil, nil)
+
+		_, err := usecase.SetLocationNamesUsecase(ctx, dto.PutLocationNamesRequest{LocationID: 3, Names: []dto.LocationNameItem{{Name: "Djakarta"}}})
+
+		assert.ErrorIs(t, err, ErrLocationNotFound)
+	})
+
+	t.Run("WHEN names are given, THEN should store them normalized and suggest the location by alias", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		location := domain.Location{ID: 3, Name: "Jakarta", Country: "Indonesia"}
+		mockRepo.On("GetLocations", ctx, repository.GetLocationsParam{ID: 3, Limit: 1}).Return([]domain.Location{location}, nil)
+		mockRepo.On("GetLocations", ctx, repository.GetLocationsParam{}).Return([]domain.Location{location}, nil).Once()
+		mockRepo.On("GetLocationNames", ctx, []int64{3}).Return(map[int64][]domain.LocationName{}, nil).Once()
+		mockRepo.On("SetLocationNames", ctx, int64(3), []domain.LocationName{
+			{LocationID: 3, Name: "Djakarta"},
+			{LocationID: 3, Name: "Jakarta Raya", Language: "id-id"},
+		}).Return(nil)
+
+		_, err := usecase.SuggestLocationsUsecase(ctx, dto.SuggestLocationsParam{Query: "jakarta"})
+		assert.NoError(t, err)
+
+		result, err := usecase.SetLocationNamesUsecase(ctx, dto.PutLocationNamesRequest{
+			LocationID: 3,
+			Names: []dto.LocationNameItem{
+				{Name: " Djakarta "},
+				{Name: "Jakarta  Raya", Language: "id_ID"},
+				{Name: "djakarta"},
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []dto.LocationNameItem{{Name: "Djakarta"}, {Name: "Jakarta Raya", Language: "id-id"}}, result.AlternateNames)
+
+		suggestions, err := usecase.SuggestLocationsUsecase(ctx, dto.SuggestLocationsParam{Query: "djakar"})
+		assert.NoError(t, err)
+		assert.Len(t, suggestions.Data, 1)
+		assert.Equal(t, "alternateName", suggestions.Data[0].MatchedField)
+	})
+}
+
+func TestGetLocationsUsecaseLocalizedName(t *testing.T) {
+	t.Run("WHEN languages are requested, THEN should localize names and fall back to the name", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		mockRepo.On("GetLocations", ctx, repository.GetLocationsParam{Limit: 10, OrderBy: "created_at_ascend"}).
+			Return([]domain.Location{{ID: 1, Name: "Jakarta"}, {ID: 2, Name: "Bandung"}}, nil)
+		mockRepo.On("GetLocationsCount", ctx, repository.GetLocationsParam{}).Return(2, nil)
+		mockRepo.On("GetLocationTags", ctx, []int64{1, 2}).Return(map[int64][]string{}, nil)
+		mockRepo.On("GetLocationNames", ctx, []int64{1, 2}).Return(map[int64][]domain.LocationName{
+			1: {{LocationID: 1, Name: "Djakarta"}, {LocationID: 1, Name: "Jakarta Raya", Language: "id"}},
+		}, nil)
+
+		result, err := usecase.GetLocationsUsecase(ctx, dto.GetLocationHandlerParam{Languages: []string{"id-id", "en"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Jakarta Raya", result.Data.Items[0].LocalizedName)
+		assert.Len(t, result.Data.Items[0].AlternateNames, 2)
+		assert.Equal(t, "Bandung", result.Data.Items[1].LocalizedName)
+		assert.Empty(t, result.Data.Items[1].AlternateNames)
+	})
+}
+
+func TestGetLocationsUsecaseCursor(t *testing.T) {
+	now := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+	locations := []domain.Location{
+		{ID: 1, Name: "Bandung", CreatedAt: now},
+		{ID: 2, Name: "Jakarta", CreatedAt: now},
+		{ID: 3, Name: "Surabaya", CreatedAt: now},
+	}
+
+	t.Run("WHEN more rows follow the page, THEN should return next cursor", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		mockRepo.On("GetLocations", ctx, repository.GetLocationsParam{Limit: 2, OrderBy: "name_ascend"}).Return(locations[:2], nil)
+		mockRepo.On("GetLocationsCount", ctx, repository.GetLocationsParam{}).Return(3, nil)
+		mockRepo.On("GetLocationTags", ctx, []int64{1, 2}).Return(map[int64][]string{}, nil)
+		mockRepo.On("GetLocationNames", ctx, []int64{1, 2}).Return(map[int64][]domain.LocationName{}, nil)
+
+		result, err := usecase.GetLocationsUsecase(ctx, dto.GetLocationHandlerParam{PageSize: 2, SortBy: "name_ascend"})
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Data.PrevCursor)
+		next, err := cursor.Decode(result.Data.NextCursor)
+		assert.NoError(t, err)
+		assert.Equal(t, cursor.Cursor{SortBy: "name_ascend", Value: "Jakarta", ID: 2}, next)
+	})
+
+	t.Run("WHEN cursor is given, THEN should continue after it and drop the extra row", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		token := cursor.Cursor{SortBy: "created_at_ascend", Value: now.Format(time.RFC3339Nano), ID: 1}.Encode()
+		mockRepo.On("GetLocations", ctx, repository.GetLocationsParam{
+			After:   &repository.Keyset{Value: now, ID: 1},
+			Limit:   2,
+			OrderBy: "created_at_ascend",
+		}).Return(locations[1:], nil)
+		mockRepo.On("GetLocationsCount", ctx, repository.GetLocationsParam{}).Return(3, nil)
+		mockRepo.On("GetLocationTags", ctx, []int64{2}).Return(map[int64][]string{}, nil)
+		mockRepo.On("GetLocationNames", ctx, []int64{2}).Return(map[int64][]domain.LocationName{}, nil)
+
+		result, err := usecase.GetLocationsUsecase(ctx, dto.GetLocationHandlerParam{PageSize: 1, Cursor: token})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Data.Items, 1)
+		assert.Equal(t, int64(2), result.Data.Items[0].ID)
+		assert.Equal(t, 0, result.Data.CurrentPage)
+		assert.NotEmpty(t, result.Data.NextCursor)
+		prev, _ := cursor.Decode(result.Data.PrevCursor)
+		assert.True(t, prev.Backward)
+		assert.Equal(t, int64(2), prev.ID)
+	})
+
+	t.Run("WHEN cursor was created for another sort, THEN should return ErrInvalidCursor", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		token := cursor.Cursor{SortBy: "name_descend", Value: "Jakarta", ID: 2}.Encode()
+
+		_, err := usecase.GetLocationsUsecase(ctx, dto.GetLocationHandlerParam{SortBy: "name_ascend", Cursor: token})
+
+		assert.ErrorIs(t, err, cursor.ErrInvalidCursor)
+	})
+}
+
+func TestSuggestLocationsUsecase(t *testing.T) {
+	locations := []domain.Location{
+		{ID: 1, Name: "Jakarta", Region: "DKI Jakarta", Country: "Indonesia"},
+		{ID: 2, Name: "Surabaya", Region: "Jawa Timur", Country: "Indonesia"},
+		{ID: 3, Name: "Surakarta", Region: "Jawa Tengah", Country: "Indonesia"},
+	}
+
+	t.Run("WHEN query has a typo, THEN should rank the closest location first and load the index once", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		mockRepo.On("GetLocations", ctx, repository.GetLocationsParam{}).Return(locations, nil).Once()
+		mockRepo.On("GetLocationNames", ctx, []int64{1, 2, 3}).Return(map[int64][]domain.LocationName{}, nil).Once()
+
+		result, err := usecase.SuggestLocationsUsecase(ctx, dto.SuggestLocationsParam{Query: "Surbaya"})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, result.Data)
+		assert.Equal(t, int64(2), result.Data[0].ID)
+		assert.Equal(t, "name", result.Data[0].MatchedField)
+
+		result, err = usecase.SuggestLocationsUsecase(ctx, dto.SuggestLocationsParam{Query: "jawa ten", Limit: 1})
+		assert.NoError(t, err)
+		assert.Len(t, result.Data, 1)
+		assert.Equal(t, int64(3), result.Data[0].ID)
+		assert.Equal(t, "region", result.Data[0].MatchedField)
+	})
+
+	t.Run("WHEN location is created after the index is loaded, THEN should suggest it without reloading", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		mockRepo.On("GetLocations", ctx, repository.GetLocationsParam{}).Return(locations, nil).Once()
+		mockRepo.On("GetLocationNames", ctx, []int64{1, 2, 3}).Return(map[int64][]domain.LocationName{}, nil).Once()
+		mockRepo.On("FindDuplicateLocations", ctx, mock.Anything).Return([]domain.Location{}, nil)
+		mockRepo.On("InsertLocation", ctx, mock.Anything).Return(domain.Location{ID: 4, Name: "Bandung", Country: "Indonesia"}, nil)
+
+		_, err := usecase.SuggestLocationsUsecase(ctx, dto.SuggestLocationsParam{Query: "jakarta"})
+		assert.NoError(t, err)
+
+		_, err = usecase.CreateLocationUsecase(ctx, dto.PostLocationHandlerRequest{Name: "Bandung", Country: "Indonesia", Latitude: -6.9, Longitude: 107.6})
+		assert.NoError(t, err)
+
+		result, err := usecase.SuggestLocationsUsecase(ctx, dto.SuggestLocationsParam{Query: "band"})
+		assert.NoError(t, err)
+		assert.Len(t, result.Data, 1)
+		assert.Equal(t, int64(4), result.Data[0].ID)
+	})
+
+	t.Run("WHEN location is created while the index loads, THEN should keep it in the loaded index", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		mockRepo.On("FindDuplicateLocations", ctx, mock.Anything).Return([]domain.Location{}, nil)
+		mockRepo.On("InsertLocation", ctx, mock.Anything).Return(domain.Location{ID: 4, Name: "Bandung", Country: "Indonesia"}, nil)
+		mockRepo.On("GetLocations", ctx, repository.GetLocationsParam{}).Return(locations, nil).Once().Run(func(args mock.Arguments) {
+			_, err := usecase.CreateLocationUsecase(ctx, dto.PostLocationHandlerRequest{Name: "Bandung", Country: "Indonesia", Latitude: -6.9, Longitude: 107.6})
+			assert.NoError(t, err)
+		})
+		mockRepo.On("GetLocationNames", ctx, []int64{1, 2, 3}).Return(map[int64][]domain.LocationName{}, nil).Once()
+
+		result, err := usecase.SuggestLocationsUsecase(ctx, dto.SuggestLocationsParam{Query: "band"})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Data, 1)
+		assert.Equal(t, int64(4), result.Data[0].ID)
+	})
+
+	t.Run("WHEN import leaves rows unchanged, THEN should only index the written rows", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		mockRepo.On("GetLocations", ctx, repository.GetLocationsParam{}).Return(locations, nil).Once()
+		mockRepo.On("GetLocationNames", ctx, []int64{1, 2, 3}).Return(map[int64][]domain.LocationName{}, nil).Once()
+		mockRepo.On("FindDuplicateLocations", ctx, mock.Anything).Return([]domain.Location{}, nil)
+		mockRepo.On("UpsertLocations", ctx, mock.Anything).Return([]repository.UpsertLocationResult{
+			{ID: 4, Status: repository.UpsertStatusCreated},
+			{ID: 0, Status: repository.UpsertStatusUnchanged},
+		}, nil)
+
+		_, err := usecase.SuggestLocationsUsecase(ctx, dto.SuggestLocationsParam{Query: "jakarta"})
+		assert.NoError(t, err)
+
+		_, err = usecase.ImportLocationsUsecase(ctx, dto.LocationImportRequest{Rows: []dto.LocationImportRow{
+			{Row: 1, Item: dto.LocationExchangeItem{Name: "Bandung", Region: "West Java", Country: "Indonesia", Latitude: -6.9, Longitude: 107.6}},
+			{Row: 2, Item: dto.LocationExchangeItem{Name: "Bandar Lampung", Region: "Lampung", Country: "Indonesia", Latitude: -5.4, Longitude: 105.3}},
+		}})
+		assert.NoError(t, err)
+
+		result, err := usecase.SuggestLocationsUsecase(ctx, dto.SuggestLocationsParam{Query: "band"})
+		assert.NoError(t, err)
+		assert.Len(t, result.Data, 1)
+		assert.Equal(t, int64(4), result.Data[0].ID)
+	})
+
+	t.Run("WHEN error occurred on load locations, THEN should return error", func(t *testing.T) {
+		mockRepo := mocks.NewLocationRepositoryInterface(t)
+		usecase := NewLocationUsecase(mockRepo, nil, nil, 1000)
+		ctx := context.Background()
+
+		mockRepo.On("GetLocations", ctx, repository.GetLocationsParam{}).Return(nil, errors.New("database error"))
+
+		_, err := usecase.SuggestLocationsUsecase(ctx, dto.SuggestLocationsParam{Query: "jakarta"})
+
+		assert.Error(t, err)
+	})
+}
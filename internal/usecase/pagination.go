@@ -0,0 +1,90 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+	"tyarus/weather-app/internal/domain"
+	"tyarus/weather-app/internal/repository"
+	"tyarus/weather-app/pkg/cursor"
+	"tyarus/weather-app/pkg/utils"
+)
+
+// weatherCursorSort is the only order weathers are keyset paginated by.
+const weatherCursorSort = "forecast_time_descend"
+
+// keysetPage drops the extra row fetched to detect whether the listing goes
+// on, and reports whether rows exist before and after the page. rows are in
+// display order and hold at most limit+1 rows.
+func keysetPage[T any](rows []T, limit int, backward bool) (page []T, hasPrev, hasNext bool) {
+	more := len(rows) > limit
+	if backward {
+		if more {
+			rows = rows[len(rows)-limit:]
+		}
+		return rows, more, true
+	}
+
+	if more {
+		rows = rows[:limit]
+	}
+	return rows, true, more
+}
+
+func locationCursor(location domain.Location, sortBy string, backward bool) string {
+	c := cursor.Cursor{SortBy: sortBy, ID: location.ID, Backward: backward}
+	switch sortBy {
+	case utils.OrderByNameAsc, utils.OrderByNameDesc:
+		c.Value = location.Name
+	default:
+		c.Value = location.CreatedAt.Format(time.RFC3339Nano)
+	}
+
+	return c.Encode()
+}
+
+// locationKeyset decodes a location cursor. The cursor must come from a
+// listing with the same sort.
+func locationKeyset(token, sortBy string) (*repository.Keyset, error) {
+	c, err := cursor.Decode(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.SortBy != sortBy {
+		return nil, fmt.Errorf("%w, cursor was created for sortBy %s", cursor.ErrInvalidCursor, c.SortBy)
+	}
+
+	keyset := &repository.Keyset{Value: c.Value, ID: c.ID, Backward: c.Backward}
+	if sortBy == utils.OrderByCreatedAtAsc || sortBy == utils.OrderByCreatedAtDesc {
+		createdAt, err := time.Parse(time.RFC3339Nano, c.Value)
+		if err != nil {
+			return nil, cursor.ErrInvalidCursor
+		}
+		keyset.Value = createdAt
+	}
+
+	return keyset, nil
+}
+
+func weatherCursor(weather domain.Weather, backward bool) string {
+	return cursor.Cursor{
+		SortBy:   weatherCursorSort,
+		Value:    weather.ForecastTime.Format(time.RFC3339Nano),
+		ID:       weather.ID,
+		Backward: backward,
+	}.Encode()
+}
+
+func weatherKeyset(token string) (*repository.Keyset, error) {
+	c, err := cursor.Decode(token)
+	if err != nil {
+		return nil, err
+	}
+
+	forecastTime, err := time.Parse(time.RFC3339Nano, c.Value)
+	if err != nil || c.SortBy != weatherCursorSort {
+		return nil, cursor.ErrInvalidCursor
+	}
+
+	return &repository.Keyset{Value: forecastTime, ID: c.ID, Backward: c.Backward}, nil
+}
@@ -0,0 +1,286 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+	"tyarus/weather-app/internal/domain"
+	"tyarus/weather-app/internal/dto"
+	"tyarus/weather-app/internal/repository"
+	"tyarus/weather-app/pkg/astronomy"
+	"tyarus/weather-app/pkg/utils"
+	"tyarus/weather-app/pkg/weather"
+)
+
+// astronomyTimeFormat is how the provider sends the times of the astro block.
+const astronomyTimeFormat = "03:04 PM"
+
+// parseAstronomy builds the astronomy of a forecast day from the astro block
+// of the provider. Values the provider left out are computed from the
+// coordinate of the location, a day without any sun times is computed
+// entirely.
+func parseAstronomy(location domain.Location, date time.Time, astro weather.Astro) domain.Astronomy {
+	computed := computeAstronomy(location, date)
+	if astro.Sunrise == "" && astro.Sunset == "" {
+		return computed
+	}
+
+	result := domain.Astronomy{
+		LocationID:       location.ID,
+		Date:             date,
+		Sunrise:          parseAstronomyTime(date, astro.Sunrise),
+		Sunset:           parseAstronomyTime(date, astro.Sunset),
+		Moonrise:         parseAstronomyTime(date, astro.Moonrise),
+		Moonset:          parseAstronomyTime(date, astro.Moonset),
+		DaylightMinutes:  computed.DaylightMinutes,
+		MoonPhase:        astro.MoonPhase,
+		MoonIllumination: int(math.Round(astro.MoonIllumination)),
+		Source:           domain.AstronomySourceProvider,
+	}
+
+	if result.Sunrise.Valid && result.Sunset.Valid {
+		// near the poles the sun sets after local midnight, a sunset before
+		// the sunrise is on the next day
+		if result.Sunset.Time.Before(result.Sunrise.Time) {
+			result.Sunset.Time = result.Sunset.Time.AddDate(0, 0, 1)
+		}
+		result.DaylightMinutes = int(result.Sunset.Time.Sub(result.Sunrise.Time).Minutes())
+	}
+	if result.MoonPhase == "" {
+		result.MoonPhase = computed.MoonPhase
+		result.MoonIllumination = computed.MoonIllumination
+	}
+
+	return result
+}
+
+// parseAstronomyTime reads a provider time as a wall clock time of the
+// date, texts such as "No moonrise" are null.
+func parseAstronomyTime(date time.Time, value string) sql.NullTime {
+	parsed, err := time.Parse(astronomyTimeFormat, value)
+	if err != nil {
+		return sql.NullTime{}
+	}
+
+	return sql.NullTime{
+		Time:  time.Date(date.Year(), date.Month(), date.Day(), parsed.Hour(), parsed.Minute(), 0, 0, time.UTC),
+		Valid: true,
+	}
+}
+
+// computeAstronomy computes the sun and the moon phase of a day from the
+// coordinate of the location. Moonrise and moonset are left null.
+func computeAstronomy(location domain.Location, date time.Time) domain.Astronomy {
+	zone := locationZone(location)
+	local := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, zone)
+	sun := astronomy.SunTimes(local, location.Latitude, location.Longitude)
+	phase, illumination := astronomy.MoonPhase(local.Add(12 * time.Hour))
+
+	result := domain.Astronomy{
+		LocationID:       location.ID,
+		Date:             time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC),
+		DaylightMinutes:  int(sun.Daylight().Minutes()),
+		MoonPhase:        phase,
+		MoonIllumination: illumination,
+		Source:           domain.AstronomySourceComputed,
+	}
+	if !sun.Sunrise.IsZero() {
+		result.Sunrise = sql.NullTime{Time: wallClock(sun.Sunrise), Valid: true}
+		result.Sunset = sql.NullTime{Time: wallClock(sun.Sunset), Valid: true}
+	}
+
+	return result
+}
+
+// locationZone returns the time zone of the location. Locations without a
+// known zone get the offset of their longitude, which is close enough for
+// sun times.
+func locationZone(location domain.Location) *time.Location {
+	if location.Timezone != "" {
+		if zone, err := time.LoadLocation(location.Timezone); err == nil {
+			return zone
+		}
+	}
+
+	offset := int(math.Round(location.Longitude/15)) * 3600
+	return time.FixedZone("", offset)
+}
+
+// wallClock labels the local wall clock of t as UTC, the way forecast times
+// of the provider are stored.
+func wallClock(t time.Time) time.Time {
+	t = t.Round(time.Minute)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+}
+
+// zonedTime is the instant of the wall clock t in zone, the reverse of
+// wallClock.
+func zonedTime(t time.Time, zone *time.Location) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), 0, 0, zone)
+}
+
+// isDaytime reports whether the sun is up at t. Days without sunrise or
+// sunset are polar days or nights.
+func isDaytime(t time.Time, day domain.Astronomy) bool {
+	if !day.Sunrise.Valid || !day.Sunset.Valid {
+		return day.DaylightMinutes >= 24*60
+	}
+	return !t.Before(day.Sunrise.Time) && t.Before(day.Sunset.Time)
+}
+
+// addAstronomy adds the astronomy of the days in the weather response and
+// tells of every hour whether it is day. Days missing from the database are
+// computed.
+func (u *weatherUsecase) addAstronomy(ctx context.Context, location domain.Location, weatherResponse *dto.GetWeatherResponse) error {
+	items := append([]dto.GetWeatherResponseItem{weatherResponse.CurrentTime}, weatherResponse.Forecast...)
+	astronomies, err := u.getAstronomyDays(ctx, []domain.Location{location}, map[int64][]dto.GetWeatherResponseItem{location.ID: items})
+	if err != nil {
+		return err
+	}
+
+	days, ok := astronomies[location.ID]
+	if !ok {
+		return nil
+	}
+
+	weatherResponse.Astronomy = astronomyResponseItems(days)
+	setIsDay(&weatherResponse.CurrentTime, days)
+	for i := range weatherResponse.Forecast {
+		setIsDay(&weatherResponse.Forecast[i], days)
+	}
+
+	return nil
+}
+
+// addBatchAstronomy adds the astronomy of the days in every batch item with
+// a single query and tells of its current hour whether it is day.
+func (u *weatherUsecase) addBatchAstronomy(ctx context.Context, locations []domain.Location, batchItems []dto.GetWeathersBatchResponseItem) error {
+	items := map[int64][]dto.GetWeatherResponseItem{}
+	for _, item := range batchItems {
+		if item.CurrentTime != nil {
+			items[item.Location.ID] = append(items[item.Location.ID], *item.CurrentTime)
+		}
+		items[item.Location.ID] = append(items[item.Location.ID], item.Forecast...)
+	}
+
+	astronomies, err := u.getAstronomyDays(ctx, locations, items)
+	if err != nil {
+		return err
+	}
+
+	for i := range batchItems {
+		days, ok := astronomies[batchItems[i].Location.ID]
+		if !ok {
+			continue
+		}
+
+		batchItems[i].Astronomy = astronomyResponseItems(days)
+		if batchItems[i].CurrentTime != nil {
+			current := *batchItems[i].CurrentTime
+			setIsDay(&current, days)
+			batchItems[i].CurrentTime = &current
+		}
+	}
+
+	return nil
+}
+
+// getAstronomyDays returns by location id the astronomy of the days of its
+// items, keyed by date, reading every location with a single query. Days
+// missing from the database are computed, locations without items are
+// left out.
+func (u *weatherUsecase) getAstronomyDays(ctx context.Context, locations []domain.Location, items map[int64][]dto.GetWeatherResponseItem) (map[int64]map[string]domain.Astronomy, error) {
+	days := map[int64]map[string]time.Time{}
+	param := repository.GetAstronomiesParam{}
+	for _, location := range locations {
+		for _, item := range items[location.ID] {
+			if item.ForecastTime.IsZero() {
+				continue
+			}
+
+			date := startOfDay(item.ForecastTime.UTC())
+			if days[location.ID] == nil {
+				days[location.ID] = map[string]time.Time{}
+				param.LocationIDs = append(param.LocationIDs, location.ID)
+			}
+			days[location.ID][date.Format(utils.DateFormat)] = date
+
+			if param.From.IsZero() || date.Before(param.From) {
+				param.From = date
+			}
+			if date.After(param.To) {
+				param.To = date
+			}
+		}
+	}
+	if len(param.LocationIDs) == 0 {
+		return nil, nil
+	}
+
+	stored, err := u.weatherRepo.GetAstronomies(ctx, param)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get astronomies: %w", err)
+	}
+
+	result := map[int64]map[string]domain.Astronomy{}
+	for _, item := range stored {
+		// the range of the query spans the days of every location
+		key := item.Date.Format(utils.DateFormat)
+		if _, ok := days[item.LocationID][key]; !ok {
+			continue
+		}
+
+		if result[item.LocationID] == nil {
+			result[item.LocationID] = map[string]domain.Astronomy{}
+		}
+		result[item.LocationID][key] = item
+	}
+
+	for _, location := range locations {
+		for key, date := range days[location.ID] {
+			if _, ok := result[location.ID][key]; ok {
+				continue
+			}
+
+			if result[location.ID] == nil {
+				result[location.ID] = map[string]domain.Astronomy{}
+			}
+			result[location.ID][key] = computeAstronomy(location, date)
+		}
+	}
+
+	return result, nil
+}
+
+// astronomyResponseItems lists the days in date order.
+func astronomyResponseItems(days map[string]domain.Astronomy) []dto.AstronomyResponseItem {
+	keys := make([]string, 0, len(days))
+	for key := range days {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	items := make([]dto.AstronomyResponseItem, len(keys))
+	for i, key := range keys {
+		items[i] = dto.ParseToAstronomyResponseItem(days[key])
+	}
+	return items
+}
+
+// setIsDay tells of an hourly item whether the sun is up, daily items and
+// hours of unknown days are left as they are.
+func setIsDay(item *dto.GetWeatherResponseItem, days map[string]domain.Astronomy) {
+	if item.ForecastType != string(domain.ForecastTypeHour) {
+		return
+	}
+
+	day, ok := days[item.ForecastTime.UTC().Format(utils.DateFormat)]
+	if !ok {
+		return
+	}
+	isDay := isDaytime(item.ForecastTime, day)
+	item.IsDay = &isDay
+}
@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"tyarus/weather-app/internal/domain"
+	"tyarus/weather-app/internal/dto"
+	"tyarus/weather-app/internal/repository"
+)
+
+type WeatherExportUsecaseInterface interface {
+	ExportWeathersUsecase(ctx context.Context, param dto.ExportWeathersParam, w io.Writer) (dto.ExportWeathersResult, error)
+}
+
+type weatherExportUsecase struct {
+	weatherRepo repository.WeatherRepositoryInterface
+}
+
+func NewWeatherExportUsecase(weatherRepo repository.WeatherRepositoryInterface) WeatherExportUsecaseInterface {
+	return &weatherExportUsecase{weatherRepo: weatherRepo}
+}
+
+// ExportWeathersUsecase writes every matching weather to w while it is read
+// from the database, up to param.MaxRows, and returns the number of rows
+// written. Output already written stays in w when an error occurs halfway.
+func (u *weatherExportUsecase) ExportWeathersUsecase(ctx context.Context, param dto.ExportWeathersParam, w io.Writer) (dto.ExportWeathersResult, error) {
+	var result dto.ExportWeathersResult
+	writer, err := dto.NewWeatherExportWriter(w, param.Format, param.Columns)
+	if err != nil {
+		return result, err
+	}
+
+	var locationIDs []int64
+	for _, id := range param.LocationIDs {
+		if !slices.Contains(locationIDs, int64(id)) {
+			locationIDs = append(locationIDs, int64(id))
+		}
+	}
+
+	streamParam := repository.StreamWeathersParam{
+		LocationIDs:    locationIDs,
+		From:           param.From,
+		To:             param.To,
+		ForecastType:   param.ForecastType,
+		ConditionCodes: param.Conditions,
+	}
+	// one row past the limit tells whether the export is cut short
+	if param.MaxRows > 0 {
+		streamParam.Limit = param.MaxRows + 1
+	}
+
+	err = u.weatherRepo.StreamWeathers(ctx, streamParam, func(weather domain.Weather) error {
+		if param.MaxRows > 0 && result.Rows == int64(param.MaxRows) {
+			result.Truncated = true
+			return nil
+		}
+
+		// provider icons are exported as served by our icon proxy
+		weather.ConditionIconURL = dto.NewWeatherConditionResponse(weather).Present(nil, param.BaseURL).IconURL
+		if err := writer.Write(weather); err != nil {
+			return fmt.Errorf("failed to write weather: %w", err)
+		}
+		result.Rows++
+		return nil
+	})
+	if err != nil {
+		writer.Close()
+		return result, fmt.Errorf("failed to stream weathers: %w", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return result, fmt.Errorf("failed to flush export: %w", err)
+	}
+
+	return result, nil
+}
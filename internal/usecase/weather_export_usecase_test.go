@@ -0,0 +1,157 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"tyarus/weather-app/internal/domain"
+	"tyarus/weather-app/internal/dto"
+	"tyarus/weather-app/internal/repository"
+	"tyarus/weather-app/mocks"
+)
+
+func TestExportWeathersUsecase(t *testing.T) {
+	forecastTime := time.Date(2024, 1, 2, 3, 0, 0, 0, time.UTC)
+	weathers := []domain.Weather{
+		{ID: 1, LocationID: 1, ForecastTime: forecastTime, ForecastType: domain.ForecastTypeHour, TemperatureCelcius: 28.5, Humidity: 80, PrecipitationMM: sql.NullFloat64{Float64: 0.4, Valid: true}, ConditionStatus: "Light rain, heavy later"},
+		{ID: 2, LocationID: 2, ForecastTime: forecastTime, ForecastType: domain.ForecastTypeHour, TemperatureCelcius: 22, Humidity: 90},
+	}
+	stream := func(rows []domain.Weather) func(context.Context, repository.StreamWeathersParam, func(domain.Weather) error) error {
+		return func(_ context.Context, _ repository.StreamWeathersParam, fn func(domain.Weather) error) error {
+			for _, row := range rows {
+				if err := fn(row); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	t.Run("WHEN format is csv, THEN should write header and selected columns", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+
+		usecase := NewWeatherExportUsecase(mockWeatherRepo)
+		ctx := context.Background()
+
+		mockWeatherRepo.On("StreamWeathers", ctx, repository.StreamWeathersParam{LocationIDs: []int64{1, 2}}, mock.Anything).Return(stream(weathers))
+
+		var buffer bytes.Buffer
+		result, err := usecase.ExportWeathersUsecase(ctx, dto.ExportWeathersParam{
+			LocationIDs: []int{1, 2, 1},
+			Format:      dto.WeatherExportFormatCSV,
+			Columns:     []string{"locationID", "forecastTime", "temperatureCelcius", "precipitationMM", "conditionStatus"},
+		}, &buffer)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), result.Rows)
+		assert.Equal(t, "locationID,forecastTime,temperatureCelcius,precipitationMM,conditionStatus\n"+
+			"1,2024-01-02T03:00:00Z,28.5,0.4,\"Light rain, heavy later\"\n"+
+			"2,2024-01-02T03:00:00Z,22,,\n", buffer.String())
+	})
+
+	t.Run("WHEN format is ndjson, THEN should write one object per line in column order", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+
+		usecase := NewWeatherExportUsecase(mockWeatherRepo)
+		ctx := context.Background()
+
+		mockWeatherRepo.On("StreamWeathers", ctx, mock.Anything, mock.Anything).Return(stream(weathers))
+
+		var buffer bytes.Buffer
+		result, err := usecase.ExportWeathersUsecase(ctx, dto.ExportWeathersParam{
+			Format:  dto.WeatherExportFormatNDJSON,
+			Columns: []string{"temperatureCelcius", "locationID", "precipitationMM"},
+		}, &buffer)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), result.Rows)
+		assert.Equal(t, "{\"temperatureCelcius\":28.5,\"locationID\":1,\"precipitationMM\":0.4}\n"+
+			"{\"temperatureCelcius\":22,\"locationID\":2,\"precipitationMM\":null}\n", buffer.String())
+	})
+
+	t.Run("WHEN rows have a provider icon, THEN should export the URL of the icon proxy", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+
+		usecase := NewWeatherExportUsecase(mockWeatherRepo)
+		ctx := context.Background()
+
+		mockWeatherRepo.On("StreamWeathers", ctx, mock.Anything, mock.Anything).Return(stream([]domain.Weather{
+			{ID: 1, ConditionIconURL: "//cdn.weatherapi.com/weather/64x64/day/296.png"},
+		}))
+
+		var buffer bytes.Buffer
+		_, err := usecase.ExportWeathersUsecase(ctx, dto.ExportWeathersParam{
+			Format:  dto.WeatherExportFormatCSV,
+			Columns: []string{"id", "conditionIconURL"},
+			BaseURL: "https://weather.example.com",
+		}, &buffer)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "id,conditionIconURL\n1,https://weather.example.com/api/v1/icons/day-296.png\n", buffer.String())
+	})
+
+	t.Run("WHEN stream fails halfway, THEN should keep written rows and return error", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+
+		usecase := NewWeatherExportUsecase(mockWeatherRepo)
+		ctx := context.Background()
+
+		mockWeatherRepo.On("StreamWeathers", ctx, mock.Anything, mock.Anything).Return(
+			func(_ context.Context, _ repository.StreamWeathersParam, fn func(domain.Weather) error) error {
+				if err := fn(weathers[0]); err != nil {
+					return err
+				}
+				return errors.New("connection lost")
+			})
+
+		var buffer bytes.Buffer
+		result, err := usecase.ExportWeathersUsecase(ctx, dto.ExportWeathersParam{
+			Format:  dto.WeatherExportFormatCSV,
+			Columns: []string{"id"},
+		}, &buffer)
+
+		assert.ErrorContains(t, err, "failed to stream weathers")
+		assert.Equal(t, int64(1), result.Rows)
+		assert.Equal(t, "id\n1\n", buffer.String())
+	})
+
+	t.Run("WHEN rows are left past the limit, THEN should stop and report the export as truncated", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+
+		usecase := NewWeatherExportUsecase(mockWeatherRepo)
+		ctx := context.Background()
+
+		mockWeatherRepo.On("StreamWeathers", ctx, repository.StreamWeathersParam{Limit: 2}, mock.Anything).Return(stream(weathers))
+
+		var buffer bytes.Buffer
+		result, err := usecase.ExportWeathersUsecase(ctx, dto.ExportWeathersParam{
+			Format:  dto.WeatherExportFormatCSV,
+			Columns: []string{"id"},
+			MaxRows: 1,
+		}, &buffer)
+
+		assert.NoError(t, err)
+		assert.Equal(t, dto.ExportWeathersResult{Rows: 1, Truncated: true}, result)
+		assert.Equal(t, "id\n1\n", buffer.String())
+	})
+
+	t.Run("WHEN column is unknown, THEN should return error before streaming", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+
+		usecase := NewWeatherExportUsecase(mockWeatherRepo)
+
+		_, err := usecase.ExportWeathersUsecase(context.Background(), dto.ExportWeathersParam{
+			Format:  dto.WeatherExportFormatCSV,
+			Columns: []string{"dewPoint"},
+		}, &bytes.Buffer{})
+
+		assert.ErrorContains(t, err, "invalid columns parameter")
+	})
+}
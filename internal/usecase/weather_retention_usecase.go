@@ -0,0 +1,238 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+	"tyarus/weather-app/internal/dto"
+	"tyarus/weather-app/internal/repository"
+	"tyarus/weather-app/pkg/utils"
+)
+
+var ErrWeatherRetentionRunning = errors.New("weather retention is already running, please wait until it finishes")
+
+type WeatherRetentionUsecaseInterface interface {
+	ApplyWeatherRetentionUsecase(ctx context.Context, req dto.ApplyWeatherRetentionRequest) (dto.WeatherRetentionReport, error)
+	StartWeatherRetentionUsecase(ctx context.Context, req dto.ApplyWeatherRetentionRequest) (dto.WeatherRetentionJob, error)
+	GetWeatherRetentionJobUsecase(ctx context.Context) dto.WeatherRetentionJob
+}
+
+type weatherRetentionUsecase struct {
+	weatherRepo  repository.WeatherRepositoryInterface
+	locationRepo repository.LocationRepositoryInterface
+	policy       dto.WeatherRetentionPolicy
+	batchPause   time.Duration
+
+	mu  sync.Mutex
+	job dto.WeatherRetentionJob
+}
+
+func NewWeatherRetentionUsecase(
+	weatherRepo repository.WeatherRepositoryInterface,
+	locationRepo repository.LocationRepositoryInterface,
+	policy dto.WeatherRetentionPolicy,
+) WeatherRetentionUsecaseInterface {
+	if policy.BatchSize <= 0 {
+		policy.BatchSize = 1000
+	}
+
+	return &weatherRetentionUsecase{
+		weatherRepo:  weatherRepo,
+		locationRepo: locationRepo,
+		policy:       policy,
+		batchPause:   utils.RetentionBatchPause,
+	}
+}
+
+// StartWeatherRetentionUsecase applies retention in the background, one run
+// at a time. The run outlives the request that started it.
+func (u *weatherRetentionUsecase) StartWeatherRetentionUsecase(ctx context.Context, req dto.ApplyWeatherRetentionRequest) (dto.WeatherRetentionJob, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.job.Status == dto.WeatherRetentionJobRunning {
+		return u.job, ErrWeatherRetentionRunning
+	}
+
+	startedAt := time.Now()
+	u.job = dto.WeatherRetentionJob{Status: dto.WeatherRetentionJobRunning, StartedAt: &startedAt}
+
+	go func() {
+		report, err := u.ApplyWeatherRetentionUsecase(context.WithoutCancel(ctx), req)
+
+		u.mu.Lock()
+		defer u.mu.Unlock()
+
+		finishedAt := time.Now()
+		u.job.FinishedAt = &finishedAt
+		u.job.Report = &report
+		u.job.Status = dto.WeatherRetentionJobDone
+		if err != nil {
+			u.job.Status = dto.WeatherRetentionJobFailed
+			u.job.Error = err.Error()
+		}
+	}()
+
+	return u.job, nil
+}
+
+// GetWeatherRetentionJobUsecase returns the last run started with
+// StartWeatherRetentionUsecase, zero when none was.
+func (u *weatherRetentionUsecase) GetWeatherRetentionJobUsecase(ctx context.Context) dto.WeatherRetentionJob {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	return u.job
+}
+
+// ApplyWeatherRetentionUsecase downsamples and deletes old hourly rows one
+// location at a time, then purges old soft deleted rows. Downsampling runs
+// in windows of days and deletes in batches of the policy size, with a pause
+// in between so other writers are not blocked for long.
+func (u *weatherRetentionUsecase) ApplyWeatherRetentionUsecase(ctx context.Context, req dto.ApplyWeatherRetentionRequest) (dto.WeatherRetentionReport, error) {
+	report := dto.WeatherRetentionReport{DryRun: req.DryRun}
+
+	// forecast days are UTC dates, see repository.forecastDate, so the cutoff
+	// is a UTC midnight too
+	today := startOfDay(time.Now().UTC())
+	var hourlyBefore, deletedBefore time.Time
+	if u.policy.HourlyDays > 0 {
+		hourlyBefore = today.AddDate(0, 0, -u.policy.HourlyDays)
+		report.HourlyBefore = hourlyBefore.Format(utils.DateFormat)
+	}
+	if u.policy.PurgeDeletedDays > 0 {
+		deletedBefore = today.AddDate(0, 0, -u.policy.PurgeDeletedDays)
+		report.DeletedBefore = deletedBefore.Format(utils.DateFormat)
+	}
+
+	if req.DryRun {
+		counts, err := u.weatherRepo.CountWeatherRetention(ctx, repository.CountWeatherRetentionParam{
+			HourlyBefore:  hourlyBefore,
+			DeletedBefore: deletedBefore,
+		})
+		if err != nil {
+			return report, fmt.Errorf("failed to count weather retention: %w", err)
+		}
+
+		report.DownsampledDays = counts.DownsampleDays
+		report.DeletedHourlyRows = counts.HourlyRows
+		report.PurgedRows = counts.DeletedRows
+		return report, nil
+	}
+
+	if !hourlyBefore.IsZero() {
+		// weathers of soft deleted locations are kept too, so they age the same
+		locations, err := u.locationRepo.GetLocations(ctx, repository.GetLocationsParam{IncludeDeleted: true})
+		if err != nil {
+			return report, fmt.Errorf("failed to get locations: %w", err)
+		}
+
+		for _, location := range locations {
+			// a location is only deleted from once its days are downsampled
+			days, err := u.downsampleInWindows(ctx, &report, location.ID, hourlyBefore)
+			if err != nil {
+				return report, fmt.Errorf("failed to downsample weathers of location %d: %w", location.ID, err)
+			}
+			report.DownsampledDays += days
+
+			deleted, err := u.deleteInBatches(ctx, &report, repository.WeatherRetentionParam{
+				LocationID: location.ID,
+				Before:     hourlyBefore,
+				Limit:      u.policy.BatchSize,
+			}, u.weatherRepo.DeleteHourlyWeathers)
+			if err != nil {
+				return report, fmt.Errorf("failed to delete hourly weathers of location %d: %w", location.ID, err)
+			}
+			report.DeletedHourlyRows += deleted
+			report.Locations++
+		}
+	}
+
+	if !deletedBefore.IsZero() {
+		purged, err := u.deleteInBatches(ctx, &report, repository.WeatherRetentionParam{
+			Before: deletedBefore,
+			Limit:  u.policy.BatchSize,
+		}, u.weatherRepo.PurgeDeletedWeathers)
+		if err != nil {
+			return report, fmt.Errorf("failed to purge deleted weathers: %w", err)
+		}
+		report.PurgedRows += purged
+	}
+
+	return report, nil
+}
+
+// downsampleInWindows downsamples the days of the location from its oldest
+// hourly row until before, a window of days per statement.
+func (u *weatherRetentionUsecase) downsampleInWindows(ctx context.Context, report *dto.WeatherRetentionReport, locationID int64, before time.Time) (int64, error) {
+	oldest, err := u.weatherRepo.GetOldestHourlyForecastTime(ctx, locationID)
+	if err != nil || oldest.IsZero() {
+		return 0, err
+	}
+
+	var total int64
+	first := startOfDay(oldest.UTC())
+	for from := first; from.Before(before); from = from.AddDate(0, 0, utils.RetentionDownsampleDays) {
+		if from.After(first) {
+			if err := u.pause(ctx); err != nil {
+				return total, err
+			}
+		}
+
+		to := from.AddDate(0, 0, utils.RetentionDownsampleDays)
+		if to.After(before) {
+			to = before
+		}
+
+		days, err := u.weatherRepo.DownsampleHourlyWeathers(ctx, repository.WeatherRetentionParam{
+			LocationID: locationID,
+			From:       from,
+			Before:     to,
+		})
+		if err != nil {
+			return total, err
+		}
+
+		total += days
+		report.Batches++
+	}
+
+	return total, nil
+}
+
+// deleteInBatches repeats remove until it deletes less than a full batch.
+func (u *weatherRetentionUsecase) deleteInBatches(
+	ctx context.Context,
+	report *dto.WeatherRetentionReport,
+	param repository.WeatherRetentionParam,
+	remove func(context.Context, repository.WeatherRetentionParam) (int64, error),
+) (int64, error) {
+	var total int64
+	for {
+		deleted, err := remove(ctx, param)
+		if err != nil {
+			return total, err
+		}
+
+		total += deleted
+		report.Batches++
+		if deleted < int64(param.Limit) {
+			return total, nil
+		}
+
+		if err := u.pause(ctx); err != nil {
+			return total, err
+		}
+	}
+}
+
+func (u *weatherRetentionUsecase) pause(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(u.batchPause):
+		return nil
+	}
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"tyarus/weather-app/internal/domain"
+	"tyarus/weather-app/internal/dto"
+	"tyarus/weather-app/internal/repository"
+	"tyarus/weather-app/mocks"
+)
+
+func TestApplyWeatherRetentionUsecase(t *testing.T) {
+	policy := dto.WeatherRetentionPolicy{HourlyDays: 90, PurgeDeletedDays: 30, BatchSize: 2}
+	newUsecase := func(weatherRepo repository.WeatherRepositoryInterface, locationRepo repository.LocationRepositoryInterface, policy dto.WeatherRetentionPolicy) WeatherRetentionUsecaseInterface {
+		usecase := NewWeatherRetentionUsecase(weatherRepo, locationRepo, policy).(*weatherRetentionUsecase)
+		usecase.batchPause = 0
+		return usecase
+	}
+
+	t.Run("WHEN dry run, THEN should only report counts", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+
+		usecase := newUsecase(mockWeatherRepo, mockLocationRepo, policy)
+		ctx := context.Background()
+
+		mockWeatherRepo.On("CountWeatherRetention", ctx, mock.MatchedBy(func(param repository.CountWeatherRetentionParam) bool {
+			return !param.HourlyBefore.IsZero() && param.DeletedBefore.After(param.HourlyBefore)
+		})).Return(domain.WeatherRetentionCounts{DownsampleDays: 3, HourlyRows: 72, DeletedRows: 5}, nil)
+
+		report, err := usecase.ApplyWeatherRetentionUsecase(ctx, dto.ApplyWeatherRetentionRequest{DryRun: true})
+
+		assert.NoError(t, err)
+		assert.True(t, report.DryRun)
+		assert.Equal(t, int64(3), report.DownsampledDays)
+		assert.Equal(t, int64(72), report.DeletedHourlyRows)
+		assert.Equal(t, int64(5), report.PurgedRows)
+		assert.NotEmpty(t, report.HourlyBefore)
+	})
+
+	t.Run("WHEN applied, THEN should downsample before deleting in batches", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+
+		usecase := newUsecase(mockWeatherRepo, mockLocationRepo, policy)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, repository.GetLocationsParam{IncludeDeleted: true}).Return([]domain.Location{{ID: 1}, {ID: 2}}, nil)
+		location := func(id int64) interface{} {
+			return mock.MatchedBy(func(param repository.WeatherRetentionParam) bool { return param.LocationID == id })
+		}
+		today := time.Now().UTC().Truncate(24 * time.Hour)
+		// 60 days to downsample, in a window of 31 days and one of 29
+		mockWeatherRepo.On("GetOldestHourlyForecastTime", ctx, int64(1)).Return(today.AddDate(0, 0, -150).Add(13*time.Hour), nil)
+		mockWeatherRepo.On("DownsampleHourlyWeathers", ctx, repository.WeatherRetentionParam{
+			LocationID: 1,
+			From:       today.AddDate(0, 0, -150),
+			Before:     today.AddDate(0, 0, -119),
+		}).Return(int64(2), nil).Once()
+		mockWeatherRepo.On("DownsampleHourlyWeathers", ctx, repository.WeatherRetentionParam{
+			LocationID: 1,
+			From:       today.AddDate(0, 0, -119),
+			Before:     today.AddDate(0, 0, -90),
+		}).Return(int64(1), nil).Once()
+		mockWeatherRepo.On("DeleteHourlyWeathers", ctx, location(1)).Return(int64(2), nil).Twice()
+		mockWeatherRepo.On("DeleteHourlyWeathers", ctx, location(1)).Return(int64(1), nil).Once()
+		mockWeatherRepo.On("GetOldestHourlyForecastTime", ctx, int64(2)).Return(time.Time{}, nil)
+		mockWeatherRepo.On("DeleteHourlyWeathers", ctx, location(2)).Return(int64(0), nil).Once()
+		mockWeatherRepo.On("PurgeDeletedWeathers", ctx, mock.Anything).Return(int64(1), nil).Once()
+
+		report, err := usecase.ApplyWeatherRetentionUsecase(ctx, dto.ApplyWeatherRetentionRequest{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Locations)
+		assert.Equal(t, int64(3), report.DownsampledDays)
+		assert.Equal(t, int64(5), report.DeletedHourlyRows)
+		assert.Equal(t, int64(1), report.PurgedRows)
+		assert.Equal(t, 7, report.Batches)
+		assert.Equal(t, today.AddDate(0, 0, -90).Format("2006-01-02"), report.HourlyBefore)
+	})
+
+	t.Run("WHEN downsampling fails, THEN should not delete hourly rows", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+
+		usecase := newUsecase(mockWeatherRepo, mockLocationRepo, policy)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{{ID: 1}}, nil)
+		mockWeatherRepo.On("GetOldestHourlyForecastTime", ctx, int64(1)).Return(time.Now().AddDate(-1, 0, 0), nil)
+		mockWeatherRepo.On("DownsampleHourlyWeathers", ctx, mock.Anything).Return(int64(0), errors.New("database error"))
+
+		_, err := usecase.ApplyWeatherRetentionUsecase(ctx, dto.ApplyWeatherRetentionRequest{})
+
+		assert.ErrorContains(t, err, "failed to downsample weathers of location 1")
+		mockWeatherRepo.AssertNotCalled(t, "DeleteHourlyWeathers", mock.Anything, mock.Anything)
+	})
+
+	t.Run("WHEN every policy is disabled, THEN should change nothing", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+
+		usecase := newUsecase(mockWeatherRepo, mockLocationRepo, dto.WeatherRetentionPolicy{})
+
+		report, err := usecase.ApplyWeatherRetentionUsecase(context.Background(), dto.ApplyWeatherRetentionRequest{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, dto.WeatherRetentionReport{}, report)
+	})
+}
+
+func TestStartWeatherRetentionUsecase(t *testing.T) {
+	policy := dto.WeatherRetentionPolicy{PurgeDeletedDays: 30, BatchSize: 2}
+
+	t.Run("WHEN started, THEN should run in the background until it is done", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+
+		usecase := NewWeatherRetentionUsecase(mockWeatherRepo, mockLocationRepo, policy)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		release := make(chan struct{})
+		mockWeatherRepo.On("PurgeDeletedWeathers", mock.Anything, mock.Anything).
+			Run(func(mock.Arguments) { <-release }).
+			Return(int64(1), nil).Once()
+
+		job, err := usecase.StartWeatherRetentionUsecase(ctx, dto.ApplyWeatherRetentionRequest{})
+		assert.NoError(t, err)
+		assert.Equal(t, dto.WeatherRetentionJobRunning, job.Status)
+		assert.NotNil(t, job.StartedAt)
+
+		// the request that started it is gone, the run goes on
+		cancel()
+
+		_, err = usecase.StartWeatherRetentionUsecase(context.Background(), dto.ApplyWeatherRetentionRequest{})
+		assert.ErrorIs(t, err, ErrWeatherRetentionRunning)
+
+		close(release)
+		assert.Eventually(t, func() bool {
+			return usecase.GetWeatherRetentionJobUsecase(context.Background()).Status == dto.WeatherRetentionJobDone
+		}, time.Second, 10*time.Millisecond)
+
+		job = usecase.GetWeatherRetentionJobUsecase(context.Background())
+		assert.NotNil(t, job.FinishedAt)
+		assert.Equal(t, int64(1), job.Report.PurgedRows)
+		assert.Empty(t, job.Error)
+	})
+
+	t.Run("WHEN the run fails, THEN should keep the error", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+
+		usecase := NewWeatherRetentionUsecase(mockWeatherRepo, mockLocationRepo, policy)
+
+		mockWeatherRepo.On("PurgeDeletedWeathers", mock.Anything, mock.Anything).Return(int64(0), errors.New("database error"))
+
+		_, err := usecase.StartWeatherRetentionUsecase(context.Background(), dto.ApplyWeatherRetentionRequest{})
+		assert.NoError(t, err)
+
+		assert.Eventually(t, func() bool {
+			return usecase.GetWeatherRetentionJobUsecase(context.Background()).Status == dto.WeatherRetentionJobFailed
+		}, time.Second, 10*time.Millisecond)
+		assert.Contains(t, usecase.GetWeatherRetentionJobUsecase(context.Background()).Error, "database error")
+	})
+}
@@ -2,22 +2,52 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 	"tyarus/weather-app/internal/domain"
 	"tyarus/weather-app/internal/dto"
 	"tyarus/weather-app/internal/infra"
 	"tyarus/weather-app/internal/repository"
+	"tyarus/weather-app/pkg/atom"
+	"tyarus/weather-app/pkg/condition"
+	"tyarus/weather-app/pkg/geojson"
+	"tyarus/weather-app/pkg/ical"
+	"tyarus/weather-app/pkg/narrative"
 	"tyarus/weather-app/pkg/response"
+	"tyarus/weather-app/pkg/stats"
+	"tyarus/weather-app/pkg/svgchart"
+	"tyarus/weather-app/pkg/units"
 	"tyarus/weather-app/pkg/utils"
 	"tyarus/weather-app/pkg/weather"
 )
 
+var (
+	ErrNoNearbyLocation = errors.New("no synced location found near the point, please check your parameter")
+	ErrIconNotFound     = errors.New("icon not found")
+)
+
 type WeatherUsecaseInterface interface {
 	SyncWeatherUsecase(ctx context.Context, req dto.PostWeatherSyncUsecaseRequest) error
 	GetWeathersUsecase(ctx context.Context, req dto.GetWeathersParam) (response.Response[dto.GetWeatherResponse], error)
+	GetWeathersBatchUsecase(ctx context.Context, param dto.GetWeathersBatchParam) (response.Response[dto.GetWeathersBatchResponse], error)
+	GetPointWeatherUsecase(ctx context.Context, param dto.GetPointWeatherParam) (response.Response[dto.GetPointWeatherResponse], error)
+	GetDailyWeatherUsecase(ctx context.Context, param dto.GetDailyWeatherParam) (response.Response[dto.GetDailyWeatherResponse], error)
+	GetWeatherStatsUsecase(ctx context.Context, param dto.GetWeatherStatsParam) (response.Response[dto.GetWeatherStatsResponse], error)
+	GetForecastCalendarUsecase(ctx context.Context, param dto.GetForecastFeedParam) (dto.ForecastFeedDocument, error)
+	GetForecastFeedUsecase(ctx context.Context, param dto.GetForecastFeedParam) (dto.ForecastFeedDocument, error)
+	CompareWeathersUsecase(ctx context.Context, param dto.CompareWeathersParam) (response.Response[dto.CompareWeathersResponse], error)
+	GetMapLayerUsecase(ctx context.Context, param dto.GetMapLayerParam) (dto.MapLayer, error)
+	GetWeatherChartUsecase(ctx context.Context, param dto.GetWeatherChartParam) (dto.WeatherChart, error)
+	GetIconUsecase(ctx context.Context, code string) ([]byte, error)
+	BackfillWeatherUsecase(ctx context.Context, param dto.BackfillWeatherParam) (dto.BackfillWeatherResult, error)
+	BackfillConditionCodesUsecase(ctx context.Context) (int64, error)
 }
 
 type weatherUsecase struct {
@@ -42,8 +72,9 @@ func NewWeatherUsecase(
 }
 
 func (u *weatherUsecase) SyncWeatherUsecase(ctx context.Context, req dto.PostWeatherSyncUsecaseRequest) error {
-	param := repository.GetLocationsParam{}
-	if req.Limit == 0 {
+	param := repository.GetLocationsParam{GroupID: req.GroupID}
+	// a group is synced as a whole unless a limit is given
+	if req.Limit == 0 && req.GroupID == 0 {
 		req.Limit = 10
 	}
 	param.Limit = req.Limit
@@ -72,6 +103,7 @@ func (u *weatherUsecase) SyncWeatherUsecase(ctx context.Context, req dto.PostWea
 		}
 
 		fmt.Printf("sync weather data success: %s\n", location.Name)
+		notifyWeatherSynced(ctx, u.cache, location.ID)
 	}
 
 	return nil
@@ -83,8 +115,15 @@ func (u *weatherUsecase) syncWeatherForLocation(ctx context.Context, location do
 		return fmt.Errorf("failed to get forecast for location %s: %w", location.Name, err)
 	}
 
+	return u.storeForecastDays(ctx, location, forecast.Forecast.Forecastday)
+}
+
+// storeForecastDays upserts the day and hour rows and the astronomy of
+// forecast or history days of the provider.
+func (u *weatherUsecase) storeForecastDays(ctx context.Context, location domain.Location, days []weather.ForecastDay) error {
 	var weathers []domain.Weather
-	for _, day := range forecast.Forecast.Forecastday {
+	var astronomies []domain.Astronomy
+	for _, day := range days {
 		forecastTime, err := time.Parse(utils.DateFormat, day.Date)
 		if err != nil {
 			fmt.Printf("Failed to parse forecast date %s: %v", day.Date, err)
@@ -95,15 +134,21 @@ func (u *weatherUsecase) syncWeatherForLocation(ctx context.Context, location do
 			LocationID:            location.ID,
 			TemperatureCelcius:    day.Day.AvgtempC,
 			TemperatureFahrenheit: day.Day.AvgtempF,
+			MinTemperatureCelcius: sql.NullFloat64{Float64: day.Day.MintempC, Valid: true},
+			MaxTemperatureCelcius: sql.NullFloat64{Float64: day.Day.MaxtempC, Valid: true},
 			Humidity:              int(day.Day.AvgHumidity),
 			WindSpeed:             day.Day.MaxWindKPH,
+			PrecipitationMM:       providerValue(day.Day.TotalPrecipMM),
+			VisibilityKM:          providerValue(day.Day.AvgVisKM),
 			ConditionStatus:       day.Day.Condition.Text,
+			ConditionCode:         string(condition.FromWeatherAPI(day.Day.Condition.Code)),
 			ConditionIconURL:      day.Day.Condition.Icon,
 			ForecastTime:          forecastTime,
 			ForecastType:          domain.ForecastTypeDay,
 		}
 
 		weathers = append(weathers, forecastWeather)
+		astronomies = append(astronomies, parseAstronomy(location, forecastTime, day.Astro))
 
 		for _, item := range day.Hours {
 			forecastHourTime, err := time.Parse(utils.DateFormatWithHour, item.ForecastTime)
@@ -118,7 +163,11 @@ func (u *weatherUsecase) syncWeatherForLocation(ctx context.Context, location do
 				TemperatureFahrenheit: item.TempF,
 				Humidity:              int(item.Humidity),
 				WindSpeed:             item.WindKph,
+				PrecipitationMM:       providerValue(item.PrecipMM),
+				PressureMB:            providerValue(item.PressureMB),
+				VisibilityKM:          providerValue(item.VisKM),
 				ConditionStatus:       item.Condition.Text,
+				ConditionCode:         string(condition.FromWeatherAPI(item.Condition.Code)),
 				ConditionIconURL:      item.Condition.Icon,
 				ForecastTime:          forecastHourTime,
 				ForecastType:          domain.ForecastTypeHour,
@@ -128,15 +177,221 @@ func (u *weatherUsecase) syncWeatherForLocation(ctx context.Context, location do
 		}
 	}
 
-	_, err = u.weatherRepo.BulkUpsertWeather(ctx, weathers)
+	_, err := u.weatherRepo.BulkUpsertWeather(ctx, weathers)
 	if err != nil {
 		return fmt.Errorf("failed to bulk upsert weather data for location %s: %w", location.Name, err)
 	}
 
+	err = u.weatherRepo.BulkUpsertAstronomies(ctx, astronomies)
+	if err != nil {
+		return fmt.Errorf("failed to bulk upsert astronomies for location %s: %w", location.Name, err)
+	}
+
+	u.cacheIcons(ctx, weathers)
+
 	return nil
 }
 
+// BackfillWeatherUsecase loads the observed history of a location day by day
+// from the provider, so statistics can reach further back than the synced
+// forecasts. Days already stored are overwritten by the observations.
+func (u *weatherUsecase) BackfillWeatherUsecase(ctx context.Context, param dto.BackfillWeatherParam) (dto.BackfillWeatherResult, error) {
+	var result dto.BackfillWeatherResult
+	location, err := u.findLocation(ctx, param.LocationID)
+	if err != nil {
+		return result, err
+	}
+
+	// the provider only has history until today of the location
+	if today := locationToday(location); param.To.After(today) {
+		param.To = today
+	}
+
+	for date := param.From; !date.After(param.To); date = date.AddDate(0, 0, 1) {
+		history, err := u.weatherAPIClient.GetHistory(ctx, location.Name, date)
+		if err != nil {
+			return result, fmt.Errorf("failed to get history of %s for location %s: %w", date.Format(utils.DateFormat), location.Name, err)
+		}
+
+		if err := u.storeForecastDays(ctx, location, history.Forecast.Forecastday); err != nil {
+			return result, err
+		}
+		result.Days++
+	}
+
+	if result.Days > 0 {
+		notifyWeatherSynced(ctx, u.cache, location.ID)
+	}
+
+	return result, nil
+}
+
+// BackfillConditionCodesUsecase maps the provider text of the rows synced
+// before the condition code was kept, with the same table as the sync.
+// Texts the table does not know stay unknown.
+func (u *weatherUsecase) BackfillConditionCodesUsecase(ctx context.Context) (int64, error) {
+	statuses, err := u.weatherRepo.GetUnknownConditionStatuses(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int64
+	for _, status := range statuses {
+		code := condition.FromWeatherAPIText(status)
+		if code == condition.Unknown {
+			continue
+		}
+
+		param := repository.UpdateConditionCodesParam{Status: status, Code: string(code), Limit: utils.ConditionBackfillBatchSize}
+		for {
+			updated, err := u.weatherRepo.UpdateConditionCodes(ctx, param)
+			if err != nil {
+				return total, err
+			}
+
+			total += updated
+			if updated < int64(param.Limit) {
+				break
+			}
+		}
+	}
+
+	return total, nil
+}
+
+// providerValue stores a measurement only when the provider sent it.
+func providerValue(value *float64) sql.NullFloat64 {
+	if value == nil {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: *value, Valid: true}
+}
+
+// cacheIcons downloads the icons of the weathers missing from the cache, so
+// the icon endpoint rarely has to reach the provider. Failures are only
+// logged, a missing icon is fetched on the next sync or request.
+func (u *weatherUsecase) cacheIcons(ctx context.Context, weathers []domain.Weather) {
+	codes := []string{}
+	for _, item := range weathers {
+		if code, ok := weather.IconCode(item.ConditionIconURL); ok && !slices.Contains(codes, code) {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return
+	}
+
+	keys := make([]string, len(codes))
+	for i, code := range codes {
+		keys[i] = fmt.Sprintf(utils.IconKey, code)
+	}
+
+	cached, err := u.cache.MGet(ctx, keys...)
+	if err != nil {
+		fmt.Printf("Failed to get cached icons: %v", err)
+		return
+	}
+
+	values := map[string]interface{}{}
+	for i, code := range codes {
+		if cached[i] != "" {
+			continue
+		}
+
+		icon, err := u.weatherAPIClient.GetIcon(ctx, code)
+		if err != nil {
+			fmt.Printf("Failed to download icon %s: %v", code, err)
+			continue
+		}
+		values[keys[i]] = icon
+	}
+
+	if len(values) == 0 {
+		return
+	}
+	if err := u.cache.MSet(ctx, values, utils.IconCacheTTL); err != nil {
+		fmt.Printf("Failed to cache icons: %v", err)
+	}
+}
+
+// GetIconUsecase returns the PNG of a provider icon code from the cache,
+// downloading and caching it when a sync has not yet.
+func (u *weatherUsecase) GetIconUsecase(ctx context.Context, code string) ([]byte, error) {
+	if !weather.ValidIconCode(code) {
+		return nil, ErrIconNotFound
+	}
+
+	key := fmt.Sprintf(utils.IconKey, code)
+	if cached, err := u.cache.Get(ctx, key); err == nil && cached != "" {
+		return []byte(cached), nil
+	}
+
+	icon, err := u.weatherAPIClient.GetIcon(ctx, code)
+	if errors.Is(err, weather.ErrIconNotFound) {
+		return nil, ErrIconNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get icon: %w", err)
+	}
+
+	if err := u.cache.Set(ctx, key, icon, utils.IconCacheTTL); err != nil {
+		fmt.Printf("Failed to set cache: %v", err)
+	}
+
+	return icon, nil
+}
+
+// notifyWeatherSynced drops the cached responses of the location and of
+// the locations merged into it, then tells subscribers (possibly in other
+// processes) that fresh data is available.
+func notifyWeatherSynced(ctx context.Context, cache infra.CacheInterface, locationID int64, mergedIDs ...int64) {
+	var keys []string
+	for _, id := range append([]int64{locationID}, mergedIDs...) {
+		keys = append(keys,
+			fmt.Sprintf(utils.WeatherLocationKey, id),
+			fmt.Sprintf(utils.WeatherBatchKey, id),
+		)
+	}
+	if err := cache.Del(ctx, keys...); err != nil {
+		fmt.Printf("Failed to invalidate cache: %v", err)
+	}
+
+	if err := cache.Publish(ctx, utils.WeatherSyncChannel, locationID); err != nil {
+		fmt.Printf("Failed to publish weather sync: %v", err)
+	}
+}
+
 func (u *weatherUsecase) GetWeathersUsecase(ctx context.Context, param dto.GetWeathersParam) (response.Response[dto.GetWeatherResponse], error) {
+	resp, err := u.getWeathers(ctx, param)
+	if err != nil {
+		return resp, err
+	}
+
+	// translations, icon URLs and summaries are added after caching, they
+	// depend on the request
+	resp.Data = resp.Data.Present(param.Languages, param.BaseURL)
+	if err := localizeLocations(ctx, u.locationRepo, []*dto.GetLocationHandlerResponseItem{&resp.Data.Location}, param.Languages); err != nil {
+		return resp, err
+	}
+	if !param.Summary {
+		return resp, nil
+	}
+
+	location, err := u.findLocation(ctx, int64(param.LocationID))
+	if err != nil {
+		return resp, err
+	}
+
+	summaries, err := u.getForecastSummaries(ctx, []domain.Location{location}, param.Languages)
+	if err != nil {
+		return resp, err
+	}
+	resp.Data.Summary = summaries[int64(param.LocationID)]
+
+	return resp, nil
+}
+
+func (u *weatherUsecase) getWeathers(ctx context.Context, param dto.GetWeathersParam) (response.Response[dto.GetWeatherResponse], error) {
 	resp := response.Response[dto.GetWeatherResponse]{
 		Status:  "success",
 		Message: "get weather data success",
@@ -153,13 +408,24 @@ func (u *weatherUsecase) GetWeathersUsecase(ctx context.Context, param dto.GetWe
 		return resp, errors.New("location not found, please check your parameter")
 	}
 
+	// cursor pages are read straight from database, the cache only holds the
+	// page-number response
+	if param.Cursor != "" {
+		return u.getWeathersByCursor(ctx, locations[0], param)
+	}
+
+	// only the unpaged response is cached, it is the one key dropped when the
+	// location is synced or merged
 	cacheKey := fmt.Sprintf(utils.WeatherLocationKey, param.LocationID)
-	cachedData, err := u.cache.Get(ctx, cacheKey)
-	if err == nil {
-		var weatherResponse dto.GetWeatherResponse
-		if err := json.Unmarshal([]byte(cachedData), &weatherResponse); err == nil {
-			resp.Data = weatherResponse
-			return resp, nil
+	cached := param.PageSize <= 0
+	if cached {
+		cachedData, err := u.cache.Get(ctx, cacheKey)
+		if err == nil {
+			var weatherResponse dto.GetWeatherResponse
+			if err := json.Unmarshal([]byte(cachedData), &weatherResponse); err == nil {
+				resp.Data = weatherResponse.WithAlerts(param.AlertRules).InUnits(param.Units)
+				return resp, nil
+			}
 		}
 	}
 
@@ -168,7 +434,7 @@ func (u *weatherUsecase) GetWeathersUsecase(ctx context.Context, param dto.GetWe
 		LocationID: int64(param.LocationID),
 		Limit:      param.PageSize,
 		Offset:     offset,
-		OrderBy:    "forecast_time DESC",
+		OrderBy:    "forecast_time DESC, id DESC",
 	}
 
 	weathers, err := u.weatherRepo.GetWeathers(ctx, repoParam)
@@ -177,9 +443,18 @@ func (u *weatherUsecase) GetWeathersUsecase(ctx context.Context, param dto.GetWe
 	}
 
 	if len(weathers) == 0 {
+		resp.Data.Units = param.Units.Labels()
 		return resp, nil
 	}
 
+	cursors := response.CursorData{}
+	if param.PageSize > 0 && len(weathers) == param.PageSize {
+		cursors.NextCursor = weatherCursor(weathers[len(weathers)-1], false)
+	}
+	if param.CurrentPage > 1 {
+		cursors.PrevCursor = weatherCursor(weathers[0], true)
+	}
+
 	location := locations[0]
 	locationResponse := dto.GetLocationHandlerResponseItem{
 		ID:        int64(param.LocationID),
@@ -188,6 +463,7 @@ func (u *weatherUsecase) GetWeathersUsecase(ctx context.Context, param dto.GetWe
 		Country:   location.Country,
 		Latitude:  location.Latitude,
 		Longitude: location.Longitude,
+		Timezone:  location.Timezone,
 		CreatedAt: time.Now(),
 	}
 
@@ -197,20 +473,7 @@ func (u *weatherUsecase) GetWeathersUsecase(ctx context.Context, param dto.GetWe
 	foundCurrentTime := false
 
 	for _, item := range weathers[1:] {
-		itemResponse := dto.GetWeatherResponseItem{
-			ForecastTime:          item.ForecastTime,
-			ForecastType:          string(item.ForecastType),
-			TemperatureCelcius:    item.TemperatureCelcius,
-			TemperatureFahrenheit: item.TemperatureFahrenheit,
-			Humidity:              item.Humidity,
-			WindSpeed:             item.WindSpeed,
-			Condition: dto.WeatherConditionResponse{
-				Status:  item.ConditionStatus,
-				IconURL: item.ConditionIconURL,
-			},
-			CreatedAt:      item.CreatedAt,
-			LastModifiedAt: item.LastModifiedAt.Time,
-		}
+		itemResponse := dto.ParseToGetWeatherResponseItem(item)
 
 		if item.ForecastTime.Truncate(24 * time.Hour).Equal(time.Now().Truncate(24 * time.Hour)) {
 			timeDiff := time.Now().Sub(item.ForecastTime)
@@ -233,16 +496,1366 @@ func (u *weatherUsecase) GetWeathersUsecase(ctx context.Context, param dto.GetWe
 		Location:    locationResponse,
 		CurrentTime: currentTimeWeather,
 		Forecast:    forecast,
+		Units:       units.Metric.Labels(),
+		CursorData:  cursors,
 	}
 
-	cacheData, err := json.Marshal(weatherResponse)
-	if err == nil {
-		err = u.cache.Set(ctx, cacheKey, cacheData, 10*time.Minute)
-		if err != nil {
-			fmt.Printf("Failed to set cache: %v", err)
+	if err := u.addAstronomy(ctx, location, &weatherResponse); err != nil {
+		return resp, err
+	}
+
+	// the cache holds metric values whatever units were requested
+	if cached {
+		cacheData, err := json.Marshal(weatherResponse)
+		if err == nil {
+			err = u.cache.Set(ctx, cacheKey, cacheData, 10*time.Minute)
+			if err != nil {
+				fmt.Printf("Failed to set cache: %v", err)
+			}
+		}
+	}
+
+	resp.Data = weatherResponse.WithAlerts(param.AlertRules).InUnits(param.Units)
+	return resp, nil
+}
+
+// getWeathersByCursor returns the page of forecast rows next to the cursor,
+// newest first.
+func (u *weatherUsecase) getWeathersByCursor(ctx context.Context, location domain.Location, param dto.GetWeathersParam) (response.Response[dto.GetWeatherResponse], error) {
+	resp := response.Response[dto.GetWeatherResponse]{
+		Status:  "success",
+		Message: "get weather data success",
+	}
+
+	keyset, err := weatherKeyset(param.Cursor)
+	if err != nil {
+		return resp, err
+	}
+
+	if param.PageSize <= 0 {
+		param.PageSize = 10
+	}
+
+	// one extra row tells whether another page follows
+	weathers, err := u.weatherRepo.GetWeathers(ctx, repository.GetWeathersParam{
+		LocationID: location.ID,
+		After:      keyset,
+		Limit:      param.PageSize + 1,
+	})
+	if err != nil {
+		return resp, fmt.Errorf("failed to get weathers: %w", err)
+	}
+
+	weathers, hasPrev, hasNext := keysetPage(weathers, param.PageSize, keyset.Backward)
+	resp.Data.Location = dto.ParseToGetLocationHandlerResponse(location)
+	resp.Data.Forecast = []dto.GetWeatherResponseItem{}
+	for _, item := range weathers {
+		resp.Data.Forecast = append(resp.Data.Forecast, dto.ParseToGetWeatherResponseItem(item))
+	}
+
+	if err := u.addAstronomy(ctx, location, &resp.Data); err != nil {
+		return resp, err
+	}
+
+	if len(weathers) > 0 {
+		if hasNext {
+			resp.Data.NextCursor = weatherCursor(weathers[len(weathers)-1], false)
+		}
+		if hasPrev {
+			resp.Data.PrevCursor = weatherCursor(weathers[0], true)
+		}
+	}
+
+	resp.Data = resp.Data.WithAlerts(param.AlertRules).InUnits(param.Units)
+	return resp, nil
+}
+
+func (u *weatherUsecase) GetWeathersBatchUsecase(ctx context.Context, param dto.GetWeathersBatchParam) (response.Response[dto.GetWeathersBatchResponse], error) {
+	resp := response.Response[dto.GetWeathersBatchResponse]{
+		Status:  "success",
+		Message: "get weather batch data success",
+	}
+
+	if param.ForecastDays == 0 {
+		param.ForecastDays = 3
+	}
+
+	var locationIDs []int64
+	for _, id := range param.LocationIDs {
+		if !slices.Contains(locationIDs, int64(id)) {
+			locationIDs = append(locationIDs, int64(id))
+		}
+	}
+
+	locationParam := repository.GetLocationsParam{
+		IDs:     locationIDs,
+		GroupID: param.GroupID,
+		Tag:     dto.NormalizeLocationTag(param.Tag),
+	}
+	if len(locationIDs) == 0 {
+		locationParam.Limit = utils.MaxBatchLocations + 1
+		locationParam.OrderBy = utils.OrderByNameAsc
+	}
+
+	locations, err := u.locationRepo.GetLocations(ctx, locationParam)
+	if err != nil {
+		return resp, fmt.Errorf("failed to get locations: %w", err)
+	}
+
+	if len(locationIDs) == 0 && len(locations) > utils.MaxBatchLocations {
+		locations = locations[:utils.MaxBatchLocations]
+		resp.Data.Truncated = true
+	}
+
+	// without ids every location of the group or tag is returned
+	if len(locationIDs) == 0 {
+		for _, location := range locations {
+			locationIDs = append(locationIDs, location.ID)
+		}
+	}
+
+	items, err := u.getWeatherBatchItems(ctx, locations)
+	if err != nil {
+		return resp, err
+	}
+
+	summaries := map[int64]string{}
+	if param.Summary {
+		if summaries, err = u.getForecastSummaries(ctx, locations, param.Languages); err != nil {
+			return resp, err
+		}
+	}
+
+	resp.Data.Items = []dto.GetWeathersBatchResponseItem{}
+	resp.Data.Units = param.Units.Labels()
+	for _, id := range locationIDs {
+		item, ok := items[id]
+		if !ok {
+			resp.Data.NotFoundLocationIDs = append(resp.Data.NotFoundLocationIDs, int(id))
+			continue
+		}
+
+		item.Summary = summaries[id]
+
+		if !param.IncludeForecast {
+			item.Forecast = nil
+		} else if len(item.Forecast) > param.ForecastDays {
+			item.Forecast = item.Forecast[:param.ForecastDays]
 		}
+
+		resp.Data.Items = append(resp.Data.Items, item.InUnits(param.Units).Present(param.Languages, param.BaseURL))
+	}
+
+	if err := u.addBatchAstronomy(ctx, locations, resp.Data.Items); err != nil {
+		return resp, err
+	}
+
+	localized := make([]*dto.GetLocationHandlerResponseItem, len(resp.Data.Items))
+	for i := range resp.Data.Items {
+		localized[i] = &resp.Data.Items[i].Location
+	}
+	if err := localizeLocations(ctx, u.locationRepo, localized, param.Languages); err != nil {
+		return resp, err
 	}
 
-	resp.Data = weatherResponse
 	return resp, nil
 }
+
+// getWeatherBatchItems reads every location from cache with a single MGET and
+// loads the misses from database with a single query.
+func (u *weatherUsecase) getWeatherBatchItems(ctx context.Context, locations []domain.Location) (map[int64]dto.GetWeathersBatchResponseItem, error) {
+	items := make(map[int64]dto.GetWeathersBatchResponseItem, len(locations))
+	if len(locations) == 0 {
+		return items, nil
+	}
+
+	keys := make([]string, len(locations))
+	for i, location := range locations {
+		keys[i] = fmt.Sprintf(utils.WeatherBatchKey, location.ID)
+	}
+
+	cachedData, err := u.cache.MGet(ctx, keys...)
+	if err != nil {
+		fmt.Printf("Failed to get cache: %v", err)
+	}
+
+	missing := []domain.Location{}
+	for i, location := range locations {
+		if i < len(cachedData) && cachedData[i] != "" {
+			var item dto.GetWeathersBatchResponseItem
+			if err := json.Unmarshal([]byte(cachedData[i]), &item); err == nil {
+				items[location.ID] = item
+				continue
+			}
+		}
+		missing = append(missing, location)
+	}
+
+	if len(missing) == 0 {
+		return items, nil
+	}
+
+	missingIDs := make([]int64, len(missing))
+	for i, location := range missing {
+		missingIDs[i] = location.ID
+	}
+
+	now := time.Now()
+	weathers, err := u.weatherRepo.GetWeatherSummaries(ctx, repository.GetWeatherSummariesParam{
+		LocationIDs:  missingIDs,
+		CurrentTime:  now,
+		ForecastDays: utils.MaxForecastDays,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weather summaries: %w", err)
+	}
+
+	weathersByLocation := map[int64][]domain.Weather{}
+	for _, weather := range weathers {
+		weathersByLocation[weather.LocationID] = append(weathersByLocation[weather.LocationID], weather)
+	}
+
+	cacheData := map[string]interface{}{}
+	for _, location := range missing {
+		item := dto.GetWeathersBatchResponseItem{
+			Location: dto.ParseToGetLocationHandlerResponse(location),
+			Forecast: []dto.GetWeatherResponseItem{},
+		}
+
+		var minTimeDiff time.Duration
+		for _, weather := range weathersByLocation[location.ID] {
+			itemResponse := dto.ParseToGetWeatherResponseItem(weather)
+			if weather.ForecastType == domain.ForecastTypeDay {
+				item.Forecast = append(item.Forecast, itemResponse)
+				continue
+			}
+
+			timeDiff := now.Sub(weather.ForecastTime).Abs()
+			if item.CurrentTime == nil || timeDiff < minTimeDiff {
+				item.CurrentTime = &itemResponse
+				minTimeDiff = timeDiff
+			}
+		}
+
+		items[location.ID] = item
+		if data, err := json.Marshal(item); err == nil {
+			cacheData[fmt.Sprintf(utils.WeatherBatchKey, location.ID)] = data
+		}
+	}
+
+	if err := u.cache.MSet(ctx, cacheData, 10*time.Minute); err != nil {
+		fmt.Printf("Failed to set cache: %v", err)
+	}
+
+	return items, nil
+}
+
+// getForecastSummaries describes the hourly forecast of today and tomorrow
+// of every location with a single query. Today, tomorrow and the current
+// hour are those of the location. Locations without hourly rows for either
+// day have no summary.
+func (u *weatherUsecase) getForecastSummaries(ctx context.Context, locations []domain.Location, languages []string) (map[int64]string, error) {
+	nows := make(map[int64]time.Time, len(locations))
+	param := repository.GetWeatherSeriesParam{}
+	for _, location := range locations {
+		now := wallClock(time.Now().In(locationZone(location)))
+		nows[location.ID] = now
+		param.LocationIDs = append(param.LocationIDs, location.ID)
+
+		today := startOfDay(now)
+		if param.From.IsZero() || today.Before(param.From) {
+			param.From = today
+		}
+		if tomorrowEnd := today.AddDate(0, 0, 2); tomorrowEnd.After(param.To) {
+			param.To = tomorrowEnd
+		}
+	}
+
+	rows, err := u.weatherRepo.GetWeatherSeries(ctx, param)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weather series: %w", err)
+	}
+
+	hoursByLocation := map[int64][]narrative.Hour{}
+	for _, row := range rows {
+		// the range of the query spans the days of every location
+		today := startOfDay(nows[row.LocationID])
+		if row.ForecastTime.Before(today) || !row.ForecastTime.Before(today.AddDate(0, 0, 2)) {
+			continue
+		}
+
+		hoursByLocation[row.LocationID] = append(hoursByLocation[row.LocationID], narrative.Hour{
+			Time:            row.ForecastTime,
+			TemperatureC:    row.TemperatureCelcius,
+			Humidity:        row.Humidity,
+			WindKph:         row.WindSpeed,
+			PrecipitationMM: row.PrecipitationMM.Float64,
+			Condition:       string(dto.NewWeatherConditionResponse(row).Code),
+		})
+	}
+
+	summaries := make(map[int64]string, len(hoursByLocation))
+	for locationID, hours := range hoursByLocation {
+		summaries[locationID] = narrative.Summarize(hours, nows[locationID], languages)
+	}
+
+	return summaries, nil
+}
+
+// GetPointWeatherUsecase interpolates the current weather at a coordinate from
+// the nearest synced locations, weighting each one by inverse squared distance.
+func (u *weatherUsecase) GetPointWeatherUsecase(ctx context.Context, param dto.GetPointWeatherParam) (response.Response[dto.GetPointWeatherResponse], error) {
+	resp := response.Response[dto.GetPointWeatherResponse]{
+		Status:  "success",
+		Message: "get point weather data success",
+	}
+
+	if param.RadiusKm <= 0 {
+		param.RadiusKm = utils.DefaultNearbyRadiusKm
+	}
+
+	nearby, err := findNearbyLocations(ctx, u.locationRepo, param.Latitude, param.Longitude, param.RadiusKm, utils.MaxNearbyLimit)
+	if err != nil {
+		return resp, fmt.Errorf("failed to get nearby locations: %w", err)
+	}
+
+	locations := make([]domain.Location, len(nearby))
+	for i, item := range nearby {
+		locations[i] = item.location
+	}
+
+	items, err := u.getWeatherBatchItems(ctx, locations)
+	if err != nil {
+		return resp, err
+	}
+
+	sources := []dto.GetNearbyLocationResponseItem{}
+	currents := []dto.GetWeatherResponseItem{}
+	for _, item := range nearby {
+		batchItem, ok := items[item.location.ID]
+		if !ok || batchItem.CurrentTime == nil {
+			continue
+		}
+
+		sources = append(sources, dto.GetNearbyLocationResponseItem{
+			Location:   dto.ParseToGetLocationHandlerResponse(item.location),
+			DistanceKm: item.distanceKm,
+		})
+		currents = append(currents, *batchItem.CurrentTime)
+		if len(sources) == utils.PointWeatherSources {
+			break
+		}
+	}
+
+	if len(sources) == 0 {
+		return resp, ErrNoNearbyLocation
+	}
+
+	localized := make([]*dto.GetLocationHandlerResponseItem, len(sources))
+	for i := range sources {
+		localized[i] = &sources[i].Location
+	}
+	if err := localizeLocations(ctx, u.locationRepo, localized, param.Languages); err != nil {
+		return resp, err
+	}
+
+	resp.Data = dto.GetPointWeatherResponse{
+		Latitude:    param.Latitude,
+		Longitude:   param.Longitude,
+		CurrentTime: interpolateWeather(sources, currents).InUnits(param.Units).Present(param.Languages, param.BaseURL),
+		Sources:     sources,
+		Units:       param.Units.Labels(),
+	}
+
+	// the sun is computed at the point, in the zone of the nearest source
+	// whose wall clock the forecast time is
+	if current := resp.Data.CurrentTime; !current.ForecastTime.IsZero() {
+		point := domain.Location{Latitude: param.Latitude, Longitude: param.Longitude, Timezone: sources[0].Location.Timezone}
+		day := computeAstronomy(point, startOfDay(current.ForecastTime.UTC()))
+		astronomy := dto.ParseToAstronomyResponseItem(day)
+		resp.Data.Astronomy = &astronomy
+		setIsDay(&resp.Data.CurrentTime, map[string]domain.Astronomy{astronomy.Date: day})
+	}
+
+	return resp, nil
+}
+
+func interpolateWeather(sources []dto.GetNearbyLocationResponseItem, currents []dto.GetWeatherResponseItem) dto.GetWeatherResponseItem {
+	// condition and timestamps are not numeric, take them from the nearest source
+	result := currents[0]
+	if sources[0].DistanceKm < 0.01 {
+		return result
+	}
+
+	var totalWeight, celcius, fahrenheit, humidity, windSpeed float64
+	for i, current := range currents {
+		weight := 1 / math.Pow(sources[i].DistanceKm, 2)
+		totalWeight += weight
+		celcius += weight * current.TemperatureCelcius
+		fahrenheit += weight * current.TemperatureFahrenheit
+		humidity += weight * float64(current.Humidity)
+		windSpeed += weight * current.WindSpeed
+	}
+
+	result.TemperatureCelcius = math.Round(celcius/totalWeight*100) / 100
+	result.TemperatureFahrenheit = math.Round(fahrenheit/totalWeight*100) / 100
+	result.Temperature = result.TemperatureCelcius
+	result.Humidity = int(math.Round(humidity / totalWeight))
+	result.WindSpeed = math.Round(windSpeed/totalWeight*100) / 100
+	result.Precipitation = interpolateOptional(sources, currents, func(item dto.GetWeatherResponseItem) *float64 { return item.Precipitation })
+	result.Pressure = interpolateOptional(sources, currents, func(item dto.GetWeatherResponseItem) *float64 { return item.Pressure })
+	result.Visibility = interpolateOptional(sources, currents, func(item dto.GetWeatherResponseItem) *float64 { return item.Visibility })
+	result.Derived = dto.DeriveWeather(result.TemperatureCelcius, result.Humidity, result.WindSpeed)
+	return result
+}
+
+// interpolateOptional weights only the sources having the value, it is nil
+// when none has.
+func interpolateOptional(sources []dto.GetNearbyLocationResponseItem, currents []dto.GetWeatherResponseItem, value func(dto.GetWeatherResponseItem) *float64) *float64 {
+	var totalWeight, total float64
+	for i, current := range currents {
+		v := value(current)
+		if v == nil {
+			continue
+		}
+		weight := 1 / math.Pow(sources[i].DistanceKm, 2)
+		totalWeight += weight
+		total += weight * *v
+	}
+
+	if totalWeight == 0 {
+		return nil
+	}
+
+	result := math.Round(total/totalWeight*100) / 100
+	return &result
+}
+
+func (u *weatherUsecase) GetDailyWeatherUsecase(ctx context.Context, param dto.GetDailyWeatherParam) (response.Response[dto.GetDailyWeatherResponse], error) {
+	resp := response.Response[dto.GetDailyWeatherResponse]{
+		Status:  "success",
+		Message: "get daily weather data success",
+	}
+
+	location, err := u.findLocation(ctx, param.LocationID)
+	if err != nil {
+		return resp, err
+	}
+
+	if param.From.IsZero() && param.To.IsZero() {
+		param.From = locationToday(location)
+	}
+	if param.From.IsZero() {
+		param.From = param.To.AddDate(0, 0, 1-utils.DefaultDailyRangeDays)
+	}
+	if param.To.IsZero() {
+		param.To = param.From.AddDate(0, 0, utils.DefaultDailyRangeDays-1)
+	}
+
+	days, err := u.weatherRepo.GetDailyWeathers(ctx, repository.GetDailyWeathersParam{
+		LocationID: param.LocationID,
+		From:       param.From,
+		To:         param.To,
+	})
+	if err != nil {
+		return resp, fmt.Errorf("failed to get daily weathers: %w", err)
+	}
+
+	items := make([]dto.DailyWeatherResponseItem, len(days))
+	for i, day := range days {
+		item := dto.ParseToDailyWeatherResponseItem(day)
+		// a day without hourly rows only has the summary, nothing to check
+		if day.SummaryTemperature.Valid && day.Hours > 0 {
+			issues := checkDailyConsistency(day, param.Units)
+			consistent := len(issues) == 0
+			item.Consistent = &consistent
+			item.Issues = issues
+		}
+		items[i] = item.InUnits(param.Units)
+	}
+
+	resp.Data = dto.GetDailyWeatherResponse{
+		Location: dto.ParseToGetLocationHandlerResponse(location),
+		From:     param.From.Format(utils.DateFormat),
+		To:       param.To.Format(utils.DateFormat),
+		Days:     items,
+		Units:    param.Units.Labels(),
+	}
+
+	return resp, nil
+}
+
+// checkDailyConsistency compares the provider's day summary with the hourly
+// aggregates in metric units and describes every disagreement in the units of
+// the response.
+func checkDailyConsistency(day domain.DailyWeather, system units.System) []string {
+	var issues []string
+	labels := system.Labels()
+	temperature := func(celsius float64) string {
+		return fmt.Sprintf("%.1f%s", system.Temperature(celsius), labels.Temperature)
+	}
+
+	summaryTemp := day.SummaryTemperature.Float64
+	if summaryTemp < day.MinTemperatureCelcius-utils.DailyTemperatureTolerance ||
+		summaryTemp > day.MaxTemperatureCelcius+utils.DailyTemperatureTolerance {
+		issues = append(issues, fmt.Sprintf("summary temperature %s is outside the hourly range %s to %s",
+			temperature(summaryTemp), temperature(day.MinTemperatureCelcius), temperature(day.MaxTemperatureCelcius)))
+	} else if math.Abs(summaryTemp-day.AvgTemperatureCelcius) > utils.DailyTemperatureTolerance {
+		issues = append(issues, fmt.Sprintf("summary temperature %s differs from the hourly average %s",
+			temperature(summaryTemp), temperature(day.AvgTemperatureCelcius)))
+	}
+
+	if day.SummaryWindSpeed.Valid && day.SummaryWindSpeed.Float64 > day.MaxWindSpeed+utils.DailyWindSpeedTolerance {
+		issues = append(issues, fmt.Sprintf("summary wind speed %.1f %s exceeds the hourly maximum %.1f %s",
+			system.Speed(day.SummaryWindSpeed.Float64), labels.Speed, system.Speed(day.MaxWindSpeed), labels.Speed))
+	}
+
+	// a relative tolerance keeps rounding of heavy rain days from being flagged
+	if day.SummaryPrecipitationMM.Valid && day.TotalPrecipitationMM.Valid {
+		summary, total := day.SummaryPrecipitationMM.Float64, day.TotalPrecipitationMM.Float64
+		tolerance := math.Max(utils.DailyPrecipitationTolerance, total*0.2)
+		if math.Abs(summary-total) > tolerance {
+			issues = append(issues, fmt.Sprintf("summary precipitation %.2f %s differs from the hourly total %.2f %s",
+				system.Precipitation(summary), labels.Precipitation, system.Precipitation(total), labels.Precipitation))
+		}
+	}
+
+	return issues
+}
+
+func (u *weatherUsecase) GetWeatherStatsUsecase(ctx context.Context, param dto.GetWeatherStatsParam) (response.Response[dto.GetWeatherStatsResponse], error) {
+	resp := response.Response[dto.GetWeatherStatsResponse]{
+		Status:  "success",
+		Message: "get weather stats success",
+	}
+
+	location, err := u.findLocation(ctx, param.LocationID)
+	if err != nil {
+		return resp, err
+	}
+
+	// without dates the current period is used, with only one of them the
+	// period containing it
+	if param.Period == "" {
+		param.Period = dto.StatsPeriodMonth
+	}
+	if param.From.IsZero() && param.To.IsZero() {
+		param.To = locationToday(location)
+	}
+	if param.From.IsZero() {
+		param.From = statsPeriodStart(param.To, param.Period)
+	}
+	if param.To.IsZero() {
+		param.To = statsPeriodNext(statsPeriodStart(param.From, param.Period), param.Period).AddDate(0, 0, -1)
+	}
+
+	// a year of hourly rows is streamed, only the values summarized are kept
+	ranges := splitStatsPeriods(param.From, param.To, param.Period)
+	samples := make([]statsSamples, len(ranges))
+	err = u.weatherRepo.StreamWeathers(ctx, repository.StreamWeathersParam{
+		LocationIDs:  []int64{param.LocationID},
+		From:         param.From,
+		To:           param.To.AddDate(0, 0, 1),
+		ForecastType: string(domain.ForecastTypeHour),
+	}, func(item domain.Weather) error {
+		for i, r := range ranges {
+			if r.containsTime(item.ForecastTime) {
+				samples[i].add(item)
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return resp, fmt.Errorf("failed to get hourly weathers: %w", err)
+	}
+
+	daily, err := u.weatherRepo.GetDailyWeathers(ctx, repository.GetDailyWeathersParam{
+		LocationID: param.LocationID,
+		From:       param.From,
+		To:         param.To,
+	})
+	if err != nil {
+		return resp, fmt.Errorf("failed to get daily weathers: %w", err)
+	}
+
+	periods := make([]dto.WeatherStatsPeriod, len(ranges))
+	for i, r := range ranges {
+		periods[i] = buildStatsPeriod(r, samples[i], daily)
+	}
+
+	for years := 1; years <= utils.StatsComparisonYears; years++ {
+		previous, err := u.weatherRepo.GetDailyWeathers(ctx, repository.GetDailyWeathersParam{
+			LocationID: param.LocationID,
+			From:       param.From.AddDate(-years, 0, 0),
+			To:         param.To.AddDate(-years, 0, 0),
+		})
+		if err != nil {
+			return resp, fmt.Errorf("failed to get daily weathers of %d years ago: %w", years, err)
+		}
+
+		for i, r := range ranges {
+			days := r.days(previous, years)
+			if len(days) == 0 {
+				continue
+			}
+			periods[i].PreviousYears = append(periods[i].PreviousYears, compareStatsPeriod(periods[i], r.from.Year()-years, days))
+		}
+	}
+
+	for i := range periods {
+		periods[i] = periods[i].InUnits(param.Units)
+	}
+
+	resp.Data = dto.GetWeatherStatsResponse{
+		Location: dto.ParseToGetLocationHandlerResponse(location),
+		Period:   param.Period,
+		From:     param.From.Format(utils.DateFormat),
+		To:       param.To.Format(utils.DateFormat),
+		Periods:  periods,
+		Units:    param.Units.Labels(),
+	}
+
+	return resp, nil
+}
+
+// statsRange is a calendar period clipped to the requested dates, both days
+// inclusive.
+type statsRange struct {
+	from time.Time
+	to   time.Time
+}
+
+// days returns the daily aggregates falling in the range once moved the given
+// number of years forward.
+func (r statsRange) days(daily []domain.DailyWeather, yearsAgo int) []domain.DailyWeather {
+	var result []domain.DailyWeather
+	for _, day := range daily {
+		date := day.Date.AddDate(yearsAgo, 0, 0)
+		if !date.Before(r.from) && !date.After(r.to) {
+			result = append(result, day)
+		}
+	}
+	return result
+}
+
+func (r statsRange) containsTime(t time.Time) bool {
+	return !t.Before(r.from) && t.Before(r.to.AddDate(0, 0, 1))
+}
+
+// locationToday returns the current date of the location, labelled UTC like
+// forecast times.
+func locationToday(location domain.Location) time.Time {
+	now := time.Now().In(locationZone(location))
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func startOfHour(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+}
+
+// statsPeriodStart returns the first day of the period containing t, weeks
+// start on Monday.
+func statsPeriodStart(t time.Time, period string) time.Time {
+	day := startOfDay(t)
+	switch period {
+	case dto.StatsPeriodWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case dto.StatsPeriodMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	case dto.StatsPeriodYear:
+		return time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, day.Location())
+	}
+	return day
+}
+
+func statsPeriodNext(start time.Time, period string) time.Time {
+	switch period {
+	case dto.StatsPeriodWeek:
+		return start.AddDate(0, 0, 7)
+	case dto.StatsPeriodMonth:
+		return start.AddDate(0, 1, 0)
+	case dto.StatsPeriodYear:
+		return start.AddDate(1, 0, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+func splitStatsPeriods(from, to time.Time, period string) []statsRange {
+	var ranges []statsRange
+	for start := statsPeriodStart(from, period); !start.After(to); start = statsPeriodNext(start, period) {
+		r := statsRange{from: start, to: statsPeriodNext(start, period).AddDate(0, 0, -1)}
+		if r.from.Before(from) {
+			r.from = from
+		}
+		if r.to.After(to) {
+			r.to = to
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// statsSamples holds the hourly values of a period the percentiles need.
+type statsSamples struct {
+	temperatures []float64
+	humidities   []float64
+	windSpeeds   []float64
+}
+
+func (s *statsSamples) add(item domain.Weather) {
+	s.temperatures = append(s.temperatures, item.TemperatureCelcius)
+	s.humidities = append(s.humidities, float64(item.Humidity))
+	s.windSpeeds = append(s.windSpeeds, item.WindSpeed)
+}
+
+func buildStatsPeriod(r statsRange, samples statsSamples, daily []domain.DailyWeather) dto.WeatherStatsPeriod {
+	days := r.days(daily, 0)
+	result := dto.WeatherStatsPeriod{
+		From:          r.from.Format(utils.DateFormat),
+		To:            r.to.Format(utils.DateFormat),
+		Hours:         len(samples.temperatures),
+		Days:          len(days),
+		Temperature:   stats.Summarize(samples.temperatures),
+		Humidity:      stats.Summarize(samples.humidities),
+		WindSpeed:     stats.Summarize(samples.windSpeeds),
+		PreviousYears: []dto.WeatherStatsComparison{},
+	}
+	result.TotalPrecipitation, result.RainyDays = precipitationStats(days)
+
+	for _, day := range days {
+		if result.HottestDay == nil || day.MaxTemperatureCelcius > result.HottestDay.Temperature {
+			result.HottestDay = &dto.WeatherStatsDay{Date: day.Date.Format(utils.DateFormat), Temperature: day.MaxTemperatureCelcius}
+		}
+		if result.ColdestDay == nil || day.MinTemperatureCelcius < result.ColdestDay.Temperature {
+			result.ColdestDay = &dto.WeatherStatsDay{Date: day.Date.Format(utils.DateFormat), Temperature: day.MinTemperatureCelcius}
+		}
+	}
+
+	return result
+}
+
+// compareStatsPeriod summarizes the daily aggregates of a previous year and
+// compares the current period, both in metric units, against it.
+func compareStatsPeriod(current dto.WeatherStatsPeriod, year int, days []domain.DailyWeather) dto.WeatherStatsComparison {
+	// days kept only as a daily row count as a whole day of hours
+	temperature, hours := 0.0, 0
+	for _, day := range days {
+		weight := day.Hours
+		if weight == 0 {
+			weight = 24
+		}
+		temperature += day.AvgTemperatureCelcius * float64(weight)
+		hours += weight
+	}
+
+	result := dto.WeatherStatsComparison{
+		Year:           year,
+		Days:           len(days),
+		AvgTemperature: math.Round(temperature/float64(hours)*100) / 100,
+	}
+	result.TotalPrecipitation, result.RainyDays = precipitationStats(days)
+	result.RainyDaysDelta = current.RainyDays - result.RainyDays
+
+	if current.Temperature.Count > 0 {
+		delta := math.Round((current.Temperature.Mean-result.AvgTemperature)*100) / 100
+		result.AvgTemperatureDelta = &delta
+	}
+	if current.TotalPrecipitation != nil && result.TotalPrecipitation != nil {
+		delta := math.Round((*current.TotalPrecipitation-*result.TotalPrecipitation)*100) / 100
+		result.TotalPrecipitationDelta = &delta
+	}
+
+	return result
+}
+
+// precipitationStats returns the total precipitation, nil when no day has
+// precipitation data, and the number of days reaching
+// utils.RainyDayThresholdMM.
+func precipitationStats(days []domain.DailyWeather) (*float64, int) {
+	var total *float64
+	rainyDays := 0
+	for _, day := range days {
+		if !day.TotalPrecipitationMM.Valid {
+			continue
+		}
+		if total == nil {
+			total = new(float64)
+		}
+		*total += day.TotalPrecipitationMM.Float64
+		if day.TotalPrecipitationMM.Float64 >= utils.RainyDayThresholdMM {
+			rainyDays++
+		}
+	}
+
+	if total != nil {
+		*total = math.Round(*total*100) / 100
+	}
+	return total, rainyDays
+}
+
+// compareRankings are computed for every requested metric they rank by.
+// Every ranking uses the hourly mean, so a location missing hours is not
+// favoured, a total would make it look drier.
+var compareRankings = []struct {
+	name         string
+	metric       string
+	highestFirst bool
+}{
+	{name: "warmest", metric: dto.CompareMetricTemperature, highestFirst: true},
+	{name: "driest", metric: dto.CompareMetricPrecipitation},
+	{name: "leastWindy", metric: dto.CompareMetricWindSpeed},
+	{name: "leastHumid", metric: dto.CompareMetricHumidity},
+}
+
+func (u *weatherUsecase) CompareWeathersUsecase(ctx context.Context, param dto.CompareWeathersParam) (response.Response[dto.CompareWeathersResponse], error) {
+	resp := response.Response[dto.CompareWeathersResponse]{
+		Status:  "success",
+		Message: "compare weather data success",
+	}
+
+	var locationIDs []int64
+	for _, id := range param.LocationIDs {
+		if !slices.Contains(locationIDs, int64(id)) {
+			locationIDs = append(locationIDs, int64(id))
+		}
+	}
+
+	if len(param.Metrics) == 0 {
+		param.Metrics = dto.CompareMetrics
+	}
+
+	locations, err := u.locationRepo.GetLocations(ctx, repository.GetLocationsParam{IDs: locationIDs})
+	if err != nil {
+		return resp, fmt.Errorf("failed to get locations: %w", err)
+	}
+
+	found := make(map[int64]domain.Location, len(locations))
+	foundIDs := []int64{}
+	for _, location := range locations {
+		found[location.ID] = location
+		foundIDs = append(foundIDs, location.ID)
+	}
+
+	// tomorrow of the first requested location by default, forecast times
+	// are local so the hours of every location line up. The series starts on
+	// a whole hour.
+	if param.From.IsZero() && param.To.IsZero() {
+		for _, id := range locationIDs {
+			if location, ok := found[id]; ok {
+				param.From = locationToday(location).AddDate(0, 0, 1)
+				break
+			}
+		}
+		if param.From.IsZero() {
+			param.From = startOfDay(time.Now()).AddDate(0, 0, 1)
+		}
+	} else if param.From.IsZero() {
+		param.From = param.To.AddDate(0, 0, -1)
+	}
+	param.From = time.Date(param.From.Year(), param.From.Month(), param.From.Day(), param.From.Hour(), 0, 0, 0, param.From.Location())
+	if param.To.IsZero() {
+		param.To = param.From.AddDate(0, 0, 1)
+	}
+
+	rows, err := u.weatherRepo.GetWeatherSeries(ctx, repository.GetWeatherSeriesParam{
+		LocationIDs: foundIDs,
+		From:        param.From,
+		To:          param.To,
+	})
+	if err != nil {
+		return resp, fmt.Errorf("failed to get weather series: %w", err)
+	}
+
+	times := []time.Time{}
+	for t := param.From; t.Before(param.To); t = t.Add(time.Hour) {
+		times = append(times, t)
+	}
+
+	rowsByLocation := map[int64][]domain.Weather{}
+	for _, row := range rows {
+		rowsByLocation[row.LocationID] = append(rowsByLocation[row.LocationID], row)
+	}
+
+	result := dto.CompareWeathersResponse{
+		From:      param.From.Format(time.RFC3339),
+		To:        param.To.Format(time.RFC3339),
+		Metrics:   param.Metrics,
+		Times:     times,
+		Locations: []dto.CompareWeathersLocation{},
+	}
+	for _, id := range locationIDs {
+		location, ok := found[id]
+		if !ok {
+			result.NotFoundLocationIDs = append(result.NotFoundLocationIDs, int(id))
+			continue
+		}
+
+		result.Locations = append(result.Locations, buildCompareSeries(location, rowsByLocation[id], param.From, len(times), param.Metrics))
+	}
+	result.Rankings = rankCompareLocations(result.Locations, param.Metrics)
+
+	resp.Data = result.InUnits(param.Units)
+	return resp, nil
+}
+
+// buildCompareSeries aligns the rows of a location on the hourly buckets
+// starting at from, hours without a row stay nil.
+func buildCompareSeries(location domain.Location, rows []domain.Weather, from time.Time, buckets int, metrics []string) dto.CompareWeathersLocation {
+	result := dto.CompareWeathersLocation{
+		Location: dto.ParseToGetLocationHandlerResponse(location),
+		Series:   make(map[string][]*float64, len(metrics)),
+	}
+	for _, metric := range metrics {
+		result.Series[metric] = make([]*float64, buckets)
+	}
+
+	filled := make([]bool, buckets)
+	for _, row := range rows {
+		index := int(row.ForecastTime.Sub(from) / time.Hour)
+		if index < 0 || index >= buckets {
+			continue
+		}
+
+		filled[index] = true
+		for _, metric := range metrics {
+			result.Series[metric][index] = compareMetricValue(row, metric)
+		}
+	}
+
+	for _, ok := range filled {
+		if !ok {
+			result.Missing++
+		}
+	}
+
+	return result
+}
+
+func compareMetricValue(row domain.Weather, metric string) *float64 {
+	var value float64
+	switch metric {
+	case dto.CompareMetricTemperature:
+		value = row.TemperatureCelcius
+	case dto.CompareMetricHumidity:
+		value = float64(row.Humidity)
+	case dto.CompareMetricWindSpeed:
+		value = row.WindSpeed
+	case dto.CompareMetricPrecipitation:
+		if !row.PrecipitationMM.Valid {
+			return nil
+		}
+		value = row.PrecipitationMM.Float64
+	}
+	return &value
+}
+
+// rankCompareLocations ranks the locations by the mean of the values they
+// have, locations without any value are left out.
+func rankCompareLocations(locations []dto.CompareWeathersLocation, metrics []string) []dto.CompareWeathersRanking {
+	rankings := []dto.CompareWeathersRanking{}
+	for _, definition := range compareRankings {
+		if !slices.Contains(metrics, definition.metric) {
+			continue
+		}
+
+		ranking := dto.CompareWeathersRanking{
+			Name:   definition.name,
+			Metric: definition.metric,
+			Items:  []dto.CompareWeathersRankingItem{},
+		}
+		for _, location := range locations {
+			total, count := 0.0, 0
+			for _, value := range location.Series[definition.metric] {
+				if value != nil {
+					total += *value
+					count++
+				}
+			}
+			if count == 0 {
+				continue
+			}
+
+			ranking.Items = append(ranking.Items, dto.CompareWeathersRankingItem{
+				LocationID: location.Location.ID,
+				Value:      math.Round(total/float64(count)*100) / 100,
+				Hours:      count,
+			})
+		}
+
+		slices.SortStableFunc(ranking.Items, func(a, b dto.CompareWeathersRankingItem) int {
+			if definition.highestFirst {
+				a, b = b, a
+			}
+			switch {
+			case a.Value < b.Value:
+				return -1
+			case a.Value > b.Value:
+				return 1
+			}
+			return 0
+		})
+		for i := range ranking.Items {
+			ranking.Items[i].Rank = i + 1
+		}
+
+		rankings = append(rankings, ranking)
+	}
+
+	return rankings
+}
+
+// findLocation returns ErrLocationNotFound for an unknown or deleted location.
+func (u *weatherUsecase) findLocation(ctx context.Context, id int64) (domain.Location, error) {
+	locations, err := u.locationRepo.GetLocations(ctx, repository.GetLocationsParam{
+		ID:    int(id),
+		Limit: 1,
+	})
+	if err != nil {
+		return domain.Location{}, fmt.Errorf("failed to get locations: %w", err)
+	}
+
+	if len(locations) == 0 {
+		return domain.Location{}, ErrLocationNotFound
+	}
+
+	return locations[0], nil
+}
+
+// GetForecastCalendarUsecase renders an iCalendar with an all-day event per
+// daily forecast row of the past week and the coming forecast days.
+func (u *weatherUsecase) GetForecastCalendarUsecase(ctx context.Context, param dto.GetForecastFeedParam) (dto.ForecastFeedDocument, error) {
+	location, err := u.findLocation(ctx, param.LocationID)
+	if err != nil {
+		return dto.ForecastFeedDocument{}, err
+	}
+
+	today := locationToday(location)
+	days, err := u.weatherRepo.GetDailyWeathers(ctx, repository.GetDailyWeathersParam{
+		LocationID: param.LocationID,
+		From:       today.AddDate(0, 0, -utils.ForecastFeedPastDays),
+		To:         today.AddDate(0, 0, utils.MaxForecastDays-1),
+	})
+	if err != nil {
+		return dto.ForecastFeedDocument{}, fmt.Errorf("failed to get daily weathers: %w", err)
+	}
+
+	latest, err := u.getChangedDayWeathers(ctx, param.LocationID, 1)
+	if err != nil {
+		return dto.ForecastFeedDocument{}, err
+	}
+
+	var lastModified time.Time
+	if len(latest) > 0 {
+		lastModified = weatherModifiedAt(latest[0])
+	}
+
+	labels := param.Units.Labels()
+	calendar := ical.Calendar{
+		ProdID:          "-//weather-app//forecast//EN",
+		Name:            location.Name + " forecast",
+		RefreshInterval: utils.ForecastFeedMaxAge,
+	}
+	for _, day := range days {
+		// only days with a daily forecast row get an event
+		if !day.SummaryTemperature.Valid {
+			continue
+		}
+
+		dayCondition := dto.NewWeatherConditionResponse(domain.Weather{
+			ConditionStatus: day.SummaryCondition.String,
+			ConditionCode:   day.SummaryConditionCode.String,
+		}).Present(param.Languages, param.BaseURL)
+		summary := fmt.Sprintf("%s, %s %s", dayCondition.Text, formatFeedValue(param.Units.Temperature(day.SummaryTemperature.Float64)), labels.Temperature)
+		if day.Hours > 0 {
+			summary = fmt.Sprintf("%s, %s–%s %s", dayCondition.Text,
+				formatFeedValue(param.Units.Temperature(day.MinTemperatureCelcius)),
+				formatFeedValue(param.Units.Temperature(day.MaxTemperatureCelcius)),
+				labels.Temperature)
+		}
+
+		description := fmt.Sprintf("Average %s %s, wind up to %s %s", formatFeedValue(param.Units.Temperature(day.SummaryTemperature.Float64)), labels.Temperature,
+			formatFeedValue(param.Units.Speed(day.SummaryWindSpeed.Float64)), labels.Speed)
+		if day.SummaryPrecipitationMM.Valid {
+			description += fmt.Sprintf(", precipitation %s %s", formatFeedValue(param.Units.Precipitation(day.SummaryPrecipitationMM.Float64)), labels.Precipitation)
+		}
+
+		stamp := lastModified
+		if stamp.IsZero() {
+			stamp = day.Date
+		}
+
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:         fmt.Sprintf("weather-%d-%s@weather-app", location.ID, day.Date.Format("20060102")),
+			Date:        day.Date,
+			Stamp:       stamp,
+			Summary:     summary,
+			Description: description,
+			Image:       dayCondition.Icon,
+		})
+	}
+
+	return dto.ForecastFeedDocument{Body: calendar.Marshal(), LastModified: lastModified}, nil
+}
+
+// GetForecastFeedUsecase renders an Atom feed with an entry per daily
+// forecast row, most recently created or changed first. Rows hold only their
+// latest values, so an entry describes the forecast after its last change,
+// with the alert rules its values match.
+func (u *weatherUsecase) GetForecastFeedUsecase(ctx context.Context, param dto.GetForecastFeedParam) (dto.ForecastFeedDocument, error) {
+	location, err := u.findLocation(ctx, param.LocationID)
+	if err != nil {
+		return dto.ForecastFeedDocument{}, err
+	}
+
+	weathers, err := u.getChangedDayWeathers(ctx, param.LocationID, utils.ForecastFeedEntries)
+	if err != nil {
+		return dto.ForecastFeedDocument{}, err
+	}
+
+	labels := param.Units.Labels()
+	feed := atom.Feed{
+		ID:      fmt.Sprintf("urn:weather-app:location:%d:forecast", location.ID),
+		Title:   location.Name + " forecast",
+		SelfURL: param.SelfURL,
+		Author:  "weather-app",
+		Updated: location.CreatedAt,
+	}
+	for i, weather := range weathers {
+		modifiedAt := weatherModifiedAt(weather)
+		if i == 0 {
+			feed.Updated = modifiedAt
+		}
+
+		action := "updated"
+		if !weather.LastModifiedAt.Valid {
+			action = "published"
+		}
+
+		item := dto.ParseToGetWeatherResponseItem(weather).WithAlerts(param.AlertRules).Present(param.Languages, param.BaseURL)
+		content := fmt.Sprintf("%s, average %s %s, humidity %d%%, wind up to %s %s", item.Condition.Text,
+			formatFeedValue(param.Units.Temperature(weather.TemperatureCelcius)), labels.Temperature,
+			weather.Humidity, formatFeedValue(param.Units.Speed(weather.WindSpeed)), labels.Speed)
+		if weather.PrecipitationMM.Valid {
+			content += fmt.Sprintf(", precipitation %s %s", formatFeedValue(param.Units.Precipitation(weather.PrecipitationMM.Float64)), labels.Precipitation)
+		}
+		if item.Derived != nil && len(item.Derived.Alerts) > 0 {
+			content += ", alerts " + strings.Join(item.Derived.Alerts, ", ")
+		}
+
+		feed.Entries = append(feed.Entries, atom.Entry{
+			ID:      fmt.Sprintf("urn:weather-app:weather:%d:%d", weather.ID, modifiedAt.Unix()),
+			Title:   fmt.Sprintf("Forecast for %s %s", weather.ForecastTime.Format("Mon, 02 Jan 2006"), action),
+			Updated: modifiedAt,
+			Content: content,
+			Icon:    item.Condition.Icon,
+		})
+	}
+
+	body, err := feed.Marshal()
+	if err != nil {
+		return dto.ForecastFeedDocument{}, fmt.Errorf("failed to render feed: %w", err)
+	}
+
+	var lastModified time.Time
+	if len(weathers) > 0 {
+		lastModified = feed.Updated
+	}
+
+	return dto.ForecastFeedDocument{Body: body, LastModified: lastModified}, nil
+}
+
+func (u *weatherUsecase) getChangedDayWeathers(ctx context.Context, locationID int64, limit int) ([]domain.Weather, error) {
+	weathers, err := u.weatherRepo.GetWeathers(ctx, repository.GetWeathersParam{
+		LocationID:   locationID,
+		ForecastType: string(domain.ForecastTypeDay),
+		Limit:        limit,
+		OrderBy:      "COALESCE(last_modified_at, created_at) DESC, id DESC",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weathers: %w", err)
+	}
+
+	return weathers, nil
+}
+
+func weatherModifiedAt(weather domain.Weather) time.Time {
+	if weather.LastModifiedAt.Valid {
+		return weather.LastModifiedAt.Time
+	}
+	return weather.CreatedAt
+}
+
+// formatFeedValue drops trailing zeros, e.g. 24 instead of 24.00.
+func formatFeedValue(value float64) string {
+	return strconv.FormatFloat(math.Round(value*10)/10, 'f', -1, 64)
+}
+
+// GetMapLayerUsecase builds a GeoJSON point per location with its latest
+// hourly row up to its local now, at most utils.MapWeatherMaxAge old, or
+// with the row of the requested forecast hour. Locations without such a row
+// keep only their own properties.
+func (u *weatherUsecase) GetMapLayerUsecase(ctx context.Context, param dto.GetMapLayerParam) (dto.MapLayer, error) {
+	// one more location than the layer holds tells whether it is truncated
+	locations, err := u.locationRepo.GetLocations(ctx, repository.GetLocationsParam{
+		BoundingBox: param.BoundingBox,
+		Limit:       utils.MaxMapLocations + 1,
+		OrderBy:     utils.OrderByNameAsc,
+	})
+	if err != nil {
+		return dto.MapLayer{}, fmt.Errorf("failed to get locations: %w", err)
+	}
+
+	truncated := len(locations) > utils.MaxMapLocations
+	if truncated {
+		locations = locations[:utils.MaxMapLocations]
+	}
+
+	var latestParam repository.GetLatestWeathersParam
+	// the current layer moves on with the hour of each location even when
+	// no row changed, it is modified no earlier than the latest of them
+	var lastModified time.Time
+	for _, location := range locations {
+		window := repository.LatestWeatherWindow{LocationID: location.ID}
+		if param.ForecastTime.IsZero() {
+			zone := locationZone(location)
+			window.To = wallClock(time.Now().In(zone))
+			window.From = window.To.Add(-utils.MapWeatherMaxAge)
+			if start := zonedTime(startOfHour(window.To), zone); start.After(lastModified) {
+				lastModified = start
+			}
+		} else {
+			window.From = startOfHour(param.ForecastTime)
+			window.To = window.From.Add(time.Hour)
+		}
+		latestParam.Windows = append(latestParam.Windows, window)
+	}
+
+	weathers, err := u.weatherRepo.GetLatestWeathers(ctx, latestParam)
+	if err != nil {
+		return dto.MapLayer{}, fmt.Errorf("failed to get latest weathers: %w", err)
+	}
+
+	weatherByLocation := make(map[int64]domain.Weather, len(weathers))
+	for _, weather := range weathers {
+		weatherByLocation[weather.LocationID] = weather
+		if modifiedAt := weatherModifiedAt(weather); modifiedAt.After(lastModified) {
+			lastModified = modifiedAt
+		}
+	}
+
+	features := make([]geojson.Feature, 0, len(locations))
+	for _, location := range locations {
+		var current *domain.Weather
+		if weather, ok := weatherByLocation[location.ID]; ok {
+			current = &weather
+		}
+		features = append(features, dto.NewMapLayerFeature(location, current, param.Units, param.BaseURL))
+	}
+
+	collection := geojson.NewFeatureCollection(features)
+	collection.Truncated = truncated
+	return dto.MapLayer{
+		Collection:   collection,
+		LastModified: lastModified,
+	}, nil
+}
+
+var chartTitles = map[string]string{
+	dto.CompareMetricTemperature:   "Temperature",
+	dto.CompareMetricHumidity:      "Humidity",
+	dto.CompareMetricWindSpeed:     "Wind speed",
+	dto.CompareMetricPrecipitation: "Precipitation",
+}
+
+// GetWeatherChartUsecase renders an hourly metric of a location from its
+// current hour on as SVG, precipitation as bars and the others as a line.
+// Hours and their labels are the wall clock of the location like forecast
+// times. Hours without a row are left as gaps.
+func (u *weatherUsecase) GetWeatherChartUsecase(ctx context.Context, param dto.GetWeatherChartParam) (dto.WeatherChart, error) {
+	if param.Metric == "" {
+		param.Metric = dto.CompareMetricTemperature
+	}
+	if param.Range == 0 {
+		param.Range = time.Duration(utils.DefaultChartRangeHours) * time.Hour
+	}
+	if param.Width == 0 {
+		param.Width = utils.DefaultChartWidth
+	}
+	if param.Height == 0 {
+		param.Height = utils.DefaultChartHeight
+	}
+	if param.Theme == "" {
+		param.Theme = "light"
+	}
+
+	location, err := u.findLocation(ctx, param.LocationID)
+	if err != nil {
+		return dto.WeatherChart{}, err
+	}
+
+	from := startOfHour(wallClock(time.Now().In(locationZone(location))))
+	hours := int(param.Range / time.Hour)
+	rows, err := u.weatherRepo.GetWeatherSeries(ctx, repository.GetWeatherSeriesParam{
+		LocationIDs: []int64{location.ID},
+		From:        from,
+		To:          from.Add(time.Duration(hours) * time.Hour),
+	})
+	if err != nil {
+		return dto.WeatherChart{}, fmt.Errorf("failed to get weather series: %w", err)
+	}
+
+	labels := param.Units.Labels()
+	convert, unit := func(value float64) float64 { return value }, "%"
+	switch param.Metric {
+	case dto.CompareMetricTemperature:
+		convert, unit = param.Units.Temperature, labels.Temperature
+	case dto.CompareMetricWindSpeed:
+		convert, unit = param.Units.Speed, labels.Speed
+	case dto.CompareMetricPrecipitation:
+		convert, unit = param.Units.Precipitation, labels.Precipitation
+	}
+
+	chart := svgchart.Chart{
+		Title:  location.Name + " " + strings.ToLower(chartTitles[param.Metric]),
+		Unit:   unit,
+		Kind:   svgchart.KindLine,
+		Width:  param.Width,
+		Height: param.Height,
+		Theme:  svgchart.Themes[param.Theme],
+		Points: make([]svgchart.Point, hours),
+	}
+	if param.Metric == dto.CompareMetricPrecipitation {
+		chart.Kind = svgchart.KindBar
+	}
+	for i := range chart.Points {
+		chart.Points[i].Time = from.Add(time.Duration(i) * time.Hour)
+	}
+
+	var lastModified time.Time
+	for _, row := range rows {
+		index := int(row.ForecastTime.Sub(from) / time.Hour)
+		if index < 0 || index >= hours {
+			continue
+		}
+
+		if value := compareMetricValue(row, param.Metric); value != nil {
+			converted := convert(*value)
+			chart.Points[index].Value = &converted
+		}
+		if modifiedAt := weatherModifiedAt(row); modifiedAt.After(lastModified) {
+			lastModified = modifiedAt
+		}
+	}
+
+	// the chart moves on with the hour even when no row changed
+	if start := zonedTime(from, locationZone(location)); start.After(lastModified) {
+		lastModified = start
+	}
+
+	return dto.WeatherChart{Body: chart.Render(), LastModified: lastModified}, nil
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,6 +17,12 @@ import (
 	"tyarus/weather-app/internal/dto"
 	"tyarus/weather-app/internal/repository"
 	"tyarus/weather-app/mocks"
+	"tyarus/weather-app/pkg/condition"
+	"tyarus/weather-app/pkg/cursor"
+	"tyarus/weather-app/pkg/units"
+	"tyarus/weather-app/pkg/utils"
+	"tyarus/weather-app/pkg/weather"
+	"tyarus/weather-app/pkg/weather/derive"
 )
 
 func TestGetWeathersUsecase(t *testing.T) {
@@ -75,11 +83,7 @@ func TestGetWeathersUsecase(t *testing.T) {
 
 		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
 		ctx := context.Background()
-		req := dto.GetWeathersParam{
-			LocationID:  1,
-			PageSize:    10,
-			CurrentPage: 1,
-		}
+		req := dto.GetWeathersParam{LocationID: 1}
 
 		locations := []domain.Location{
 			{
@@ -101,6 +105,7 @@ func TestGetWeathersUsecase(t *testing.T) {
 			Location: dto.GetLocationHandlerResponseItem{
 				ID: 1,
 			},
+			Units: units.Metric.Labels(),
 		}
 		cacheData, _ := json.Marshal(expectedResponse)
 		mockCache.On("Get", ctx, cacheKey).Return(string(cacheData), nil)
@@ -111,6 +116,102 @@ func TestGetWeathersUsecase(t *testing.T) {
 		assert.Equal(t, expectedResponse, result.Data)
 	})
 
+	t.Run("WHEN imperial units are requested, THEN should convert the cached metric data", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, repository.GetLocationsParam{ID: 1, Limit: 1}).
+			Return([]domain.Location{{ID: 1, Name: "Test Location"}}, nil)
+
+		visibility := 10.0
+		cacheData, _ := json.Marshal(dto.GetWeatherResponse{
+			Location:    dto.GetLocationHandlerResponseItem{ID: 1},
+			CurrentTime: dto.GetWeatherResponseItem{TemperatureCelcius: 100, Temperature: 100, WindSpeed: 36, Visibility: &visibility},
+			Forecast:    []dto.GetWeatherResponseItem{{TemperatureCelcius: 0, WindSpeed: 16.09344}},
+			Units:       units.Metric.Labels(),
+		})
+		mockCache.On("Get", ctx, "weather:location:1").Return(string(cacheData), nil)
+
+		result, err := usecase.GetWeathersUsecase(ctx, dto.GetWeathersParam{LocationID: 1, Units: units.Imperial})
+
+		assert.NoError(t, err)
+		assert.Equal(t, float64(212), result.Data.CurrentTime.Temperature)
+		assert.Equal(t, float64(100), result.Data.CurrentTime.TemperatureCelcius)
+		assert.Equal(t, 22.37, result.Data.CurrentTime.WindSpeed)
+		assert.Equal(t, 6.21, *result.Data.CurrentTime.Visibility)
+		assert.Equal(t, float64(32), result.Data.Forecast[0].Temperature)
+		assert.Equal(t, float64(10), result.Data.Forecast[0].WindSpeed)
+		assert.Equal(t, units.Imperial.Labels(), result.Data.Units)
+	})
+
+	t.Run("WHEN a language is requested, THEN should translate the cached conditions with absolute icon URLs", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, repository.GetLocationsParam{ID: 1, Limit: 1}).
+			Return([]domain.Location{{ID: 1, Name: "Test Location"}}, nil)
+		cacheData, _ := json.Marshal(dto.GetWeatherResponse{
+			Location:    dto.GetLocationHandlerResponseItem{ID: 1, Name: "Jakarta"},
+			CurrentTime: dto.ParseToGetWeatherResponseItem(domain.Weather{ConditionStatus: "Moderate rain", ConditionCode: "rain", ConditionIconURL: "//cdn.weatherapi.com/weather/64x64/day/302.png"}),
+			Forecast:    []dto.GetWeatherResponseItem{dto.ParseToGetWeatherResponseItem(domain.Weather{ConditionStatus: "Patchy light drizzle"})},
+		})
+		mockCache.On("Get", ctx, "weather:location:1").Return(string(cacheData), nil)
+		mockLocationRepo.On("GetLocationNames", ctx, []int64{1}).Return(map[int64][]domain.LocationName{
+			1: {{LocationID: 1, Name: "Jakarta Raya", Language: "id"}},
+		}, nil)
+
+		result, err := usecase.GetWeathersUsecase(ctx, dto.GetWeathersParam{LocationID: 1, Units: units.Metric, Languages: []string{"id"}, BaseURL: "https://weather.example.com"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, condition.Rain, result.Data.CurrentTime.Condition.Code)
+		assert.Equal(t, "Hujan", result.Data.CurrentTime.Condition.Text)
+		assert.Equal(t, "Moderate rain", result.Data.CurrentTime.Condition.Status)
+		assert.Equal(t, condition.Drizzle, result.Data.Forecast[0].Condition.Code)
+		assert.Equal(t, "Gerimis", result.Data.Forecast[0].Condition.Text)
+		assert.Equal(t, "https://weather.example.com/api/v1/icons/day-302.png", result.Data.CurrentTime.Condition.IconURL)
+		assert.Equal(t, "https://weather.example.com/api/v1/conditions/drizzle.svg", result.Data.Forecast[0].Condition.Icon)
+		assert.Equal(t, "Jakarta Raya", result.Data.Location.LocalizedName)
+	})
+
+	t.Run("WHEN summary is requested, THEN should add it in the requested language", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		// a day ahead of most server zones, tomorrow is the one of the location
+		location := domain.Location{ID: 1, Name: "Kiritimati", Timezone: "Pacific/Kiritimati"}
+		mockLocationRepo.On("GetLocations", ctx, repository.GetLocationsParam{ID: 1, Limit: 1}).
+			Return([]domain.Location{location}, nil)
+		cacheData, _ := json.Marshal(dto.GetWeatherResponse{Location: dto.GetLocationHandlerResponseItem{ID: 1}})
+		mockCache.On("Get", ctx, "weather:location:1").Return(string(cacheData), nil)
+		mockLocationRepo.On("GetLocationNames", ctx, []int64{1}).Return(map[int64][]domain.LocationName{}, nil)
+
+		today := locationToday(location)
+		mockWeatherRepo.On("GetWeatherSeries", ctx, repository.GetWeatherSeriesParam{
+			LocationIDs: []int64{1},
+			From:        today,
+			To:          today.AddDate(0, 0, 2),
+		}).Return([]domain.Weather{
+			{LocationID: 1, ForecastTime: today.AddDate(0, 0, 1).Add(12 * time.Hour), TemperatureCelcius: 33, Humidity: 50, ConditionStatus: "Moderate rain"},
+		}, nil)
+
+		result, err := usecase.GetWeathersUsecase(ctx, dto.GetWeathersParam{LocationID: 1, Units: units.Metric, Summary: true, Languages: []string{"id"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Panas besok disertai hujan", result.Data.Summary)
+	})
+
 	t.Run("WHEN error occurred on get weathers, THEN should return error accordingly", func(t *testing.T) {
 		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
 		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
@@ -139,15 +240,12 @@ func TestGetWeathersUsecase(t *testing.T) {
 			Limit: 1,
 		}).Return(locations, nil)
 
-		cacheKey := "weather:location:1"
-		mockCache.On("Get", ctx, cacheKey).Return("", errors.New("cache miss"))
-
 		expectedError := errors.New("database error")
 		mockWeatherRepo.On("GetWeathers", ctx, repository.GetWeathersParam{
 			LocationID: 1,
 			Limit:      10,
 			Offset:     0,
-			OrderBy:    "forecast_time DESC",
+			OrderBy:    "forecast_time DESC, id DESC",
 		}).Return(nil, expectedError)
 
 		_, err := usecase.GetWeathersUsecase(ctx, req)
@@ -184,15 +282,12 @@ func TestGetWeathersUsecase(t *testing.T) {
 			Limit: 1,
 		}).Return(locations, nil)
 
-		cacheKey := "weather:location:1"
-		mockCache.On("Get", ctx, cacheKey).Return("", errors.New("cache miss"))
-
 		weathers := []domain.Weather{}
 		mockWeatherRepo.On("GetWeathers", ctx, repository.GetWeathersParam{
 			LocationID: 1,
 			Limit:      10,
 			Offset:     0,
-			OrderBy:    "forecast_time DESC",
+			OrderBy:    "forecast_time DESC, id DESC",
 		}).Return(weathers, nil)
 
 		result, err := usecase.GetWeathersUsecase(ctx, req)
@@ -201,18 +296,14 @@ func TestGetWeathersUsecase(t *testing.T) {
 		assert.Empty(t, result.Data.Forecast)
 	})
 
-	t.Run("WHEN weathers found, THEN should return result accordingly", func(t *testing.T) {
+	t.Run("WHEN weathers found without page, THEN should return result and cache it", func(t *testing.T) {
 		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
 		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
 		mockCache := mocks.NewCacheInterface(t)
 
 		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
 		ctx := context.Background()
-		req := dto.GetWeathersParam{
-			LocationID:  1,
-			PageSize:    10,
-			CurrentPage: 1,
-		}
+		req := dto.GetWeathersParam{LocationID: 1}
 
 		locations := []domain.Location{
 			{
@@ -245,9 +336,7 @@ func TestGetWeathersUsecase(t *testing.T) {
 		}
 		mockWeatherRepo.On("GetWeathers", ctx, repository.GetWeathersParam{
 			LocationID: 1,
-			Limit:      10,
-			Offset:     0,
-			OrderBy:    "forecast_time DESC",
+			OrderBy:    "forecast_time DESC, id DESC",
 		}).Return(weathers, nil)
 
 		mockCache.On("Set", ctx, cacheKey, mock.Anything, 10*time.Minute).Return(nil)
@@ -260,3 +349,1690 @@ func TestGetWeathersUsecase(t *testing.T) {
 	})
 
 }
+
+func TestSyncWeatherUsecase(t *testing.T) {
+	t.Run("WHEN provider leaves out measurements, THEN should store them as null", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+		mockClient := mocks.NewWeatherAPIClientInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, mockClient)
+		ctx := context.Background()
+
+		var forecast weather.ForecastResponse
+		err := json.Unmarshal([]byte(`{"forecast": {"forecastday": [{
+			"date": "2024-03-01",
+			"day": {"avgtemp_c": 28, "totalprecip_mm": 0},
+			"hour": [
+				{"time": "2024-03-01 00:00", "temp_c": 26, "precip_mm": 0.4, "pressure_mb": 1009, "vis_km": 10},
+				{"time": "2024-03-01 01:00", "temp_c": 25}
+			]
+		}]}}`), &forecast)
+		assert.NoError(t, err)
+
+		mockLocationRepo.On("GetLocations", ctx, repository.GetLocationsParam{ID: 1, Limit: 10}).
+			Return([]domain.Location{{ID: 1, Name: "Jakarta", Latitude: -6.2, Longitude: 106.8}}, nil)
+		mockClient.On("GetForecast", mock.Anything, "Jakarta", 14).Return(&forecast, nil)
+		mockWeatherRepo.On("BulkUpsertWeather", mock.Anything, mock.MatchedBy(func(weathers []domain.Weather) bool {
+			return len(weathers) == 3 &&
+				// a day total of zero was sent, the visibility was not
+				weathers[0].PrecipitationMM == sql.NullFloat64{Float64: 0, Valid: true} &&
+				!weathers[0].VisibilityKM.Valid &&
+				weathers[1].PressureMB == sql.NullFloat64{Float64: 1009, Valid: true} &&
+				!weathers[2].PrecipitationMM.Valid && !weathers[2].PressureMB.Valid && !weathers[2].VisibilityKM.Valid
+		})).Return(nil, nil)
+		mockWeatherRepo.On("BulkUpsertAstronomies", mock.Anything, mock.Anything).Return(nil)
+		mockCache.On("Del", mock.Anything, "weather:location:1", "weather:batch:location:1").Return(nil)
+		mockCache.On("Publish", mock.Anything, "weather:synced", int64(1)).Return(nil)
+
+		err = usecase.SyncWeatherUsecase(ctx, dto.PostWeatherSyncUsecaseRequest{LocationID: 1})
+
+		assert.NoError(t, err)
+	})
+}
+
+func TestBackfillWeatherUsecase(t *testing.T) {
+	location := domain.Location{ID: 1, Name: "Jakarta", Timezone: "Asia/Jakarta"}
+
+	t.Run("WHEN location not found, THEN should return ErrLocationNotFound", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mocks.NewCacheInterface(t), mocks.NewWeatherAPIClientInterface(t))
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{}, nil)
+
+		_, err := usecase.BackfillWeatherUsecase(ctx, dto.BackfillWeatherParam{LocationID: 1, From: time.Now(), To: time.Now()})
+
+		assert.ErrorIs(t, err, ErrLocationNotFound)
+	})
+
+	t.Run("WHEN range reaches the future, THEN should store every day until today", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+		mockClient := mocks.NewWeatherAPIClientInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, mockClient)
+		ctx := context.Background()
+
+		now := time.Now().In(time.FixedZone("WIB", 7*3600))
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		yesterday := today.AddDate(0, 0, -1)
+		history := func(date time.Time) *weather.ForecastResponse {
+			return &weather.ForecastResponse{Forecast: weather.Forecast{Forecastday: []weather.ForecastDay{{
+				Date:  date.Format("2006-01-02"),
+				Hours: []weather.Hour{{ForecastTime: date.Format("2006-01-02") + " 00:00", TempC: 25}},
+			}}}}
+		}
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{location}, nil)
+		mockClient.On("GetHistory", ctx, "Jakarta", yesterday).Return(history(yesterday), nil).Once()
+		mockClient.On("GetHistory", ctx, "Jakarta", today).Return(history(today), nil).Once()
+		mockWeatherRepo.On("BulkUpsertWeather", ctx, mock.MatchedBy(func(weathers []domain.Weather) bool {
+			return len(weathers) == 2 && weathers[1].ForecastType == domain.ForecastTypeHour
+		})).Return(nil, nil).Twice()
+		mockWeatherRepo.On("BulkUpsertAstronomies", ctx, mock.Anything).Return(nil).Twice()
+		mockCache.On("Del", ctx, "weather:location:1", "weather:batch:location:1").Return(nil)
+		mockCache.On("Publish", ctx, "weather:synced", int64(1)).Return(nil)
+
+		result, err := usecase.BackfillWeatherUsecase(ctx, dto.BackfillWeatherParam{
+			LocationID: 1,
+			From:       yesterday,
+			To:         today.AddDate(0, 0, 5),
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Days)
+	})
+
+	t.Run("WHEN provider fails, THEN should stop with the failed date", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockClient := mocks.NewWeatherAPIClientInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mocks.NewCacheInterface(t), mockClient)
+		ctx := context.Background()
+
+		date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{location}, nil)
+		mockClient.On("GetHistory", ctx, "Jakarta", date).Return(nil, errors.New("weather api returned status 400"))
+
+		_, err := usecase.BackfillWeatherUsecase(ctx, dto.BackfillWeatherParam{LocationID: 1, From: date, To: date.AddDate(0, 0, 2)})
+
+		assert.ErrorContains(t, err, "failed to get history of 2024-01-01")
+	})
+}
+
+func TestBackfillConditionCodesUsecase(t *testing.T) {
+	t.Run("WHEN error occurred on get statuses, THEN should return error accordingly", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, nil, nil, nil)
+		ctx := context.Background()
+
+		mockWeatherRepo.On("GetUnknownConditionStatuses", ctx).Return(nil, errors.New("database error"))
+
+		_, err := usecase.BackfillConditionCodesUsecase(ctx)
+
+		assert.ErrorContains(t, err, "database error")
+	})
+
+	t.Run("WHEN rows have a known provider text, THEN should map them in batches with the sync table", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, nil, nil, nil)
+		ctx := context.Background()
+
+		mockWeatherRepo.On("GetUnknownConditionStatuses", ctx).Return([]string{"Patchy rain possible", "Tornado"}, nil)
+		param := repository.UpdateConditionCodesParam{Status: "Patchy rain possible", Code: string(condition.Drizzle), Limit: utils.ConditionBackfillBatchSize}
+		mockWeatherRepo.On("UpdateConditionCodes", ctx, param).Return(int64(utils.ConditionBackfillBatchSize), nil).Once()
+		mockWeatherRepo.On("UpdateConditionCodes", ctx, param).Return(int64(2), nil).Once()
+
+		updated, err := usecase.BackfillConditionCodesUsecase(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(utils.ConditionBackfillBatchSize+2), updated)
+		mockWeatherRepo.AssertNumberOfCalls(t, "UpdateConditionCodes", 2)
+	})
+}
+
+func TestGetWeathersBatchUsecase(t *testing.T) {
+	locations := []domain.Location{
+		{ID: 1, Name: "Jakarta", Region: "DKI Jakarta", Country: "Indonesia"},
+		{ID: 2, Name: "Bandung", Region: "West Java", Country: "Indonesia"},
+	}
+
+	t.Run("WHEN error occurred on get locations, THEN should return error accordingly", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, repository.GetLocationsParam{IDs: []int64{1, 2}}).Return(nil, errors.New("database error"))
+
+		_, err := usecase.GetWeathersBatchUsecase(ctx, dto.GetWeathersBatchParam{LocationIDs: []int{1, 2}})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get locations")
+	})
+
+	t.Run("WHEN all locations available on cache, THEN should not query weathers", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, repository.GetLocationsParam{IDs: []int64{1, 2}}).Return(locations, nil)
+
+		first, _ := json.Marshal(dto.GetWeathersBatchResponseItem{Location: dto.GetLocationHandlerResponseItem{ID: 1}})
+		second, _ := json.Marshal(dto.GetWeathersBatchResponseItem{Location: dto.GetLocationHandlerResponseItem{ID: 2}})
+		mockCache.On("MGet", ctx, "weather:batch:location:1", "weather:batch:location:2").Return([]string{string(first), string(second)}, nil)
+
+		result, err := usecase.GetWeathersBatchUsecase(ctx, dto.GetWeathersBatchParam{LocationIDs: []int{1, 2, 1}})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Data.Items, 2)
+		assert.Equal(t, int64(1), result.Data.Items[0].Location.ID)
+		assert.Equal(t, int64(2), result.Data.Items[1].Location.ID)
+	})
+
+	t.Run("WHEN group is given without ids, THEN should return every location of the group", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, repository.GetLocationsParam{
+			GroupID: 3,
+			Tag:     "java ops",
+			Limit:   101,
+			OrderBy: "name_ascend",
+		}).Return(locations, nil)
+
+		first, _ := json.Marshal(dto.GetWeathersBatchResponseItem{Location: dto.GetLocationHandlerResponseItem{ID: 1}})
+		second, _ := json.Marshal(dto.GetWeathersBatchResponseItem{Location: dto.GetLocationHandlerResponseItem{ID: 2}})
+		mockCache.On("MGet", ctx, "weather:batch:location:1", "weather:batch:location:2").Return([]string{string(first), string(second)}, nil)
+
+		result, err := usecase.GetWeathersBatchUsecase(ctx, dto.GetWeathersBatchParam{GroupID: 3, Tag: " Java  Ops"})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Data.Items, 2)
+		assert.Empty(t, result.Data.NotFoundLocationIDs)
+	})
+
+	t.Run("WHEN a language is requested, THEN should localize the location names with a single query", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, repository.GetLocationsParam{IDs: []int64{1, 2}}).Return(locations, nil)
+		first, _ := json.Marshal(dto.GetWeathersBatchResponseItem{Location: dto.GetLocationHandlerResponseItem{ID: 1, Name: "Jakarta"}})
+		second, _ := json.Marshal(dto.GetWeathersBatchResponseItem{Location: dto.GetLocationHandlerResponseItem{ID: 2, Name: "Bandung"}})
+		mockCache.On("MGet", ctx, "weather:batch:location:1", "weather:batch:location:2").Return([]string{string(first), string(second)}, nil)
+		mockLocationRepo.On("GetLocationNames", ctx, []int64{1, 2}).Return(map[int64][]domain.LocationName{
+			1: {{LocationID: 1, Name: "Jakarta Raya", Language: "id"}},
+		}, nil).Once()
+
+		result, err := usecase.GetWeathersBatchUsecase(ctx, dto.GetWeathersBatchParam{LocationIDs: []int{1, 2}, Languages: []string{"id"}})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Jakarta Raya", result.Data.Items[0].Location.LocalizedName)
+		assert.Equal(t, "Bandung", result.Data.Items[1].Location.LocalizedName)
+	})
+
+	t.Run("WHEN group has more locations than a batch, THEN should return the first ones and flag the response", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		many := []domain.Location{}
+		mgetArgs := []interface{}{ctx}
+		cached := []string{}
+		for id := int64(1); id <= 101; id++ {
+			many = append(many, domain.Location{ID: id})
+			if id <= 100 {
+				item, _ := json.Marshal(dto.GetWeathersBatchResponseItem{Location: dto.GetLocationHandlerResponseItem{ID: id}})
+				mgetArgs = append(mgetArgs, fmt.Sprintf("weather:batch:location:%d", id))
+				cached = append(cached, string(item))
+			}
+		}
+		mockLocationRepo.On("GetLocations", ctx, repository.GetLocationsParam{GroupID: 3, Limit: 101, OrderBy: "name_ascend"}).Return(many, nil)
+		mockCache.On("MGet", mgetArgs...).Return(cached, nil)
+
+		result, err := usecase.GetWeathersBatchUsecase(ctx, dto.GetWeathersBatchParam{GroupID: 3})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Data.Items, 100)
+		assert.True(t, result.Data.Truncated)
+	})
+
+	t.Run("WHEN locations missing on cache, THEN should load them with a single query and cache them", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, repository.GetLocationsParam{IDs: []int64{1, 2, 3}}).Return(locations, nil)
+		mockCache.On("MGet", ctx, "weather:batch:location:1", "weather:batch:location:2").Return([]string{"", ""}, nil)
+
+		now := time.Now()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		weathers := []domain.Weather{
+			{LocationID: 1, ForecastType: domain.ForecastTypeDay, ForecastTime: today, TemperatureCelcius: 30},
+			{LocationID: 1, ForecastType: domain.ForecastTypeDay, ForecastTime: today.AddDate(0, 0, 1), TemperatureCelcius: 31},
+			{LocationID: 1, ForecastType: domain.ForecastTypeHour, ForecastTime: now.Add(-50 * time.Minute), TemperatureCelcius: 28},
+			{LocationID: 1, ForecastType: domain.ForecastTypeHour, ForecastTime: now.Add(10 * time.Minute), TemperatureCelcius: 29},
+			{LocationID: 2, ForecastType: domain.ForecastTypeHour, ForecastTime: now, TemperatureCelcius: 24},
+		}
+		mockWeatherRepo.On("GetWeatherSummaries", ctx, mock.MatchedBy(func(param repository.GetWeatherSummariesParam) bool {
+			return assert.ObjectsAreEqual([]int64{1, 2}, param.LocationIDs) && param.ForecastDays == 14
+		})).Return(weathers, nil)
+		mockCache.On("MSet", ctx, mock.MatchedBy(func(values map[string]interface{}) bool {
+			return len(values) == 2
+		}), 10*time.Minute).Return(nil)
+		mockWeatherRepo.On("GetAstronomies", ctx, mock.MatchedBy(func(param repository.GetAstronomiesParam) bool {
+			return assert.ObjectsAreEqual([]int64{1, 2}, param.LocationIDs)
+		})).Return(nil, nil).Once()
+
+		result, err := usecase.GetWeathersBatchUsecase(ctx, dto.GetWeathersBatchParam{
+			LocationIDs:     []int{1, 2, 3},
+			IncludeForecast: true,
+			ForecastDays:    1,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Data.Items, 2)
+		assert.Equal(t, []int{3}, result.Data.NotFoundLocationIDs)
+		assert.Equal(t, float64(29), result.Data.Items[0].CurrentTime.TemperatureCelcius)
+		assert.Len(t, result.Data.Items[0].Forecast, 1)
+		assert.Equal(t, float64(24), result.Data.Items[1].CurrentTime.TemperatureCelcius)
+		assert.Empty(t, result.Data.Items[1].Forecast)
+		// days missing from the database are computed
+		assert.NotEmpty(t, result.Data.Items[0].Astronomy)
+		assert.NotNil(t, result.Data.Items[0].CurrentTime.IsDay)
+		assert.NotEmpty(t, result.Data.Items[1].Astronomy)
+		assert.NotNil(t, result.Data.Items[1].CurrentTime.IsDay)
+	})
+
+	t.Run("WHEN summary is requested, THEN should describe every location with a single query", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, repository.GetLocationsParam{IDs: []int64{1, 2}}).Return(locations, nil)
+		first, _ := json.Marshal(dto.GetWeathersBatchResponseItem{Location: dto.GetLocationHandlerResponseItem{ID: 1}})
+		second, _ := json.Marshal(dto.GetWeathersBatchResponseItem{Location: dto.GetLocationHandlerResponseItem{ID: 2}})
+		mockCache.On("MGet", ctx, "weather:batch:location:1", "weather:batch:location:2").Return([]string{string(first), string(second)}, nil)
+
+		tomorrowNoon := locationToday(locations[0]).AddDate(0, 0, 1).Add(12 * time.Hour)
+		mockWeatherRepo.On("GetWeatherSeries", ctx, mock.MatchedBy(func(param repository.GetWeatherSeriesParam) bool {
+			return assert.ObjectsAreEqual([]int64{1, 2}, param.LocationIDs)
+		})).Return([]domain.Weather{
+			{LocationID: 1, ForecastTime: tomorrowNoon, TemperatureCelcius: 20, Humidity: 60, ConditionStatus: "Fog"},
+		}, nil)
+
+		result, err := usecase.GetWeathersBatchUsecase(ctx, dto.GetWeathersBatchParam{LocationIDs: []int{1, 2}, Summary: true})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Mild tomorrow with fog", result.Data.Items[0].Summary)
+		assert.Empty(t, result.Data.Items[1].Summary)
+	})
+
+	t.Run("WHEN error occurred on get weather summaries, THEN should return error accordingly", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, repository.GetLocationsParam{IDs: []int64{1}}).Return(locations[:1], nil)
+		mockCache.On("MGet", ctx, "weather:batch:location:1").Return(nil, errors.New("redis down"))
+		mockWeatherRepo.On("GetWeatherSummaries", ctx, mock.Anything).Return(nil, errors.New("database error"))
+
+		_, err := usecase.GetWeathersBatchUsecase(ctx, dto.GetWeathersBatchParam{LocationIDs: []int{1}})
+
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to get weather summaries")
+	})
+}
+
+func TestGetPointWeatherUsecase(t *testing.T) {
+	locations := []domain.Location{
+		{ID: 1, Name: "Jakarta", Latitude: -6.2088, Longitude: 106.8456},
+		{ID: 2, Name: "Bandung", Latitude: -6.9175, Longitude: 107.6191},
+	}
+
+	t.Run("WHEN no synced location near the point, THEN should return ErrNoNearbyLocation", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{}, nil)
+
+		_, err := usecase.GetPointWeatherUsecase(ctx, dto.GetPointWeatherParam{GetNearbyLocationsParam: dto.GetNearbyLocationsParam{Latitude: 0, Longitude: 0}})
+
+		assert.ErrorIs(t, err, ErrNoNearbyLocation)
+	})
+
+	t.Run("WHEN nearby locations are synced, THEN should interpolate by inverse distance", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return(locations, nil)
+
+		jakarta, _ := json.Marshal(dto.GetWeathersBatchResponseItem{
+			Location:    dto.GetLocationHandlerResponseItem{ID: 1},
+			CurrentTime: &dto.GetWeatherResponseItem{TemperatureCelcius: 32, Humidity: 70},
+		})
+		bandung, _ := json.Marshal(dto.GetWeathersBatchResponseItem{
+			Location:    dto.GetLocationHandlerResponseItem{ID: 2},
+			CurrentTime: &dto.GetWeatherResponseItem{TemperatureCelcius: 24, Humidity: 90},
+		})
+		mockCache.On("MGet", ctx, mock.Anything, mock.Anything).Return([]string{string(jakarta), string(bandung)}, nil)
+
+		// the midpoint of Jakarta and Bandung
+		result, err := usecase.GetPointWeatherUsecase(ctx, dto.GetPointWeatherParam{
+			GetNearbyLocationsParam: dto.GetNearbyLocationsParam{
+				Latitude:  -6.56315,
+				Longitude: 107.23235,
+				RadiusKm:  100,
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Data.Sources, 2)
+		assert.InDelta(t, 28, result.Data.CurrentTime.TemperatureCelcius, 0.1)
+		assert.InDelta(t, 28, result.Data.CurrentTime.Temperature, 0.1)
+		assert.Equal(t, 80, result.Data.CurrentTime.Humidity)
+		assert.Equal(t, "°C", result.Data.Units.Temperature)
+	})
+
+	t.Run("WHEN the point is on a synced location, THEN should return its weather as is", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return(locations, nil)
+
+		jakarta, _ := json.Marshal(dto.GetWeathersBatchResponseItem{
+			Location:    dto.GetLocationHandlerResponseItem{ID: 1},
+			CurrentTime: &dto.GetWeatherResponseItem{TemperatureCelcius: 32, Humidity: 70},
+		})
+		mockCache.On("MGet", ctx, mock.Anything).Return([]string{string(jakarta)}, nil)
+
+		result, err := usecase.GetPointWeatherUsecase(ctx, dto.GetPointWeatherParam{
+			GetNearbyLocationsParam: dto.GetNearbyLocationsParam{
+				Latitude:  -6.2088,
+				Longitude: 106.8456,
+				RadiusKm:  10,
+			},
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, float64(32), result.Data.CurrentTime.TemperatureCelcius)
+	})
+
+	t.Run("WHEN imperial units are requested, THEN should convert the interpolated weather", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return(locations, nil)
+
+		precipitation := 10.0
+		jakarta, _ := json.Marshal(dto.GetWeathersBatchResponseItem{
+			Location:    dto.GetLocationHandlerResponseItem{ID: 1},
+			CurrentTime: &dto.GetWeatherResponseItem{TemperatureCelcius: 30, WindSpeed: 20, Precipitation: &precipitation},
+		})
+		bandung, _ := json.Marshal(dto.GetWeathersBatchResponseItem{
+			Location:    dto.GetLocationHandlerResponseItem{ID: 2},
+			CurrentTime: &dto.GetWeatherResponseItem{TemperatureCelcius: 20, WindSpeed: 10},
+		})
+		mockCache.On("MGet", ctx, mock.Anything, mock.Anything).Return([]string{string(jakarta), string(bandung)}, nil)
+
+		result, err := usecase.GetPointWeatherUsecase(ctx, dto.GetPointWeatherParam{
+			GetNearbyLocationsParam: dto.GetNearbyLocationsParam{
+				Latitude:  -6.56315,
+				Longitude: 107.23235,
+				RadiusKm:  100,
+			},
+			Units: units.Imperial,
+		})
+
+		assert.NoError(t, err)
+		assert.InDelta(t, 25, result.Data.CurrentTime.TemperatureCelcius, 0.1)
+		assert.InDelta(t, 77, result.Data.CurrentTime.Temperature, 0.2)
+		assert.InDelta(t, 9.32, result.Data.CurrentTime.WindSpeed, 0.1)
+		// only Jakarta reported precipitation, 10 mm
+		assert.InDelta(t, 0.39, *result.Data.CurrentTime.Precipitation, 0.01)
+		assert.Nil(t, result.Data.CurrentTime.Pressure)
+		assert.Equal(t, "mph", result.Data.Units.Speed)
+		// nothing is derived without humidity
+		assert.Nil(t, result.Data.CurrentTime.Derived)
+	})
+
+	t.Run("WHEN the current hour is known, THEN should compute the astronomy of the point", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return(locations, nil)
+
+		noon := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+		jakarta, _ := json.Marshal(dto.GetWeathersBatchResponseItem{
+			Location:    dto.GetLocationHandlerResponseItem{ID: 1},
+			CurrentTime: &dto.GetWeatherResponseItem{ForecastTime: noon, ForecastType: string(domain.ForecastTypeHour), TemperatureCelcius: 32},
+		})
+		mockCache.On("MGet", ctx, mock.Anything).Return([]string{string(jakarta)}, nil)
+
+		result, err := usecase.GetPointWeatherUsecase(ctx, dto.GetPointWeatherParam{
+			GetNearbyLocationsParam: dto.GetNearbyLocationsParam{
+				Latitude:  -6.2088,
+				Longitude: 106.8456,
+				RadiusKm:  10,
+			},
+		})
+
+		assert.NoError(t, err)
+		if assert.NotNil(t, result.Data.Astronomy) {
+			assert.Equal(t, "2024-01-01", result.Data.Astronomy.Date)
+			assert.Equal(t, domain.AstronomySourceComputed, result.Data.Astronomy.Source)
+		}
+		if assert.NotNil(t, result.Data.CurrentTime.IsDay) {
+			assert.True(t, *result.Data.CurrentTime.IsDay)
+		}
+	})
+}
+
+func TestGetWeathersUsecaseCursor(t *testing.T) {
+	forecastTime := time.Date(2025, 9, 1, 10, 0, 0, 0, time.UTC)
+	locations := []domain.Location{{ID: 1, Name: "Jakarta"}}
+
+	t.Run("WHEN backward cursor is given, THEN should return the rows before it without cache", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, repository.GetLocationsParam{ID: 1, Limit: 1}).Return(locations, nil)
+
+		token := cursor.Cursor{SortBy: "forecast_time_descend", Value: forecastTime.Format(time.RFC3339Nano), ID: 10, Backward: true}.Encode()
+		mockWeatherRepo.On("GetWeathers", ctx, repository.GetWeathersParam{
+			LocationID: 1,
+			After:      &repository.Keyset{Value: forecastTime, ID: 10, Backward: true},
+			Limit:      3,
+		}).Return([]domain.Weather{
+			{ID: 13, ForecastTime: forecastTime.Add(3 * time.Hour)},
+			{ID: 12, ForecastTime: forecastTime.Add(2 * time.Hour)},
+			{ID: 11, ForecastTime: forecastTime.Add(time.Hour)},
+		}, nil)
+		mockWeatherRepo.On("GetAstronomies", ctx, mock.Anything).Return(nil, nil)
+
+		result, err := usecase.GetWeathersUsecase(ctx, dto.GetWeathersParam{LocationID: 1, PageSize: 2, Cursor: token})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Data.Forecast, 2)
+		assert.Equal(t, forecastTime.Add(2*time.Hour), result.Data.Forecast[0].ForecastTime)
+		assert.NotEmpty(t, result.Data.PrevCursor)
+		next, _ := cursor.Decode(result.Data.NextCursor)
+		assert.Equal(t, int64(11), next.ID)
+		assert.False(t, next.Backward)
+	})
+
+	t.Run("WHEN cursor is malformed, THEN should return ErrInvalidCursor", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mocks.NewCacheInterface(t), nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, repository.GetLocationsParam{ID: 1, Limit: 1}).Return(locations, nil)
+
+		_, err := usecase.GetWeathersUsecase(ctx, dto.GetWeathersParam{LocationID: 1, Cursor: "???"})
+
+		assert.ErrorIs(t, err, cursor.ErrInvalidCursor)
+	})
+
+	t.Run("WHEN alert rules are given, THEN should list the matched rules of each item", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mocks.NewCacheInterface(t), nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, repository.GetLocationsParam{ID: 1, Limit: 1}).Return(locations, nil)
+		mockWeatherRepo.On("GetWeathers", ctx, mock.Anything).Return([]domain.Weather{
+			{ID: 12, ForecastTime: forecastTime.Add(time.Hour), TemperatureCelcius: 32.22, Humidity: 70},
+			{ID: 11, ForecastTime: forecastTime, TemperatureCelcius: 24, Humidity: 70},
+			{ID: 10, ForecastTime: forecastTime.Add(-time.Hour), TemperatureCelcius: 35},
+		}, nil)
+		mockWeatherRepo.On("GetAstronomies", ctx, mock.Anything).Return(nil, nil)
+
+		rules, err := derive.ParseRules("heatIndex>=40")
+		assert.NoError(t, err)
+
+		token := cursor.Cursor{SortBy: "forecast_time_descend", Value: forecastTime.Add(2 * time.Hour).Format(time.RFC3339Nano), ID: 13}.Encode()
+		result, err := usecase.GetWeathersUsecase(ctx, dto.GetWeathersParam{
+			LocationID: 1,
+			PageSize:   3,
+			Cursor:     token,
+			Units:      units.Imperial,
+			AlertRules: rules,
+		})
+
+		assert.NoError(t, err)
+		// thresholds are metric whatever units are requested
+		assert.Equal(t, []string{"heatIndex>=40"}, result.Data.Forecast[0].Derived.Alerts)
+		assert.Empty(t, result.Data.Forecast[1].Derived.Alerts)
+		// nothing is derived without humidity
+		assert.Nil(t, result.Data.Forecast[2].Derived)
+	})
+}
+
+func TestGetWeathersUsecaseAstronomy(t *testing.T) {
+	date := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	location := domain.Location{ID: 1, Name: "Jakarta", Latitude: -6.2, Longitude: 106.85}
+	token := cursor.Cursor{SortBy: "forecast_time_descend", Value: date.Format(time.RFC3339Nano), ID: 1}.Encode()
+
+	t.Run("WHEN astronomy is stored, THEN should return it and tell whether each hour is day", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mocks.NewCacheInterface(t), nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, repository.GetLocationsParam{ID: 1, Limit: 1}).Return([]domain.Location{location}, nil)
+		mockWeatherRepo.On("GetWeathers", ctx, mock.Anything).Return([]domain.Weather{
+			{ID: 3, ForecastType: domain.ForecastTypeHour, ForecastTime: date.Add(20 * time.Hour)},
+			{ID: 2, ForecastType: domain.ForecastTypeHour, ForecastTime: date.Add(10 * time.Hour)},
+		}, nil)
+		mockWeatherRepo.On("GetAstronomies", ctx, repository.GetAstronomiesParam{
+			LocationIDs: []int64{1},
+			From:        date,
+			To:          date,
+		}).Return([]domain.Astronomy{{
+			LocationID:       1,
+			Date:             date,
+			Sunrise:          sql.NullTime{Valid: true, Time: date.Add(5*time.Hour + 45*time.Minute)},
+			Sunset:           sql.NullTime{Valid: true, Time: date.Add(17*time.Hour + 50*time.Minute)},
+			DaylightMinutes:  725,
+			MoonPhase:        "Waxing Gibbous",
+			MoonIllumination: 62,
+			Source:           domain.AstronomySourceProvider,
+		}}, nil)
+
+		result, err := usecase.GetWeathersUsecase(ctx, dto.GetWeathersParam{LocationID: 1, PageSize: 2, Cursor: token})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Data.Astronomy, 1)
+		assert.Equal(t, "2025-09-01", result.Data.Astronomy[0].Date)
+		assert.InDelta(t, 12.08, result.Data.Astronomy[0].DaylightHours, 0.01)
+		assert.Nil(t, result.Data.Astronomy[0].Moonrise)
+		assert.Equal(t, domain.AstronomySourceProvider, result.Data.Astronomy[0].Source)
+		assert.False(t, *result.Data.Forecast[0].IsDay)
+		assert.True(t, *result.Data.Forecast[1].IsDay)
+	})
+
+	t.Run("WHEN astronomy of a day is missing, THEN should compute it from the coordinate", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mocks.NewCacheInterface(t), nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, repository.GetLocationsParam{ID: 1, Limit: 1}).Return([]domain.Location{location}, nil)
+		mockWeatherRepo.On("GetWeathers", ctx, mock.Anything).Return([]domain.Weather{
+			{ID: 2, ForecastType: domain.ForecastTypeHour, ForecastTime: date.Add(5 * time.Hour)},
+		}, nil)
+		mockWeatherRepo.On("GetAstronomies", ctx, mock.Anything).Return(nil, nil)
+
+		result, err := usecase.GetWeathersUsecase(ctx, dto.GetWeathersParam{LocationID: 1, PageSize: 2, Cursor: token})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Data.Astronomy, 1)
+		assert.Equal(t, domain.AstronomySourceComputed, result.Data.Astronomy[0].Source)
+		// Jakarta, UTC+7 from its longitude, sees the sun rise at about 05:53
+		assert.WithinDuration(t, date.Add(5*time.Hour+53*time.Minute), *result.Data.Astronomy[0].Sunrise, 3*time.Minute)
+		assert.InDelta(t, 12, result.Data.Astronomy[0].DaylightHours, 0.2)
+		assert.NotEmpty(t, result.Data.Astronomy[0].MoonPhase)
+		assert.False(t, *result.Data.Forecast[0].IsDay)
+	})
+}
+
+func TestGetDailyWeatherUsecase(t *testing.T) {
+	location := domain.Location{ID: 1, Name: "Jakarta", Timezone: "Asia/Jakarta"}
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	t.Run("WHEN location not found, THEN should return ErrLocationNotFound", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{}, nil)
+
+		_, err := usecase.GetDailyWeatherUsecase(ctx, dto.GetDailyWeatherParam{LocationID: 1, From: from, To: to})
+
+		assert.ErrorIs(t, err, ErrLocationNotFound)
+	})
+
+	t.Run("WHEN error occurred on get daily weathers, THEN should return error accordingly", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{location}, nil)
+		mockWeatherRepo.On("GetDailyWeathers", ctx, mock.Anything).Return(nil, errors.New("database error"))
+
+		_, err := usecase.GetDailyWeatherUsecase(ctx, dto.GetDailyWeatherParam{LocationID: 1, From: from, To: to})
+
+		assert.ErrorContains(t, err, "failed to get daily weathers")
+	})
+
+	t.Run("WHEN dates are empty, THEN should default to a week from today", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		// the date of the location, not of the server
+		now := time.Now().In(time.FixedZone("WIB", 7*3600))
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{location}, nil)
+		mockWeatherRepo.On("GetDailyWeathers", ctx, repository.GetDailyWeathersParam{
+			LocationID: 1,
+			From:       today,
+			To:         today.AddDate(0, 0, 6),
+		}).Return([]domain.DailyWeather{}, nil)
+
+		result, err := usecase.GetDailyWeatherUsecase(ctx, dto.GetDailyWeatherParam{LocationID: 1, Units: units.Metric})
+
+		assert.NoError(t, err)
+		assert.Empty(t, result.Data.Days)
+		assert.Equal(t, today.Format("2006-01-02"), result.Data.From)
+	})
+
+	t.Run("WHEN provider summary disagrees with hourly data, THEN should flag the day", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		days := []domain.DailyWeather{
+			{
+				Date:                   from,
+				Hours:                  24,
+				MinTemperatureCelcius:  24,
+				MaxTemperatureCelcius:  32,
+				AvgTemperatureCelcius:  28,
+				MaxWindSpeed:           20,
+				TotalPrecipitationMM:   sql.NullFloat64{Float64: 5, Valid: true},
+				DominantCondition:      "Sunny",
+				SummaryTemperature:     sql.NullFloat64{Float64: 27.5, Valid: true},
+				SummaryWindSpeed:       sql.NullFloat64{Float64: 19, Valid: true},
+				SummaryPrecipitationMM: sql.NullFloat64{Float64: 5.5, Valid: true},
+				SummaryCondition:       sql.NullString{String: "Sunny", Valid: true},
+			},
+			{
+				Date:                   to,
+				Hours:                  24,
+				MinTemperatureCelcius:  24,
+				MaxTemperatureCelcius:  30,
+				AvgTemperatureCelcius:  27,
+				MaxWindSpeed:           10,
+				TotalPrecipitationMM:   sql.NullFloat64{Float64: 0, Valid: true},
+				DominantCondition:      "Cloudy",
+				SummaryTemperature:     sql.NullFloat64{Float64: 35, Valid: true},
+				SummaryWindSpeed:       sql.NullFloat64{Float64: 30, Valid: true},
+				SummaryPrecipitationMM: sql.NullFloat64{Float64: 12, Valid: true},
+				SummaryCondition:       sql.NullString{String: "Rain", Valid: true},
+			},
+		}
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{location}, nil)
+		mockWeatherRepo.On("GetDailyWeathers", ctx, mock.Anything).Return(days, nil)
+
+		result, err := usecase.GetDailyWeatherUsecase(ctx, dto.GetDailyWeatherParam{LocationID: 1, From: from, To: to, Units: units.Metric})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Data.Days, 2)
+		assert.True(t, *result.Data.Days[0].Consistent)
+		assert.Empty(t, result.Data.Days[0].Issues)
+		assert.False(t, *result.Data.Days[1].Consistent)
+		assert.Len(t, result.Data.Days[1].Issues, 3)
+	})
+
+	t.Run("WHEN imperial units are requested, THEN should describe issues in them", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{location}, nil)
+		mockWeatherRepo.On("GetDailyWeathers", ctx, mock.Anything).Return([]domain.DailyWeather{
+			{
+				Date:                  from,
+				Hours:                 24,
+				MinTemperatureCelcius: 20,
+				MaxTemperatureCelcius: 30,
+				AvgTemperatureCelcius: 25,
+				MaxWindSpeed:          10,
+				SummaryTemperature:    sql.NullFloat64{Float64: 35, Valid: true},
+				SummaryWindSpeed:      sql.NullFloat64{Float64: 30, Valid: true},
+			},
+		}, nil)
+
+		result, err := usecase.GetDailyWeatherUsecase(ctx, dto.GetDailyWeatherParam{LocationID: 1, From: from, To: to, Units: units.Imperial})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"summary temperature 95.0°F is outside the hourly range 68.0°F to 86.0°F",
+			"summary wind speed 18.6 mph exceeds the hourly maximum 6.2 mph",
+		}, result.Data.Days[0].Issues)
+	})
+
+	t.Run("WHEN provider sent no summary, THEN should skip the consistency check", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{location}, nil)
+		mockWeatherRepo.On("GetDailyWeathers", ctx, mock.Anything).Return([]domain.DailyWeather{
+			{Date: from, Hours: 12, MinTemperatureCelcius: 20, MaxTemperatureCelcius: 30, AvgTemperatureCelcius: 25},
+		}, nil)
+
+		result, err := usecase.GetDailyWeatherUsecase(ctx, dto.GetDailyWeatherParam{LocationID: 1, From: from, To: to, Units: units.Imperial})
+
+		assert.NoError(t, err)
+		assert.Nil(t, result.Data.Days[0].Consistent)
+		assert.Nil(t, result.Data.Days[0].Summary)
+		assert.Equal(t, 86.0, result.Data.Days[0].MaxTemperature)
+		assert.Equal(t, "2024-01-01", result.Data.Days[0].Date)
+	})
+}
+
+func TestGetForecastCalendarUsecase(t *testing.T) {
+	location := domain.Location{ID: 1, Name: "Jakarta"}
+
+	t.Run("WHEN location not found, THEN should return ErrLocationNotFound", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{}, nil)
+
+		_, err := usecase.GetForecastCalendarUsecase(ctx, dto.GetForecastFeedParam{LocationID: 1, Units: units.Metric})
+
+		assert.ErrorIs(t, err, ErrLocationNotFound)
+	})
+
+	t.Run("WHEN error occurred on get daily weathers, THEN should return error accordingly", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{location}, nil)
+		mockWeatherRepo.On("GetDailyWeathers", ctx, mock.Anything).Return(nil, errors.New("database error"))
+
+		_, err := usecase.GetForecastCalendarUsecase(ctx, dto.GetForecastFeedParam{LocationID: 1, Units: units.Metric})
+
+		assert.ErrorContains(t, err, "failed to get daily weathers")
+	})
+
+	t.Run("WHEN location is far from the server zone, THEN should list the days around its own date", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		kiritimati := domain.Location{ID: 1, Name: "Kiritimati", Timezone: "Pacific/Kiritimati"}
+		zone, err := time.LoadLocation(kiritimati.Timezone)
+		assert.NoError(t, err)
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{kiritimati}, nil)
+		mockWeatherRepo.On("GetDailyWeathers", ctx, mock.MatchedBy(func(param repository.GetDailyWeathersParam) bool {
+			// dates are the calendar of the location labelled UTC
+			now := time.Now().In(zone)
+			today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+			return param.From.Equal(today.AddDate(0, 0, -utils.ForecastFeedPastDays)) &&
+				param.To.Equal(today.AddDate(0, 0, utils.MaxForecastDays-1))
+		})).Return(nil, nil)
+		mockWeatherRepo.On("GetWeathers", ctx, mock.Anything).Return(nil, nil)
+
+		_, err = usecase.GetForecastCalendarUsecase(ctx, dto.GetForecastFeedParam{LocationID: 1, Units: units.Metric})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("WHEN days have a daily forecast, THEN should render an event per day", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		modifiedAt := time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{location}, nil)
+		mockWeatherRepo.On("GetDailyWeathers", ctx, mock.Anything).Return([]domain.DailyWeather{
+			{
+				Date:                  time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local),
+				Hours:                 24,
+				MinTemperatureCelcius: 24,
+				MaxTemperatureCelcius: 32,
+				SummaryTemperature:    sql.NullFloat64{Float64: 28, Valid: true},
+				SummaryWindSpeed:      sql.NullFloat64{Float64: 12, Valid: true},
+				SummaryCondition:      sql.NullString{String: "Sunny", Valid: true},
+				SummaryConditionCode:  sql.NullString{String: "clear", Valid: true},
+			},
+			{Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local), Hours: 24},
+		}, nil)
+		mockWeatherRepo.On("GetWeathers", ctx, mock.Anything).Return([]domain.Weather{
+			{ID: 10, CreatedAt: modifiedAt},
+		}, nil)
+
+		result, err := usecase.GetForecastCalendarUsecase(ctx, dto.GetForecastFeedParam{LocationID: 1, Units: units.Metric, Languages: []string{"id"}, BaseURL: "https://weather.example.com"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, modifiedAt, result.LastModified)
+		body := string(result.Body)
+		assert.Equal(t, 1, strings.Count(body, "BEGIN:VEVENT"))
+		assert.Contains(t, body, "UID:weather-1-20240101@weather-app")
+		assert.Contains(t, body, "SUMMARY:Cerah\\, 24–32 °C")
+		assert.Contains(t, strings.ReplaceAll(body, "\r\n ", ""), "IMAGE;VALUE=URI;DISPLAY=BADGE:https://weather.example.com/api/v1/conditions/clear.svg")
+	})
+}
+
+func TestGetForecastFeedUsecase(t *testing.T) {
+	location := domain.Location{ID: 1, Name: "Jakarta"}
+
+	t.Run("WHEN error occurred on get weathers, THEN should return error accordingly", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{location}, nil)
+		mockWeatherRepo.On("GetWeathers", ctx, mock.Anything).Return(nil, errors.New("database error"))
+
+		_, err := usecase.GetForecastFeedUsecase(ctx, dto.GetForecastFeedParam{LocationID: 1, Units: units.Metric})
+
+		assert.ErrorContains(t, err, "failed to get weathers")
+	})
+
+	t.Run("WHEN day forecasts changed, THEN should render the latest change first", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		modifiedAt := time.Date(2024, 1, 1, 6, 0, 0, 0, time.UTC)
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{location}, nil)
+		mockWeatherRepo.On("GetWeathers", ctx, repository.GetWeathersParam{
+			LocationID:   1,
+			ForecastType: string(domain.ForecastTypeDay),
+			Limit:        50,
+			OrderBy:      "COALESCE(last_modified_at, created_at) DESC, id DESC",
+		}).Return([]domain.Weather{
+			{ID: 11, ForecastTime: createdAt.AddDate(0, 0, 1), ConditionStatus: "Rain", TemperatureCelcius: 26, CreatedAt: createdAt, LastModifiedAt: sql.NullTime{Time: modifiedAt, Valid: true}},
+			{ID: 10, ForecastTime: createdAt, ConditionStatus: "Sunny", ConditionCode: "clear", TemperatureCelcius: 30, CreatedAt: createdAt},
+		}, nil)
+
+		result, err := usecase.GetForecastFeedUsecase(ctx, dto.GetForecastFeedParam{LocationID: 1, Units: units.Metric, SelfURL: "http://localhost/feed"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, modifiedAt, result.LastModified)
+		body := string(result.Body)
+		assert.Contains(t, body, "urn:weather-app:weather:11:1704088800")
+		assert.Contains(t, body, "updated</title>")
+		assert.Contains(t, body, "published</title>")
+		assert.Contains(t, body, "Clear, average 30 °C")
+		assert.NotContains(t, body, "Sunny")
+		assert.Less(t, strings.Index(body, "weather:11:"), strings.Index(body, "weather:10:"))
+	})
+
+	t.Run("WHEN alert rules are configured, THEN should list the rules a day matches", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{location}, nil)
+		mockWeatherRepo.On("GetWeathers", ctx, mock.Anything).Return([]domain.Weather{
+			{ID: 11, ForecastTime: createdAt.AddDate(0, 0, 1), ConditionStatus: "Sunny", TemperatureCelcius: 35, Humidity: 70, CreatedAt: createdAt},
+			{ID: 10, ForecastTime: createdAt, ConditionStatus: "Rain", TemperatureCelcius: 24, Humidity: 90, CreatedAt: createdAt},
+		}, nil)
+
+		rules, err := derive.ParseRules("heatIndex>=40")
+		assert.NoError(t, err)
+
+		result, err := usecase.GetForecastFeedUsecase(ctx, dto.GetForecastFeedParam{LocationID: 1, Units: units.Metric, AlertRules: rules})
+
+		assert.NoError(t, err)
+		body := string(result.Body)
+		assert.Equal(t, 1, strings.Count(body, "alerts heatIndex&gt;=40"))
+		assert.Less(t, strings.Index(body, "alerts"), strings.Index(body, "weather:10:"))
+	})
+}
+
+func TestGetWeatherStatsUsecase(t *testing.T) {
+	location := domain.Location{ID: 1, Name: "Jakarta"}
+	day := func(year int, month time.Month, date int) time.Time {
+		return time.Date(year, month, date, 0, 0, 0, 0, time.UTC)
+	}
+
+	t.Run("WHEN location not found, THEN should return ErrLocationNotFound", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{}, nil)
+
+		_, err := usecase.GetWeatherStatsUsecase(ctx, dto.GetWeatherStatsParam{LocationID: 1})
+
+		assert.ErrorIs(t, err, ErrLocationNotFound)
+	})
+
+	t.Run("WHEN error occurred on get hourly weathers, THEN should return error accordingly", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{location}, nil)
+		mockWeatherRepo.On("StreamWeathers", ctx, mock.Anything, mock.Anything).Return(errors.New("database error"))
+
+		_, err := usecase.GetWeatherStatsUsecase(ctx, dto.GetWeatherStatsParam{LocationID: 1})
+
+		assert.ErrorContains(t, err, "failed to get hourly weathers")
+	})
+
+	t.Run("WHEN only from is given, THEN should use the period containing it", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{location}, nil)
+		mockWeatherRepo.On("StreamWeathers", ctx, repository.StreamWeathersParam{
+			LocationIDs:  []int64{1},
+			From:         day(2024, time.February, 10),
+			To:           day(2024, time.March, 1),
+			ForecastType: "hour",
+		}, mock.Anything).Return(nil)
+		mockWeatherRepo.On("GetDailyWeathers", ctx, mock.Anything).Return([]domain.DailyWeather{}, nil)
+
+		result, err := usecase.GetWeatherStatsUsecase(ctx, dto.GetWeatherStatsParam{
+			LocationID: 1,
+			From:       day(2024, time.February, 10),
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "month", result.Data.Period)
+		assert.Len(t, result.Data.Periods, 1)
+		assert.Empty(t, result.Data.Periods[0].PreviousYears)
+	})
+
+	t.Run("WHEN history is stored, THEN should summarize every period and compare previous years", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		from, to := day(2024, time.January, 30), day(2024, time.February, 2)
+		hourly := []domain.Weather{
+			{ForecastTime: from.Add(12 * time.Hour), TemperatureCelcius: 30, Humidity: 70, WindSpeed: 10},
+			{ForecastTime: from.Add(36 * time.Hour), TemperatureCelcius: 26, Humidity: 90, WindSpeed: 20},
+			{ForecastTime: to.Add(12 * time.Hour), TemperatureCelcius: 28, Humidity: 80, WindSpeed: 15},
+		}
+		daily := []domain.DailyWeather{
+			{Date: from, Hours: 1, MinTemperatureCelcius: 30, MaxTemperatureCelcius: 30, AvgTemperatureCelcius: 30, TotalPrecipitationMM: sql.NullFloat64{Float64: 0.2, Valid: true}},
+			{Date: from.AddDate(0, 0, 1), Hours: 1, MinTemperatureCelcius: 26, MaxTemperatureCelcius: 26, AvgTemperatureCelcius: 26, TotalPrecipitationMM: sql.NullFloat64{Float64: 12, Valid: true}},
+			{Date: to, Hours: 1, MinTemperatureCelcius: 28, MaxTemperatureCelcius: 28, AvgTemperatureCelcius: 28, TotalPrecipitationMM: sql.NullFloat64{Float64: 3, Valid: true}},
+		}
+		lastYear := []domain.DailyWeather{
+			{Date: day(2023, time.January, 30), Hours: 2, AvgTemperatureCelcius: 26, TotalPrecipitationMM: sql.NullFloat64{Float64: 20, Valid: true}},
+			{Date: day(2023, time.January, 31), Hours: 2, AvgTemperatureCelcius: 25, TotalPrecipitationMM: sql.NullFloat64{Float64: 10, Valid: true}},
+		}
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{location}, nil)
+		mockWeatherRepo.On("StreamWeathers", ctx, mock.Anything, mock.Anything).Return(
+			func(_ context.Context, _ repository.StreamWeathersParam, fn func(domain.Weather) error) error {
+				for _, item := range hourly {
+					if err := fn(item); err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+		mockWeatherRepo.On("GetDailyWeathers", ctx, repository.GetDailyWeathersParam{LocationID: 1, From: from, To: to}).Return(daily, nil)
+		mockWeatherRepo.On("GetDailyWeathers", ctx, repository.GetDailyWeathersParam{LocationID: 1, From: from.AddDate(-1, 0, 0), To: to.AddDate(-1, 0, 0)}).Return(lastYear, nil)
+		mockWeatherRepo.On("GetDailyWeathers", ctx, mock.Anything).Return([]domain.DailyWeather{}, nil)
+
+		result, err := usecase.GetWeatherStatsUsecase(ctx, dto.GetWeatherStatsParam{
+			LocationID: 1,
+			Period:     dto.StatsPeriodMonth,
+			From:       from,
+			To:         to,
+			Units:      units.Metric,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Data.Periods, 2)
+
+		january := result.Data.Periods[0]
+		assert.Equal(t, "2024-01-30", january.From)
+		assert.Equal(t, "2024-01-31", january.To)
+		assert.Equal(t, 2, january.Hours)
+		assert.Equal(t, float64(28), january.Temperature.Mean)
+		assert.Equal(t, float64(26.4), january.Temperature.P10)
+		assert.Equal(t, 1, january.RainyDays)
+		assert.Equal(t, 12.2, *january.TotalPrecipitation)
+		assert.Equal(t, "2024-01-30", january.HottestDay.Date)
+		assert.Equal(t, "2024-01-31", january.ColdestDay.Date)
+		assert.Len(t, january.PreviousYears, 1)
+		assert.Equal(t, 2023, january.PreviousYears[0].Year)
+		assert.Equal(t, 25.5, january.PreviousYears[0].AvgTemperature)
+		assert.Equal(t, 2.5, *january.PreviousYears[0].AvgTemperatureDelta)
+		assert.Equal(t, -17.8, *january.PreviousYears[0].TotalPrecipitationDelta)
+		assert.Equal(t, -1, january.PreviousYears[0].RainyDaysDelta)
+
+		february := result.Data.Periods[1]
+		assert.Equal(t, "2024-02-01", february.From)
+		assert.Equal(t, 1, february.Hours)
+		assert.Equal(t, 1, february.RainyDays)
+		assert.Empty(t, february.PreviousYears)
+	})
+}
+
+func TestCompareWeathersUsecase(t *testing.T) {
+	from := time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local)
+	locations := []domain.Location{
+		{ID: 1, Name: "Jakarta"},
+		{ID: 2, Name: "Bandung"},
+	}
+
+	t.Run("WHEN error occurred on get weather series, THEN should return error accordingly", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return(locations, nil)
+		mockWeatherRepo.On("GetWeatherSeries", ctx, mock.Anything).Return(nil, errors.New("database error"))
+
+		_, err := usecase.CompareWeathersUsecase(ctx, dto.CompareWeathersParam{LocationIDs: []int{1, 2}})
+
+		assert.ErrorContains(t, err, "failed to get weather series")
+	})
+
+	t.Run("WHEN dates are empty, THEN should compare tomorrow of the first location", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mocks.NewCacheInterface(t), nil)
+		ctx := context.Background()
+
+		kiritimati := domain.Location{ID: 3, Name: "Kiritimati", Timezone: "Pacific/Kiritimati"}
+		now := time.Now().In(time.FixedZone("LINT", 14*3600))
+		tomorrow := time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{locations[0], kiritimati}, nil)
+		mockWeatherRepo.On("GetWeatherSeries", ctx, repository.GetWeatherSeriesParam{
+			LocationIDs: []int64{1, 3},
+			From:        tomorrow,
+			To:          tomorrow.AddDate(0, 0, 1),
+		}).Return([]domain.Weather{}, nil)
+
+		result, err := usecase.CompareWeathersUsecase(ctx, dto.CompareWeathersParam{LocationIDs: []int{3, 1}, Units: units.Metric})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Data.Times, 24)
+	})
+
+	t.Run("WHEN locations have gaps, THEN should align series and rank by available values", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		to := from.Add(3 * time.Hour)
+		rows := []domain.Weather{
+			{LocationID: 1, ForecastTime: from, TemperatureCelcius: 30, WindSpeed: 10, PrecipitationMM: sql.NullFloat64{Float64: 0, Valid: true}},
+			{LocationID: 1, ForecastTime: from.Add(time.Hour), TemperatureCelcius: 32, WindSpeed: 12, PrecipitationMM: sql.NullFloat64{Float64: 1, Valid: true}},
+			{LocationID: 1, ForecastTime: from.Add(2 * time.Hour), TemperatureCelcius: 31, WindSpeed: 14, PrecipitationMM: sql.NullFloat64{Float64: 2, Valid: true}},
+			{LocationID: 2, ForecastTime: from, TemperatureCelcius: 22, WindSpeed: 5, PrecipitationMM: sql.NullFloat64{Float64: 0.5, Valid: true}},
+			{LocationID: 2, ForecastTime: from.Add(2 * time.Hour), TemperatureCelcius: 24, WindSpeed: 7},
+		}
+
+		mockLocationRepo.On("GetLocations", ctx, repository.GetLocationsParam{IDs: []int64{2, 1, 9}}).Return(locations, nil)
+		mockWeatherRepo.On("GetWeatherSeries", ctx, repository.GetWeatherSeriesParam{
+			LocationIDs: []int64{1, 2},
+			From:        from,
+			To:          to,
+		}).Return(rows, nil)
+
+		result, err := usecase.CompareWeathersUsecase(ctx, dto.CompareWeathersParam{
+			LocationIDs: []int{2, 1, 9, 2},
+			From:        from,
+			To:          to,
+			Metrics:     []string{dto.CompareMetricTemperature, dto.CompareMetricWindSpeed, dto.CompareMetricPrecipitation},
+			Units:       units.Metric,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Data.Times, 3)
+		assert.Equal(t, []int{9}, result.Data.NotFoundLocationIDs)
+		assert.Len(t, result.Data.Locations, 2)
+
+		bandung := result.Data.Locations[0]
+		assert.Equal(t, int64(2), bandung.Location.ID)
+		assert.Equal(t, 1, bandung.Missing)
+		assert.Nil(t, bandung.Series[dto.CompareMetricTemperature][1])
+		assert.Equal(t, 24.0, *bandung.Series[dto.CompareMetricTemperature][2])
+		assert.Nil(t, bandung.Series[dto.CompareMetricPrecipitation][2])
+		assert.NotContains(t, bandung.Series, dto.CompareMetricHumidity)
+
+		assert.Len(t, result.Data.Rankings, 3)
+		warmest := result.Data.Rankings[0]
+		assert.Equal(t, "warmest", warmest.Name)
+		assert.Equal(t, int64(1), warmest.Items[0].LocationID)
+		assert.Equal(t, 31.0, warmest.Items[0].Value)
+		assert.Equal(t, 2, warmest.Items[1].Rank)
+
+		driest := result.Data.Rankings[1]
+		assert.Equal(t, int64(2), driest.Items[0].LocationID)
+		assert.Equal(t, 0.5, driest.Items[0].Value)
+		assert.Equal(t, 1, driest.Items[0].Hours)
+		// a mean, the 3 mm of Jakarta came in 3 hours
+		assert.Equal(t, 1.0, driest.Items[1].Value)
+		assert.Equal(t, 3, driest.Items[1].Hours)
+
+		leastWindy := result.Data.Rankings[2]
+		assert.Equal(t, int64(2), leastWindy.Items[0].LocationID)
+	})
+
+	t.Run("WHEN imperial units are requested, THEN should convert series and rankings", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return(locations[:1], nil)
+		mockWeatherRepo.On("GetWeatherSeries", ctx, mock.Anything).Return([]domain.Weather{
+			{LocationID: 1, ForecastTime: from, TemperatureCelcius: 30, Humidity: 70},
+		}, nil)
+
+		result, err := usecase.CompareWeathersUsecase(ctx, dto.CompareWeathersParam{
+			LocationIDs: []int{1},
+			From:        from,
+			Metrics:     []string{dto.CompareMetricTemperature, dto.CompareMetricHumidity},
+			Units:       units.Imperial,
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Data.Times, 24)
+		assert.Equal(t, 23, result.Data.Locations[0].Missing)
+		assert.Equal(t, 86.0, *result.Data.Locations[0].Series[dto.CompareMetricTemperature][0])
+		assert.Equal(t, 70.0, *result.Data.Locations[0].Series[dto.CompareMetricHumidity][0])
+		assert.Equal(t, 86.0, result.Data.Rankings[0].Items[0].Value)
+		assert.Equal(t, "leastHumid", result.Data.Rankings[1].Name)
+		assert.Equal(t, "°F", result.Data.Units.Temperature)
+	})
+}
+
+func TestGetMapLayerUsecase(t *testing.T) {
+	locations := []domain.Location{
+		{ID: 1, Name: "Bandung", Latitude: -6.9, Longitude: 107.6},
+		{ID: 2, Name: "Jakarta", Latitude: -6.2, Longitude: 106.8},
+	}
+
+	t.Run("WHEN error occurred on get locations, THEN should return error accordingly", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return(nil, errors.New("database error"))
+
+		_, err := usecase.GetMapLayerUsecase(ctx, dto.GetMapLayerParam{Units: units.Metric})
+
+		assert.ErrorContains(t, err, "failed to get locations")
+	})
+
+	t.Run("WHEN error occurred on get latest weathers, THEN should return error accordingly", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return(locations, nil)
+		mockWeatherRepo.On("GetLatestWeathers", ctx, mock.Anything).Return(nil, errors.New("database error"))
+
+		_, err := usecase.GetMapLayerUsecase(ctx, dto.GetMapLayerParam{Units: units.Metric})
+
+		assert.ErrorContains(t, err, "failed to get latest weathers")
+	})
+
+	t.Run("WHEN forecast time is given, THEN should slice the layer at its hour", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		hour := time.Date(2024, 1, 1, 15, 0, 0, 0, time.UTC)
+		createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return(locations, nil)
+		mockWeatherRepo.On("GetLatestWeathers", ctx, repository.GetLatestWeathersParam{Windows: []repository.LatestWeatherWindow{
+			{LocationID: 1, From: hour, To: hour.Add(time.Hour)},
+			{LocationID: 2, From: hour, To: hour.Add(time.Hour)},
+		}}).Return([]domain.Weather{
+			{ID: 10, LocationID: 2, TemperatureCelcius: 30, ConditionStatus: "Sunny", ConditionIconURL: "//cdn/sunny.png", ForecastTime: hour, CreatedAt: createdAt},
+		}, nil)
+
+		result, err := usecase.GetMapLayerUsecase(ctx, dto.GetMapLayerParam{ForecastTime: hour.Add(30 * time.Minute), Units: units.Imperial})
+
+		assert.NoError(t, err)
+		assert.Equal(t, createdAt, result.LastModified)
+		assert.Len(t, result.Collection.Features, 2)
+		assert.NotContains(t, result.Collection.Features[0].Properties, "temperature")
+		assert.Equal(t, 86.0, result.Collection.Features[1].Properties["temperature"])
+		assert.Equal(t, "°F", result.Collection.Features[1].Properties["temperatureUnit"])
+		assert.Equal(t, "Sunny", result.Collection.Features[1].Properties["condition"])
+		assert.Equal(t, []float64{106.8, -6.2}, result.Collection.Features[1].Geometry.Coordinates)
+		assert.False(t, result.Collection.Truncated)
+	})
+
+	t.Run("WHEN no forecast time is given, THEN should bound every location to its recent hours", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		zoned := []domain.Location{
+			{ID: 1, Name: "Honolulu", Timezone: "Pacific/Honolulu"},
+			{ID: 2, Name: "Kiritimati", Timezone: "Pacific/Kiritimati"},
+		}
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return(zoned, nil)
+		mockWeatherRepo.On("GetLatestWeathers", ctx, mock.MatchedBy(func(param repository.GetLatestWeathersParam) bool {
+			if len(param.Windows) != 2 {
+				return false
+			}
+			// the same instant is 24 hours apart on the wall clocks of the two
+			honolulu, kiritimati := param.Windows[0], param.Windows[1]
+			return kiritimati.To.Sub(honolulu.To) == 24*time.Hour &&
+				honolulu.To.Sub(honolulu.From) == utils.MapWeatherMaxAge
+		})).Return(nil, nil)
+
+		result, err := usecase.GetMapLayerUsecase(ctx, dto.GetMapLayerParam{Units: units.Metric})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Collection.Features, 2)
+		assert.NotContains(t, result.Collection.Features[0].Properties, "temperature")
+	})
+
+	t.Run("WHEN no forecast time is given and rows are older than the hour, THEN should be modified at the start of the hour", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		kathmandu := domain.Location{ID: 1, Name: "Kathmandu", Timezone: "Asia/Kathmandu"}
+		zone := locationZone(kathmandu)
+		hour := startOfHour(wallClock(time.Now().In(zone)))
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{kathmandu}, nil)
+		mockWeatherRepo.On("GetLatestWeathers", ctx, mock.Anything).Return([]domain.Weather{
+			{ID: 10, LocationID: 1, TemperatureCelcius: 20, ForecastTime: hour, CreatedAt: time.Now().Add(-2 * time.Hour)},
+		}, nil)
+
+		result, err := usecase.GetMapLayerUsecase(ctx, dto.GetMapLayerParam{Units: units.Metric})
+
+		// the hour of Kathmandu starts at a quarter past the hour of UTC
+		assert.NoError(t, err)
+		assert.True(t, result.LastModified.Equal(zonedTime(hour, zone)))
+		assert.Equal(t, 15, result.LastModified.UTC().Minute())
+	})
+
+	t.Run("WHEN more locations match than the layer holds, THEN should mark it truncated", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		many := make([]domain.Location, utils.MaxMapLocations+1)
+		for i := range many {
+			many[i] = domain.Location{ID: int64(i + 1)}
+		}
+		mockLocationRepo.On("GetLocations", ctx, mock.MatchedBy(func(param repository.GetLocationsParam) bool {
+			return param.Limit == utils.MaxMapLocations+1
+		})).Return(many, nil)
+		mockWeatherRepo.On("GetLatestWeathers", ctx, mock.MatchedBy(func(param repository.GetLatestWeathersParam) bool {
+			return len(param.Windows) == utils.MaxMapLocations
+		})).Return(nil, nil)
+
+		result, err := usecase.GetMapLayerUsecase(ctx, dto.GetMapLayerParam{Units: units.Metric})
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Collection.Features, utils.MaxMapLocations)
+		assert.True(t, result.Collection.Truncated)
+	})
+}
+
+func TestGetWeatherChartUsecase(t *testing.T) {
+	location := domain.Location{ID: 1, Name: "Jakarta"}
+
+	t.Run("WHEN location not found, THEN should return ErrLocationNotFound", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{}, nil)
+
+		_, err := usecase.GetWeatherChartUsecase(ctx, dto.GetWeatherChartParam{LocationID: 1, Units: units.Metric})
+
+		assert.ErrorIs(t, err, ErrLocationNotFound)
+	})
+
+	t.Run("WHEN error occurred on get weather series, THEN should return error accordingly", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{location}, nil)
+		mockWeatherRepo.On("GetWeatherSeries", ctx, mock.Anything).Return(nil, errors.New("database error"))
+
+		_, err := usecase.GetWeatherChartUsecase(ctx, dto.GetWeatherChartParam{LocationID: 1, Units: units.Metric})
+
+		assert.ErrorContains(t, err, "failed to get weather series")
+	})
+
+	t.Run("WHEN parameters are empty, THEN should chart the temperature of the next 72 hours", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		from := startOfHour(wallClock(time.Now().In(locationZone(location))))
+		createdAt := zonedTime(from, locationZone(location)).Add(time.Minute)
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{location}, nil)
+		mockWeatherRepo.On("GetWeatherSeries", ctx, repository.GetWeatherSeriesParam{
+			LocationIDs: []int64{1},
+			From:        from,
+			To:          from.Add(72 * time.Hour),
+		}).Return([]domain.Weather{
+			{LocationID: 1, TemperatureCelcius: 25, ForecastTime: from, CreatedAt: createdAt},
+			{LocationID: 1, TemperatureCelcius: 30, ForecastTime: from.Add(time.Hour), CreatedAt: createdAt},
+		}, nil)
+
+		result, err := usecase.GetWeatherChartUsecase(ctx, dto.GetWeatherChartParam{LocationID: 1, Units: units.Imperial})
+
+		assert.NoError(t, err)
+		assert.Equal(t, createdAt, result.LastModified)
+		body := string(result.Body)
+		assert.Contains(t, body, `width="800" height="300"`)
+		assert.Contains(t, body, "Jakarta temperature (°F)")
+		assert.Contains(t, body, "<path d=\"M")
+	})
+
+	t.Run("WHEN location is far from the server zone, THEN should chart from its current hour", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		kiritimati := domain.Location{ID: 1, Name: "Kiritimati", Timezone: "Pacific/Kiritimati"}
+		zone, err := time.LoadLocation(kiritimati.Timezone)
+		assert.NoError(t, err)
+
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{kiritimati}, nil)
+		mockWeatherRepo.On("GetWeatherSeries", ctx, mock.MatchedBy(func(param repository.GetWeatherSeriesParam) bool {
+			// forecast times are the wall clock of the location labelled UTC
+			now := time.Now().In(zone)
+			hour := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), 0, 0, 0, time.UTC)
+			return param.From.Location() == time.UTC && !param.From.Before(hour) && !param.From.After(hour.Add(time.Hour))
+		})).Return(nil, nil)
+
+		_, err = usecase.GetWeatherChartUsecase(ctx, dto.GetWeatherChartParam{LocationID: 1, Range: 24 * time.Hour, Units: units.Metric})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("WHEN rows were stored before the current hour, THEN should be modified at the start of the hour", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		kiritimati := domain.Location{ID: 1, Name: "Kiritimati", Timezone: "Pacific/Kiritimati"}
+		zone := locationZone(kiritimati)
+		from := startOfHour(wallClock(time.Now().In(zone)))
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{kiritimati}, nil)
+		mockWeatherRepo.On("GetWeatherSeries", ctx, mock.Anything).Return([]domain.Weather{
+			{LocationID: 1, TemperatureCelcius: 25, ForecastTime: from, CreatedAt: time.Now().Add(-2 * time.Hour)},
+		}, nil)
+
+		result, err := usecase.GetWeatherChartUsecase(ctx, dto.GetWeatherChartParam{LocationID: 1, Range: 24 * time.Hour, Units: units.Metric})
+
+		// a client that fetched the chart last hour must not get a 304
+		assert.NoError(t, err)
+		assert.True(t, result.LastModified.Equal(zonedTime(from, zone)))
+		assert.True(t, result.LastModified.After(time.Now().Add(-time.Hour)))
+	})
+
+	t.Run("WHEN metric is precipitation, THEN should render bars", func(t *testing.T) {
+		mockWeatherRepo := mocks.NewWeatherRepositoryInterface(t)
+		mockLocationRepo := mocks.NewLocationRepositoryInterface(t)
+		mockCache := mocks.NewCacheInterface(t)
+
+		usecase := NewWeatherUsecase(mockWeatherRepo, mockLocationRepo, mockCache, nil)
+		ctx := context.Background()
+
+		from := startOfHour(wallClock(time.Now().In(locationZone(location))))
+		mockLocationRepo.On("GetLocations", ctx, mock.Anything).Return([]domain.Location{location}, nil)
+		mockWeatherRepo.On("GetWeatherSeries", ctx, mock.Anything).Return([]domain.Weather{
+			{LocationID: 1, PrecipitationMM: sql.NullFloat64{Float64: 2, Valid: true}, ForecastTime: from},
+		}, nil)
+
+		result, err := usecase.GetWeatherChartUsecase(ctx, dto.GetWeatherChartParam{
+			LocationID: 1,
+			Metric:     dto.CompareMetricPrecipitation,
+			Range:      24 * time.Hour,
+			Theme:      "dark",
+			Units:      units.Metric,
+		})
+
+		assert.NoError(t, err)
+		body := string(result.Body)
+		assert.Contains(t, body, "Jakarta precipitation (mm)")
+		assert.Equal(t, 2, strings.Count(body, "<rect "))
+		assert.NotContains(t, body, "<path")
+	})
+}
+
+func TestGetIconUsecase(t *testing.T) {
+	t.Run("WHEN icon code is invalid, THEN should return not found without fetching", func(t *testing.T) {
+		usecase := NewWeatherUsecase(nil, nil, nil, nil)
+
+		_, err := usecase.GetIconUsecase(context.Background(), "../day-113")
+
+		assert.ErrorIs(t, err, ErrIconNotFound)
+	})
+
+	t.Run("WHEN icon is cached, THEN should return it without fetching", func(t *testing.T) {
+		mockCache := mocks.NewCacheInterface(t)
+		mockClient := mocks.NewWeatherAPIClientInterface(t)
+
+		usecase := NewWeatherUsecase(nil, nil, mockCache, mockClient)
+		ctx := context.Background()
+
+		mockCache.On("Get", ctx, "weather:icon:day-113").Return("png", nil)
+
+		icon, err := usecase.GetIconUsecase(ctx, "day-113")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("png"), icon)
+	})
+
+	t.Run("WHEN icon is not cached, THEN should fetch and cache it", func(t *testing.T) {
+		mockCache := mocks.NewCacheInterface(t)
+		mockClient := mocks.NewWeatherAPIClientInterface(t)
+
+		usecase := NewWeatherUsecase(nil, nil, mockCache, mockClient)
+		ctx := context.Background()
+
+		mockCache.On("Get", ctx, "weather:icon:night-296").Return("", errors.New("redis: nil"))
+		mockClient.On("GetIcon", ctx, "night-296").Return([]byte("png"), nil)
+		mockCache.On("Set", ctx, "weather:icon:night-296", []byte("png"), 30*24*time.Hour).Return(nil)
+
+		icon, err := usecase.GetIconUsecase(ctx, "night-296")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("png"), icon)
+	})
+
+	t.Run("WHEN provider has no such icon, THEN should return not found", func(t *testing.T) {
+		mockCache := mocks.NewCacheInterface(t)
+		mockClient := mocks.NewWeatherAPIClientInterface(t)
+
+		usecase := NewWeatherUsecase(nil, nil, mockCache, mockClient)
+		ctx := context.Background()
+
+		mockCache.On("Get", ctx, "weather:icon:day-999").Return("", errors.New("redis: nil"))
+		mockClient.On("GetIcon", ctx, "day-999").Return(nil, fmt.Errorf("failed to fetch icon: %w", weather.ErrIconNotFound))
+
+		_, err := usecase.GetIconUsecase(ctx, "day-999")
+
+		assert.ErrorIs(t, err, ErrIconNotFound)
+	})
+}
+
+func TestParseAstronomy(t *testing.T) {
+	date := time.Date(2024, 6, 21, 0, 0, 0, 0, time.UTC)
+
+	t.Run("WHEN the sun sets before midnight, THEN should count the daylight between sunrise and sunset", func(t *testing.T) {
+		location := domain.Location{ID: 1, Latitude: -6.2088, Longitude: 106.8456, Timezone: "Asia/Jakarta"}
+
+		result := parseAstronomy(location, date, weather.Astro{Sunrise: "06:01 AM", Sunset: "05:51 PM", MoonPhase: "Full Moon"})
+
+		assert.Equal(t, time.Date(2024, 6, 21, 17, 51, 0, 0, time.UTC), result.Sunset.Time)
+		assert.Equal(t, 11*60+50, result.DaylightMinutes)
+	})
+
+	t.Run("WHEN the sun sets after local midnight, THEN should put the sunset on the next day", func(t *testing.T) {
+		location := domain.Location{ID: 1, Latitude: 65.0121, Longitude: 25.4651, Timezone: "Europe/Helsinki"}
+
+		result := parseAstronomy(location, date, weather.Astro{Sunrise: "02:30 AM", Sunset: "12:40 AM", MoonPhase: "Full Moon"})
+
+		assert.Equal(t, time.Date(2024, 6, 22, 0, 40, 0, 0, time.UTC), result.Sunset.Time)
+		assert.Equal(t, 22*60+10, result.DaylightMinutes)
+		assert.True(t, isDaytime(time.Date(2024, 6, 21, 23, 0, 0, 0, time.UTC), result))
+	})
+}
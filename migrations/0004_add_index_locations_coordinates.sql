@@ -0,0 +1 @@
+CREATE INDEX idx_location_coordinates ON locations(latitude, longitude);
@@ -0,0 +1 @@
+ALTER TABLE locations ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+ALTER TABLE locations ADD COLUMN external_key VARCHAR(100) NULL;
+
+CREATE UNIQUE INDEX idx_location_external_key ON locations(external_key);
@@ -0,0 +1,42 @@
+CREATE TABLE IF NOT EXISTS location_groups (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_modified_at TIMESTAMP NULL,
+    deleted_at TIMESTAMP NULL
+);
+
+CREATE INDEX idx_location_group_name ON location_groups(name);
+
+DELIMITER $$
+
+CREATE TRIGGER trigger_location_groups_last_modified_at
+BEFORE UPDATE ON location_groups
+FOR EACH ROW
+BEGIN
+    SET NEW.last_modified_at = NOW();
+END$$
+
+DELIMITER ;
+
+CREATE TABLE IF NOT EXISTS location_group_members (
+    group_id BIGINT NOT NULL,
+    location_id BIGINT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (group_id, location_id),
+    FOREIGN KEY (group_id) REFERENCES location_groups(id) ON DELETE CASCADE,
+    FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_location_group_member_location ON location_group_members(location_id);
+
+CREATE TABLE IF NOT EXISTS location_tags (
+    location_id BIGINT NOT NULL,
+    tag VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (location_id, tag),
+    FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_location_tag ON location_tags(tag);
@@ -0,0 +1,4 @@
+ALTER TABLE weathers
+    ADD COLUMN precipitation_mm DECIMAL(6,2) NULL AFTER wind_speed,
+    ADD COLUMN pressure_mb DECIMAL(6,1) NULL AFTER precipitation_mm,
+    ADD COLUMN visibility_km DECIMAL(5,1) NULL AFTER pressure_mb;
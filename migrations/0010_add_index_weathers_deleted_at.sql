@@ -0,0 +1 @@
+ALTER TABLE weathers ADD INDEX idx_weathers_deleted_at (deleted_at);
@@ -0,0 +1,4 @@
+ALTER TABLE weathers ADD COLUMN condition_code VARCHAR(32) NOT NULL DEFAULT 'unknown' AFTER condition_status;
+
+-- rows synced before the code was kept are mapped from the provider text by
+-- "weather-cli backfill-conditions", with the same table as the sync
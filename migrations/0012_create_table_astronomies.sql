@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS astronomies (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    location_id BIGINT NOT NULL,
+    date DATE NOT NULL,
+    sunrise TIMESTAMP NULL,
+    sunset TIMESTAMP NULL,
+    moonrise TIMESTAMP NULL,
+    moonset TIMESTAMP NULL,
+    daylight_minutes INT NOT NULL DEFAULT 0,
+    moon_phase VARCHAR(32) NOT NULL DEFAULT '',
+    moon_illumination INT NOT NULL DEFAULT 0,
+    source ENUM('provider', 'computed') NOT NULL DEFAULT 'provider',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    last_modified_at TIMESTAMP NULL,
+    UNIQUE KEY unique_location_date (location_id, date),
+    FOREIGN KEY (location_id) REFERENCES locations(id) ON DELETE CASCADE
+);
+
+DELIMITER $$
+
+CREATE TRIGGER trigger_astronomies_last_modified_at
+BEFORE UPDATE ON astronomies
+FOR EACH ROW
+BEGIN
+    SET NEW.last_modified_at = NOW();
+END$$
+
+DELIMITER ;
@@ -0,0 +1,21 @@
+ALTER TABLE locations ADD COLUMN name_key VARCHAR(255) NULL;
+
+-- the application computes the key the same way, lowercase with runs of
+-- whitespace collapsed to a single space
+UPDATE locations
+SET name_key = CONCAT(
+    LOWER(REGEXP_REPLACE(TRIM(name), '[[:space:]]+', ' ')),
+    '|',
+    LOWER(REGEXP_REPLACE(TRIM(country), '[[:space:]]+', ' '))
+)
+WHERE deleted_at IS NULL;
+
+-- duplicates created before the key existed keep their rows, only the oldest
+-- one owns the key until they are merged
+UPDATE locations l
+JOIN (
+    SELECT name_key, MIN(id) AS id FROM locations WHERE name_key IS NOT NULL GROUP BY name_key
+) owner ON owner.name_key = l.name_key AND owner.id <> l.id
+SET l.name_key = NULL;
+
+CREATE UNIQUE INDEX idx_location_name_key ON locations(name_key);
@@ -0,0 +1,12 @@
+ALTER TABLE location_groups ADD COLUMN name_key VARCHAR(100) NULL;
+
+-- only active groups own their name, a deleted group frees it
+UPDATE location_groups SET name_key = name WHERE deleted_at IS NULL;
+
+UPDATE location_groups g
+JOIN (
+    SELECT name_key, MIN(id) AS id FROM location_groups WHERE name_key IS NOT NULL GROUP BY name_key
+) owner ON owner.name_key = g.name_key AND owner.id <> g.id
+SET g.name_key = NULL;
+
+CREATE UNIQUE INDEX idx_location_group_name_key ON location_groups(name_key);
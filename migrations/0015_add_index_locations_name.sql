@@ -0,0 +1,4 @@
+-- name, country and region filters compare the columns through their case
+-- insensitive collation, so plain indexes serve them
+CREATE INDEX idx_location_name ON locations(name);
+CREATE INDEX idx_location_country_region ON locations(country, region);
@@ -0,0 +1,5 @@
+-- daily rows keep the range of the day, sent by the provider or aggregated
+-- by retention from the hourly rows it deletes
+ALTER TABLE weathers
+    ADD COLUMN min_temperature_celcius DECIMAL(5,2) NULL AFTER temperature_fahrenheit,
+    ADD COLUMN max_temperature_celcius DECIMAL(5,2) NULL AFTER min_temperature_celcius;
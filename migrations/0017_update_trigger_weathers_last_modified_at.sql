@@ -0,0 +1,32 @@
+-- BulkUpsertWeather rewrites every synced row, so the modification time is
+-- only bumped when a value actually changes, otherwise feeds would report
+-- a change on every sync. The condition code follows the provider text, it
+-- is left out so backfilling it is not a forecast change
+DROP TRIGGER IF EXISTS trigger_weather_last_modified_at;
+
+DELIMITER $$
+
+CREATE TRIGGER trigger_weather_last_modified_at
+BEFORE UPDATE ON weathers
+FOR EACH ROW
+BEGIN
+    IF NOT (NEW.location_id <=> OLD.location_id
+        AND NEW.temperature_celcius <=> OLD.temperature_celcius
+        AND NEW.temperature_fahrenheit <=> OLD.temperature_fahrenheit
+        AND NEW.min_temperature_celcius <=> OLD.min_temperature_celcius
+        AND NEW.max_temperature_celcius <=> OLD.max_temperature_celcius
+        AND NEW.humidity <=> OLD.humidity
+        AND NEW.wind_speed <=> OLD.wind_speed
+        AND NEW.precipitation_mm <=> OLD.precipitation_mm
+        AND NEW.pressure_mb <=> OLD.pressure_mb
+        AND NEW.visibility_km <=> OLD.visibility_km
+        AND NEW.condition_status <=> OLD.condition_status
+        AND NEW.condition_icon_url <=> OLD.condition_icon_url
+        AND NEW.forecast_time <=> OLD.forecast_time
+        AND NEW.forecast_type <=> OLD.forecast_type
+        AND NEW.deleted_at <=> OLD.deleted_at) THEN
+        SET NEW.last_modified_at = NOW();
+    END IF;
+END$$
+
+DELIMITER ;
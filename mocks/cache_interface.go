@@ -5,9 +5,9 @@ package mocks
 import (
 	context "context"
 
-	mock "github.com/stretchr/testify/mock"
-
 	time "time"
+
+	mock "github.com/stretchr/testify/mock"
 )
 
 // CacheInterface is an autogenerated mock type for the CacheInterface type
@@ -33,6 +33,31 @@ func (_m *CacheInterface) Close() error {
 	return r0
 }
 
+// Del provides a mock function with given fields: ctx, keys
+func (_m *CacheInterface) Del(ctx context.Context, keys ...string) error {
+	_va := make([]interface{}, len(keys))
+	for _i := range keys {
+		_va[_i] = keys[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Del")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...string) error); ok {
+		r0 = rf(ctx, keys...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Get provides a mock function with given fields: ctx, key
 func (_m *CacheInterface) Get(ctx context.Context, key string) (string, error) {
 	ret := _m.Called(ctx, key)
@@ -61,6 +86,61 @@ func (_m *CacheInterface) Get(ctx context.Context, key string) (string, error) {
 	return r0, r1
 }
 
+// MGet provides a mock function with given fields: ctx, keys
+func (_m *CacheInterface) MGet(ctx context.Context, keys ...string) ([]string, error) {
+	_va := make([]interface{}, len(keys))
+	for _i := range keys {
+		_va[_i] = keys[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MGet")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...string) ([]string, error)); ok {
+		return rf(ctx, keys...)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, ...string) []string); ok {
+		r0 = rf(ctx, keys...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, ...string) error); ok {
+		r1 = rf(ctx, keys...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MSet provides a mock function with given fields: ctx, values, expiration
+func (_m *CacheInterface) MSet(ctx context.Context, values map[string]interface{}, expiration time.Duration) error {
+	ret := _m.Called(ctx, values, expiration)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MSet")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, map[string]interface{}, time.Duration) error); ok {
+		r0 = rf(ctx, values, expiration)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Ping provides a mock function with given fields: ctx
 func (_m *CacheInterface) Ping(ctx context.Context) error {
 	ret := _m.Called(ctx)
@@ -79,6 +159,24 @@ func (_m *CacheInterface) Ping(ctx context.Context) error {
 	return r0
 }
 
+// Publish provides a mock function with given fields: ctx, channel, message
+func (_m *CacheInterface) Publish(ctx context.Context, channel string, message interface{}) error {
+	ret := _m.Called(ctx, channel, message)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Publish")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, interface{}) error); ok {
+		r0 = rf(ctx, channel, message)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Set provides a mock function with given fields: ctx, key, value, expiration
 func (_m *CacheInterface) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
 	ret := _m.Called(ctx, key, value, expiration)
@@ -97,6 +195,26 @@ func (_m *CacheInterface) Set(ctx context.Context, key string, value interface{}
 	return r0
 }
 
+// Subscribe provides a mock function with given fields: ctx, channel
+func (_m *CacheInterface) Subscribe(ctx context.Context, channel string) <-chan string {
+	ret := _m.Called(ctx, channel)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Subscribe")
+	}
+
+	var r0 <-chan string
+	if rf, ok := ret.Get(0).(func(context.Context, string) <-chan string); ok {
+		r0 = rf(ctx, channel)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(<-chan string)
+		}
+	}
+
+	return r0
+}
+
 // NewCacheInterface creates a new instance of CacheInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewCacheInterface(t interface {
@@ -0,0 +1,208 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	domain "tyarus/weather-app/internal/domain"
+	repository "tyarus/weather-app/internal/repository"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LocationGroupRepositoryInterface is an autogenerated mock type for the LocationGroupRepositoryInterface type
+type LocationGroupRepositoryInterface struct {
+	mock.Mock
+}
+
+// AddLocationGroupMembers provides a mock function with given fields: ctx, groupID, locationIDs
+func (_m *LocationGroupRepositoryInterface) AddLocationGroupMembers(ctx context.Context, groupID int64, locationIDs []int64) (int64, error) {
+	ret := _m.Called(ctx, groupID, locationIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddLocationGroupMembers")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []int64) (int64, error)); ok {
+		return rf(ctx, groupID, locationIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []int64) int64); ok {
+		r0 = rf(ctx, groupID, locationIDs)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []int64) error); ok {
+		r1 = rf(ctx, groupID, locationIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteLocationGroup provides a mock function with given fields: ctx, id
+func (_m *LocationGroupRepositoryInterface) DeleteLocationGroup(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteLocationGroup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetLocationGroups provides a mock function with given fields: ctx, param
+func (_m *LocationGroupRepositoryInterface) GetLocationGroups(ctx context.Context, param repository.GetLocationGroupsParam) ([]domain.LocationGroup, error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLocationGroups")
+	}
+
+	var r0 []domain.LocationGroup
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.GetLocationGroupsParam) ([]domain.LocationGroup, error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.GetLocationGroupsParam) []domain.LocationGroup); ok {
+		r0 = rf(ctx, param)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.LocationGroup)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.GetLocationGroupsParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLocationGroupsCount provides a mock function with given fields: ctx
+func (_m *LocationGroupRepositoryInterface) GetLocationGroupsCount(ctx context.Context) (int, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLocationGroupsCount")
+	}
+
+	var r0 int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InsertLocationGroup provides a mock function with given fields: ctx, group
+func (_m *LocationGroupRepositoryInterface) InsertLocationGroup(ctx context.Context, group domain.LocationGroup) (domain.LocationGroup, error) {
+	ret := _m.Called(ctx, group)
+
+	if len(ret) == 0 {
+		panic("no return value specified for InsertLocationGroup")
+	}
+
+	var r0 domain.LocationGroup
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.LocationGroup) (domain.LocationGroup, error)); ok {
+		return rf(ctx, group)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.LocationGroup) domain.LocationGroup); ok {
+		r0 = rf(ctx, group)
+	} else {
+		r0 = ret.Get(0).(domain.LocationGroup)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.LocationGroup) error); ok {
+		r1 = rf(ctx, group)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveLocationGroupMembers provides a mock function with given fields: ctx, groupID, locationIDs
+func (_m *LocationGroupRepositoryInterface) RemoveLocationGroupMembers(ctx context.Context, groupID int64, locationIDs []int64) (int64, error) {
+	ret := _m.Called(ctx, groupID, locationIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveLocationGroupMembers")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []int64) (int64, error)); ok {
+		return rf(ctx, groupID, locationIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []int64) int64); ok {
+		r0 = rf(ctx, groupID, locationIDs)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, []int64) error); ok {
+		r1 = rf(ctx, groupID, locationIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateLocationGroup provides a mock function with given fields: ctx, group
+func (_m *LocationGroupRepositoryInterface) UpdateLocationGroup(ctx context.Context, group domain.LocationGroup) error {
+	ret := _m.Called(ctx, group)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLocationGroup")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.LocationGroup) error); ok {
+		r0 = rf(ctx, group)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewLocationGroupRepositoryInterface creates a new instance of LocationGroupRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLocationGroupRepositoryInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LocationGroupRepositoryInterface {
+	mock := &LocationGroupRepositoryInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -0,0 +1,234 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	dto "tyarus/weather-app/internal/dto"
+	response "tyarus/weather-app/pkg/response"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// LocationGroupUsecaseInterface is an autogenerated mock type for the LocationGroupUsecaseInterface type
+type LocationGroupUsecaseInterface struct {
+	mock.Mock
+}
+
+// AddLocationGroupMembersUsecase provides a mock function with given fields: ctx, req
+func (_m *LocationGroupUsecaseInterface) AddLocationGroupMembersUsecase(ctx context.Context, req dto.LocationGroupMembersRequest) (dto.LocationGroupMembersResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddLocationGroupMembersUsecase")
+	}
+
+	var r0 dto.LocationGroupMembersResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.LocationGroupMembersRequest) (dto.LocationGroupMembersResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.LocationGroupMembersRequest) dto.LocationGroupMembersResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(dto.LocationGroupMembersResponse)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.LocationGroupMembersRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateLocationGroupUsecase provides a mock function with given fields: ctx, req
+func (_m *LocationGroupUsecaseInterface) CreateLocationGroupUsecase(ctx context.Context, req dto.PostLocationGroupRequest) (dto.LocationGroupResponseItem, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateLocationGroupUsecase")
+	}
+
+	var r0 dto.LocationGroupResponseItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.PostLocationGroupRequest) (dto.LocationGroupResponseItem, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.PostLocationGroupRequest) dto.LocationGroupResponseItem); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(dto.LocationGroupResponseItem)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.PostLocationGroupRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteLocationGroupUsecase provides a mock function with given fields: ctx, id
+func (_m *LocationGroupUsecaseInterface) DeleteLocationGroupUsecase(ctx context.Context, id int64) error {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteLocationGroupUsecase")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetLocationGroupUsecase provides a mock function with given fields: ctx, id
+func (_m *LocationGroupUsecaseInterface) GetLocationGroupUsecase(ctx context.Context, id int64) (dto.GetLocationGroupDetailResponse, error) {
+	ret := _m.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLocationGroupUsecase")
+	}
+
+	var r0 dto.GetLocationGroupDetailResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (dto.GetLocationGroupDetailResponse, error)); ok {
+		return rf(ctx, id)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) dto.GetLocationGroupDetailResponse); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Get(0).(dto.GetLocationGroupDetailResponse)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLocationGroupsUsecase provides a mock function with given fields: ctx, param
+func (_m *LocationGroupUsecaseInterface) GetLocationGroupsUsecase(ctx context.Context, param dto.GetLocationGroupsParam) (response.Response[response.PaginationData[dto.LocationGroupResponseItem]], error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLocationGroupsUsecase")
+	}
+
+	var r0 response.Response[response.PaginationData[dto.LocationGroupResponseItem]]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetLocationGroupsParam) (response.Response[response.PaginationData[dto.LocationGroupResponseItem]], error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetLocationGroupsParam) response.Response[response.PaginationData[dto.LocationGroupResponseItem]]); ok {
+		r0 = rf(ctx, param)
+	} else {
+		r0 = ret.Get(0).(response.Response[response.PaginationData[dto.LocationGroupResponseItem]])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.GetLocationGroupsParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveLocationGroupMembersUsecase provides a mock function with given fields: ctx, req
+func (_m *LocationGroupUsecaseInterface) RemoveLocationGroupMembersUsecase(ctx context.Context, req dto.LocationGroupMembersRequest) (dto.LocationGroupMembersResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveLocationGroupMembersUsecase")
+	}
+
+	var r0 dto.LocationGroupMembersResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.LocationGroupMembersRequest) (dto.LocationGroupMembersResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.LocationGroupMembersRequest) dto.LocationGroupMembersResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(dto.LocationGroupMembersResponse)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.LocationGroupMembersRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SyncLocationGroupWeatherUsecase provides a mock function with given fields: ctx, req
+func (_m *LocationGroupUsecaseInterface) SyncLocationGroupWeatherUsecase(ctx context.Context, req dto.PostWeatherSyncUsecaseRequest) error {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SyncLocationGroupWeatherUsecase")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.PostWeatherSyncUsecaseRequest) error); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateLocationGroupUsecase provides a mock function with given fields: ctx, req
+func (_m *LocationGroupUsecaseInterface) UpdateLocationGroupUsecase(ctx context.Context, req dto.PostLocationGroupRequest) (dto.LocationGroupResponseItem, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateLocationGroupUsecase")
+	}
+
+	var r0 dto.LocationGroupResponseItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.PostLocationGroupRequest) (dto.LocationGroupResponseItem, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.PostLocationGroupRequest) dto.LocationGroupResponseItem); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(dto.LocationGroupResponseItem)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.PostLocationGroupRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewLocationGroupUsecaseInterface creates a new instance of LocationGroupUsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewLocationGroupUsecaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *LocationGroupUsecaseInterface {
+	mock := &LocationGroupUsecaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
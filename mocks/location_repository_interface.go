@@ -5,10 +5,9 @@ package mocks
 import (
 	context "context"
 	domain "tyarus/weather-app/internal/domain"
+	repository "tyarus/weather-app/internal/repository"
 
 	mock "github.com/stretchr/testify/mock"
-
-	repository "tyarus/weather-app/internal/repository"
 )
 
 // LocationRepositoryInterface is an autogenerated mock type for the LocationRepositoryInterface type
@@ -16,6 +15,96 @@ type LocationRepositoryInterface struct {
 	mock.Mock
 }
 
+// FindDuplicateLocations provides a mock function with given fields: ctx, param
+func (_m *LocationRepositoryInterface) FindDuplicateLocations(ctx context.Context, param repository.FindDuplicateLocationsParam) ([]domain.Location, error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindDuplicateLocations")
+	}
+
+	var r0 []domain.Location
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.FindDuplicateLocationsParam) ([]domain.Location, error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.FindDuplicateLocationsParam) []domain.Location); ok {
+		r0 = rf(ctx, param)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Location)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.FindDuplicateLocationsParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLocationNames provides a mock function with given fields: ctx, locationIDs
+func (_m *LocationRepositoryInterface) GetLocationNames(ctx context.Context, locationIDs []int64) (map[int64][]domain.LocationName, error) {
+	ret := _m.Called(ctx, locationIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLocationNames")
+	}
+
+	var r0 map[int64][]domain.LocationName
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) (map[int64][]domain.LocationName, error)); ok {
+		return rf(ctx, locationIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) map[int64][]domain.LocationName); ok {
+		r0 = rf(ctx, locationIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int64][]domain.LocationName)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, locationIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLocationTags provides a mock function with given fields: ctx, locationIDs
+func (_m *LocationRepositoryInterface) GetLocationTags(ctx context.Context, locationIDs []int64) (map[int64][]string, error) {
+	ret := _m.Called(ctx, locationIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLocationTags")
+	}
+
+	var r0 map[int64][]string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) (map[int64][]string, error)); ok {
+		return rf(ctx, locationIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []int64) map[int64][]string); ok {
+		r0 = rf(ctx, locationIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int64][]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []int64) error); ok {
+		r1 = rf(ctx, locationIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLocations provides a mock function with given fields: ctx, param
 func (_m *LocationRepositoryInterface) GetLocations(ctx context.Context, param repository.GetLocationsParam) ([]domain.Location, error) {
 	ret := _m.Called(ctx, param)
@@ -46,9 +135,9 @@ func (_m *LocationRepositoryInterface) GetLocations(ctx context.Context, param r
 	return r0, r1
 }
 
-// GetLocationsCount provides a mock function with given fields: ctx
-func (_m *LocationRepositoryInterface) GetLocationsCount(ctx context.Context) (int, error) {
-	ret := _m.Called(ctx)
+// GetLocationsCount provides a mock function with given fields: ctx, param
+func (_m *LocationRepositoryInterface) GetLocationsCount(ctx context.Context, param repository.GetLocationsParam) (int, error) {
+	ret := _m.Called(ctx, param)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetLocationsCount")
@@ -56,17 +145,17 @@ func (_m *LocationRepositoryInterface) GetLocationsCount(ctx context.Context) (i
 
 	var r0 int
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) (int, error)); ok {
-		return rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, repository.GetLocationsParam) (int, error)); ok {
+		return rf(ctx, param)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) int); ok {
-		r0 = rf(ctx)
+	if rf, ok := ret.Get(0).(func(context.Context, repository.GetLocationsParam) int); ok {
+		r0 = rf(ctx, param)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
-		r1 = rf(ctx)
+	if rf, ok := ret.Get(1).(func(context.Context, repository.GetLocationsParam) error); ok {
+		r1 = rf(ctx, param)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -102,6 +191,100 @@ func (_m *LocationRepositoryInterface) InsertLocation(ctx context.Context, locat
 	return r0, r1
 }
 
+// MergeLocations provides a mock function with given fields: ctx, canonicalID, duplicateID
+func (_m *LocationRepositoryInterface) MergeLocations(ctx context.Context, canonicalID int64, duplicateID int64) (repository.MergeLocationsResult, error) {
+	ret := _m.Called(ctx, canonicalID, duplicateID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MergeLocations")
+	}
+
+	var r0 repository.MergeLocationsResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) (repository.MergeLocationsResult, error)); ok {
+		return rf(ctx, canonicalID, duplicateID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int64) repository.MergeLocationsResult); ok {
+		r0 = rf(ctx, canonicalID, duplicateID)
+	} else {
+		r0 = ret.Get(0).(repository.MergeLocationsResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64, int64) error); ok {
+		r1 = rf(ctx, canonicalID, duplicateID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetLocationNames provides a mock function with given fields: ctx, locationID, names
+func (_m *LocationRepositoryInterface) SetLocationNames(ctx context.Context, locationID int64, names []domain.LocationName) error {
+	ret := _m.Called(ctx, locationID, names)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetLocationNames")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []domain.LocationName) error); ok {
+		r0 = rf(ctx, locationID, names)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetLocationTags provides a mock function with given fields: ctx, locationID, tags
+func (_m *LocationRepositoryInterface) SetLocationTags(ctx context.Context, locationID int64, tags []string) error {
+	ret := _m.Called(ctx, locationID, tags)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetLocationTags")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, []string) error); ok {
+		r0 = rf(ctx, locationID, tags)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpsertLocations provides a mock function with given fields: ctx, locations
+func (_m *LocationRepositoryInterface) UpsertLocations(ctx context.Context, locations []domain.Location) ([]repository.UpsertLocationResult, error) {
+	ret := _m.Called(ctx, locations)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpsertLocations")
+	}
+
+	var r0 []repository.UpsertLocationResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.Location) ([]repository.UpsertLocationResult, error)); ok {
+		return rf(ctx, locations)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.Location) []repository.UpsertLocationResult); ok {
+		r0 = rf(ctx, locations)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.UpsertLocationResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []domain.Location) error); ok {
+		r1 = rf(ctx, locations)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewLocationRepositoryInterface creates a new instance of LocationRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewLocationRepositoryInterface(t interface {
@@ -5,10 +5,9 @@ package mocks
 import (
 	context "context"
 	dto "tyarus/weather-app/internal/dto"
+	response "tyarus/weather-app/pkg/response"
 
 	mock "github.com/stretchr/testify/mock"
-
-	response "tyarus/weather-app/pkg/response"
 )
 
 // LocationUsecaseInterface is an autogenerated mock type for the LocationUsecaseInterface type
@@ -44,6 +43,34 @@ func (_m *LocationUsecaseInterface) CreateLocationUsecase(ctx context.Context, r
 	return r0, r1
 }
 
+// ExportLocationsUsecase provides a mock function with given fields: ctx, param
+func (_m *LocationUsecaseInterface) ExportLocationsUsecase(ctx context.Context, param dto.LocationExportParam) (dto.LocationExportPage, error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportLocationsUsecase")
+	}
+
+	var r0 dto.LocationExportPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.LocationExportParam) (dto.LocationExportPage, error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.LocationExportParam) dto.LocationExportPage); ok {
+		r0 = rf(ctx, param)
+	} else {
+		r0 = ret.Get(0).(dto.LocationExportPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.LocationExportParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLocationsUsecase provides a mock function with given fields: ctx, param
 func (_m *LocationUsecaseInterface) GetLocationsUsecase(ctx context.Context, param dto.GetLocationHandlerParam) (response.Response[response.PaginationData[dto.GetLocationHandlerResponseItem]], error) {
 	ret := _m.Called(ctx, param)
@@ -72,6 +99,174 @@ func (_m *LocationUsecaseInterface) GetLocationsUsecase(ctx context.Context, par
 	return r0, r1
 }
 
+// GetNearbyLocationsUsecase provides a mock function with given fields: ctx, param
+func (_m *LocationUsecaseInterface) GetNearbyLocationsUsecase(ctx context.Context, param dto.GetNearbyLocationsParam) (response.Response[[]dto.GetNearbyLocationResponseItem], error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetNearbyLocationsUsecase")
+	}
+
+	var r0 response.Response[[]dto.GetNearbyLocationResponseItem]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetNearbyLocationsParam) (response.Response[[]dto.GetNearbyLocationResponseItem], error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetNearbyLocationsParam) response.Response[[]dto.GetNearbyLocationResponseItem]); ok {
+		r0 = rf(ctx, param)
+	} else {
+		r0 = ret.Get(0).(response.Response[[]dto.GetNearbyLocationResponseItem])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.GetNearbyLocationsParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportLocationsUsecase provides a mock function with given fields: ctx, req
+func (_m *LocationUsecaseInterface) ImportLocationsUsecase(ctx context.Context, req dto.LocationImportRequest) (dto.LocationImportResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ImportLocationsUsecase")
+	}
+
+	var r0 dto.LocationImportResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.LocationImportRequest) (dto.LocationImportResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.LocationImportRequest) dto.LocationImportResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(dto.LocationImportResponse)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.LocationImportRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MergeLocationsUsecase provides a mock function with given fields: ctx, req
+func (_m *LocationUsecaseInterface) MergeLocationsUsecase(ctx context.Context, req dto.PostMergeLocationRequest) (dto.MergeLocationResponse, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MergeLocationsUsecase")
+	}
+
+	var r0 dto.MergeLocationResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.PostMergeLocationRequest) (dto.MergeLocationResponse, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.PostMergeLocationRequest) dto.MergeLocationResponse); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(dto.MergeLocationResponse)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.PostMergeLocationRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetLocationNamesUsecase provides a mock function with given fields: ctx, req
+func (_m *LocationUsecaseInterface) SetLocationNamesUsecase(ctx context.Context, req dto.PutLocationNamesRequest) (dto.GetLocationHandlerResponseItem, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetLocationNamesUsecase")
+	}
+
+	var r0 dto.GetLocationHandlerResponseItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.PutLocationNamesRequest) (dto.GetLocationHandlerResponseItem, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.PutLocationNamesRequest) dto.GetLocationHandlerResponseItem); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(dto.GetLocationHandlerResponseItem)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.PutLocationNamesRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetLocationTagsUsecase provides a mock function with given fields: ctx, req
+func (_m *LocationUsecaseInterface) SetLocationTagsUsecase(ctx context.Context, req dto.PutLocationTagsRequest) (dto.GetLocationHandlerResponseItem, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetLocationTagsUsecase")
+	}
+
+	var r0 dto.GetLocationHandlerResponseItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.PutLocationTagsRequest) (dto.GetLocationHandlerResponseItem, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.PutLocationTagsRequest) dto.GetLocationHandlerResponseItem); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(dto.GetLocationHandlerResponseItem)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.PutLocationTagsRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SuggestLocationsUsecase provides a mock function with given fields: ctx, param
+func (_m *LocationUsecaseInterface) SuggestLocationsUsecase(ctx context.Context, param dto.SuggestLocationsParam) (response.Response[[]dto.LocationSuggestion], error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SuggestLocationsUsecase")
+	}
+
+	var r0 response.Response[[]dto.LocationSuggestion]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.SuggestLocationsParam) (response.Response[[]dto.LocationSuggestion], error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.SuggestLocationsParam) response.Response[[]dto.LocationSuggestion]); ok {
+		r0 = rf(ctx, param)
+	} else {
+		r0 = ret.Get(0).(response.Response[[]dto.LocationSuggestion])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.SuggestLocationsParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewLocationUsecaseInterface creates a new instance of LocationUsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewLocationUsecaseInterface(t interface {
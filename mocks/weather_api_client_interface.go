@@ -4,6 +4,7 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 	weather "tyarus/weather-app/pkg/weather"
 
 	mock "github.com/stretchr/testify/mock"
@@ -44,6 +45,126 @@ func (_m *WeatherAPIClientInterface) GetForecast(ctx context.Context, location s
 	return r0, r1
 }
 
+// GetHistory provides a mock function with given fields: ctx, location, date
+func (_m *WeatherAPIClientInterface) GetHistory(ctx context.Context, location string, date time.Time) (*weather.ForecastResponse, error) {
+	ret := _m.Called(ctx, location, date)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetHistory")
+	}
+
+	var r0 *weather.ForecastResponse
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) (*weather.ForecastResponse, error)); ok {
+		return rf(ctx, location, date)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) *weather.ForecastResponse); ok {
+		r0 = rf(ctx, location, date)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*weather.ForecastResponse)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, location, date)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIcon provides a mock function with given fields: ctx, code
+func (_m *WeatherAPIClientInterface) GetIcon(ctx context.Context, code string) ([]byte, error) {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIcon")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]byte, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
+		r0 = rf(ctx, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTimezone provides a mock function with given fields: ctx, query
+func (_m *WeatherAPIClientInterface) GetTimezone(ctx context.Context, query string) (*weather.Location, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTimezone")
+	}
+
+	var r0 *weather.Location
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (*weather.Location, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) *weather.Location); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*weather.Location)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SearchLocations provides a mock function with given fields: ctx, query
+func (_m *WeatherAPIClientInterface) SearchLocations(ctx context.Context, query string) ([]weather.SearchLocation, error) {
+	ret := _m.Called(ctx, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SearchLocations")
+	}
+
+	var r0 []weather.SearchLocation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]weather.SearchLocation, error)); ok {
+		return rf(ctx, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []weather.SearchLocation); ok {
+		r0 = rf(ctx, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]weather.SearchLocation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, query)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewWeatherAPIClientInterface creates a new instance of WeatherAPIClientInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewWeatherAPIClientInterface(t interface {
@@ -0,0 +1,58 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	io "io"
+	dto "tyarus/weather-app/internal/dto"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WeatherExportUsecaseInterface is an autogenerated mock type for the WeatherExportUsecaseInterface type
+type WeatherExportUsecaseInterface struct {
+	mock.Mock
+}
+
+// ExportWeathersUsecase provides a mock function with given fields: ctx, param, w
+func (_m *WeatherExportUsecaseInterface) ExportWeathersUsecase(ctx context.Context, param dto.ExportWeathersParam, w io.Writer) (dto.ExportWeathersResult, error) {
+	ret := _m.Called(ctx, param, w)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ExportWeathersUsecase")
+	}
+
+	var r0 dto.ExportWeathersResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.ExportWeathersParam, io.Writer) (dto.ExportWeathersResult, error)); ok {
+		return rf(ctx, param, w)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.ExportWeathersParam, io.Writer) dto.ExportWeathersResult); ok {
+		r0 = rf(ctx, param, w)
+	} else {
+		r0 = ret.Get(0).(dto.ExportWeathersResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.ExportWeathersParam, io.Writer) error); ok {
+		r1 = rf(ctx, param, w)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewWeatherExportUsecaseInterface creates a new instance of WeatherExportUsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWeatherExportUsecaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WeatherExportUsecaseInterface {
+	mock := &WeatherExportUsecaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
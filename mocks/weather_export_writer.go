@@ -0,0 +1,64 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	domain "tyarus/weather-app/internal/domain"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WeatherExportWriter is an autogenerated mock type for the WeatherExportWriter type
+type WeatherExportWriter struct {
+	mock.Mock
+}
+
+// Close provides a mock function with no fields
+func (_m *WeatherExportWriter) Close() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Close")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Write provides a mock function with given fields: weather
+func (_m *WeatherExportWriter) Write(weather domain.Weather) error {
+	ret := _m.Called(weather)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Write")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(domain.Weather) error); ok {
+		r0 = rf(weather)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// NewWeatherExportWriter creates a new instance of WeatherExportWriter. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWeatherExportWriter(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WeatherExportWriter {
+	mock := &WeatherExportWriter{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -4,11 +4,11 @@ package mocks
 
 import (
 	context "context"
+	time "time"
 	domain "tyarus/weather-app/internal/domain"
+	repository "tyarus/weather-app/internal/repository"
 
 	mock "github.com/stretchr/testify/mock"
-
-	repository "tyarus/weather-app/internal/repository"
 )
 
 // WeatherRepositoryInterface is an autogenerated mock type for the WeatherRepositoryInterface type
@@ -16,6 +16,24 @@ type WeatherRepositoryInterface struct {
 	mock.Mock
 }
 
+// BulkUpsertAstronomies provides a mock function with given fields: ctx, astronomies
+func (_m *WeatherRepositoryInterface) BulkUpsertAstronomies(ctx context.Context, astronomies []domain.Astronomy) error {
+	ret := _m.Called(ctx, astronomies)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BulkUpsertAstronomies")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.Astronomy) error); ok {
+		r0 = rf(ctx, astronomies)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // BulkUpsertWeather provides a mock function with given fields: ctx, weathers
 func (_m *WeatherRepositoryInterface) BulkUpsertWeather(ctx context.Context, weathers []domain.Weather) ([]domain.Weather, error) {
 	ret := _m.Called(ctx, weathers)
@@ -46,6 +64,298 @@ func (_m *WeatherRepositoryInterface) BulkUpsertWeather(ctx context.Context, wea
 	return r0, r1
 }
 
+// CountWeatherRetention provides a mock function with given fields: ctx, param
+func (_m *WeatherRepositoryInterface) CountWeatherRetention(ctx context.Context, param repository.CountWeatherRetentionParam) (domain.WeatherRetentionCounts, error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountWeatherRetention")
+	}
+
+	var r0 domain.WeatherRetentionCounts
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.CountWeatherRetentionParam) (domain.WeatherRetentionCounts, error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.CountWeatherRetentionParam) domain.WeatherRetentionCounts); ok {
+		r0 = rf(ctx, param)
+	} else {
+		r0 = ret.Get(0).(domain.WeatherRetentionCounts)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.CountWeatherRetentionParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DeleteHourlyWeathers provides a mock function with given fields: ctx, param
+func (_m *WeatherRepositoryInterface) DeleteHourlyWeathers(ctx context.Context, param repository.WeatherRetentionParam) (int64, error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteHourlyWeathers")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.WeatherRetentionParam) (int64, error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.WeatherRetentionParam) int64); ok {
+		r0 = rf(ctx, param)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.WeatherRetentionParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// DownsampleHourlyWeathers provides a mock function with given fields: ctx, param
+func (_m *WeatherRepositoryInterface) DownsampleHourlyWeathers(ctx context.Context, param repository.WeatherRetentionParam) (int64, error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DownsampleHourlyWeathers")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.WeatherRetentionParam) (int64, error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.WeatherRetentionParam) int64); ok {
+		r0 = rf(ctx, param)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.WeatherRetentionParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAstronomies provides a mock function with given fields: ctx, param
+func (_m *WeatherRepositoryInterface) GetAstronomies(ctx context.Context, param repository.GetAstronomiesParam) ([]domain.Astronomy, error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAstronomies")
+	}
+
+	var r0 []domain.Astronomy
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.GetAstronomiesParam) ([]domain.Astronomy, error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.GetAstronomiesParam) []domain.Astronomy); ok {
+		r0 = rf(ctx, param)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Astronomy)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.GetAstronomiesParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDailyWeathers provides a mock function with given fields: ctx, param
+func (_m *WeatherRepositoryInterface) GetDailyWeathers(ctx context.Context, param repository.GetDailyWeathersParam) ([]domain.DailyWeather, error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDailyWeathers")
+	}
+
+	var r0 []domain.DailyWeather
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.GetDailyWeathersParam) ([]domain.DailyWeather, error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.GetDailyWeathersParam) []domain.DailyWeather); ok {
+		r0 = rf(ctx, param)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.DailyWeather)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.GetDailyWeathersParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLatestWeathers provides a mock function with given fields: ctx, param
+func (_m *WeatherRepositoryInterface) GetLatestWeathers(ctx context.Context, param repository.GetLatestWeathersParam) ([]domain.Weather, error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLatestWeathers")
+	}
+
+	var r0 []domain.Weather
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.GetLatestWeathersParam) ([]domain.Weather, error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.GetLatestWeathersParam) []domain.Weather); ok {
+		r0 = rf(ctx, param)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Weather)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.GetLatestWeathersParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetOldestHourlyForecastTime provides a mock function with given fields: ctx, locationID
+func (_m *WeatherRepositoryInterface) GetOldestHourlyForecastTime(ctx context.Context, locationID int64) (time.Time, error) {
+	ret := _m.Called(ctx, locationID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetOldestHourlyForecastTime")
+	}
+
+	var r0 time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (time.Time, error)); ok {
+		return rf(ctx, locationID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) time.Time); ok {
+		r0 = rf(ctx, locationID)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, locationID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetUnknownConditionStatuses provides a mock function with given fields: ctx
+func (_m *WeatherRepositoryInterface) GetUnknownConditionStatuses(ctx context.Context) ([]string, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUnknownConditionStatuses")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]string, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []string); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWeatherSeries provides a mock function with given fields: ctx, param
+func (_m *WeatherRepositoryInterface) GetWeatherSeries(ctx context.Context, param repository.GetWeatherSeriesParam) ([]domain.Weather, error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWeatherSeries")
+	}
+
+	var r0 []domain.Weather
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.GetWeatherSeriesParam) ([]domain.Weather, error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.GetWeatherSeriesParam) []domain.Weather); ok {
+		r0 = rf(ctx, param)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Weather)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.GetWeatherSeriesParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWeatherSummaries provides a mock function with given fields: ctx, param
+func (_m *WeatherRepositoryInterface) GetWeatherSummaries(ctx context.Context, param repository.GetWeatherSummariesParam) ([]domain.Weather, error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWeatherSummaries")
+	}
+
+	var r0 []domain.Weather
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.GetWeatherSummariesParam) ([]domain.Weather, error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.GetWeatherSummariesParam) []domain.Weather); ok {
+		r0 = rf(ctx, param)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Weather)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.GetWeatherSummariesParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetWeathers provides a mock function with given fields: ctx, param
 func (_m *WeatherRepositoryInterface) GetWeathers(ctx context.Context, param repository.GetWeathersParam) ([]domain.Weather, error) {
 	ret := _m.Called(ctx, param)
@@ -104,6 +414,80 @@ func (_m *WeatherRepositoryInterface) GetWeathersCount(ctx context.Context) (int
 	return r0, r1
 }
 
+// PurgeDeletedWeathers provides a mock function with given fields: ctx, param
+func (_m *WeatherRepositoryInterface) PurgeDeletedWeathers(ctx context.Context, param repository.WeatherRetentionParam) (int64, error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeDeletedWeathers")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.WeatherRetentionParam) (int64, error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.WeatherRetentionParam) int64); ok {
+		r0 = rf(ctx, param)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.WeatherRetentionParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// StreamWeathers provides a mock function with given fields: ctx, param, fn
+func (_m *WeatherRepositoryInterface) StreamWeathers(ctx context.Context, param repository.StreamWeathersParam, fn func(domain.Weather) error) error {
+	ret := _m.Called(ctx, param, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StreamWeathers")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.StreamWeathersParam, func(domain.Weather) error) error); ok {
+		r0 = rf(ctx, param, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateConditionCodes provides a mock function with given fields: ctx, param
+func (_m *WeatherRepositoryInterface) UpdateConditionCodes(ctx context.Context, param repository.UpdateConditionCodesParam) (int64, error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateConditionCodes")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, repository.UpdateConditionCodesParam) (int64, error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, repository.UpdateConditionCodesParam) int64); ok {
+		r0 = rf(ctx, param)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, repository.UpdateConditionCodesParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // NewWeatherRepositoryInterface creates a new instance of WeatherRepositoryInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewWeatherRepositoryInterface(t interface {
@@ -0,0 +1,103 @@
+// Code generated by mockery v2.53.5. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+	dto "tyarus/weather-app/internal/dto"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// WeatherRetentionUsecaseInterface is an autogenerated mock type for the WeatherRetentionUsecaseInterface type
+type WeatherRetentionUsecaseInterface struct {
+	mock.Mock
+}
+
+// ApplyWeatherRetentionUsecase provides a mock function with given fields: ctx, req
+func (_m *WeatherRetentionUsecaseInterface) ApplyWeatherRetentionUsecase(ctx context.Context, req dto.ApplyWeatherRetentionRequest) (dto.WeatherRetentionReport, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ApplyWeatherRetentionUsecase")
+	}
+
+	var r0 dto.WeatherRetentionReport
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.ApplyWeatherRetentionRequest) (dto.WeatherRetentionReport, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.ApplyWeatherRetentionRequest) dto.WeatherRetentionReport); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(dto.WeatherRetentionReport)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.ApplyWeatherRetentionRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWeatherRetentionJobUsecase provides a mock function with given fields: ctx
+func (_m *WeatherRetentionUsecaseInterface) GetWeatherRetentionJobUsecase(ctx context.Context) dto.WeatherRetentionJob {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWeatherRetentionJobUsecase")
+	}
+
+	var r0 dto.WeatherRetentionJob
+	if rf, ok := ret.Get(0).(func(context.Context) dto.WeatherRetentionJob); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(dto.WeatherRetentionJob)
+	}
+
+	return r0
+}
+
+// StartWeatherRetentionUsecase provides a mock function with given fields: ctx, req
+func (_m *WeatherRetentionUsecaseInterface) StartWeatherRetentionUsecase(ctx context.Context, req dto.ApplyWeatherRetentionRequest) (dto.WeatherRetentionJob, error) {
+	ret := _m.Called(ctx, req)
+
+	if len(ret) == 0 {
+		panic("no return value specified for StartWeatherRetentionUsecase")
+	}
+
+	var r0 dto.WeatherRetentionJob
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.ApplyWeatherRetentionRequest) (dto.WeatherRetentionJob, error)); ok {
+		return rf(ctx, req)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.ApplyWeatherRetentionRequest) dto.WeatherRetentionJob); ok {
+		r0 = rf(ctx, req)
+	} else {
+		r0 = ret.Get(0).(dto.WeatherRetentionJob)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.ApplyWeatherRetentionRequest) error); ok {
+		r1 = rf(ctx, req)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// NewWeatherRetentionUsecaseInterface creates a new instance of WeatherRetentionUsecaseInterface. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewWeatherRetentionUsecaseInterface(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *WeatherRetentionUsecaseInterface {
+	mock := &WeatherRetentionUsecaseInterface{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
@@ -5,10 +5,9 @@ package mocks
 import (
 	context "context"
 	dto "tyarus/weather-app/internal/dto"
+	response "tyarus/weather-app/pkg/response"
 
 	mock "github.com/stretchr/testify/mock"
-
-	response "tyarus/weather-app/pkg/response"
 )
 
 // WeatherUsecaseInterface is an autogenerated mock type for the WeatherUsecaseInterface type
@@ -16,6 +15,344 @@ type WeatherUsecaseInterface struct {
 	mock.Mock
 }
 
+// BackfillConditionCodesUsecase provides a mock function with given fields: ctx
+func (_m *WeatherUsecaseInterface) BackfillConditionCodesUsecase(ctx context.Context) (int64, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BackfillConditionCodesUsecase")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// BackfillWeatherUsecase provides a mock function with given fields: ctx, param
+func (_m *WeatherUsecaseInterface) BackfillWeatherUsecase(ctx context.Context, param dto.BackfillWeatherParam) (dto.BackfillWeatherResult, error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for BackfillWeatherUsecase")
+	}
+
+	var r0 dto.BackfillWeatherResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.BackfillWeatherParam) (dto.BackfillWeatherResult, error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.BackfillWeatherParam) dto.BackfillWeatherResult); ok {
+		r0 = rf(ctx, param)
+	} else {
+		r0 = ret.Get(0).(dto.BackfillWeatherResult)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.BackfillWeatherParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CompareWeathersUsecase provides a mock function with given fields: ctx, param
+func (_m *WeatherUsecaseInterface) CompareWeathersUsecase(ctx context.Context, param dto.CompareWeathersParam) (response.Response[dto.CompareWeathersResponse], error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompareWeathersUsecase")
+	}
+
+	var r0 response.Response[dto.CompareWeathersResponse]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.CompareWeathersParam) (response.Response[dto.CompareWeathersResponse], error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.CompareWeathersParam) response.Response[dto.CompareWeathersResponse]); ok {
+		r0 = rf(ctx, param)
+	} else {
+		r0 = ret.Get(0).(response.Response[dto.CompareWeathersResponse])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.CompareWeathersParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDailyWeatherUsecase provides a mock function with given fields: ctx, param
+func (_m *WeatherUsecaseInterface) GetDailyWeatherUsecase(ctx context.Context, param dto.GetDailyWeatherParam) (response.Response[dto.GetDailyWeatherResponse], error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDailyWeatherUsecase")
+	}
+
+	var r0 response.Response[dto.GetDailyWeatherResponse]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetDailyWeatherParam) (response.Response[dto.GetDailyWeatherResponse], error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetDailyWeatherParam) response.Response[dto.GetDailyWeatherResponse]); ok {
+		r0 = rf(ctx, param)
+	} else {
+		r0 = ret.Get(0).(response.Response[dto.GetDailyWeatherResponse])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.GetDailyWeatherParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetForecastCalendarUsecase provides a mock function with given fields: ctx, param
+func (_m *WeatherUsecaseInterface) GetForecastCalendarUsecase(ctx context.Context, param dto.GetForecastFeedParam) (dto.ForecastFeedDocument, error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForecastCalendarUsecase")
+	}
+
+	var r0 dto.ForecastFeedDocument
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetForecastFeedParam) (dto.ForecastFeedDocument, error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetForecastFeedParam) dto.ForecastFeedDocument); ok {
+		r0 = rf(ctx, param)
+	} else {
+		r0 = ret.Get(0).(dto.ForecastFeedDocument)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.GetForecastFeedParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetForecastFeedUsecase provides a mock function with given fields: ctx, param
+func (_m *WeatherUsecaseInterface) GetForecastFeedUsecase(ctx context.Context, param dto.GetForecastFeedParam) (dto.ForecastFeedDocument, error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForecastFeedUsecase")
+	}
+
+	var r0 dto.ForecastFeedDocument
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetForecastFeedParam) (dto.ForecastFeedDocument, error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetForecastFeedParam) dto.ForecastFeedDocument); ok {
+		r0 = rf(ctx, param)
+	} else {
+		r0 = ret.Get(0).(dto.ForecastFeedDocument)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.GetForecastFeedParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIconUsecase provides a mock function with given fields: ctx, code
+func (_m *WeatherUsecaseInterface) GetIconUsecase(ctx context.Context, code string) ([]byte, error) {
+	ret := _m.Called(ctx, code)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetIconUsecase")
+	}
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]byte, error)); ok {
+		return rf(ctx, code)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []byte); ok {
+		r0 = rf(ctx, code)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, code)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMapLayerUsecase provides a mock function with given fields: ctx, param
+func (_m *WeatherUsecaseInterface) GetMapLayerUsecase(ctx context.Context, param dto.GetMapLayerParam) (dto.MapLayer, error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetMapLayerUsecase")
+	}
+
+	var r0 dto.MapLayer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetMapLayerParam) (dto.MapLayer, error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetMapLayerParam) dto.MapLayer); ok {
+		r0 = rf(ctx, param)
+	} else {
+		r0 = ret.Get(0).(dto.MapLayer)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.GetMapLayerParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetPointWeatherUsecase provides a mock function with given fields: ctx, param
+func (_m *WeatherUsecaseInterface) GetPointWeatherUsecase(ctx context.Context, param dto.GetPointWeatherParam) (response.Response[dto.GetPointWeatherResponse], error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetPointWeatherUsecase")
+	}
+
+	var r0 response.Response[dto.GetPointWeatherResponse]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetPointWeatherParam) (response.Response[dto.GetPointWeatherResponse], error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetPointWeatherParam) response.Response[dto.GetPointWeatherResponse]); ok {
+		r0 = rf(ctx, param)
+	} else {
+		r0 = ret.Get(0).(response.Response[dto.GetPointWeatherResponse])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.GetPointWeatherParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWeatherChartUsecase provides a mock function with given fields: ctx, param
+func (_m *WeatherUsecaseInterface) GetWeatherChartUsecase(ctx context.Context, param dto.GetWeatherChartParam) (dto.WeatherChart, error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWeatherChartUsecase")
+	}
+
+	var r0 dto.WeatherChart
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetWeatherChartParam) (dto.WeatherChart, error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetWeatherChartParam) dto.WeatherChart); ok {
+		r0 = rf(ctx, param)
+	} else {
+		r0 = ret.Get(0).(dto.WeatherChart)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.GetWeatherChartParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWeatherStatsUsecase provides a mock function with given fields: ctx, param
+func (_m *WeatherUsecaseInterface) GetWeatherStatsUsecase(ctx context.Context, param dto.GetWeatherStatsParam) (response.Response[dto.GetWeatherStatsResponse], error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWeatherStatsUsecase")
+	}
+
+	var r0 response.Response[dto.GetWeatherStatsResponse]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetWeatherStatsParam) (response.Response[dto.GetWeatherStatsResponse], error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetWeatherStatsParam) response.Response[dto.GetWeatherStatsResponse]); ok {
+		r0 = rf(ctx, param)
+	} else {
+		r0 = ret.Get(0).(response.Response[dto.GetWeatherStatsResponse])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.GetWeatherStatsParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetWeathersBatchUsecase provides a mock function with given fields: ctx, param
+func (_m *WeatherUsecaseInterface) GetWeathersBatchUsecase(ctx context.Context, param dto.GetWeathersBatchParam) (response.Response[dto.GetWeathersBatchResponse], error) {
+	ret := _m.Called(ctx, param)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetWeathersBatchUsecase")
+	}
+
+	var r0 response.Response[dto.GetWeathersBatchResponse]
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetWeathersBatchParam) (response.Response[dto.GetWeathersBatchResponse], error)); ok {
+		return rf(ctx, param)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, dto.GetWeathersBatchParam) response.Response[dto.GetWeathersBatchResponse]); ok {
+		r0 = rf(ctx, param)
+	} else {
+		r0 = ret.Get(0).(response.Response[dto.GetWeathersBatchResponse])
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, dto.GetWeathersBatchParam) error); ok {
+		r1 = rf(ctx, param)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetWeathersUsecase provides a mock function with given fields: ctx, req
 func (_m *WeatherUsecaseInterface) GetWeathersUsecase(ctx context.Context, req dto.GetWeathersParam) (response.Response[dto.GetWeatherResponse], error) {
 	ret := _m.Called(ctx, req)
@@ -0,0 +1,117 @@
+package astronomy
+
+import (
+	"math"
+	"time"
+)
+
+// Sun is the sunrise and sunset of a day. On a polar day or night the sun
+// does not cross the horizon, both times are zero and AlwaysUp tells which.
+type Sun struct {
+	Sunrise  time.Time
+	Sunset   time.Time
+	AlwaysUp bool
+}
+
+// Daylight is how long the sun is above the horizon.
+func (s Sun) Daylight() time.Duration {
+	if s.Sunrise.IsZero() || s.Sunset.IsZero() {
+		if s.AlwaysUp {
+			return 24 * time.Hour
+		}
+		return 0
+	}
+	return s.Sunset.Sub(s.Sunrise)
+}
+
+const (
+	julianUnixEpoch = 2440587.5
+	julian2000      = 2451545.0
+	// sunrise is when the upper limb of the sun, refracted, touches the
+	// horizon
+	horizonDegrees  = -0.833
+	obliquityDegree = 23.4397
+)
+
+// SunTimes computes the sunrise and sunset of the calendar day of date at a
+// coordinate with the sunrise equation, accurate to a minute or two away
+// from the poles. The times are in the location of date.
+func SunTimes(date time.Time, latitude, longitude float64) Sun {
+	days := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC).
+		Sub(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)).Hours() / 24
+
+	// mean solar noon, in days since J2000
+	noon := days - longitude/360
+	anomaly := radians(math.Mod(357.5291+0.98560028*noon, 360))
+	center := 1.9148*math.Sin(anomaly) + 0.02*math.Sin(2*anomaly) + 0.0003*math.Sin(3*anomaly)
+	ecliptic := radians(math.Mod(degrees(anomaly)+center+180+102.9372, 360))
+	transit := julian2000 + noon + 0.0053*math.Sin(anomaly) - 0.0069*math.Sin(2*ecliptic)
+
+	declination := math.Asin(math.Sin(ecliptic) * math.Sin(radians(obliquityDegree)))
+	lat := radians(latitude)
+	cosHourAngle := (math.Sin(radians(horizonDegrees)) - math.Sin(lat)*math.Sin(declination)) /
+		(math.Cos(lat) * math.Cos(declination))
+	switch {
+	case cosHourAngle < -1:
+		return Sun{AlwaysUp: true}
+	case cosHourAngle > 1:
+		return Sun{}
+	}
+
+	hourAngle := degrees(math.Acos(cosHourAngle)) / 360
+	return Sun{
+		Sunrise: fromJulian(transit-hourAngle, date.Location()),
+		Sunset:  fromJulian(transit+hourAngle, date.Location()),
+	}
+}
+
+// Moon phase names, the same as the ones of weatherapi.com.
+const (
+	NewMoon        = "New Moon"
+	WaxingCrescent = "Waxing Crescent"
+	FirstQuarter   = "First Quarter"
+	WaxingGibbous  = "Waxing Gibbous"
+	FullMoon       = "Full Moon"
+	WaningGibbous  = "Waning Gibbous"
+	LastQuarter    = "Last Quarter"
+	WaningCrescent = "Waning Crescent"
+)
+
+const synodicMonthDays = 29.530588853
+
+// knownNewMoon is a new moon to count lunations from.
+var knownNewMoon = time.Date(2000, 1, 6, 18, 14, 0, 0, time.UTC)
+
+var moonPhases = []string{
+	NewMoon, WaxingCrescent, FirstQuarter, WaxingGibbous,
+	FullMoon, WaningGibbous, LastQuarter, WaningCrescent,
+}
+
+// MoonPhase returns the phase of the moon at t and the illuminated share of
+// its disc in percent, from the mean length of a lunation.
+func MoonPhase(t time.Time) (string, int) {
+	age := math.Mod(t.Sub(knownNewMoon).Hours()/24, synodicMonthDays)
+	if age < 0 {
+		age += synodicMonthDays
+	}
+
+	fraction := age / synodicMonthDays
+	illumination := (1 - math.Cos(2*math.Pi*fraction)) / 2 * 100
+
+	// every phase is centred on its eighth of the lunation
+	index := int(math.Floor(fraction*8+0.5)) % len(moonPhases)
+	return moonPhases[index], int(math.Round(illumination))
+}
+
+func fromJulian(julian float64, loc *time.Location) time.Time {
+	seconds := (julian - julianUnixEpoch) * 86400
+	return time.Unix(int64(math.Round(seconds)), 0).In(loc)
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func degrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
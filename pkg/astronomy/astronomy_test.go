@@ -0,0 +1,73 @@
+package astronomy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSunTimes(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*3600)
+	london := time.FixedZone("BST", 3600)
+	tests := []struct {
+		name    string
+		date    time.Time
+		lat     float64
+		lon     float64
+		sunrise string
+		sunset  string
+	}{
+		{"Jakarta", time.Date(2024, 1, 1, 0, 0, 0, 0, jakarta), -6.2, 106.85, "05:41", "18:10"},
+		{"London", time.Date(2024, 6, 21, 0, 0, 0, 0, london), 51.51, -0.13, "04:43", "21:21"},
+		{"Los Angeles", time.Date(2024, 12, 21, 0, 0, 0, 0, time.FixedZone("PST", -8*3600)), 34.05, -118.24, "06:55", "16:47"},
+	}
+
+	for _, tt := range tests {
+		sun := SunTimes(tt.date, tt.lat, tt.lon)
+
+		assert.InDelta(t, clock(t, tt.date, tt.sunrise).Unix(), sun.Sunrise.Unix(), 120, tt.name+" sunrise "+sun.Sunrise.String())
+		assert.InDelta(t, clock(t, tt.date, tt.sunset).Unix(), sun.Sunset.Unix(), 120, tt.name+" sunset "+sun.Sunset.String())
+		assert.Equal(t, tt.date.Location(), sun.Sunrise.Location())
+	}
+}
+
+func TestSunTimesPolar(t *testing.T) {
+	tromso := time.FixedZone("CET", 3600)
+
+	summer := SunTimes(time.Date(2024, 6, 21, 0, 0, 0, 0, tromso), 69.65, 18.96)
+	assert.True(t, summer.Sunrise.IsZero())
+	assert.True(t, summer.AlwaysUp)
+	assert.Equal(t, 24*time.Hour, summer.Daylight())
+
+	winter := SunTimes(time.Date(2024, 12, 21, 0, 0, 0, 0, tromso), 69.65, 18.96)
+	assert.True(t, winter.Sunset.IsZero())
+	assert.False(t, winter.AlwaysUp)
+	assert.Equal(t, time.Duration(0), winter.Daylight())
+}
+
+func TestMoonPhase(t *testing.T) {
+	tests := []struct {
+		time         time.Time
+		phase        string
+		illumination int
+	}{
+		{time.Date(2024, 1, 11, 11, 57, 0, 0, time.UTC), NewMoon, 0},
+		{time.Date(2024, 1, 25, 17, 54, 0, 0, time.UTC), FullMoon, 100},
+		{time.Date(2024, 1, 18, 3, 52, 0, 0, time.UTC), FirstQuarter, 50},
+		{time.Date(2024, 1, 4, 3, 30, 0, 0, time.UTC), LastQuarter, 50},
+	}
+
+	for _, tt := range tests {
+		phase, illumination := MoonPhase(tt.time)
+
+		assert.Equal(t, tt.phase, phase, tt.time.String())
+		assert.InDelta(t, tt.illumination, illumination, 6, tt.time.String())
+	}
+}
+
+func clock(t *testing.T, date time.Time, value string) time.Time {
+	parsed, err := time.Parse("15:04", value)
+	assert.NoError(t, err)
+	return time.Date(date.Year(), date.Month(), date.Day(), parsed.Hour(), parsed.Minute(), 0, 0, date.Location())
+}
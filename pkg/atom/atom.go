@@ -0,0 +1,95 @@
+package atom
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+const namespace = "http://www.w3.org/2005/Atom"
+
+// Feed is an Atom (RFC 4287) feed of plain text entries.
+type Feed struct {
+	ID      string
+	Title   string
+	SelfURL string
+	Author  string
+	Updated time.Time
+	Entries []Entry
+}
+
+type Entry struct {
+	ID      string
+	Title   string
+	Updated time.Time
+	Content string
+	// Icon is the absolute URL of an image of the entry, linked as an
+	// enclosure when set
+	Icon string
+}
+
+type xmlFeed struct {
+	XMLName xml.Name   `xml:"feed"`
+	Xmlns   string     `xml:"xmlns,attr"`
+	ID      string     `xml:"id"`
+	Title   string     `xml:"title"`
+	Updated string     `xml:"updated"`
+	Link    *xmlLink   `xml:"link,omitempty"`
+	Author  xmlAuthor  `xml:"author"`
+	Entries []xmlEntry `xml:"entry"`
+}
+
+type xmlLink struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+}
+
+type xmlAuthor struct {
+	Name string `xml:"name"`
+}
+
+type xmlEntry struct {
+	ID      string     `xml:"id"`
+	Title   string     `xml:"title"`
+	Updated string     `xml:"updated"`
+	Link    *xmlLink   `xml:"link,omitempty"`
+	Content xmlContent `xml:"content"`
+}
+
+type xmlContent struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+// Marshal renders the feed as an XML document.
+func (f Feed) Marshal() ([]byte, error) {
+	feed := xmlFeed{
+		Xmlns:   namespace,
+		ID:      f.ID,
+		Title:   f.Title,
+		Updated: f.Updated.UTC().Format(time.RFC3339),
+		Author:  xmlAuthor{Name: f.Author},
+	}
+	if f.SelfURL != "" {
+		feed.Link = &xmlLink{Rel: "self", Href: f.SelfURL}
+	}
+
+	for _, entry := range f.Entries {
+		item := xmlEntry{
+			ID:      entry.ID,
+			Title:   entry.Title,
+			Updated: entry.Updated.UTC().Format(time.RFC3339),
+			Content: xmlContent{Type: "text", Text: entry.Content},
+		}
+		if entry.Icon != "" {
+			item.Link = &xmlLink{Rel: "enclosure", Href: entry.Icon}
+		}
+		feed.Entries = append(feed.Entries, item)
+	}
+
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), append(body, '\n')...), nil
+}
@@ -0,0 +1,49 @@
+package atom
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshal(t *testing.T) {
+	t.Run("WHEN feed has entries, THEN should render an Atom document", func(t *testing.T) {
+		feed := Feed{
+			ID:      "urn:weather-app:location:1:forecast",
+			Title:   "Jakarta forecast",
+			SelfURL: "http://localhost/api/v1/locations/1/forecast.atom",
+			Author:  "weather-app",
+			Updated: time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC),
+			Entries: []Entry{{
+				ID:      "urn:weather-app:weather:5:1704105000",
+				Title:   "Forecast for 2024-01-02 updated",
+				Updated: time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC),
+				Content: "Rain & thunder, 24–31 °C",
+				Icon:    "http://localhost/api/v1/conditions/thunderstorm.svg",
+			}},
+		}
+
+		body, err := feed.Marshal()
+
+		assert.NoError(t, err)
+		assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <id>urn:weather-app:location:1:forecast</id>
+  <title>Jakarta forecast</title>
+  <updated>2024-01-01T10:30:00Z</updated>
+  <link rel="self" href="http://localhost/api/v1/locations/1/forecast.atom"></link>
+  <author>
+    <name>weather-app</name>
+  </author>
+  <entry>
+    <id>urn:weather-app:weather:5:1704105000</id>
+    <title>Forecast for 2024-01-02 updated</title>
+    <updated>2024-01-01T10:30:00Z</updated>
+    <link rel="enclosure" href="http://localhost/api/v1/conditions/thunderstorm.svg"></link>
+    <content type="text">Rain &amp; thunder, 24–31 °C</content>
+  </entry>
+</feed>
+`, string(body))
+	})
+}
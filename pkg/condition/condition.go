@@ -0,0 +1,161 @@
+package condition
+
+import (
+	"strings"
+	"tyarus/weather-app/pkg/language"
+)
+
+// Code is a provider independent weather condition.
+type Code string
+
+const (
+	Clear        Code = "clear"
+	PartlyCloudy Code = "partly_cloudy"
+	Cloudy       Code = "cloudy"
+	Overcast     Code = "overcast"
+	Fog          Code = "fog"
+	Drizzle      Code = "drizzle"
+	Rain         Code = "rain"
+	HeavyRain    Code = "heavy_rain"
+	FreezingRain Code = "freezing_rain"
+	Sleet        Code = "sleet"
+	Snow         Code = "snow"
+	HeavySnow    Code = "heavy_snow"
+	Thunderstorm Code = "thunderstorm"
+	Unknown      Code = "unknown"
+)
+
+// Codes lists every code from the calmest to the most severe, Unknown last.
+var Codes = []Code{
+	Clear, PartlyCloudy, Cloudy, Overcast, Fog, Drizzle, Rain, HeavyRain,
+	FreezingRain, Sleet, Snow, HeavySnow, Thunderstorm, Unknown,
+}
+
+// Languages lists the languages with translations, the first one is the
+// fallback.
+var Languages = []string{"en", "id"}
+
+var translations = map[Code][]string{
+	Clear:        {"Clear", "Cerah"},
+	PartlyCloudy: {"Partly cloudy", "Cerah berawan"},
+	Cloudy:       {"Cloudy", "Berawan"},
+	Overcast:     {"Overcast", "Mendung"},
+	Fog:          {"Fog", "Berkabut"},
+	Drizzle:      {"Drizzle", "Gerimis"},
+	Rain:         {"Rain", "Hujan"},
+	HeavyRain:    {"Heavy rain", "Hujan lebat"},
+	FreezingRain: {"Freezing rain", "Hujan beku"},
+	Sleet:        {"Sleet", "Hujan es"},
+	Snow:         {"Snow", "Salju"},
+	HeavySnow:    {"Heavy snow", "Salju lebat"},
+	Thunderstorm: {"Thunderstorm", "Badai petir"},
+	Unknown:      {"Unknown", "Tidak diketahui"},
+}
+
+// Valid reports whether the code is part of the taxonomy.
+func (c Code) Valid() bool {
+	_, ok := translations[c]
+	return ok
+}
+
+// Text returns the name of the code in the first of the preferred
+// normalized language tags having a translation, English otherwise.
+func (c Code) Text(preferred []string) string {
+	names, ok := translations[c]
+	if !ok {
+		names = translations[Unknown]
+	}
+
+	if i, ok := language.Match(preferred, Languages); ok {
+		return names[i]
+	}
+	return names[0]
+}
+
+// weatherAPIConditions are the condition codes of weatherapi.com with the
+// day text of each, https://www.weatherapi.com/docs/weather_conditions.json
+var weatherAPIConditions = []struct {
+	code int
+	text string
+	to   Code
+}{
+	{1000, "Sunny", Clear},
+	{1003, "Partly cloudy", PartlyCloudy},
+	{1006, "Cloudy", Cloudy},
+	{1009, "Overcast", Overcast},
+	{1030, "Mist", Fog},
+	{1063, "Patchy rain possible", Drizzle},
+	{1066, "Patchy snow possible", Snow},
+	{1069, "Patchy sleet possible", Sleet},
+	{1072, "Patchy freezing drizzle possible", FreezingRain},
+	{1087, "Thundery outbreaks possible", Thunderstorm},
+	{1114, "Blowing snow", HeavySnow},
+	{1117, "Blizzard", HeavySnow},
+	{1135, "Fog", Fog},
+	{1147, "Freezing fog", Fog},
+	{1150, "Patchy light drizzle", Drizzle},
+	{1153, "Light drizzle", Drizzle},
+	{1168, "Freezing drizzle", FreezingRain},
+	{1171, "Heavy freezing drizzle", FreezingRain},
+	{1180, "Patchy light rain", Rain},
+	{1183, "Light rain", Rain},
+	{1186, "Moderate rain at times", Rain},
+	{1189, "Moderate rain", Rain},
+	{1192, "Heavy rain at times", HeavyRain},
+	{1195, "Heavy rain", HeavyRain},
+	{1198, "Light freezing rain", FreezingRain},
+	{1201, "Moderate or heavy freezing rain", FreezingRain},
+	{1204, "Light sleet", Sleet},
+	{1207, "Moderate or heavy sleet", Sleet},
+	{1210, "Patchy light snow", Snow},
+	{1213, "Light snow", Snow},
+	{1216, "Patchy moderate snow", Snow},
+	{1219, "Moderate snow", Snow},
+	{1222, "Patchy heavy snow", HeavySnow},
+	{1225, "Heavy snow", HeavySnow},
+	{1237, "Ice pellets", Sleet},
+	{1240, "Light rain shower", Rain},
+	{1243, "Moderate or heavy rain shower", HeavyRain},
+	{1246, "Torrential rain shower", HeavyRain},
+	{1249, "Light sleet showers", Sleet},
+	{1252, "Moderate or heavy sleet showers", Sleet},
+	{1255, "Light snow showers", Snow},
+	{1258, "Moderate or heavy snow showers", HeavySnow},
+	{1261, "Light showers of ice pellets", Sleet},
+	{1264, "Moderate or heavy showers of ice pellets", Sleet},
+	{1273, "Patchy light rain with thunder", Thunderstorm},
+	{1276, "Moderate or heavy rain with thunder", Thunderstorm},
+	{1279, "Patchy light snow with thunder", Thunderstorm},
+	{1282, "Moderate or heavy snow with thunder", Thunderstorm},
+}
+
+var (
+	byWeatherAPICode = map[int]Code{}
+	byWeatherAPIText = map[string]Code{"clear": Clear}
+)
+
+func init() {
+	for _, item := range weatherAPIConditions {
+		byWeatherAPICode[item.code] = item.to
+		byWeatherAPIText[strings.ToLower(item.text)] = item.to
+	}
+}
+
+// FromWeatherAPI maps a weatherapi.com condition code, unknown codes are
+// Unknown.
+func FromWeatherAPI(code int) Code {
+	if to, ok := byWeatherAPICode[code]; ok {
+		return to
+	}
+	return Unknown
+}
+
+// FromWeatherAPIText maps the English condition text of weatherapi.com, for
+// rows stored before codes were kept. Night texts only differ for code 1000,
+// "Clear", and the provider pads some texts with spaces.
+func FromWeatherAPIText(text string) Code {
+	if to, ok := byWeatherAPIText[strings.ToLower(strings.TrimSpace(text))]; ok {
+		return to
+	}
+	return Unknown
+}
@@ -0,0 +1,63 @@
+package condition
+
+import (
+	"encoding/xml"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromWeatherAPI(t *testing.T) {
+	t.Run("WHEN code is known, THEN should map it to the taxonomy", func(t *testing.T) {
+		assert.Equal(t, Clear, FromWeatherAPI(1000))
+		assert.Equal(t, Fog, FromWeatherAPI(1030))
+		assert.Equal(t, HeavyRain, FromWeatherAPI(1246))
+		assert.Equal(t, Thunderstorm, FromWeatherAPI(1279))
+	})
+
+	t.Run("WHEN code is unknown, THEN should return Unknown", func(t *testing.T) {
+		assert.Equal(t, Unknown, FromWeatherAPI(0))
+		assert.Equal(t, Unknown, FromWeatherAPI(9999))
+	})
+}
+
+func TestFromWeatherAPIText(t *testing.T) {
+	t.Run("WHEN text is a provider text, THEN should map it whatever the case and padding", func(t *testing.T) {
+		assert.Equal(t, Clear, FromWeatherAPIText("Clear "))
+		assert.Equal(t, Rain, FromWeatherAPIText("patchy light rain"))
+		assert.Equal(t, Unknown, FromWeatherAPIText("Raining cats and dogs"))
+	})
+}
+
+func TestText(t *testing.T) {
+	t.Run("WHEN language has translations, THEN should return the translation", func(t *testing.T) {
+		assert.Equal(t, "Hujan lebat", HeavyRain.Text([]string{"id-id"}))
+		assert.Equal(t, "Heavy rain", HeavyRain.Text([]string{"fr", "en-gb"}))
+	})
+
+	t.Run("WHEN language or code is unknown, THEN should fall back", func(t *testing.T) {
+		assert.Equal(t, "Thunderstorm", Thunderstorm.Text(nil))
+		assert.Equal(t, "Tidak diketahui", Code("tornado").Text([]string{"id"}))
+	})
+}
+
+func TestIcon(t *testing.T) {
+	t.Run("WHEN code is any code, THEN should render well-formed SVG", func(t *testing.T) {
+		for _, code := range append(Codes, "tornado") {
+			var document struct {
+				XMLName xml.Name
+			}
+			assert.NoError(t, xml.Unmarshal(Icon(code), &document), code)
+			assert.Equal(t, "svg", document.XMLName.Local, code)
+		}
+	})
+
+	t.Run("WHEN codes differ, THEN should render different icons", func(t *testing.T) {
+		seen := map[string]Code{}
+		for _, code := range Codes {
+			icon := string(Icon(code))
+			assert.NotContains(t, seen, icon, "%s looks like %s", code, seen[icon])
+			seen[icon] = code
+		}
+	})
+}
@@ -0,0 +1,86 @@
+package condition
+
+import (
+	"fmt"
+	"strings"
+)
+
+// icon parts are drawn on a 64 by 64 canvas, the cloud covers the upper
+// two thirds and precipitation falls below it.
+const (
+	iconSun       = `<g stroke="#f2a900" stroke-width="3" stroke-linecap="round"><path d="M32 6v6M32 52v6M6 32h6M52 32h6M13.6 13.6l4.3 4.3M46.1 46.1l4.3 4.3M13.6 50.4l4.3-4.3M46.1 17.9l4.3-4.3"/></g><circle cx="32" cy="32" r="12" fill="#f2c200"/>`
+	iconSmallSun  = `<g stroke="#f2a900" stroke-width="2.5" stroke-linecap="round"><path d="M22 4v4M8 18h4M12.1 8.1l2.8 2.8M31.9 8.1l-2.8 2.8"/></g><circle cx="22" cy="20" r="8" fill="#f2c200"/>`
+	iconCloud     = `<path d="M18 44a10 10 0 0 1 0-20 14 14 0 0 1 27-3 11 11 0 0 1 1 23z" fill="%s"/>`
+	iconDrop      = `<path d="M%d %dl-3 7" stroke="#2f80ed" stroke-width="3" stroke-linecap="round"/>`
+	iconFlake     = `<circle cx="%d" cy="%d" r="2.5" fill="#9ab8d8"/>`
+	iconBolt      = `<path d="M34 40l-8 12h7l-3 10 10-14h-7l4-8z" fill="#f2c200"/>`
+	iconFog       = `<g stroke="#9aa5b1" stroke-width="3" stroke-linecap="round"><path d="M12 24h40M8 34h48M12 44h40M16 54h32"/></g>`
+	iconQuestion  = `<circle cx="32" cy="32" r="24" fill="#cbd2d9"/><text x="32" y="42" font-family="sans-serif" font-size="30" font-weight="bold" text-anchor="middle" fill="#52606d">?</text>`
+	cloudLight    = "#cbd2d9"
+	cloudDark     = "#9aa5b1"
+	cloudStormy   = "#7b8794"
+	iconSVGPrefix = `<svg xmlns="http://www.w3.org/2000/svg" width="64" height="64" viewBox="0 0 64 64">`
+)
+
+// Icon returns the SVG icon of the code, unknown codes get a question mark.
+func Icon(code Code) []byte {
+	var parts []string
+	cloud := func(color string) {
+		parts = append(parts, fmt.Sprintf(iconCloud, color))
+	}
+	drops := func(xs ...int) {
+		for _, x := range xs {
+			parts = append(parts, fmt.Sprintf(iconDrop, x, 50))
+		}
+	}
+	flakes := func(xs ...int) {
+		for i, x := range xs {
+			parts = append(parts, fmt.Sprintf(iconFlake, x, 52+4*(i%2)))
+		}
+	}
+
+	switch code {
+	case Clear:
+		parts = append(parts, iconSun)
+	case PartlyCloudy:
+		parts = append(parts, iconSmallSun)
+		cloud(cloudLight)
+	case Cloudy:
+		cloud(cloudLight)
+	case Overcast:
+		cloud(cloudDark)
+	case Fog:
+		parts = append(parts, iconFog)
+	case Drizzle:
+		cloud(cloudLight)
+		drops(26, 40)
+	case Rain:
+		cloud(cloudDark)
+		drops(22, 33, 44)
+	case HeavyRain:
+		cloud(cloudStormy)
+		drops(17, 25, 33, 41, 49)
+	case FreezingRain:
+		cloud(cloudDark)
+		drops(24, 42)
+		flakes(33)
+	case Sleet:
+		cloud(cloudDark)
+		drops(22, 40)
+		flakes(31, 49)
+	case Snow:
+		cloud(cloudLight)
+		flakes(22, 32, 42)
+	case HeavySnow:
+		cloud(cloudDark)
+		flakes(16, 24, 32, 40, 48)
+	case Thunderstorm:
+		cloud(cloudStormy)
+		parts = append(parts, iconBolt)
+		drops(20, 46)
+	default:
+		parts = append(parts, iconQuestion)
+	}
+
+	return []byte(iconSVGPrefix + strings.Join(parts, "") + "</svg>\n")
+}
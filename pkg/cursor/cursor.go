@@ -0,0 +1,39 @@
+package cursor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor parameter, please check your parameter")
+
+// Cursor points at a row of a keyset paginated listing: the value of the sort
+// column and the id of the row. Backward cursors list the rows before it.
+type Cursor struct {
+	SortBy   string `json:"s"`
+	Value    string `json:"v"`
+	ID       int64  `json:"id"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+// Encode returns the cursor as an opaque URL safe token.
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode parses a token created by Encode.
+func Decode(token string) (Cursor, error) {
+	var c Cursor
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(data, &c); err != nil || c.ID <= 0 {
+		return c, ErrInvalidCursor
+	}
+
+	return c, nil
+}
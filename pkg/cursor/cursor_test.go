@@ -0,0 +1,30 @@
+package cursor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecode(t *testing.T) {
+	t.Run("WHEN token was created by Encode, THEN should return the same cursor", func(t *testing.T) {
+		c := Cursor{SortBy: "name_ascend", Value: "Bandung", ID: 2, Backward: true}
+
+		decoded, err := Decode(c.Encode())
+
+		assert.NoError(t, err)
+		assert.Equal(t, c, decoded)
+	})
+
+	t.Run("WHEN token is not base64, THEN should return ErrInvalidCursor", func(t *testing.T) {
+		_, err := Decode("not a cursor!")
+
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+
+	t.Run("WHEN token has no id, THEN should return ErrInvalidCursor", func(t *testing.T) {
+		_, err := Decode(Cursor{SortBy: "name_ascend", Value: "Bandung"}.Encode())
+
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
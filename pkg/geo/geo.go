@@ -0,0 +1,59 @@
+package geo
+
+import "math"
+
+const EarthRadiusKm float64 = 6371.0088
+
+type BoundingBox struct {
+	MinLatitude  float64
+	MaxLatitude  float64
+	MinLongitude float64
+	MaxLongitude float64
+}
+
+// Distance returns the great-circle distance in kilometers between two
+// coordinates using the haversine formula.
+func Distance(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1, phi2 := toRadians(lat1), toRadians(lat2)
+	deltaPhi := toRadians(lat2 - lat1)
+	deltaLambda := toRadians(lon2 - lon1)
+
+	a := math.Sin(deltaPhi/2)*math.Sin(deltaPhi/2) +
+		math.Cos(phi1)*math.Cos(phi2)*math.Sin(deltaLambda/2)*math.Sin(deltaLambda/2)
+	return 2 * EarthRadiusKm * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// BoundingBoxAround returns the smallest latitude/longitude box containing the
+// circle of radiusKm around the given point. The box falls back to the full
+// longitude range when the circle touches a pole or crosses the antimeridian,
+// so it is always safe to use as a prefilter.
+func BoundingBoxAround(lat, lon, radiusKm float64) BoundingBox {
+	deltaLat := toDegrees(radiusKm / EarthRadiusKm)
+	box := BoundingBox{
+		MinLatitude:  math.Max(-90, lat-deltaLat),
+		MaxLatitude:  math.Min(90, lat+deltaLat),
+		MinLongitude: -180,
+		MaxLongitude: 180,
+	}
+
+	if box.MinLatitude == -90 || box.MaxLatitude == 90 {
+		return box
+	}
+
+	deltaLon := toDegrees(math.Asin(math.Min(1, math.Sin(radiusKm/EarthRadiusKm)/math.Cos(toRadians(lat)))))
+	if lon-deltaLon < -180 || lon+deltaLon > 180 {
+		return box
+	}
+
+	box.MinLongitude = lon - deltaLon
+	box.MaxLongitude = lon + deltaLon
+	return box
+}
+
+func toRadians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+func toDegrees(rad float64) float64 {
+	return rad * 180 / math.Pi
+}
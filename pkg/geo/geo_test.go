@@ -0,0 +1,41 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistance(t *testing.T) {
+	t.Run("WHEN both points are equal, THEN should return zero", func(t *testing.T) {
+		assert.Equal(t, float64(0), Distance(-6.2088, 106.8456, -6.2088, 106.8456))
+	})
+
+	t.Run("WHEN points are Jakarta and Bandung, THEN should return great-circle distance", func(t *testing.T) {
+		assert.InDelta(t, 116.0, Distance(-6.2088, 106.8456, -6.9175, 107.6191), 1.0)
+	})
+}
+
+func TestBoundingBoxAround(t *testing.T) {
+	t.Run("WHEN radius is small, THEN should contain points within the radius", func(t *testing.T) {
+		box := BoundingBoxAround(-6.2088, 106.8456, 150)
+
+		assert.True(t, box.MinLatitude < -6.9175 && -6.9175 < box.MaxLatitude)
+		assert.True(t, box.MinLongitude < 107.6191 && 107.6191 < box.MaxLongitude)
+		assert.False(t, box.MinLongitude < 112.7521 && 112.7521 < box.MaxLongitude)
+	})
+
+	t.Run("WHEN circle crosses the antimeridian, THEN should use full longitude range", func(t *testing.T) {
+		box := BoundingBoxAround(0, 179.9, 50)
+
+		assert.Equal(t, float64(-180), box.MinLongitude)
+		assert.Equal(t, float64(180), box.MaxLongitude)
+	})
+
+	t.Run("WHEN circle reaches a pole, THEN should use full longitude range", func(t *testing.T) {
+		box := BoundingBoxAround(89.9, 0, 50)
+
+		assert.Equal(t, float64(90), box.MaxLatitude)
+		assert.Equal(t, float64(-180), box.MinLongitude)
+	})
+}
@@ -0,0 +1,64 @@
+package geojson
+
+import (
+	"errors"
+)
+
+const (
+	TypeFeatureCollection = "FeatureCollection"
+	TypeFeature           = "Feature"
+	TypePoint             = "Point"
+)
+
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+	// Truncated is a foreign member, RFC 7946 section 6.1, set when features
+	// were left out to bound the collection
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+type Feature struct {
+	Type       string                 `json:"type"`
+	ID         interface{}            `json:"id,omitempty"`
+	Geometry   *Geometry              `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Geometry only supports points, coordinates are [longitude, latitude] as
+// defined by RFC 7946.
+type Geometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+func NewFeatureCollection(features []Feature) FeatureCollection {
+	if features == nil {
+		features = []Feature{}
+	}
+	return FeatureCollection{Type: TypeFeatureCollection, Features: features}
+}
+
+func NewPointFeature(id interface{}, lat, lon float64, properties map[string]interface{}) Feature {
+	return Feature{
+		Type:       TypeFeature,
+		ID:         id,
+		Geometry:   &Geometry{Type: TypePoint, Coordinates: []float64{lon, lat}},
+		Properties: properties,
+	}
+}
+
+// Point returns the latitude and longitude of a point feature.
+func (f Feature) Point() (lat, lon float64, err error) {
+	if f.Geometry == nil || f.Geometry.Type != TypePoint || len(f.Geometry.Coordinates) < 2 {
+		return 0, 0, errors.New("feature geometry must be a point")
+	}
+	return f.Geometry.Coordinates[1], f.Geometry.Coordinates[0], nil
+}
+
+// StringProperty returns the property as string, or empty when it is missing
+// or not a string.
+func (f Feature) StringProperty(key string) string {
+	value, _ := f.Properties[key].(string)
+	return value
+}
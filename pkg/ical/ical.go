@@ -0,0 +1,116 @@
+package ical
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Calendar is a published iCalendar (RFC 5545) of all-day events.
+type Calendar struct {
+	ProdID string
+	Name   string
+	// RefreshInterval tells subscribed clients how often to fetch again
+	RefreshInterval time.Duration
+	Events          []Event
+}
+
+// Event is an all-day event on Date, in the calendar's local time.
+type Event struct {
+	UID         string
+	Date        time.Time
+	Stamp       time.Time
+	Summary     string
+	Description string
+	// Image is the absolute URL of an icon shown next to the event (RFC 7986)
+	Image string
+}
+
+// Marshal renders the calendar with CRLF line endings and lines folded at 75
+// octets.
+func (c Calendar) Marshal() []byte {
+	var buf bytes.Buffer
+	write := func(line string) {
+		buf.WriteString(fold(line))
+		buf.WriteString("\r\n")
+	}
+
+	write("BEGIN:VCALENDAR")
+	write("VERSION:2.0")
+	write("PRODID:" + c.ProdID)
+	write("CALSCALE:GREGORIAN")
+	write("METHOD:PUBLISH")
+	if c.Name != "" {
+		write("X-WR-CALNAME:" + Escape(c.Name))
+	}
+	if c.RefreshInterval > 0 {
+		duration := formatDuration(c.RefreshInterval)
+		write("REFRESH-INTERVAL;VALUE=DURATION:" + duration)
+		write("X-PUBLISHED-TTL:" + duration)
+	}
+
+	for _, event := range c.Events {
+		write("BEGIN:VEVENT")
+		write("UID:" + event.UID)
+		write("DTSTAMP:" + event.Stamp.UTC().Format("20060102T150405Z"))
+		write("DTSTART;VALUE=DATE:" + event.Date.Format("20060102"))
+		write("DTEND;VALUE=DATE:" + event.Date.AddDate(0, 0, 1).Format("20060102"))
+		write("SUMMARY:" + Escape(event.Summary))
+		if event.Description != "" {
+			write("DESCRIPTION:" + Escape(event.Description))
+		}
+		if event.Image != "" {
+			write("IMAGE;VALUE=URI;DISPLAY=BADGE:" + event.Image)
+		}
+		write("TRANSP:TRANSPARENT")
+		write("END:VEVENT")
+	}
+
+	write("END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// formatDuration renders a duration in whole minutes, e.g. PT1H30M.
+func formatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	result := "PT"
+	if minutes >= 60 {
+		result += strconv.Itoa(minutes/60) + "H"
+	}
+	if minutes%60 > 0 || minutes < 60 {
+		result += strconv.Itoa(minutes%60) + "M"
+	}
+	return result
+}
+
+var escaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// Escape escapes a TEXT property value.
+func Escape(value string) string {
+	return escaper.Replace(value)
+}
+
+// fold splits a content line into lines of at most 75 octets, continuation
+// lines start with a space. Multi-byte characters are never split.
+func fold(line string) string {
+	if len(line) <= 75 {
+		return line
+	}
+
+	var buf strings.Builder
+	limit := 75
+	size := 0
+	for _, r := range line {
+		length := len(string(r))
+		if size+length > limit {
+			buf.WriteString("\r\n ")
+			// the leading space counts towards the next line
+			limit = 74
+			size = 0
+		}
+		buf.WriteRune(r)
+		size += length
+	}
+	return buf.String()
+}
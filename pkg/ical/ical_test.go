@@ -0,0 +1,74 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshal(t *testing.T) {
+	t.Run("WHEN calendar has an event, THEN should render an all-day event with CRLF", func(t *testing.T) {
+		calendar := Calendar{
+			ProdID:          "-//weather-app//forecast//EN",
+			Name:            "Jakarta, Indonesia",
+			RefreshInterval: time.Hour,
+			Events: []Event{{
+				UID:         "weather-1-20240102@weather-app",
+				Date:        time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+				Stamp:       time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC),
+				Summary:     "Sunny, 24–31 °C",
+				Description: "Wind up to 20 km/h; dry",
+				Image:       "http://localhost/api/v1/conditions/clear.svg",
+			}},
+		}
+
+		expected := strings.Join([]string{
+			"BEGIN:VCALENDAR",
+			"VERSION:2.0",
+			"PRODID:-//weather-app//forecast//EN",
+			"CALSCALE:GREGORIAN",
+			"METHOD:PUBLISH",
+			`X-WR-CALNAME:Jakarta\, Indonesia`,
+			"REFRESH-INTERVAL;VALUE=DURATION:PT1H",
+			"X-PUBLISHED-TTL:PT1H",
+			"BEGIN:VEVENT",
+			"UID:weather-1-20240102@weather-app",
+			"DTSTAMP:20240101T103000Z",
+			"DTSTART;VALUE=DATE:20240102",
+			"DTEND;VALUE=DATE:20240103",
+			`SUMMARY:Sunny\, 24–31 °C`,
+			`DESCRIPTION:Wind up to 20 km/h\; dry`,
+			"IMAGE;VALUE=URI;DISPLAY=BADGE:http://localhost/api/v1/conditions/clear.svg",
+			"TRANSP:TRANSPARENT",
+			"END:VEVENT",
+			"END:VCALENDAR",
+			"",
+		}, "\r\n")
+		assert.Equal(t, expected, string(calendar.Marshal()))
+	})
+
+	t.Run("WHEN refresh interval has minutes, THEN should render a duration", func(t *testing.T) {
+		calendar := Calendar{ProdID: "-//test//EN", RefreshInterval: 90 * time.Minute}
+
+		assert.Contains(t, string(calendar.Marshal()), "REFRESH-INTERVAL;VALUE=DURATION:PT1H30M\r\n")
+	})
+}
+
+func TestFold(t *testing.T) {
+	t.Run("WHEN line is longer than 75 octets, THEN should fold without splitting characters", func(t *testing.T) {
+		line := "SUMMARY:" + strings.Repeat("é", 60)
+
+		folded := fold(line)
+
+		for _, part := range strings.Split(folded, "\r\n") {
+			assert.LessOrEqual(t, len(part), 75)
+		}
+		assert.Equal(t, line, strings.ReplaceAll(folded, "\r\n ", ""))
+	})
+
+	t.Run("WHEN value has special characters, THEN should escape them", func(t *testing.T) {
+		assert.Equal(t, `a\\b\;c\,d\ne`, Escape("a\\b;c,d\ne"))
+	})
+}
@@ -0,0 +1,96 @@
+package language
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var tagPattern = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})*$`)
+
+// Normalize lowercases the tag and uses "-" as separator, so "id_ID" and
+// "id-id" are the same tag.
+func Normalize(tag string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(tag)), "_", "-")
+}
+
+// Valid reports whether the normalized tag looks like a BCP 47 language tag,
+// e.g. "id", "en-us" or "zh-hant".
+func Valid(tag string) bool {
+	return tagPattern.MatchString(tag)
+}
+
+// Base returns the primary language of the tag, "en" for "en-us".
+func Base(tag string) string {
+	base, _, _ := strings.Cut(tag, "-")
+	return base
+}
+
+// ParseAcceptLanguage returns the normalized tags of an Accept-Language
+// header, most preferred first. Invalid tags, "*" and tags with q=0 are
+// dropped.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	items := []weighted{}
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = Normalize(tag)
+		if !Valid(tag) {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.TrimSpace(key) != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				parsed = 0
+			}
+			q = parsed
+		}
+
+		if q > 0 {
+			items = append(items, weighted{tag: tag, q: q})
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].q > items[j].q
+	})
+
+	tags := make([]string, len(items))
+	for i, item := range items {
+		tags[i] = item.tag
+	}
+
+	return tags
+}
+
+// Match returns the index of the available tag that best serves the
+// preferred tags, trying each preferred tag in order. An exact match wins
+// over one sharing only the base language, e.g. "id-id" is served by "id".
+func Match(preferred, available []string) (int, bool) {
+	for _, tag := range preferred {
+		for i, candidate := range available {
+			if candidate == tag {
+				return i, true
+			}
+		}
+
+		for i, candidate := range available {
+			if candidate != "" && Base(candidate) == Base(tag) {
+				return i, true
+			}
+		}
+	}
+
+	return -1, false
+}
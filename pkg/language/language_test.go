@@ -0,0 +1,44 @@
+package language
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	t.Run("WHEN header has weights, THEN should order the tags by weight", func(t *testing.T) {
+		tags := ParseAcceptLanguage("en;q=0.8, id-ID, fr;q=0, *;q=0.1, de;q=0.8")
+
+		assert.Equal(t, []string{"id-id", "en", "de"}, tags)
+	})
+
+	t.Run("WHEN header is empty or invalid, THEN should return no tags", func(t *testing.T) {
+		assert.Empty(t, ParseAcceptLanguage(""))
+		assert.Empty(t, ParseAcceptLanguage("not a language, 12"))
+	})
+}
+
+func TestMatch(t *testing.T) {
+	available := []string{"", "en", "id", "id-id"}
+
+	t.Run("WHEN preferred tag exists, THEN should match it exactly", func(t *testing.T) {
+		i, ok := Match([]string{"id-id"}, available)
+
+		assert.True(t, ok)
+		assert.Equal(t, 3, i)
+	})
+
+	t.Run("WHEN only the base language exists, THEN should fall back to it", func(t *testing.T) {
+		i, ok := Match([]string{"fr", "en-gb"}, available)
+
+		assert.True(t, ok)
+		assert.Equal(t, 1, i)
+	})
+
+	t.Run("WHEN nothing matches, THEN should not match aliases without language", func(t *testing.T) {
+		_, ok := Match([]string{"fr"}, available)
+
+		assert.False(t, ok)
+	})
+}
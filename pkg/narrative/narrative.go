@@ -0,0 +1,206 @@
+package narrative
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Hour is an hourly forecast in metric units.
+type Hour struct {
+	Time            time.Time
+	TemperatureC    float64
+	Humidity        int
+	WindKph         float64
+	PrecipitationMM float64
+	Condition       string
+}
+
+const (
+	hotCelsius   = 32
+	warmCelsius  = 25
+	mildCelsius  = 18
+	coolCelsius  = 10
+	humidPercent = 70
+	dryPercent   = 35
+	windyKph     = 35
+	rainMM       = 0.5
+	trendCelsius = 3
+)
+
+const (
+	EventThunderstorms = "thunderstorms"
+	EventSnow          = "snow"
+	EventRain          = "rain"
+	EventFog           = "fog"
+)
+
+// events are in priority order, a day with rain and thunder is described by
+// its thunderstorms.
+var events = []struct {
+	name     string
+	keywords []string
+}{
+	{EventThunderstorms, []string{"thunder"}},
+	{EventSnow, []string{"snow", "sleet", "blizzard", "ice"}},
+	{EventRain, []string{"rain", "drizzle", "shower"}},
+	{EventFog, []string{"fog", "mist"}},
+}
+
+// day holds the facts the templates render, in no particular language.
+type day struct {
+	Feel  []string
+	Event string
+	// After is when Event starts, nil when it already does at the first hour
+	After *time.Time
+}
+
+type facts struct {
+	Today    *day
+	Tomorrow *day
+	Trend    string
+}
+
+// Summarize describes the rest of today and tomorrow, the days of now, in a
+// short sentence of the language, e.g. "Hot and humid today with
+// thunderstorms after 3 PM; cooler tomorrow", in the first of the preferred
+// languages having templates, English otherwise. It is empty when hours has
+// no row for either day.
+func Summarize(hours []Hour, now time.Time, preferred []string) string {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	tomorrow := today.AddDate(0, 0, 1)
+	currentHour := time.Date(now.Year(), now.Month(), now.Day(), now.Hour(), 0, 0, 0, now.Location())
+
+	var todayHours, remainingHours, tomorrowHours []Hour
+	for _, hour := range hours {
+		switch {
+		case hour.Time.Before(today):
+		case hour.Time.Before(tomorrow):
+			todayHours = append(todayHours, hour)
+			if !hour.Time.Before(currentHour) {
+				remainingHours = append(remainingHours, hour)
+			}
+		case hour.Time.Before(tomorrow.AddDate(0, 0, 1)):
+			tomorrowHours = append(tomorrowHours, hour)
+		}
+	}
+
+	result := facts{}
+	if len(todayHours) > 0 {
+		result.Today = describe(todayHours, remainingHours)
+	}
+	if len(tomorrowHours) > 0 {
+		result.Tomorrow = describe(tomorrowHours, tomorrowHours)
+		// tomorrow only gets a timing when it is the first day described
+		if result.Today != nil {
+			result.Tomorrow.After = nil
+		}
+	}
+	if result.Today == nil && result.Tomorrow == nil {
+		return ""
+	}
+
+	if result.Today != nil && result.Tomorrow != nil {
+		diff := maxTemperature(tomorrowHours) - maxTemperature(todayHours)
+		switch {
+		case diff <= -trendCelsius:
+			result.Trend = "cooler"
+		case diff >= trendCelsius:
+			result.Trend = "warmer"
+		default:
+			result.Trend = "same"
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := templateFor(preferred).Execute(&buf, result); err != nil {
+		return ""
+	}
+	return capitalize(buf.String())
+}
+
+// describe tells how the whole day feels and the most notable event of the
+// hours still to come.
+func describe(dayHours, upcoming []Hour) *day {
+	var humidity float64
+	var wind float64
+	for _, hour := range dayHours {
+		humidity += float64(hour.Humidity)
+		if hour.WindKph > wind {
+			wind = hour.WindKph
+		}
+	}
+	humidity /= float64(len(dayHours))
+
+	result := &day{}
+	high := maxTemperature(dayHours)
+	switch {
+	case high >= hotCelsius:
+		result.Feel = append(result.Feel, "hot")
+	case high >= warmCelsius:
+		result.Feel = append(result.Feel, "warm")
+	case high >= mildCelsius:
+		result.Feel = append(result.Feel, "mild")
+	case high >= coolCelsius:
+		result.Feel = append(result.Feel, "cool")
+	default:
+		result.Feel = append(result.Feel, "cold")
+	}
+
+	switch {
+	case humidity >= humidPercent && high >= warmCelsius:
+		result.Feel = append(result.Feel, "humid")
+	case humidity <= dryPercent:
+		result.Feel = append(result.Feel, "dry")
+	}
+
+	if wind >= windyKph {
+		result.Feel = append(result.Feel, "windy")
+	}
+
+	for _, event := range events {
+		for i, hour := range upcoming {
+			if !matchEvent(hour, event.name, event.keywords) {
+				continue
+			}
+
+			result.Event = event.name
+			if i > 0 {
+				after := hour.Time
+				result.After = &after
+			}
+			return result
+		}
+	}
+
+	return result
+}
+
+func matchEvent(hour Hour, name string, keywords []string) bool {
+	condition := strings.ToLower(hour.Condition)
+	for _, keyword := range keywords {
+		if strings.Contains(condition, keyword) {
+			return true
+		}
+	}
+
+	// measurable precipitation is rain even when the condition says otherwise
+	return name == EventRain && hour.PrecipitationMM >= rainMM
+}
+
+func maxTemperature(hours []Hour) float64 {
+	high := hours[0].TemperatureC
+	for _, hour := range hours[1:] {
+		if hour.TemperatureC > high {
+			high = hour.TemperatureC
+		}
+	}
+	return high
+}
+
+func capitalize(text string) string {
+	first, size := utf8.DecodeRuneInString(text)
+	return string(unicode.ToUpper(first)) + text[size:]
+}
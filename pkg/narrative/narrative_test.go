@@ -0,0 +1,114 @@
+package narrative
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	now := time.Date(2024, 1, 1, 9, 30, 0, 0, time.UTC)
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, 1, day, hour, 0, 0, 0, time.UTC)
+	}
+	// day repeats the hour every 3 hours of the day, replacing the condition
+	// from the hour on
+	day := func(date int, hour Hour, conditionFrom int, condition string) []Hour {
+		hours := []Hour{}
+		for h := 0; h < 24; h += 3 {
+			item := hour
+			item.Time = at(date, h)
+			if conditionFrom >= 0 && h >= conditionFrom {
+				item.Condition = condition
+			}
+			hours = append(hours, item)
+		}
+		return hours
+	}
+	join := func(days ...[]Hour) []Hour {
+		result := []Hour{}
+		for _, d := range days {
+			result = append(result, d...)
+		}
+		return result
+	}
+
+	hotHumid := Hour{TemperatureC: 33, Humidity: 80, WindKph: 10, Condition: "Sunny"}
+	mild := Hour{TemperatureC: 24, Humidity: 60, WindKph: 10, Condition: "Partly cloudy"}
+	coldWindy := Hour{TemperatureC: 4, Humidity: 30, WindKph: 45, Condition: "Overcast"}
+
+	tests := []struct {
+		name      string
+		hours     []Hour
+		languages []string
+		expected  string
+	}{
+		{
+			name:      "hot and humid with thunderstorms in the afternoon, cooler tomorrow",
+			hours:     join(day(1, hotHumid, 15, "Patchy light rain with thunder"), day(2, mild, -1, "")),
+			languages: []string{"en"},
+			expected:  "Hot and humid today with thunderstorms after 3 PM; cooler tomorrow",
+		},
+		{
+			name:      "same in Indonesian",
+			hours:     join(day(1, hotHumid, 15, "Patchy light rain with thunder"), day(2, mild, -1, "")),
+			languages: []string{"id-id"},
+			expected:  "Panas dan lembap hari ini disertai badai petir setelah pukul 15.00; lebih sejuk besok",
+		},
+		{
+			name:      "rain already falling, warmer tomorrow with rain",
+			hours:     join(day(1, mild, 0, "Moderate rain"), day(2, hotHumid, 12, "Light drizzle")),
+			languages: []string{"en"},
+			expected:  "Mild today with rain; warmer tomorrow with rain",
+		},
+		{
+			name:      "thunder wins over earlier rain",
+			hours:     append(day(1, mild, 9, "Light rain"), Hour{Time: at(1, 23), TemperatureC: 20, Humidity: 60, Condition: "Thundery outbreaks possible"}),
+			languages: []string{"en"},
+			expected:  "Mild today with thunderstorms after 11 PM",
+		},
+		{
+			name:      "precipitation without a rainy condition",
+			hours:     join(day(1, Hour{TemperatureC: 26, Humidity: 50, PrecipitationMM: 1.2, Condition: "Cloudy"}, -1, ""), day(2, Hour{TemperatureC: 27, Humidity: 50}, -1, "")),
+			languages: []string{"en"},
+			expected:  "Warm today with rain; much the same tomorrow",
+		},
+		{
+			name:      "cold, dry and windy",
+			hours:     day(1, coldWindy, -1, ""),
+			languages: []string{"id"},
+			expected:  "Sangat dingin, kering dan berangin hari ini",
+		},
+		{
+			name:      "only tomorrow, with its timing",
+			hours:     day(2, Hour{TemperatureC: 12, Humidity: 90}, 6, "Light snow"),
+			languages: []string{"en"},
+			expected:  "Cool tomorrow with snow after 6 AM",
+		},
+		{
+			name:      "fog in the past is left out",
+			hours:     day(1, Hour{TemperatureC: 20, Humidity: 60, Condition: "Fog"}, 9, "Sunny"),
+			languages: []string{"en"},
+			expected:  "Mild today",
+		},
+		{
+			name:      "unknown language falls back to English",
+			hours:     day(1, mild, -1, ""),
+			languages: []string{"fr", "de"},
+			expected:  "Mild today",
+		},
+		{
+			name:      "no row for today or tomorrow",
+			hours:     day(3, mild, -1, ""),
+			languages: []string{"en"},
+			expected:  "",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run("WHEN "+test.name+", THEN should describe it", func(t *testing.T) {
+			assert.Equal(t, test.expected, Summarize(test.hours, now, test.languages))
+		})
+	}
+}
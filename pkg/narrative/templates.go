@@ -0,0 +1,85 @@
+package narrative
+
+import (
+	"strings"
+	"text/template"
+	"time"
+	"tyarus/weather-app/pkg/language"
+)
+
+// Languages lists the languages with templates, the first one is the
+// fallback.
+var Languages = []string{"en", "id"}
+
+type templateSet struct {
+	words map[string]string
+	and   string
+	clock func(time.Time) string
+	text  string
+}
+
+var templateSets = map[string]templateSet{
+	"en": {
+		words: map[string]string{
+			"hot": "hot", "warm": "warm", "mild": "mild", "cool": "cool", "cold": "cold",
+			"humid": "humid", "dry": "dry", "windy": "windy",
+			EventThunderstorms: "thunderstorms", EventSnow: "snow", EventRain: "rain", EventFog: "fog",
+			"cooler": "cooler", "warmer": "warmer", "same": "much the same",
+		},
+		and:   "and",
+		clock: func(t time.Time) string { return t.Format("3 PM") },
+		text: `{{define "event"}}{{with .Event}} with {{word .}}{{end}}{{with .After}} after {{clock .}}{{end}}{{end}}` +
+			`{{with .Today}}{{list .Feel}} today{{template "event" .}}{{end}}` +
+			`{{if and .Today .Tomorrow}}; {{word .Trend}} tomorrow{{template "event" .Tomorrow}}` +
+			`{{else}}{{with .Tomorrow}}{{list .Feel}} tomorrow{{template "event" .}}{{end}}{{end}}`,
+	},
+	"id": {
+		words: map[string]string{
+			"hot": "panas", "warm": "hangat", "mild": "sejuk", "cool": "dingin", "cold": "sangat dingin",
+			"humid": "lembap", "dry": "kering", "windy": "berangin",
+			EventThunderstorms: "badai petir", EventSnow: "salju", EventRain: "hujan", EventFog: "kabut",
+			"cooler": "lebih sejuk", "warmer": "lebih hangat", "same": "cuaca serupa",
+		},
+		and:   "dan",
+		clock: func(t time.Time) string { return "pukul " + t.Format("15.04") },
+		text: `{{define "event"}}{{with .Event}} disertai {{word .}}{{end}}{{with .After}} setelah {{clock .}}{{end}}{{end}}` +
+			`{{with .Today}}{{list .Feel}} hari ini{{template "event" .}}{{end}}` +
+			`{{if and .Today .Tomorrow}}; {{word .Trend}} besok{{template "event" .Tomorrow}}` +
+			`{{else}}{{with .Tomorrow}}{{list .Feel}} besok{{template "event" .}}{{end}}{{end}}`,
+	},
+}
+
+var templates = map[string]*template.Template{}
+
+func init() {
+	for lang, set := range templateSets {
+		set := set
+		templates[lang] = template.Must(template.New(lang).Funcs(template.FuncMap{
+			"word":  func(key string) string { return set.words[key] },
+			"clock": set.clock,
+			"list":  func(keys []string) string { return joinWords(keys, set.words, set.and) },
+		}).Parse(set.text))
+	}
+}
+
+// templateFor returns the template of the language best serving the
+// preferred normalized tags.
+func templateFor(preferred []string) *template.Template {
+	if i, ok := language.Match(preferred, Languages); ok {
+		return templates[Languages[i]]
+	}
+	return templates[Languages[0]]
+}
+
+// joinWords lists the words as "a, b and c".
+func joinWords(keys []string, words map[string]string, and string) string {
+	translated := make([]string, len(keys))
+	for i, key := range keys {
+		translated[i] = words[key]
+	}
+
+	if len(translated) < 2 {
+		return strings.Join(translated, "")
+	}
+	return strings.Join(translated[:len(translated)-1], ", ") + " " + and + " " + translated[len(translated)-1]
+}
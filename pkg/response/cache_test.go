@@ -0,0 +1,64 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCached(t *testing.T) {
+	lastModified := time.Date(2024, 1, 1, 10, 30, 0, 0, time.UTC)
+	body := []byte("BEGIN:VCALENDAR")
+
+	t.Run("WHEN request has no validators, THEN should write body with caching headers", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/forecast.ics", nil)
+
+		Cached(recorder, request, "text/calendar", body, lastModified, 15*time.Minute)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "BEGIN:VCALENDAR", recorder.Body.String())
+		assert.Equal(t, "text/calendar", recorder.Header().Get("Content-Type"))
+		assert.Equal(t, "public, max-age=900", recorder.Header().Get("Cache-Control"))
+		assert.Equal(t, "Mon, 01 Jan 2024 10:30:00 GMT", recorder.Header().Get("Last-Modified"))
+		assert.NotEmpty(t, recorder.Header().Get("ETag"))
+	})
+
+	t.Run("WHEN ETag matches, THEN should answer not modified", func(t *testing.T) {
+		first := httptest.NewRecorder()
+		Cached(first, httptest.NewRequest(http.MethodGet, "/forecast.ics", nil), "text/calendar", body, lastModified, time.Minute)
+
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/forecast.ics", nil)
+		request.Header.Set("If-None-Match", `"other", `+first.Header().Get("ETag"))
+
+		Cached(recorder, request, "text/calendar", body, lastModified, time.Minute)
+
+		assert.Equal(t, http.StatusNotModified, recorder.Code)
+		assert.Empty(t, recorder.Body.String())
+	})
+
+	t.Run("WHEN ETag differs, THEN should ignore If-Modified-Since", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/forecast.ics", nil)
+		request.Header.Set("If-None-Match", `"other"`)
+		request.Header.Set("If-Modified-Since", lastModified.Format(http.TimeFormat))
+
+		Cached(recorder, request, "text/calendar", body, lastModified, time.Minute)
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+	})
+
+	t.Run("WHEN not modified since, THEN should answer not modified", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		request := httptest.NewRequest(http.MethodGet, "/forecast.ics", nil)
+		request.Header.Set("If-Modified-Since", lastModified.Add(time.Hour).Format(http.TimeFormat))
+
+		Cached(recorder, request, "text/calendar", body, lastModified.Add(500*time.Millisecond), time.Minute)
+
+		assert.Equal(t, http.StatusNotModified, recorder.Code)
+	})
+}
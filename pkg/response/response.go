@@ -17,6 +17,14 @@ type PaginationData[T any] struct {
 	Total       int `json:"total"`
 	CurrentPage int `json:"currentPage"`
 	PageSize    int `json:"pageSize"`
+	CursorData
+}
+
+// CursorData holds the opaque tokens to fetch the page after and before the
+// current one, empty when there is no such page.
+type CursorData struct {
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
 }
 
 func JSON[T any](w http.ResponseWriter, statusCode int, status, message string, data T) {
@@ -0,0 +1,268 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// MinSimilarity is the trigram similarity a field needs to match a query
+// that is not a prefix of one of its words.
+const MinSimilarity = 0.3
+
+// Document is one searchable record carrying Value back in the results.
+// Fields are matched separately and the best match counts, weighted by the
+// index weight of the same position.
+type Document[T any] struct {
+	ID     int64
+	Fields []string
+	Value  T
+}
+
+type Result[T any] struct {
+	ID    int64
+	Score float64
+	Field int
+	Value T
+}
+
+type indexedField struct {
+	words []string
+	grams map[string]struct{}
+}
+
+type indexedDocument[T any] struct {
+	fields []indexedField
+	value  T
+}
+
+// Index is an in-memory trigram index for typo tolerant and prefix search.
+// It is safe for concurrent use.
+type Index[T any] struct {
+	weights []float64
+
+	mu       sync.RWMutex
+	docs     map[int64]indexedDocument[T]
+	postings map[string]map[int64]struct{}
+}
+
+// NewIndex creates an index weighting document fields by position. Fields
+// past the last weight use the last weight, so trailing fields can be
+// repeated, e.g. a field per alias.
+func NewIndex[T any](weights ...float64) *Index[T] {
+	return &Index[T]{
+		weights:  weights,
+		docs:     map[int64]indexedDocument[T]{},
+		postings: map[string]map[int64]struct{}{},
+	}
+}
+
+// Replace drops every document and indexes docs instead.
+func (i *Index[T]) Replace(docs []Document[T]) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.docs = map[int64]indexedDocument[T]{}
+	i.postings = map[string]map[int64]struct{}{}
+	for _, doc := range docs {
+		i.add(doc)
+	}
+}
+
+// Add indexes the document, replacing the one with the same id.
+func (i *Index[T]) Add(doc Document[T]) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(doc.ID)
+	i.add(doc)
+}
+
+func (i *Index[T]) Remove(id int64) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.remove(id)
+}
+
+func (i *Index[T]) Len() int {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return len(i.docs)
+}
+
+// Search returns up to limit documents matching query, best first. A field
+// matches when one of its words starts with the query or when its trigram
+// similarity reaches MinSimilarity.
+func (i *Index[T]) Search(query string, limit int) []Result[T] {
+	words := Normalize(query)
+	if len(words) == 0 || limit <= 0 {
+		return nil
+	}
+	queryGrams := trigrams(words)
+	phrase := strings.Join(words, " ")
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	// most words share their leading grams with many others, so a document
+	// is only scored when it shares enough grams to match at all
+	shared := map[int64]int{}
+	for gram := range queryGrams {
+		for id := range i.postings[gram] {
+			shared[id]++
+		}
+	}
+	minShared := minSharedGrams(len(queryGrams))
+
+	results := []Result[T]{}
+	for id, count := range shared {
+		if count < minShared {
+			continue
+		}
+
+		doc := i.docs[id]
+		best := Result[T]{ID: id, Value: doc.value}
+		for f, field := range doc.fields {
+			score := similarity(queryGrams, field.grams)
+			if hasPrefix(field.words, phrase) {
+				// autocomplete matches rank above fuzzy ones
+				score += 1
+			} else if score < MinSimilarity {
+				continue
+			}
+
+			score *= i.weight(f)
+			if score > best.Score {
+				best.Score, best.Field = score, f
+			}
+		}
+
+		if best.Score > 0 {
+			results = append(results, best)
+		}
+	}
+
+	sort.Slice(results, func(a, b int) bool {
+		if results[a].Score != results[b].Score {
+			return results[a].Score > results[b].Score
+		}
+		return results[a].ID < results[b].ID
+	})
+
+	if len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
+
+func (i *Index[T]) weight(field int) float64 {
+	if len(i.weights) == 0 {
+		return 1
+	}
+	return i.weights[min(field, len(i.weights)-1)]
+}
+
+func (i *Index[T]) add(doc Document[T]) {
+	fields := make([]indexedField, len(doc.Fields))
+	for f, value := range doc.Fields {
+		words := Normalize(value)
+		fields[f] = indexedField{words: words, grams: trigrams(words)}
+		for gram := range fields[f].grams {
+			if i.postings[gram] == nil {
+				i.postings[gram] = map[int64]struct{}{}
+			}
+			i.postings[gram][doc.ID] = struct{}{}
+		}
+	}
+
+	i.docs[doc.ID] = indexedDocument[T]{fields: fields, value: doc.Value}
+}
+
+func (i *Index[T]) remove(id int64) {
+	for _, field := range i.docs[id].fields {
+		for gram := range field.grams {
+			delete(i.postings[gram], id)
+			if len(i.postings[gram]) == 0 {
+				delete(i.postings, gram)
+			}
+		}
+	}
+
+	delete(i.docs, id)
+}
+
+// Normalize lowercases the text and splits it into words of letters and
+// digits.
+func Normalize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// trigrams pads every word with two leading and one trailing space, so the
+// start of a word weighs more than its end, the same way pg_trgm does.
+func trigrams(words []string) map[string]struct{} {
+	grams := map[string]struct{}{}
+	for _, word := range words {
+		runes := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(runes); i++ {
+			grams[string(runes[i:i+3])] = struct{}{}
+		}
+	}
+
+	return grams
+}
+
+// similarity is the Jaccard index of both trigram sets.
+func similarity(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for gram := range a {
+		if _, ok := b[gram]; ok {
+			shared++
+		}
+	}
+
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// minSharedGrams is the least number of grams a document shares with a query
+// of n grams when it matches. A prefix match misses only the trailing gram of
+// the last word, a fuzzy match needs MinSimilarity of the query grams, since
+// the Jaccard index never exceeds shared / n.
+func minSharedGrams(n int) int {
+	// the epsilon keeps float noise, e.g. 0.3 * 10, from rounding up
+	return max(1, min(n-1, int(math.Ceil(MinSimilarity*float64(n)-1e-9))))
+}
+
+// hasPrefix reports whether phrase is a prefix of the words starting at any
+// word, e.g. "jakarta ra" matches "dki jakarta raya". Every word but the last
+// one of the phrase has to match a whole word.
+func hasPrefix(words []string, phrase string) bool {
+	for start := range words {
+		rest := phrase
+		for _, word := range words[start:] {
+			if len(rest) <= len(word) {
+				if strings.HasPrefix(word, rest) {
+					return true
+				}
+				break
+			}
+
+			if !strings.HasPrefix(rest, word) || rest[len(word)] != ' ' {
+				break
+			}
+			rest = rest[len(word)+1:]
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,112 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestIndex() *Index[string] {
+	index := NewIndex[string](1, 0.6, 0.5)
+	index.Replace([]Document[string]{
+		{ID: 1, Fields: []string{"Jakarta", "DKI Jakarta", "Indonesia"}, Value: "jkt"},
+		{ID: 2, Fields: []string{"Bandung", "West Java", "Indonesia"}},
+		{ID: 3, Fields: []string{"Surabaya", "East Java", "Indonesia"}},
+		{ID: 4, Fields: []string{"Surakarta", "Central Java", "Indonesia"}},
+	})
+	return index
+}
+
+func TestSearch(t *testing.T) {
+	t.Run("WHEN document has more fields than weights, THEN should weigh them like the last field", func(t *testing.T) {
+		index := NewIndex[string](1, 0.5)
+		index.Add(Document[string]{ID: 1, Fields: []string{"Jakarta", "Batavia", "Djakarta"}})
+
+		results := index.Search("djakarta", 1)
+
+		assert.Len(t, results, 1)
+		assert.Equal(t, 2, results[0].Field)
+		assert.InDelta(t, 1.0, results[0].Score, 0.001)
+	})
+
+	t.Run("WHEN query has a typo, THEN should return the closest location first", func(t *testing.T) {
+		results := newTestIndex().Search("Surbaya", 10)
+
+		assert.NotEmpty(t, results)
+		assert.Equal(t, int64(3), results[0].ID)
+	})
+
+	t.Run("WHEN document matches, THEN should return its value", func(t *testing.T) {
+		results := newTestIndex().Search("jakarta", 1)
+
+		assert.Equal(t, "jkt", results[0].Value)
+	})
+
+	t.Run("WHEN query is a prefix, THEN should return every location starting with it", func(t *testing.T) {
+		results := newTestIndex().Search("sura", 10)
+
+		ids := []int64{}
+		for _, result := range results {
+			ids = append(ids, result.ID)
+		}
+		assert.ElementsMatch(t, []int64{3, 4}, ids)
+	})
+
+	t.Run("WHEN query matches name and region, THEN should rank the name match higher", func(t *testing.T) {
+		results := newTestIndex().Search("java", 10)
+
+		assert.Len(t, results, 3)
+		assert.Equal(t, 1, results[0].Field)
+	})
+
+	t.Run("WHEN document is removed, THEN should not return it", func(t *testing.T) {
+		index := newTestIndex()
+		index.Remove(1)
+
+		for _, result := range index.Search("jakarta", 10) {
+			assert.NotEqual(t, int64(1), result.ID)
+		}
+		assert.Equal(t, 3, index.Len())
+	})
+
+	t.Run("WHEN document is added again, THEN should search the new fields", func(t *testing.T) {
+		index := newTestIndex()
+		index.Add(Document[string]{ID: 2, Fields: []string{"Bogor", "West Java", "Indonesia"}})
+
+		assert.Empty(t, index.Search("bandung", 10))
+		assert.Equal(t, int64(2), index.Search("bogor", 10)[0].ID)
+	})
+
+	t.Run("WHEN limit is lower than the matches, THEN should cut the results", func(t *testing.T) {
+		assert.Len(t, newTestIndex().Search("indonesia", 2), 2)
+	})
+}
+
+func TestHasPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		words    []string
+		phrase   string
+		expected bool
+	}{
+		{"WHEN phrase starts the first word, THEN should match", []string{"jakarta"}, "jak", true},
+		{"WHEN phrase is the whole word, THEN should match", []string{"jakarta"}, "jakarta", true},
+		{"WHEN phrase starts a later word, THEN should match", []string{"dki", "jakarta", "raya"}, "jakarta ra", true},
+		{"WHEN phrase is inside a word, THEN should not match", []string{"jakarta"}, "kart", false},
+		{"WHEN a leading phrase word is only a prefix, THEN should not match", []string{"jakarta", "raya"}, "jak raya", false},
+		{"WHEN phrase is longer than the words, THEN should not match", []string{"dki"}, "dki jakarta", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, hasPrefix(tt.words, tt.phrase))
+		})
+	}
+}
+
+func TestMinSharedGrams(t *testing.T) {
+	assert.Equal(t, 1, minSharedGrams(1))
+	assert.Equal(t, 1, minSharedGrams(2))
+	assert.Equal(t, 3, minSharedGrams(10))
+	assert.Equal(t, 4, minSharedGrams(11))
+}
@@ -0,0 +1,85 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// Summary describes the distribution of a set of values.
+type Summary struct {
+	Count int     `json:"count"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Mean  float64 `json:"mean"`
+	P10   float64 `json:"p10"`
+	P50   float64 `json:"p50"`
+	P90   float64 `json:"p90"`
+}
+
+// Summarize returns the summary of values, the zero Summary when there are
+// none. values is not modified.
+func Summarize(values []float64) Summary {
+	if len(values) == 0 {
+		return Summary{}
+	}
+
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, value := range sorted {
+		sum += value
+	}
+
+	return Summary{
+		Count: len(sorted),
+		Min:   sorted[0],
+		Max:   sorted[len(sorted)-1],
+		Mean:  round(sum / float64(len(sorted))),
+		P10:   round(Percentile(sorted, 10)),
+		P50:   round(Percentile(sorted, 50)),
+		P90:   round(Percentile(sorted, 90)),
+	}
+}
+
+// Percentile returns the p-th percentile, 0 to 100, of sorted values using
+// linear interpolation between the closest ranks.
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower < 0 {
+		return sorted[0]
+	}
+	if upper >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// Map applies convert to every value of the summary except Count. convert
+// must be an increasing affine function, such as a unit conversion, for the
+// result to still describe the converted values.
+func (s Summary) Map(convert func(float64) float64) Summary {
+	if s.Count == 0 {
+		return s
+	}
+
+	s.Min = convert(s.Min)
+	s.Max = convert(s.Max)
+	s.Mean = convert(s.Mean)
+	s.P10 = convert(s.P10)
+	s.P50 = convert(s.P50)
+	s.P90 = convert(s.P90)
+	return s
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,49 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{10, 20, 30, 40, 50}
+
+	t.Run("WHEN percentile falls on a value, THEN should return the value", func(t *testing.T) {
+		assert.Equal(t, float64(30), Percentile(sorted, 50))
+		assert.Equal(t, float64(10), Percentile(sorted, 0))
+		assert.Equal(t, float64(50), Percentile(sorted, 100))
+	})
+
+	t.Run("WHEN percentile falls between values, THEN should interpolate", func(t *testing.T) {
+		assert.InDelta(t, 14, Percentile(sorted, 10), 0.0001)
+		assert.InDelta(t, 46, Percentile(sorted, 90), 0.0001)
+	})
+
+	t.Run("WHEN values are empty, THEN should return zero", func(t *testing.T) {
+		assert.Equal(t, float64(0), Percentile(nil, 50))
+	})
+}
+
+func TestSummarize(t *testing.T) {
+	t.Run("WHEN values are unsorted, THEN should summarize without modifying them", func(t *testing.T) {
+		values := []float64{30, 10, 20}
+
+		summary := Summarize(values)
+
+		assert.Equal(t, Summary{Count: 3, Min: 10, Max: 30, Mean: 20, P10: 12, P50: 20, P90: 28}, summary)
+		assert.Equal(t, []float64{30, 10, 20}, values)
+	})
+
+	t.Run("WHEN values are empty, THEN should return zero summary", func(t *testing.T) {
+		assert.Equal(t, Summary{}, Summarize(nil))
+	})
+
+	t.Run("WHEN mapped, THEN should convert every value except count", func(t *testing.T) {
+		summary := Summary{Count: 2, Min: 1, Max: 2, Mean: 1.5, P10: 1.1, P50: 1.5, P90: 1.9}
+
+		mapped := summary.Map(func(v float64) float64 { return v * 2 })
+
+		assert.Equal(t, Summary{Count: 2, Min: 2, Max: 4, Mean: 3, P10: 2.2, P50: 3, P90: 3.8}, mapped)
+	})
+}
@@ -0,0 +1,190 @@
+package svgchart
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"math"
+	"strconv"
+	"time"
+)
+
+type Kind string
+
+const (
+	KindLine Kind = "line"
+	KindBar  Kind = "bar"
+)
+
+// Theme holds the colors of a chart as CSS color values.
+type Theme struct {
+	Background string
+	Foreground string
+	Grid       string
+	Series     string
+}
+
+var Themes = map[string]Theme{
+	"light": {Background: "#ffffff", Foreground: "#1f2933", Grid: "#e4e7eb", Series: "#2f80ed"},
+	"dark":  {Background: "#1f2933", Foreground: "#e4e7eb", Grid: "#3e4c59", Series: "#56ccf2"},
+}
+
+// Point is a value at a time, a nil Value leaves a gap in the chart.
+type Point struct {
+	Time  time.Time
+	Value *float64
+}
+
+// Chart is a single series chart. Points are drawn evenly spaced in the
+// given order, the time labels are formatted in the location of each time.
+type Chart struct {
+	Title  string
+	Unit   string
+	Kind   Kind
+	Width  int
+	Height int
+	Theme  Theme
+	Points []Point
+}
+
+const (
+	marginLeft   = 56
+	marginRight  = 16
+	marginTop    = 36
+	marginBottom = 32
+	targetTicks  = 4
+	targetLabels = 6
+)
+
+// Render draws the chart as a standalone SVG document without scripts, so it
+// can be embedded where JavaScript does not run.
+func (c Chart) Render() []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`+"\n",
+		c.Width, c.Height, c.Width, c.Height)
+	fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", c.Theme.Background)
+
+	title := c.Title
+	if c.Unit != "" {
+		title += " (" + c.Unit + ")"
+	}
+	fmt.Fprintf(&buf, `<text x="%d" y="22" fill="%s" font-size="14" font-weight="bold">%s</text>`+"\n",
+		marginLeft, c.Theme.Foreground, html.EscapeString(title))
+
+	plotWidth := float64(c.Width - marginLeft - marginRight)
+	plotHeight := float64(c.Height - marginTop - marginBottom)
+	low, high, ok := c.bounds()
+	if !ok || plotWidth <= 0 || plotHeight <= 0 {
+		fmt.Fprintf(&buf, `<text x="%s" y="%s" fill="%s" text-anchor="middle">No data</text>`+"\n",
+			formatCoord(float64(c.Width)/2), formatCoord(float64(c.Height)/2), c.Theme.Foreground)
+		buf.WriteString("</svg>\n")
+		return buf.Bytes()
+	}
+
+	step := niceStep((high - low) / targetTicks)
+	low = math.Floor(low/step) * step
+	high = math.Ceil(high/step) * step
+	if high == low {
+		high = low + step
+	}
+
+	y := func(value float64) float64 {
+		return marginTop + plotHeight - (value-low)/(high-low)*plotHeight
+	}
+
+	decimals := 0
+	if step < 1 {
+		decimals = int(math.Ceil(-math.Log10(step)))
+	}
+	for i := 0; low+float64(i)*step <= high+step/2; i++ {
+		tick := low + float64(i)*step
+		fmt.Fprintf(&buf, `<line x1="%d" y1="%s" x2="%s" y2="%s" stroke="%s"/>`+"\n",
+			marginLeft, formatCoord(y(tick)), formatCoord(marginLeft+plotWidth), formatCoord(y(tick)), c.Theme.Grid)
+		fmt.Fprintf(&buf, `<text x="%d" y="%s" fill="%s" text-anchor="end">%s</text>`+"\n",
+			marginLeft-6, formatCoord(y(tick)+4), c.Theme.Foreground, strconv.FormatFloat(tick, 'f', decimals, 64))
+	}
+
+	slot := plotWidth / float64(len(c.Points))
+	x := func(index int) float64 {
+		return marginLeft + slot*(float64(index)+0.5)
+	}
+
+	every := int(math.Ceil(float64(len(c.Points)) / targetLabels))
+	for i := 0; i < len(c.Points); i += every {
+		fmt.Fprintf(&buf, `<text x="%s" y="%d" fill="%s" text-anchor="middle">%s</text>`+"\n",
+			formatCoord(x(i)), c.Height-marginBottom+18, c.Theme.Foreground, c.Points[i].Time.Format("Jan 2 15:04"))
+	}
+
+	switch c.Kind {
+	case KindBar:
+		base := y(math.Max(low, 0))
+		for i, point := range c.Points {
+			if point.Value == nil {
+				continue
+			}
+			top := math.Min(y(*point.Value), base)
+			fmt.Fprintf(&buf, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`+"\n",
+				formatCoord(x(i)-slot*0.4), formatCoord(top), formatCoord(slot*0.8), formatCoord(math.Abs(base-y(*point.Value))), c.Theme.Series)
+		}
+	default:
+		var path bytes.Buffer
+		command := "M"
+		for i, point := range c.Points {
+			if point.Value == nil {
+				command = "M"
+				continue
+			}
+			if path.Len() > 0 {
+				path.WriteByte(' ')
+			}
+			path.WriteString(command + formatCoord(x(i)) + " " + formatCoord(y(*point.Value)))
+			command = "L"
+		}
+		fmt.Fprintf(&buf, `<path d="%s" fill="none" stroke="%s" stroke-width="2" stroke-linejoin="round"/>`+"\n",
+			path.String(), c.Theme.Series)
+	}
+
+	buf.WriteString("</svg>\n")
+	return buf.Bytes()
+}
+
+// bounds returns the lowest and highest value, bars always include zero.
+func (c Chart) bounds() (low, high float64, ok bool) {
+	for _, point := range c.Points {
+		if point.Value == nil {
+			continue
+		}
+		if !ok || *point.Value < low {
+			low = *point.Value
+		}
+		if !ok || *point.Value > high {
+			high = *point.Value
+		}
+		ok = true
+	}
+
+	if ok && c.Kind == KindBar {
+		low = math.Min(low, 0)
+		high = math.Max(high, 0)
+	}
+	return low, high, ok
+}
+
+// niceStep rounds a raw tick step up to 1, 2 or 5 times a power of ten.
+func niceStep(raw float64) float64 {
+	if raw <= 0 {
+		return 1
+	}
+
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, factor := range []float64{1, 2, 5} {
+		if raw <= factor*magnitude {
+			return factor * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+func formatCoord(value float64) string {
+	return strconv.FormatFloat(math.Round(value*10)/10, 'f', -1, 64)
+}
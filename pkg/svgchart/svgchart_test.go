@@ -0,0 +1,79 @@
+package svgchart
+
+import (
+	"flag"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestRender(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := func(values ...float64) []Point {
+		result := make([]Point, len(values))
+		for i, value := range values {
+			result[i] = Point{Time: start.Add(time.Duration(i) * 6 * time.Hour)}
+			if !math.IsNaN(value) {
+				v := value
+				result[i].Value = &v
+			}
+		}
+		return result
+	}
+	gap := math.NaN()
+
+	tests := []struct {
+		name  string
+		chart Chart
+	}{
+		{
+			name: "line_light",
+			chart: Chart{Title: "Temperature", Unit: "°C", Kind: KindLine, Width: 480, Height: 200, Theme: Themes["light"],
+				Points: points(24.5, 23, 26.1, 30.4, 31, 28.2, 25, 24)},
+		},
+		{
+			name: "line_gap_dark",
+			chart: Chart{Title: "Wind <gusts>", Unit: "km/h", Kind: KindLine, Width: 480, Height: 200, Theme: Themes["dark"],
+				Points: points(5, 8, gap, gap, 12, 15)},
+		},
+		{
+			name: "bar_light",
+			chart: Chart{Title: "Precipitation", Unit: "mm", Kind: KindBar, Width: 480, Height: 200, Theme: Themes["light"],
+				Points: points(0, 0.2, 1.4, gap, 0.6)},
+		},
+		{
+			name:  "empty",
+			chart: Chart{Title: "Humidity", Unit: "%", Kind: KindLine, Width: 320, Height: 160, Theme: Themes["light"]},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run("WHEN chart is "+test.name+", THEN should match the golden file", func(t *testing.T) {
+			golden := filepath.Join("testdata", test.name+".svg")
+			actual := test.chart.Render()
+			if *update {
+				assert.NoError(t, os.WriteFile(golden, actual, 0o644))
+			}
+
+			expected, err := os.ReadFile(golden)
+			assert.NoError(t, err)
+			assert.Equal(t, string(expected), string(actual))
+		})
+	}
+}
+
+func TestNiceStep(t *testing.T) {
+	t.Run("WHEN step is raw, THEN should round up to 1, 2 or 5 times a power of ten", func(t *testing.T) {
+		assert.Equal(t, 1.0, niceStep(0.8))
+		assert.Equal(t, 2.0, niceStep(1.7))
+		assert.Equal(t, 5.0, niceStep(4.2))
+		assert.Equal(t, 10.0, niceStep(6))
+		assert.InDelta(t, 0.05, niceStep(0.035), 1e-9)
+	})
+}
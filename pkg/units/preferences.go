@@ -0,0 +1,63 @@
+package units
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Preferences picks the unit system of a request: the requested one, else
+// the default of the caller's API key, else the global default.
+type Preferences struct {
+	Default  System
+	ByAPIKey map[string]System
+}
+
+// ParsePreferences reads the global default and the per API key defaults
+// written as "key1=imperial,key2=si". An empty default means metric.
+func ParsePreferences(defaultSystem, byAPIKey string) (Preferences, error) {
+	prefs := Preferences{Default: Metric, ByAPIKey: map[string]System{}}
+	if strings.TrimSpace(defaultSystem) != "" {
+		system, err := Parse(defaultSystem)
+		if err != nil {
+			return prefs, fmt.Errorf("invalid default unit system: %w", err)
+		}
+		prefs.Default = system
+	}
+
+	for _, entry := range strings.Split(byAPIKey, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return prefs, fmt.Errorf("invalid API key unit system %q, expected key=system", entry)
+		}
+
+		system, err := Parse(value)
+		if err != nil {
+			return prefs, fmt.Errorf("invalid unit system of API key %s: %w", key, err)
+		}
+		prefs.ByAPIKey[key] = system
+	}
+
+	return prefs, nil
+}
+
+// Resolve returns the unit system for the requested name and API key, either
+// may be empty.
+func (p Preferences) Resolve(requested, apiKey string) (System, error) {
+	if strings.TrimSpace(requested) != "" {
+		return Parse(requested)
+	}
+
+	if system, ok := p.ByAPIKey[apiKey]; ok && apiKey != "" {
+		return system, nil
+	}
+
+	if p.Default == "" {
+		return Metric, nil
+	}
+	return p.Default, nil
+}
@@ -0,0 +1,154 @@
+package units
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// System is a set of units weather values are presented in. Values are
+// stored in metric units, the other systems are converted from them.
+type System string
+
+const (
+	Metric   System = "metric"
+	Imperial System = "imperial"
+	// SI uses base units where they are practical. Precipitation stays in
+	// millimeters, which equals kg/m².
+	SI System = "si"
+)
+
+var ErrUnknownSystem = errors.New("unknown unit system, only allow metric, imperial, si")
+
+// Labels names the unit of every converted quantity.
+type Labels struct {
+	System        System `json:"system"`
+	Temperature   string `json:"temperature"`
+	Speed         string `json:"speed"`
+	Precipitation string `json:"precipitation"`
+	Pressure      string `json:"pressure"`
+	Distance      string `json:"distance"`
+}
+
+var labels = map[System]Labels{
+	Metric:   {System: Metric, Temperature: "°C", Speed: "km/h", Precipitation: "mm", Pressure: "hPa", Distance: "km"},
+	Imperial: {System: Imperial, Temperature: "°F", Speed: "mph", Precipitation: "in", Pressure: "inHg", Distance: "mi"},
+	SI:       {System: SI, Temperature: "K", Speed: "m/s", Precipitation: "mm", Pressure: "Pa", Distance: "m"},
+}
+
+// Parse reads a unit system name, case insensitive.
+func Parse(value string) (System, error) {
+	system := System(strings.ToLower(strings.TrimSpace(value)))
+	if _, ok := labels[system]; !ok {
+		return "", fmt.Errorf("%w: %q", ErrUnknownSystem, value)
+	}
+
+	return system, nil
+}
+
+// Labels returns the unit names of the system, metric ones for an unknown
+// system.
+func (s System) Labels() Labels {
+	if l, ok := labels[s]; ok {
+		return l
+	}
+	return labels[Metric]
+}
+
+// Temperature converts degrees Celsius.
+func (s System) Temperature(celsius float64) float64 {
+	switch s {
+	case Imperial:
+		return round(CelsiusToFahrenheit(celsius))
+	case SI:
+		return round(CelsiusToKelvin(celsius))
+	}
+	return round(celsius)
+}
+
+// TemperatureDifference converts a difference between two temperatures in
+// degrees Celsius, which has no offset unlike Temperature.
+func (s System) TemperatureDifference(celsius float64) float64 {
+	if s == Imperial {
+		return round(celsius * 9 / 5)
+	}
+	return round(celsius)
+}
+
+// Speed converts kilometers per hour.
+func (s System) Speed(kph float64) float64 {
+	switch s {
+	case Imperial:
+		return round(KphToMph(kph))
+	case SI:
+		return round(KphToMps(kph))
+	}
+	return round(kph)
+}
+
+// Precipitation converts millimeters.
+func (s System) Precipitation(mm float64) float64 {
+	if s == Imperial {
+		return round(MillimetersToInches(mm))
+	}
+	return round(mm)
+}
+
+// Pressure converts hectopascals, the same as millibars.
+func (s System) Pressure(hpa float64) float64 {
+	switch s {
+	case Imperial:
+		return round(HectopascalsToInHg(hpa))
+	case SI:
+		return round(hpa * 100)
+	}
+	return round(hpa)
+}
+
+// Distance converts kilometers.
+func (s System) Distance(km float64) float64 {
+	switch s {
+	case Imperial:
+		return round(KilometersToMiles(km))
+	case SI:
+		return round(km * 1000)
+	}
+	return round(km)
+}
+
+func CelsiusToFahrenheit(celsius float64) float64 {
+	return celsius*9/5 + 32
+}
+
+func FahrenheitToCelsius(fahrenheit float64) float64 {
+	return (fahrenheit - 32) * 5 / 9
+}
+
+func CelsiusToKelvin(celsius float64) float64 {
+	return celsius + 273.15
+}
+
+func KphToMph(kph float64) float64 {
+	return kph / 1.609344
+}
+
+func KphToMps(kph float64) float64 {
+	return kph / 3.6
+}
+
+func MillimetersToInches(mm float64) float64 {
+	return mm / 25.4
+}
+
+func HectopascalsToInHg(hpa float64) float64 {
+	return hpa / 33.8638866667
+}
+
+func KilometersToMiles(km float64) float64 {
+	return km / 1.609344
+}
+
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
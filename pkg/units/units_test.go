@@ -0,0 +1,95 @@
+package units
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSystemConversions(t *testing.T) {
+	t.Run("WHEN system is metric, THEN should keep the stored values", func(t *testing.T) {
+		assert.Equal(t, 21.5, Metric.Temperature(21.5))
+		assert.Equal(t, 36.0, Metric.Speed(36))
+		assert.Equal(t, 1013.0, Metric.Pressure(1013))
+		assert.Equal(t, 10.0, Metric.Distance(10))
+	})
+
+	t.Run("WHEN system is imperial, THEN should convert to imperial units", func(t *testing.T) {
+		assert.Equal(t, 212.0, Imperial.Temperature(100))
+		assert.Equal(t, -40.0, Imperial.Temperature(-40))
+		assert.Equal(t, 62.14, Imperial.Speed(100))
+		assert.Equal(t, 1.0, Imperial.Precipitation(25.4))
+		assert.Equal(t, 29.92, Imperial.Pressure(1013.25))
+		assert.Equal(t, 6.21, Imperial.Distance(10))
+	})
+
+	t.Run("WHEN system is si, THEN should convert to base units", func(t *testing.T) {
+		assert.Equal(t, 273.15, SI.Temperature(0))
+		assert.Equal(t, 10.0, SI.Speed(36))
+		assert.Equal(t, 2.5, SI.Precipitation(2.5))
+		assert.Equal(t, 101325.0, SI.Pressure(1013.25))
+		assert.Equal(t, 10000.0, SI.Distance(10))
+	})
+
+	t.Run("WHEN converting fahrenheit back, THEN should return celsius", func(t *testing.T) {
+		assert.InDelta(t, 37.0, FahrenheitToCelsius(CelsiusToFahrenheit(37)), 1e-9)
+	})
+}
+
+func TestTemperatureDifference(t *testing.T) {
+	t.Run("WHEN converting a difference, THEN should not add the scale offset", func(t *testing.T) {
+		assert.Equal(t, 9.0, Imperial.TemperatureDifference(5))
+		assert.Equal(t, 5.0, SI.TemperatureDifference(5))
+		assert.Equal(t, -2.5, Metric.TemperatureDifference(-2.5))
+	})
+}
+
+func TestParse(t *testing.T) {
+	system, err := Parse(" Imperial ")
+	assert.NoError(t, err)
+	assert.Equal(t, Imperial, system)
+	assert.Equal(t, "mph", system.Labels().Speed)
+
+	_, err = Parse("kelvin")
+	assert.ErrorIs(t, err, ErrUnknownSystem)
+}
+
+func TestPreferences(t *testing.T) {
+	prefs, err := ParsePreferences("si", "abc=imperial, def = metric")
+	assert.NoError(t, err)
+
+	t.Run("WHEN units are requested, THEN should win over the defaults", func(t *testing.T) {
+		system, err := prefs.Resolve("metric", "abc")
+
+		assert.NoError(t, err)
+		assert.Equal(t, Metric, system)
+	})
+
+	t.Run("WHEN API key has a default, THEN should use it", func(t *testing.T) {
+		system, err := prefs.Resolve("", "abc")
+
+		assert.NoError(t, err)
+		assert.Equal(t, Imperial, system)
+	})
+
+	t.Run("WHEN API key is unknown, THEN should use the global default", func(t *testing.T) {
+		system, err := prefs.Resolve("", "zzz")
+
+		assert.NoError(t, err)
+		assert.Equal(t, SI, system)
+	})
+
+	t.Run("WHEN requested units are unknown, THEN should return error", func(t *testing.T) {
+		_, err := prefs.Resolve("furlongs", "")
+
+		assert.ErrorIs(t, err, ErrUnknownSystem)
+	})
+
+	t.Run("WHEN config is malformed, THEN should return error", func(t *testing.T) {
+		_, err := ParsePreferences("", "abc")
+		assert.Error(t, err)
+
+		_, err = ParsePreferences("", "abc=feet")
+		assert.ErrorIs(t, err, ErrUnknownSystem)
+	})
+}
@@ -8,6 +8,110 @@ const (
 	DateFormat         string        = "2006-01-02"
 	DateFormatWithHour string        = "2006-01-02 15:04"
 	WeatherLocationKey string        = "weather:location:%d"
+	WeatherBatchKey    string        = "weather:batch:location:%d"
+	WeatherSyncChannel string        = "weather:synced"
+	MaxForecastDays    int           = 14
+	MaxBatchLocations  int           = 100
+)
+
+const (
+	DefaultNearbyRadiusKm float64 = 50
+	MaxNearbyRadiusKm     float64 = 1000
+	DefaultNearbyLimit    int     = 10
+	MaxNearbyLimit        int     = 100
+	PointWeatherSources   int     = 3
+)
+
+const (
+	LocationImportBatchSize int = 100
+	MaxLocationImportSize   int = 10 << 20
+	MaxLocationExportRows   int = 10000
+)
+
+const (
+	DefaultSuggestLimit           int           = 10
+	MaxSuggestLimit               int           = 50
+	LocationSearchRefreshInterval time.Duration = 5 * time.Minute
+)
+
+const (
+	MaxLocationTags       int = 20
+	MaxLocationTagLength  int = 50
+	MaxGroupMembers       int = 500
+	MaxLocationNames      int = 50
+	MaxLocationNameLength int = 100
+)
+
+const (
+	DefaultDailyRangeDays int = 7
+	MaxDailyRangeDays     int = 31
+	// a provider day summary further off the hourly data than these
+	// tolerances is flagged as inconsistent
+	DailyTemperatureTolerance   float64 = 2
+	DailyWindSpeedTolerance     float64 = 5
+	DailyPrecipitationTolerance float64 = 1
+)
+
+const (
+	MaxStatsRangeDays    int     = 366
+	StatsComparisonYears int     = 3
+	RainyDayThresholdMM  float64 = 1
+)
+
+const (
+	MaxCompareLocations  int = 10
+	MaxCompareRangeHours int = 7 * 24
+)
+
+const (
+	MaxExportRangeDays int = 366
+	MaxExportRows      int = 1000000
+)
+
+const (
+	RetentionDBTimeout  time.Duration = 30 * time.Second
+	RetentionBatchPause time.Duration = 100 * time.Millisecond
+	// RetentionDownsampleDays bounds the days a single downsample statement
+	// aggregates
+	RetentionDownsampleDays int = 31
+)
+
+const ConditionBackfillBatchSize int = 1000
+
+const (
+	ForecastFeedPastDays int           = 7
+	ForecastFeedEntries  int           = 50
+	ForecastFeedMaxAge   time.Duration = 15 * time.Minute
+)
+
+const (
+	MaxMapLocations int           = 1000
+	MapLayerMaxAge  time.Duration = 5 * time.Minute
+	// MapWeatherMaxAge is how old the current weather of a location may be
+	// before the map layer leaves it out
+	MapWeatherMaxAge time.Duration = 6 * time.Hour
+)
+
+const (
+	DefaultChartRangeHours int           = 72
+	MaxChartRangeHours     int           = MaxForecastDays * 24
+	DefaultChartWidth      int           = 800
+	DefaultChartHeight     int           = 300
+	MinChartWidth          int           = 200
+	MaxChartWidth          int           = 2000
+	MinChartHeight         int           = 100
+	MaxChartHeight         int           = 1000
+	ChartMaxAge            time.Duration = 15 * time.Minute
+)
+
+// ConditionIconMaxAge is long, an icon only changes with a release.
+const ConditionIconMaxAge time.Duration = 24 * time.Hour
+
+const (
+	IconKey      string        = "weather:icon:%s"
+	IconCacheTTL time.Duration = 30 * 24 * time.Hour
+	IconMaxAge   time.Duration = 24 * time.Hour
+	MaxIconSize  int64         = 1 << 20
 )
 
 const (
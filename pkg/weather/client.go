@@ -3,8 +3,12 @@ package weather
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"time"
 	"tyarus/weather-app/internal/config"
 	"tyarus/weather-app/pkg/utils"
@@ -12,8 +16,17 @@ import (
 
 type WeatherAPIClientInterface interface {
 	GetForecast(ctx context.Context, location string, day int) (*ForecastResponse, error)
+	GetHistory(ctx context.Context, location string, date time.Time) (*ForecastResponse, error)
+	SearchLocations(ctx context.Context, query string) ([]SearchLocation, error)
+	GetTimezone(ctx context.Context, query string) (*Location, error)
+	GetIcon(ctx context.Context, code string) ([]byte, error)
 }
 
+var (
+	ErrIconNotFound = errors.New("icon not found")
+	ErrIconTooLarge = errors.New("icon too large")
+)
+
 type WeatherAPIClient struct {
 	HTTP   *http.Client
 	Config config.Config
@@ -34,37 +47,127 @@ func (c *WeatherAPIClient) GetForecast(ctx context.Context, location string, day
 	}
 
 	var forecast ForecastResponse
-	fetchForecastFunc := func() error {
-		url := fmt.Sprintf("%s/forecast.json?key=%s&q=%s&days=%d",
-			c.Config.WeatherAPIBaseURL, c.Config.WeatherAPIKey, location, day)
+	err := c.get(ctx, "/forecast.json", url.Values{
+		"q":    {location},
+		"days": {strconv.Itoa(day)},
+	}, &forecast)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch forecast with backoff: %w", err)
+	}
+
+	return &forecast, nil
+}
+
+// GetHistory fetches the observed weather of a past day in the same shape as
+// a forecast of that day.
+func (c *WeatherAPIClient) GetHistory(ctx context.Context, location string, date time.Time) (*ForecastResponse, error) {
+	var history ForecastResponse
+	err := c.get(ctx, "/history.json", url.Values{
+		"q":  {location},
+		"dt": {date.Format(utils.DateFormat)},
+	}, &history)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch history with backoff: %w", err)
+	}
+
+	return &history, nil
+}
+
+// SearchLocations resolves a city name, coordinates ("lat,lon") or a search
+// id ("id:123") into matching locations.
+func (c *WeatherAPIClient) SearchLocations(ctx context.Context, query string) ([]SearchLocation, error) {
+	var locations []SearchLocation
+	err := c.get(ctx, "/search.json", url.Values{"q": {query}}, &locations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search locations with backoff: %w", err)
+	}
+
+	return locations, nil
+}
+
+func (c *WeatherAPIClient) GetTimezone(ctx context.Context, query string) (*Location, error) {
+	var timezone TimezoneResponse
+	err := c.get(ctx, "/timezone.json", url.Values{"q": {query}}, &timezone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch timezone with backoff: %w", err)
+	}
+
+	return &timezone.Location, nil
+}
+
+// GetIcon downloads the PNG of an icon code from the provider's CDN. An icon
+// over utils.MaxIconSize fails with ErrIconTooLarge rather than being cut.
+func (c *WeatherAPIClient) GetIcon(ctx context.Context, code string) ([]byte, error) {
+	if !ValidIconCode(code) {
+		return nil, ErrIconNotFound
+	}
+
+	var icon []byte
+	err := c.fetch(ctx, IconURL(code), func(resp *http.Response) error {
+		if resp.StatusCode == http.StatusNotFound {
+			return ErrIconNotFound
+		}
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("icon cdn returned status %d", resp.StatusCode)
+		}
 
-		resp, err := c.HTTP.Get(url)
+		// one byte past the limit tells a complete icon from a cut one
+		var err error
+		icon, err = io.ReadAll(io.LimitReader(resp.Body, utils.MaxIconSize+1))
 		if err != nil {
-			return fmt.Errorf("failed to request weather api: %w", err)
+			return fmt.Errorf("failed to read icon: %w", err)
 		}
-		defer resp.Body.Close()
+		if int64(len(icon)) > utils.MaxIconSize {
+			return ErrIconTooLarge
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch icon %s with backoff: %w", code, err)
+	}
+
+	return icon, nil
+}
+
+func (c *WeatherAPIClient) get(ctx context.Context, path string, query url.Values, dest interface{}) error {
+	query.Set("key", c.Config.WeatherAPIKey)
+	endpoint := c.Config.WeatherAPIBaseURL + path + "?" + query.Encode()
 
+	return c.fetch(ctx, endpoint, func(resp *http.Response) error {
 		if resp.StatusCode != http.StatusOK {
 			return fmt.Errorf("weather api returned status %d", resp.StatusCode)
 		}
 
-		if err := json.NewDecoder(resp.Body).Decode(&forecast); err != nil {
+		if err := json.NewDecoder(resp.Body).Decode(dest); err != nil {
 			return fmt.Errorf("failed to decode response: %w", err)
 		}
 
 		return nil
+	})
+}
+
+// fetch requests endpoint with retries, read is called with every response
+// and the request is retried when it returns an error.
+func (c *WeatherAPIClient) fetch(ctx context.Context, endpoint string, read func(resp *http.Response) error) error {
+	fetchFunc := func() error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return fmt.Errorf("failed to create weather api request: %w", err)
+		}
+
+		resp, err := c.HTTP.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to request weather api: %w", err)
+		}
+		defer resp.Body.Close()
+
+		return read(resp)
 	}
 
-	err := utils.RetryWithBackoff(ctx, utils.RetryWithBackoffParam{
-		Func:       fetchForecastFunc,
+	return utils.RetryWithBackoff(ctx, utils.RetryWithBackoffParam{
+		Func:       fetchFunc,
 		BaseDelay:  time.Duration(c.Config.BackoffBaseDelay),
 		MaxRetries: c.Config.BackoffMaxRetries,
 		MaxDelay:   time.Duration(c.Config.BackoffMaxDelay),
 	})
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch forecast with backoff: %w", err)
-	}
-
-	return &forecast, nil
 }
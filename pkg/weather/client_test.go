@@ -0,0 +1,48 @@
+package weather
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"tyarus/weather-app/internal/config"
+	"tyarus/weather-app/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// roundTripFunc answers the requests of a client without a network.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func newIconClient(size int64) *WeatherAPIClient {
+	return &WeatherAPIClient{
+		HTTP: &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader(make([]byte, size))),
+			}, nil
+		})},
+		Config: config.Config{BackoffMaxRetries: 0},
+	}
+}
+
+func TestGetIcon(t *testing.T) {
+	t.Run("WHEN the icon is within the limit, THEN should return it whole", func(t *testing.T) {
+		icon, err := newIconClient(utils.MaxIconSize).GetIcon(context.Background(), "day-113")
+
+		assert.NoError(t, err)
+		assert.Len(t, icon, int(utils.MaxIconSize))
+	})
+
+	t.Run("WHEN the icon is over the limit, THEN should fail instead of cutting it", func(t *testing.T) {
+		icon, err := newIconClient(utils.MaxIconSize+1).GetIcon(context.Background(), "day-113")
+
+		assert.ErrorIs(t, err, ErrIconTooLarge)
+		assert.Nil(t, icon)
+	})
+}
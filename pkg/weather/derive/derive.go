@@ -0,0 +1,143 @@
+// Package derive computes comfort indicators the weather provider does not
+// supply consistently. Temperatures are in degrees Celsius, relative
+// humidity in percent and wind speed in kilometers per hour.
+package derive
+
+import "math"
+
+// Names of the indicators, as accepted by Indicators.Value.
+const (
+	NameDewPoint            = "dewPoint"
+	NameHeatIndex           = "heatIndex"
+	NameHumidex             = "humidex"
+	NameWindChill           = "windChill"
+	NameApparentTemperature = "apparentTemperature"
+)
+
+// DewPoint uses the Magnus formula with the Alduchov and Eskridge
+// coefficients. It is undefined without humidity.
+func DewPoint(celsius, humidity float64) (float64, bool) {
+	if humidity <= 0 || humidity > 100 {
+		return 0, false
+	}
+
+	const a, b = 17.625, 243.04
+	gamma := math.Log(humidity/100) + a*celsius/(b+celsius)
+	return b * gamma / (a - gamma), true
+}
+
+// HeatIndex is the NWS heat index, only defined from 26.7°C (80°F) on.
+func HeatIndex(celsius, humidity float64) (float64, bool) {
+	if celsius < 26.7 || humidity <= 0 || humidity > 100 {
+		return 0, false
+	}
+
+	t := celsius*9/5 + 32
+	rh := humidity
+
+	// the simple formula is accurate enough below 80°F
+	hi := 0.5 * (t + 61 + (t-68)*1.2 + rh*0.094)
+	if (hi+t)/2 >= 80 {
+		hi = -42.379 + 2.04901523*t + 10.14333127*rh -
+			0.22475541*t*rh - 0.00683783*t*t - 0.05481717*rh*rh +
+			0.00122874*t*t*rh + 0.00085282*t*rh*rh - 0.00000199*t*t*rh*rh
+
+		switch {
+		case rh < 13 && t >= 80 && t <= 112:
+			hi -= (13 - rh) / 4 * math.Sqrt((17-math.Abs(t-95))/17)
+		case rh > 85 && t >= 80 && t <= 87:
+			hi += (rh - 85) / 10 * (87 - t) / 5
+		}
+	}
+
+	return (hi - 32) * 5 / 9, true
+}
+
+// Humidex is the Environment Canada humidex, reported from 20°C on when it
+// reaches 25.
+func Humidex(celsius, humidity float64) (float64, bool) {
+	if celsius < 20 {
+		return 0, false
+	}
+
+	dewPoint, ok := DewPoint(celsius, humidity)
+	if !ok {
+		return 0, false
+	}
+
+	vapourPressure := 6.11 * math.Exp(5417.7530*(1/273.16-1/(273.15+dewPoint)))
+	humidex := celsius + 0.5555*(vapourPressure-10)
+	if humidex < 25 {
+		return 0, false
+	}
+
+	return humidex, true
+}
+
+// WindChill is the North American wind chill index, only defined at 10°C
+// and below with wind of at least 4.8 km/h.
+func WindChill(celsius, windKph float64) (float64, bool) {
+	if celsius > 10 || windKph < 4.8 {
+		return 0, false
+	}
+
+	v := math.Pow(windKph, 0.16)
+	return 13.12 + 0.6215*celsius - 11.37*v + 0.3965*celsius*v, true
+}
+
+// ApparentTemperature is the Australian Bureau of Meteorology apparent
+// temperature in the shade, combining humidity and wind.
+func ApparentTemperature(celsius, humidity, windKph float64) float64 {
+	vapourPressure := humidity / 100 * 6.105 * math.Exp(17.27*celsius/(237.7+celsius))
+	return celsius + 0.33*vapourPressure - 0.70*windKph/3.6 - 4.00
+}
+
+// Indicators holds every indicator, nil when it is undefined for the
+// weather.
+type Indicators struct {
+	DewPoint            *float64
+	HeatIndex           *float64
+	Humidex             *float64
+	WindChill           *float64
+	ApparentTemperature float64
+}
+
+func Compute(celsius, humidity, windKph float64) Indicators {
+	return Indicators{
+		DewPoint:            optional(DewPoint(celsius, humidity)),
+		HeatIndex:           optional(HeatIndex(celsius, humidity)),
+		Humidex:             optional(Humidex(celsius, humidity)),
+		WindChill:           optional(WindChill(celsius, windKph)),
+		ApparentTemperature: ApparentTemperature(celsius, humidity, windKph),
+	}
+}
+
+// Value returns an indicator by name, so rules can refer to indicators
+// by configuration.
+func (i Indicators) Value(name string) (float64, bool) {
+	var value *float64
+	switch name {
+	case NameDewPoint:
+		value = i.DewPoint
+	case NameHeatIndex:
+		value = i.HeatIndex
+	case NameHumidex:
+		value = i.Humidex
+	case NameWindChill:
+		value = i.WindChill
+	case NameApparentTemperature:
+		return i.ApparentTemperature, true
+	}
+
+	if value == nil {
+		return 0, false
+	}
+	return *value, true
+}
+
+func optional(value float64, ok bool) *float64 {
+	if !ok {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,122 @@
+package derive
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDewPoint(t *testing.T) {
+	dewPoint, ok := DewPoint(25, 60)
+
+	assert.True(t, ok)
+	assert.InDelta(t, 16.7, dewPoint, 0.1)
+
+	_, ok = DewPoint(25, 0)
+	assert.False(t, ok)
+}
+
+func TestHeatIndex(t *testing.T) {
+	t.Run("WHEN hot and humid, THEN should match the NWS table", func(t *testing.T) {
+		// 90°F at 70% is 106°F
+		heatIndex, ok := HeatIndex(32.22, 70)
+
+		assert.True(t, ok)
+		assert.InDelta(t, 41.1, heatIndex, 0.6)
+	})
+
+	t.Run("WHEN below 26.7°C, THEN should be undefined", func(t *testing.T) {
+		_, ok := HeatIndex(25, 90)
+
+		assert.False(t, ok)
+	})
+}
+
+func TestHumidex(t *testing.T) {
+	t.Run("WHEN warm and humid, THEN should match the Environment Canada table", func(t *testing.T) {
+		// 30°C with a dew point of about 15°C is 34
+		humidex, ok := Humidex(30, 40)
+
+		assert.True(t, ok)
+		assert.InDelta(t, 34, humidex, 0.5)
+	})
+
+	t.Run("WHEN humidex stays below 25, THEN should be undefined", func(t *testing.T) {
+		_, ok := Humidex(21, 20)
+
+		assert.False(t, ok)
+	})
+}
+
+func TestWindChill(t *testing.T) {
+	t.Run("WHEN cold and windy, THEN should match the Environment Canada table", func(t *testing.T) {
+		windChill, ok := WindChill(-10, 20)
+
+		assert.True(t, ok)
+		assert.InDelta(t, -17.9, windChill, 0.1)
+	})
+
+	t.Run("WHEN warm or calm, THEN should be undefined", func(t *testing.T) {
+		_, ok := WindChill(15, 30)
+		assert.False(t, ok)
+
+		_, ok = WindChill(-5, 2)
+		assert.False(t, ok)
+	})
+}
+
+func TestApparentTemperature(t *testing.T) {
+	assert.InDelta(t, 26.2, ApparentTemperature(25, 50, 0), 0.1)
+	assert.Less(t, ApparentTemperature(25, 50, 36), ApparentTemperature(25, 50, 0))
+}
+
+func TestIndicatorsValue(t *testing.T) {
+	indicators := Compute(-10, 80, 20)
+
+	windChill, ok := indicators.Value(NameWindChill)
+	assert.True(t, ok)
+	assert.InDelta(t, -17.9, windChill, 0.1)
+
+	_, ok = indicators.Value(NameHeatIndex)
+	assert.False(t, ok)
+
+	_, ok = indicators.Value("unknown")
+	assert.False(t, ok)
+}
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Rule
+		wantErr bool
+	}{
+		{value: "heatIndex>=32", want: Rule{Indicator: NameHeatIndex, Operator: ">=", Threshold: 32}},
+		{value: "windChill < -10", want: Rule{Indicator: NameWindChill, Operator: "<", Threshold: -10}},
+		{value: "humidex>40.5", want: Rule{Indicator: NameHumidex, Operator: ">", Threshold: 40.5}},
+		{value: "pressure>1000", wantErr: true},
+		{value: "dewPoint=20", wantErr: true},
+		{value: "dewPoint>warm", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			rule, err := ParseRule(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, rule)
+		})
+	}
+}
+
+func TestRuleMatch(t *testing.T) {
+	hot := Compute(32.22, 70, 10)
+
+	assert.True(t, Rule{Indicator: NameHeatIndex, Operator: ">=", Threshold: 40}.Match(hot))
+	assert.False(t, Rule{Indicator: NameHeatIndex, Operator: "<", Threshold: 40}.Match(hot))
+	// wind chill is undefined at 32°C, so no rule on it matches
+	assert.False(t, Rule{Indicator: NameWindChill, Operator: "<", Threshold: 100}.Match(hot))
+}
@@ -0,0 +1,94 @@
+package derive
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Rule compares an indicator with a threshold, e.g. "heatIndex>=32".
+type Rule struct {
+	Indicator string
+	Operator  string
+	Threshold float64
+}
+
+var (
+	operators  = []string{">=", "<=", ">", "<"}
+	indicators = []string{NameDewPoint, NameHeatIndex, NameHumidex, NameWindChill, NameApparentTemperature}
+)
+
+// ParseRule parses "<indicator><operator><threshold>" with one of
+// >, >=, < and <=. The threshold is in metric units.
+func ParseRule(value string) (Rule, error) {
+	value = strings.ReplaceAll(value, " ", "")
+	for _, operator := range operators {
+		indicator, threshold, found := strings.Cut(value, operator)
+		if !found {
+			continue
+		}
+
+		if !isIndicator(indicator) {
+			return Rule{}, fmt.Errorf("unknown indicator %q", indicator)
+		}
+
+		parsed, err := strconv.ParseFloat(threshold, 64)
+		if err != nil {
+			return Rule{}, fmt.Errorf("invalid threshold %q: %w", threshold, err)
+		}
+
+		return Rule{Indicator: indicator, Operator: operator, Threshold: parsed}, nil
+	}
+
+	return Rule{}, fmt.Errorf("rule %q has no operator", value)
+}
+
+// Match reports whether the indicator is defined and passes the threshold.
+func (r Rule) Match(i Indicators) bool {
+	value, ok := i.Value(r.Indicator)
+	if !ok {
+		return false
+	}
+
+	switch r.Operator {
+	case ">":
+		return value > r.Threshold
+	case ">=":
+		return value >= r.Threshold
+	case "<":
+		return value < r.Threshold
+	case "<=":
+		return value <= r.Threshold
+	}
+	return false
+}
+
+// ParseRules parses comma separated rules, e.g. "heatIndex>=32,windChill<-20".
+func ParseRules(value string) ([]Rule, error) {
+	var rules []Rule
+	for _, entry := range strings.Split(value, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+
+		rule, err := ParseRule(entry)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (r Rule) String() string {
+	return r.Indicator + r.Operator + strconv.FormatFloat(r.Threshold, 'f', -1, 64)
+}
+
+func isIndicator(name string) bool {
+	for _, indicator := range indicators {
+		if indicator == name {
+			return true
+		}
+	}
+	return false
+}
@@ -16,6 +16,8 @@ type Location struct {
 	Localtime string  `json:"localtime"`
 }
 
+// Hour measurements the provider does not always send are pointers, nil
+// when the field was missing.
 type Hour struct {
 	ForecastTime string    `json:"time"`
 	TempC        float64   `json:"temp_c"`
@@ -23,6 +25,9 @@ type Hour struct {
 	Condition    Condition `json:"condition"`
 	Humidity     int       `json:"humidity"`
 	WindKph      float64   `json:"wind_kph"`
+	PrecipMM     *float64  `json:"precip_mm"`
+	PressureMB   *float64  `json:"pressure_mb"`
+	VisKM        *float64  `json:"vis_km"`
 }
 
 type Forecast struct {
@@ -32,17 +37,33 @@ type Forecast struct {
 type ForecastDay struct {
 	Date  string `json:"date"`
 	Day   Day    `json:"day"`
+	Astro Astro  `json:"astro"`
 	Hours []Hour `json:"hour"`
 }
 
+// Astro times are local to the location in "05:37 AM" format, or a text such
+// as "No moonrise" when the event does not happen on the day.
+type Astro struct {
+	Sunrise          string  `json:"sunrise"`
+	Sunset           string  `json:"sunset"`
+	Moonrise         string  `json:"moonrise"`
+	Moonset          string  `json:"moonset"`
+	MoonPhase        string  `json:"moon_phase"`
+	MoonIllumination float64 `json:"moon_illumination"`
+}
+
+// Day measurements the provider does not always send are pointers like in
+// Hour.
 type Day struct {
-	MaxtempC    float64   `json:"maxtemp_c"`
-	MintempC    float64   `json:"mintemp_c"`
-	AvgtempC    float64   `json:"avgtemp_c"`
-	AvgtempF    float64   `json:"avgtemp_f"`
-	AvgHumidity float64   `json:"avghumidity"`
-	MaxWindKPH  float64   `json:"avgmaxwind_kph"`
-	Condition   Condition `json:"condition"`
+	MaxtempC      float64   `json:"maxtemp_c"`
+	MintempC      float64   `json:"mintemp_c"`
+	AvgtempC      float64   `json:"avgtemp_c"`
+	AvgtempF      float64   `json:"avgtemp_f"`
+	AvgHumidity   float64   `json:"avghumidity"`
+	MaxWindKPH    float64   `json:"avgmaxwind_kph"`
+	TotalPrecipMM *float64  `json:"totalprecip_mm"`
+	AvgVisKM      *float64  `json:"avgvis_km"`
+	Condition     Condition `json:"condition"`
 }
 
 type Condition struct {
@@ -50,3 +71,17 @@ type Condition struct {
 	Icon string `json:"icon"`
 	Code int    `json:"code"`
 }
+
+type SearchLocation struct {
+	ID      int64   `json:"id"`
+	Name    string  `json:"name"`
+	Region  string  `json:"region"`
+	Country string  `json:"country"`
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+	URL     string  `json:"url"`
+}
+
+type TimezoneResponse struct {
+	Location Location `json:"location"`
+}
@@ -0,0 +1,34 @@
+package weather
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// iconURLPattern matches the icon URLs of weatherapi.com, e.g.
+// //cdn.weatherapi.com/weather/64x64/day/113.png
+var iconURLPattern = regexp.MustCompile(`^(?:https?:)?//cdn\.weatherapi\.com/weather/64x64/(day|night)/(\d{3})\.png$`)
+
+var iconCodePattern = regexp.MustCompile(`^(day|night)-\d{3}$`)
+
+// IconCode returns the code of a provider icon URL such as "day-113", false
+// when the URL is not a provider icon.
+func IconCode(iconURL string) (string, bool) {
+	matches := iconURLPattern.FindStringSubmatch(strings.TrimSpace(iconURL))
+	if matches == nil {
+		return "", false
+	}
+	return matches[1] + "-" + matches[2], true
+}
+
+// ValidIconCode reports whether code has the form returned by IconCode.
+func ValidIconCode(code string) bool {
+	return iconCodePattern.MatchString(code)
+}
+
+// IconURL is the absolute provider URL of an icon code.
+func IconURL(code string) string {
+	period, number, _ := strings.Cut(code, "-")
+	return fmt.Sprintf("https://cdn.weatherapi.com/weather/64x64/%s/%s.png", period, number)
+}
@@ -0,0 +1,35 @@
+package weather
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIconCode(t *testing.T) {
+	tests := []struct {
+		url  string
+		code string
+		ok   bool
+	}{
+		{"//cdn.weatherapi.com/weather/64x64/day/113.png", "day-113", true},
+		{"https://cdn.weatherapi.com/weather/64x64/night/296.png", "night-296", true},
+		{"//cdn.weatherapi.com/weather/128x128/day/113.png", "", false},
+		{"https://example.com/weather/64x64/day/113.png", "", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		code, ok := IconCode(tt.url)
+		assert.Equal(t, tt.code, code, tt.url)
+		assert.Equal(t, tt.ok, ok, tt.url)
+	}
+}
+
+func TestIconURL(t *testing.T) {
+	code, _ := IconCode("//cdn.weatherapi.com/weather/64x64/night/113.png")
+
+	assert.True(t, ValidIconCode(code))
+	assert.Equal(t, "https://cdn.weatherapi.com/weather/64x64/night/113.png", IconURL(code))
+	assert.False(t, ValidIconCode("day-113/../../x"))
+}